@@ -393,6 +393,8 @@ type CloudProfilerConfig struct {
 type Config struct {
 	AppName string `yaml:"app-name"`
 
+	AsOf string `yaml:"as-of"`
+
 	CacheDir ResolvedPath `yaml:"cache-dir"`
 
 	CloudProfiler CloudProfilerConfig `yaml:"cloud-profiler"`
@@ -511,6 +513,12 @@ type FileCacheConfig struct {
 }
 
 type FileSystemConfig struct {
+	BucketMounts []string `yaml:"bucket-mounts"`
+
+	BucketQuotas []string `yaml:"bucket-quotas"`
+
+	CaseInsensitiveLookup bool `yaml:"case-insensitive-lookup"`
+
 	CongestionThreshold int64 `yaml:"congestion-threshold"`
 
 	DirMode Octal `yaml:"dir-mode"`
@@ -519,10 +527,20 @@ type FileSystemConfig struct {
 
 	EnableKernelReader bool `yaml:"enable-kernel-reader"`
 
+	EnableSpecialFiles bool `yaml:"enable-special-files"`
+
+	EnableVersionsDirs bool `yaml:"enable-versions-dirs"`
+
+	EnforcePermissions bool `yaml:"enforce-permissions"`
+
+	EscapeUnsupportedPaths bool `yaml:"escape-unsupported-paths"`
+
 	ExperimentalEnableDentryCache bool `yaml:"experimental-enable-dentry-cache"`
 
 	ExperimentalEnableReaddirplus bool `yaml:"experimental-enable-readdirplus"`
 
+	ExperimentalEnableXattrs bool `yaml:"experimental-enable-xattrs"`
+
 	ExperimentalODirect bool `yaml:"experimental-o-direct"`
 
 	FileMode Octal `yaml:"file-mode"`
@@ -535,6 +553,8 @@ type FileSystemConfig struct {
 
 	InactiveMrdCacheSize int64 `yaml:"inactive-mrd-cache-size"`
 
+	InodeIndexFile ResolvedPath `yaml:"inode-index-file"`
+
 	KernelListCacheTtlSecs int64 `yaml:"kernel-list-cache-ttl-secs"`
 
 	KernelParamsFile ResolvedPath `yaml:"kernel-params-file"`
@@ -543,13 +563,47 @@ type FileSystemConfig struct {
 
 	MaxReadAheadKb int64 `yaml:"max-read-ahead-kb"`
 
+	NormalizeCreatedNames bool `yaml:"normalize-created-names"`
+
+	OverlayDir ResolvedPath `yaml:"overlay-dir"`
+
+	PathRules []string `yaml:"path-rules"`
+
 	PreconditionErrors bool `yaml:"precondition-errors"`
 
+	PreservePosix bool `yaml:"preserve-posix"`
+
+	QuotaMb int64 `yaml:"quota-mb"`
+
 	RenameDirLimit int64 `yaml:"rename-dir-limit"`
 
+	RenameDirParallelism int64 `yaml:"rename-dir-parallelism"`
+
+	RenameJournalDir ResolvedPath `yaml:"rename-journal-dir"`
+
+	RenameJournalRecovery string `yaml:"rename-journal-recovery"`
+
+	StableInodeNumbers bool `yaml:"stable-inode-numbers"`
+
 	TempDir ResolvedPath `yaml:"temp-dir"`
 
+	TrashPrefix string `yaml:"trash-prefix"`
+
+	TrashRetention time.Duration `yaml:"trash-retention"`
+
 	Uid int64 `yaml:"uid"`
+
+	UnicodeNormalizedLookup bool `yaml:"unicode-normalized-lookup"`
+
+	UnionLayers []string `yaml:"union-layers"`
+
+	UsageRefreshInterval time.Duration `yaml:"usage-refresh-interval"`
+
+	WriteQuotaMb int64 `yaml:"write-quota-mb"`
+
+	WriteQuotaObjects int64 `yaml:"write-quota-objects"`
+
+	WriteQuotaPerTopLevelDir bool `yaml:"write-quota-per-top-level-dir"`
 }
 
 type GcsAuthConfig struct {
@@ -633,6 +687,12 @@ type LoggingConfig struct {
 }
 
 type MetadataCacheConfig struct {
+	ChangeFeed string `yaml:"change-feed"`
+
+	ChangeListingInterval time.Duration `yaml:"change-listing-interval"`
+
+	ChangeListingPrefixes []string `yaml:"change-listing-prefixes"`
+
 	DeprecatedStatCacheCapacity int64 `yaml:"deprecated-stat-cache-capacity"`
 
 	DeprecatedStatCacheTtl time.Duration `yaml:"deprecated-stat-cache-ttl"`
@@ -748,10 +808,24 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.StringP("app-name", "", "", "The application name of this mount.")
 
+	flagSet.StringP("as-of", "", "", "Mounts the bucket read-only as it was at the given RFC 3339 time, e.g. 2026-01-02T15:04:05Z, serving the generation of each object that was live then. The bucket must have object versioning enabled.")
+
 	flagSet.StringP("billing-project", "", "", "Project to use for billing when accessing a bucket enabled with \"Requester Pays\".")
 
+	flagSet.StringSliceP("bucket-mounts", "", []string{}, "Mounts only the listed buckets, each under its own directory of the mount's root, as comma separated <dir>=<bucket>[/<prefix>] entries. An entry may be followed by ;billing-project=<project>, ;read-only, ;ttl-secs=<secs> and ;negative-ttl-secs=<secs> to override those settings for its bucket. Requires a dynamic mount.")
+
+	flagSet.StringSliceP("bucket-quotas", "", []string{}, "Per-bucket capacity reported by statfs, as comma separated <bucket>=<MiB> entries. The entry for the mounted bucket takes precedence over --quota-mb. Not used for dynamic mounts.")
+
 	flagSet.StringP("cache-dir", "", "", "Enables file-caching. Specifies the directory to use for file-cache.")
 
+	flagSet.BoolP("case-insensitive-lookup", "", false, "Looks names that match no entry of a directory exactly up again ignoring case. A name that matches several entries this way, and none exactly, fails with ENOTUNIQ.")
+
+	flagSet.StringP("change-feed", "", "", "Where to read notifications of changes to the mounted bucket from, which refresh the metadata caches and the kernel's caches of the changed objects. Either a file or, prefixed with unix:, a Unix socket, carrying one Cloud Storage Pub/Sub notification per line, as the JSON object resource or as the JSON of a pushed Pub/Sub message. Not used for dynamic mounts.")
+
+	flagSet.DurationP("change-listing-interval", "", 0*time.Nanosecond, "How often to list the mounted bucket for objects that have changed since the last listing, which refreshes the metadata caches and the kernel's caches of the changed objects. 0 disables listing for changes. Not used for dynamic mounts.")
+
+	flagSet.StringSliceP("change-listing-prefixes", "", []string{}, "Comma separated object name prefixes to list for changes, to limit the cost of listing large buckets. The whole bucket is listed if none are given.")
+
 	flagSet.IntP("chunk-retry-deadline-secs", "", 120, "We send larger file uploads in 16 MiB (Legacy Writes) or 32MiB (Streaming Writes) chunks. This flag controls the overall duration that GCSFuse would keep retrying for a single chunk upload completion. 0 means infinity duration for chunk retries.")
 
 	if err := flagSet.MarkHidden("chunk-retry-deadline-secs"); err != nil {
@@ -960,6 +1034,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("enable-special-files", "", false, "Lets mknod create named pipes, sockets and device nodes, which are stored as empty objects recording the file type and device number. Without it, creating pipes and sockets fails with ENOTSUP, and device nodes are created as regular files.")
+
 	flagSet.BoolP("enable-standard-symlinks", "", true, "Enables the creation and reading of symbolic links using the standard GCS representation. When enabled, new symlinks created via GCSFuse mount ensure compatibility with other GCS clients like Storage Transfer Service (STS).")
 
 	if err := flagSet.MarkHidden("enable-standard-symlinks"); err != nil {
@@ -980,6 +1056,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("enable-versions-dirs", "", false, "Serves every generation of a file, noncurrent ones included, as a read-only file in the virtual directory <file>@versions, for buckets with object versioning.")
+
+	flagSet.BoolP("enforce-permissions", "", false, "Checks the mode, uid and gid of files and directories against the user of each operation, and records the user creating a file or directory as its owner. Requires --preserve-posix, and -o allow_other for the mount to be shared between users.")
+
+	flagSet.BoolP("escape-unsupported-paths", "", false, "Shows objects whose names have empty, '.' or '..' path segments, such as a//b or ../c, under reversibly escaped names instead of hiding them, so that they can be read, renamed and deleted. Names of files that begin with '%' are reserved for escaped names.")
+
 	flagSet.BoolP("experimental-enable-dentry-cache", "", false, "When enabled, it sets the Dentry cache entry timeout same as metadata-cache-ttl. This enables kernel to use cached entry to map the file paths to inodes, instead of making LookUpInode calls to GCSFuse.")
 
 	if err := flagSet.MarkHidden("experimental-enable-dentry-cache"); err != nil {
@@ -1004,6 +1086,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("experimental-enable-xattrs", "", false, "Experimental: Enables extended attributes on files. Attributes in the user namespace are stored as custom metadata on the backing object.")
+
+	if err := flagSet.MarkHidden("experimental-enable-xattrs"); err != nil {
+		return err
+	}
+
 	flagSet.IntP("experimental-grpc-conn-pool-size", "", 1, "The number of gRPC channel in grpc client.")
 
 	if err := flagSet.MarkDeprecated("experimental-grpc-conn-pool-size", "Experimental flag: can be removed in a minor release."); err != nil {
@@ -1122,6 +1210,8 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.StringP("inode-index-file", "", "", "Records the inode numbers handed out with --stable-inode-numbers after hash collisions in this file, so that collisions are settled the same way every time gcsfuse is mounted.")
+
 	flagSet.IntP("kernel-list-cache-ttl-secs", "", 0, "How long the directory listing (output of ls <dir>) should be cached in the kernel page cache. If a particular directory cache entry is kept by kernel for longer than TTL, then it will be sent for invalidation by gcsfuse on next opendir (comes in the start, as part of next listing) call. 0 means no caching. Use -1 to cache for lifetime (no ttl). Negative value other than -1 will throw error.")
 
 	flagSet.StringP("kernel-params-file", "", "", "File path used to communicate various kernel parameters to CSI Driver in GKE environment.")
@@ -1212,20 +1302,30 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.BoolP("normalize-created-names", "", false, "Converts the names of created files, directories and symlinks, and the new names of renamed ones, to Unicode normalization form C (NFC).")
+
 	flagSet.StringSliceP("o", "", []string{}, "Additional system-specific mount options. Multiple options can be passed as comma separated. For readonly, use --o ro")
 
 	flagSet.StringP("only-dir", "", "", "Mount only a specific directory within the bucket. See docs/mounting for more information")
 
+	flagSet.StringP("overlay-dir", "", "", "Keeps writes, and deletions as whiteouts, in this local directory instead of the bucket, for buckets that can only be read with the credentials at hand. Reads prefer the local copies. The directory can be committed to a writable bucket later with \"gcsfuse overlay commit\".")
+
+	flagSet.StringSliceP("path-rules", "", []string{}, "Ordered rules restricting what may be done to paths in the mount, as comma separated <action>=<glob> or <action>=regex:<regexp> entries, where the action is read-only, hidden, no-delete or no-overwrite. A rule that matches a directory applies to everything beneath it, and the first rule that applies to a path wins.")
+
 	flagSet.BoolP("precondition-errors", "", true, "Throw Stale NFS file handle error in case the object being synced or read from is modified by some other concurrent process. This helps prevent silent data loss or data corruption.")
 
 	if err := flagSet.MarkHidden("precondition-errors"); err != nil {
 		return err
 	}
 
+	flagSet.BoolP("preserve-posix", "", false, "Stores the mode, uid, gid and atime set through chmod, chown and utimes in object metadata, and reports them in place of the mount-wide defaults. Uses the same metadata keys as `gcloud storage cp --preserve-posix`.")
+
 	flagSet.StringP("profile", "", "", "The name of the profile to apply. e.g. aiml-training, aiml-serving, aiml-checkpointing")
 
 	flagSet.IntP("prometheus-port", "", 0, "Expose Prometheus metrics endpoint on this port and a path of /metrics.")
 
+	flagSet.IntP("quota-mb", "", 0, "Capacity of the mount in MiB, as reported by statfs to tools like df. 0 reports an effectively unlimited capacity.")
+
 	flagSet.IntP("read-block-size-mb", "", 16, "Specifies the block size for buffered reads. The value should be more than 0. This is used to read data in chunks from GCS.")
 
 	if err := flagSet.MarkHidden("read-block-size-mb"); err != nil {
@@ -1296,12 +1396,20 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("rename-dir-limit", "", 0, "Allow rename a directory containing fewer descendants than this limit.")
 
+	flagSet.IntP("rename-dir-parallelism", "", 16, "Number of objects moved at once by a journaled directory rename. Only used with --rename-journal-dir.")
+
+	flagSet.StringP("rename-journal-dir", "", "", "Enables journaled directory renames in flat buckets, keeping their local journal in this directory. An intent record is also written under the .gcsfuse_renames/ prefix of the bucket, so that a rename interrupted by a crash can be finished or undone later.")
+
+	flagSet.StringP("rename-journal-recovery", "", "resume", "What to do on mount with journaled directory renames that were interrupted: resume, rollback or none. Only used with --rename-journal-dir.")
+
 	flagSet.Float64P("retry-multiplier", "", 2, "Param for exponential backoff algorithm, which is used to increase waiting time b/w two consecutive retries.")
 
 	flagSet.BoolP("reuse-token-from-url", "", true, "If false, the token acquired from token-url is not reused.")
 
 	flagSet.IntP("sequential-read-size-mb", "", 200, "File chunk size to read from GCS in one call. Need to specify the value in MB. ChunkSize less than 1MB is not supported")
 
+	flagSet.BoolP("stable-inode-numbers", "", false, "Derives inode numbers from the bucket and object names instead of handing them out in order, so that files keep their inode numbers across remounts.")
+
 	flagSet.DurationP("stackdriver-export-interval", "", 0*time.Nanosecond, "Export metrics to stackdriver with this interval. A value of 0 indicates no exporting.")
 
 	if err := flagSet.MarkDeprecated("stackdriver-export-interval", "Please use --cloud-metrics-export-interval-secs instead."); err != nil {
@@ -1340,6 +1448,10 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.Float64P("trace-sampling-ratio", "", 0, "Specifies the fraction of traces to export, ranging from 0.0 to 1.0. Setting a value greater than 0 enables tracing; 1.0 exports all traces, while 0.0 (default) disables them. Use this to balance the number of traces exported with the tradeoff of higher perf and cost impact.")
 
+	flagSet.StringP("trash-prefix", "", "", "Turns on trash mode: unlinking a file or removing a directory moves the backing object under this prefix of the bucket, e.g. .trash/, instead of deleting it. Objects already under the prefix are deleted for good. Trashed objects are listed, restored and purged with gcsfuse trash.")
+
+	flagSet.DurationP("trash-retention", "", 0*time.Nanosecond, "Objects that were trashed longer ago than this are purged when the bucket is mounted. 0 keeps them until purged with gcsfuse trash purge. Only used with --trash-prefix.")
+
 	flagSet.IntP("type-cache-max-size-mb", "", 4, "Max size of type-cache maps which are maintained at a per-directory level. This flag has been deprecated in favour of a single unified flag stat-cache-max-size-mb.")
 
 	flagSet.DurationP("type-cache-ttl", "", 60000000000*time.Nanosecond, "Usage: How long to cache StatObject results and inode attributes. This flag has been deprecated (starting v2.0) in favor of metadata-cache-ttl-secs. For now, the minimum of stat-cache-ttl and type-cache-ttl values, rounded up to the next higher multiple of a second is used as ttl for both stat-cache and type-cache, when metadata-cache-ttl-secs is not set.")
@@ -1350,6 +1462,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 
 	flagSet.IntP("uid", "", -1, "UID owner of all inodes.")
 
+	flagSet.BoolP("unicode-normalized-lookup", "", false, "Looks names that match no entry of a directory exactly up again comparing their Unicode normalization form C (NFC), so that names written in composed and decomposed form match. A name that matches several entries this way, and none exactly, fails with ENOTUNIQ.")
+
+	flagSet.StringSliceP("union-layers", "", []string{}, "Overlays the mounted bucket on the listed layers, as comma separated <bucket>[/<prefix>] entries in decreasing order of priority. A name resolves to the highest layer that has it, and listings merge all layers. Writes go to the mounted bucket, which hides names deleted from the layers below with .wh.<name> whiteout objects.")
+
+	flagSet.DurationP("usage-refresh-interval", "", 0*time.Nanosecond, "How often to list the mounted bucket in the background to find the bytes and objects in use, which statfs reports. 0 disables the listing, so statfs reports nothing as used. Not used for dynamic mounts.")
+
 	flagSet.BoolP("visualize-workload-insight", "", false, "A flag to enable workload visualization. When enabled, workload insights will include visualizations to help understand access patterns. Insights will be written to the file specified by --workload-insight-output-file.")
 
 	if err := flagSet.MarkHidden("visualize-workload-insight"); err != nil {
@@ -1388,6 +1506,12 @@ func BuildFlagSet(flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	flagSet.IntP("write-quota-mb", "", 0, "Limits the MiB that may be written through the mount, in total or with --write-quota-per-top-level-dir in each top-level directory. Writes past the limit fail with EDQUOT. 0 means no limit.")
+
+	flagSet.IntP("write-quota-objects", "", 0, "Limits the files that may be created through the mount, in total or with --write-quota-per-top-level-dir in each top-level directory. Creating more fails with EDQUOT. 0 means no limit.")
+
+	flagSet.BoolP("write-quota-per-top-level-dir", "", false, "Applies --write-quota-mb and --write-quota-objects to each top-level directory of the mount separately.")
+
 	return nil
 }
 
@@ -1401,14 +1525,42 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("as-of", flagSet.Lookup("as-of")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-connection.billing-project", flagSet.Lookup("billing-project")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.bucket-mounts", flagSet.Lookup("bucket-mounts")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.bucket-quotas", flagSet.Lookup("bucket-quotas")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("cache-dir", flagSet.Lookup("cache-dir")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.case-insensitive-lookup", flagSet.Lookup("case-insensitive-lookup")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("metadata-cache.change-feed", flagSet.Lookup("change-feed")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("metadata-cache.change-listing-interval", flagSet.Lookup("change-listing-interval")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("metadata-cache.change-listing-prefixes", flagSet.Lookup("change-listing-prefixes")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-retries.chunk-retry-deadline-secs", flagSet.Lookup("chunk-retry-deadline-secs")); err != nil {
 		return err
 	}
@@ -1565,6 +1717,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-special-files", flagSet.Lookup("enable-special-files")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("enable-standard-symlinks", flagSet.Lookup("enable-standard-symlinks")); err != nil {
 		return err
 	}
@@ -1581,6 +1737,18 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.enable-versions-dirs", flagSet.Lookup("enable-versions-dirs")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.enforce-permissions", flagSet.Lookup("enforce-permissions")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.escape-unsupported-paths", flagSet.Lookup("escape-unsupported-paths")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.experimental-enable-dentry-cache", flagSet.Lookup("experimental-enable-dentry-cache")); err != nil {
 		return err
 	}
@@ -1597,6 +1765,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.experimental-enable-xattrs", flagSet.Lookup("experimental-enable-xattrs")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-connection.grpc-conn-pool-size", flagSet.Lookup("experimental-grpc-conn-pool-size")); err != nil {
 		return err
 	}
@@ -1713,6 +1885,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.inode-index-file", flagSet.Lookup("inode-index-file")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.kernel-list-cache-ttl-secs", flagSet.Lookup("kernel-list-cache-ttl-secs")); err != nil {
 		return err
 	}
@@ -1817,6 +1993,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.normalize-created-names", flagSet.Lookup("normalize-created-names")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.fuse-options", flagSet.Lookup("o")); err != nil {
 		return err
 	}
@@ -1825,10 +2005,22 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.overlay-dir", flagSet.Lookup("overlay-dir")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.path-rules", flagSet.Lookup("path-rules")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("file-system.precondition-errors", flagSet.Lookup("precondition-errors")); err != nil {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.preserve-posix", flagSet.Lookup("preserve-posix")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("profile", flagSet.Lookup("profile")); err != nil {
 		return err
 	}
@@ -1837,6 +2029,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.quota-mb", flagSet.Lookup("quota-mb")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("read.block-size-mb", flagSet.Lookup("read-block-size-mb")); err != nil {
 		return err
 	}
@@ -1889,6 +2085,18 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.rename-dir-parallelism", flagSet.Lookup("rename-dir-parallelism")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.rename-journal-dir", flagSet.Lookup("rename-journal-dir")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.rename-journal-recovery", flagSet.Lookup("rename-journal-recovery")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("gcs-retries.multiplier", flagSet.Lookup("retry-multiplier")); err != nil {
 		return err
 	}
@@ -1901,6 +2109,10 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.stable-inode-numbers", flagSet.Lookup("stable-inode-numbers")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metrics.stackdriver-export-interval", flagSet.Lookup("stackdriver-export-interval")); err != nil {
 		return err
 	}
@@ -1937,6 +2149,14 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.trash-prefix", flagSet.Lookup("trash-prefix")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.trash-retention", flagSet.Lookup("trash-retention")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("metadata-cache.type-cache-max-size-mb", flagSet.Lookup("type-cache-max-size-mb")); err != nil {
 		return err
 	}
@@ -1949,6 +2169,18 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.unicode-normalized-lookup", flagSet.Lookup("unicode-normalized-lookup")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.union-layers", flagSet.Lookup("union-layers")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.usage-refresh-interval", flagSet.Lookup("usage-refresh-interval")); err != nil {
+		return err
+	}
+
 	if err := v.BindPFlag("workload-insight.visualize", flagSet.Lookup("visualize-workload-insight")); err != nil {
 		return err
 	}
@@ -1977,5 +2209,17 @@ func BindFlags(v *viper.Viper, flagSet *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := v.BindPFlag("file-system.write-quota-mb", flagSet.Lookup("write-quota-mb")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.write-quota-objects", flagSet.Lookup("write-quota-objects")); err != nil {
+		return err
+	}
+
+	if err := v.BindPFlag("file-system.write-quota-per-top-level-dir", flagSet.Lookup("write-quota-per-top-level-dir")); err != nil {
+		return err
+	}
+
 	return nil
 }
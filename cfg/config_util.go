@@ -17,6 +17,7 @@ package cfg
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -73,6 +74,117 @@ func IsMetricsEnabled(c *MetricsConfig) bool {
 	return c.CloudMetricsExportIntervalSecs > 0 || c.PrometheusPort > 0
 }
 
+// parseBucketQuotas parses the <bucket>=<MiB> entries of bucket-quotas.
+func parseBucketQuotas(entries []string) (map[string]int64, error) {
+	quotas := make(map[string]int64, len(entries))
+	for _, e := range entries {
+		bucket, mb, ok := strings.Cut(e, "=")
+		if !ok || bucket == "" {
+			return nil, fmt.Errorf("invalid bucket quota %q; should be <bucket>=<MiB>", e)
+		}
+		quota, err := strconv.ParseInt(mb, 10, 64)
+		if err != nil || quota < 0 {
+			return nil, fmt.Errorf("invalid bucket quota %q; should be a non-negative number of MiB", e)
+		}
+		quotas[bucket] = quota
+	}
+
+	return quotas, nil
+}
+
+// QuotaMbForBucket returns the capacity in MiB that statfs reports for a mount
+// of the given bucket, or zero if it has none. An empty name stands for a
+// dynamic mount, for which only quota-mb applies.
+func QuotaMbForBucket(c *FileSystemConfig, bucketName string) (int64, error) {
+	quotas, err := parseBucketQuotas(c.BucketQuotas)
+	if err != nil {
+		return 0, err
+	}
+	if quota, ok := quotas[bucketName]; ok && bucketName != "" {
+		return quota, nil
+	}
+
+	return c.QuotaMb, nil
+}
+
+// BucketMount is an entry of bucket-mounts: a bucket, or a prefix of one,
+// mounted under a directory of the mount's root, with the settings that
+// differ for it from the rest of the mount.
+type BucketMount struct {
+	Dir    string
+	Bucket string
+	// The prefix of the bucket mounted, as with only-dir, if any.
+	OnlyDir        string
+	BillingProject string
+	ReadOnly       bool
+	// If set, these replace metadata-cache ttl-secs and negative-ttl-secs for
+	// the stat cache of the bucket.
+	StatCacheTTL         *time.Duration
+	NegativeStatCacheTTL *time.Duration
+}
+
+// ParseBucketMounts parses the <dir>=<bucket>[/<prefix>][;<option>]...
+// entries of bucket-mounts.
+func ParseBucketMounts(entries []string) ([]BucketMount, error) {
+	var mounts []BucketMount
+	dirs := make(map[string]bool, len(entries))
+	buckets := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		fields := strings.Split(e, ";")
+		dir, target, ok := strings.Cut(fields[0], "=")
+		if !ok || dir == "" || target == "" {
+			return nil, fmt.Errorf("invalid bucket mount %q; should be <dir>=<bucket>[/<prefix>]", e)
+		}
+		if dir == "." || dir == ".." || strings.Contains(dir, "/") {
+			return nil, fmt.Errorf("invalid directory %q of bucket mount %q", dir, e)
+		}
+		if dirs[dir] {
+			return nil, fmt.Errorf("directory %q is mounted more than once", dir)
+		}
+		dirs[dir] = true
+
+		m := BucketMount{Dir: dir}
+		m.Bucket, m.OnlyDir, _ = strings.Cut(target, "/")
+		if m.Bucket == "" {
+			return nil, fmt.Errorf("invalid bucket mount %q; the bucket is missing", e)
+		}
+		// Objects of a bucket mounted twice would be cached under the same names.
+		if buckets[m.Bucket] {
+			return nil, fmt.Errorf("bucket %q is mounted more than once", m.Bucket)
+		}
+		buckets[m.Bucket] = true
+
+		for _, option := range fields[1:] {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "billing-project":
+				m.BillingProject = value
+			case "read-only":
+				m.ReadOnly = true
+			case "ttl-secs", "negative-ttl-secs":
+				secs, err := strconv.ParseInt(value, 10, 64)
+				if err == nil {
+					err = isTTLInSecsValid(secs)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s of bucket mount %q: %w", key, e, err)
+				}
+				ttl := ListCacheTTLSecsToDuration(secs)
+				if key == "ttl-secs" {
+					m.StatCacheTTL = &ttl
+				} else {
+					m.NegativeStatCacheTTL = &ttl
+				}
+			default:
+				return nil, fmt.Errorf("unknown option %q of bucket mount %q", option, e)
+			}
+		}
+		mounts = append(mounts, m)
+	}
+
+	return mounts, nil
+}
+
 // IsGKEEnvironment returns true for /dev/fd/N mountpoints.
 func IsGKEEnvironment(mountPoint string) bool {
 	return strings.HasPrefix(mountPoint, "/dev/fd/")
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_DefaultMaxBackground(t *testing.T) {
@@ -237,6 +238,50 @@ func TestIsGKEEnvironment(t *testing.T) {
 	}
 }
 
+func TestQuotaMbForBucket(t *testing.T) {
+	c := &FileSystemConfig{QuotaMb: 100, BucketQuotas: []string{"a=10", "b=0"}}
+	var testCases = []struct {
+		testName   string
+		bucketName string
+		expected   int64
+	}{
+		{"bucket with quota", "a", 10},
+		{"bucket with zero quota", "b", 0},
+		{"bucket without quota", "c", 100},
+		{"dynamic mount", "", 100},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			quota, err := QuotaMbForBucket(c, tc.bucketName)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, quota)
+		})
+	}
+}
+
+func TestParseBucketMounts(t *testing.T) {
+	mounts, err := ParseBucketMounts([]string{
+		"raw=raw-bucket",
+		"models=ml-bucket/models/v2;billing-project=ml-proj;read-only;ttl-secs=-1;negative-ttl-secs=0",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, mounts, 2)
+	assert.Equal(t, BucketMount{Dir: "raw", Bucket: "raw-bucket"}, mounts[0])
+	m := mounts[1]
+	assert.Equal(t, "models", m.Dir)
+	assert.Equal(t, "ml-bucket", m.Bucket)
+	assert.Equal(t, "models/v2", m.OnlyDir)
+	assert.Equal(t, "ml-proj", m.BillingProject)
+	assert.True(t, m.ReadOnly)
+	require.NotNil(t, m.StatCacheTTL)
+	assert.Equal(t, maxSupportedTTL, *m.StatCacheTTL)
+	require.NotNil(t, m.NegativeStatCacheTTL)
+	assert.Equal(t, time.Duration(0), *m.NegativeStatCacheTTL)
+}
+
 func TestGetBucketType(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -48,6 +48,20 @@ const (
 	ExperimentalMetadataPrefetchOnMountAsynchronous = "async"
 )
 
+const (
+	// RenameJournalRecoveryResume finishes interrupted directory renames on mount.
+	RenameJournalRecoveryResume = "resume"
+	// RenameJournalRecoveryRollback undoes interrupted directory renames on mount.
+	RenameJournalRecoveryRollback = "rollback"
+	// RenameJournalRecoveryNone leaves interrupted directory renames for the
+	// rename-journal command.
+	RenameJournalRecoveryNone = "none"
+)
+
+// ChangeFeedSocketPrefix marks a change-feed that is a Unix socket to listen
+// on rather than a file.
+const ChangeFeedSocketPrefix = "unix:"
+
 const (
 	// maxSequentialReadSizeMb is the max value supported by sequential-read-size-mb flag.
 	maxSequentialReadSizeMB = 1024
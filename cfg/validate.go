@@ -21,7 +21,9 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/pathrules"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/util"
 	"github.com/spf13/viper"
 )
@@ -302,6 +304,152 @@ func isValidMRDConfig(mrdConfig *MrdConfig) error {
 	return nil
 }
 
+func isValidQuotaConfig(fsConfig *FileSystemConfig) error {
+	if fsConfig.QuotaMb < 0 {
+		return fmt.Errorf("invalid value of quota-mb: %d; should be >=0", fsConfig.QuotaMb)
+	}
+
+	if fsConfig.UsageRefreshInterval < 0 {
+		return fmt.Errorf("invalid value of usage-refresh-interval: %v; should be >=0", fsConfig.UsageRefreshInterval)
+	}
+
+	_, err := parseBucketQuotas(fsConfig.BucketQuotas)
+	return err
+}
+
+func isValidWriteQuotaConfig(fsConfig *FileSystemConfig) error {
+	if fsConfig.WriteQuotaMb < 0 {
+		return fmt.Errorf("invalid value of write-quota-mb: %d; should be >=0", fsConfig.WriteQuotaMb)
+	}
+
+	if fsConfig.WriteQuotaObjects < 0 {
+		return fmt.Errorf("invalid value of write-quota-objects: %d; should be >=0", fsConfig.WriteQuotaObjects)
+	}
+	return nil
+}
+
+func isValidEnforcePermissionsConfig(fsConfig *FileSystemConfig) error {
+	if fsConfig.EnforcePermissions && !fsConfig.PreservePosix {
+		return fmt.Errorf("enforce-permissions requires preserve-posix, as ownership and modes are kept in object metadata")
+	}
+	return nil
+}
+
+func isValidPathRulesConfig(fsConfig *FileSystemConfig) error {
+	_, err := pathrules.Parse(fsConfig.PathRules)
+	return err
+}
+
+func isValidChangeDetectionConfig(c *MetadataCacheConfig) error {
+	if c.ChangeListingInterval < 0 {
+		return fmt.Errorf("invalid value of change-listing-interval: %v; should be >=0", c.ChangeListingInterval)
+	}
+
+	if c.ChangeFeed == ChangeFeedSocketPrefix {
+		return fmt.Errorf("invalid value of change-feed: %q; the socket path is missing", c.ChangeFeed)
+	}
+	return nil
+}
+
+func isValidRenameJournalConfig(fsConfig *FileSystemConfig) error {
+	if fsConfig.RenameJournalDir == "" {
+		return nil
+	}
+
+	if fsConfig.RenameDirParallelism < 1 {
+		return fmt.Errorf("invalid value of rename-dir-parallelism: %d; should be >=1", fsConfig.RenameDirParallelism)
+	}
+
+	switch fsConfig.RenameJournalRecovery {
+	case RenameJournalRecoveryResume, RenameJournalRecoveryRollback, RenameJournalRecoveryNone:
+		return nil
+	default:
+		return fmt.Errorf("invalid value of rename-journal-recovery: %q; should be one of %q, %q or %q", fsConfig.RenameJournalRecovery,
+			RenameJournalRecoveryResume, RenameJournalRecoveryRollback, RenameJournalRecoveryNone)
+	}
+}
+
+func isValidStableInodeConfig(fsConfig *FileSystemConfig) error {
+	if fsConfig.InodeIndexFile != "" && !fsConfig.StableInodeNumbers {
+		return errors.New("inode-index-file can only be used with stable-inode-numbers")
+	}
+	return nil
+}
+
+func isValidTrashConfig(fsConfig *FileSystemConfig) error {
+	if fsConfig.TrashRetention < 0 {
+		return fmt.Errorf("invalid value of trash-retention: %v; should be >=0", fsConfig.TrashRetention)
+	}
+
+	if fsConfig.TrashPrefix == "" {
+		return nil
+	}
+
+	if !strings.HasSuffix(fsConfig.TrashPrefix, "/") || strings.HasPrefix(fsConfig.TrashPrefix, "/") {
+		return fmt.Errorf("invalid value of trash-prefix: %q; should be a relative directory name ending in /", fsConfig.TrashPrefix)
+	}
+	return nil
+}
+
+func isValidAsOf(config *Config) error {
+	if config.AsOf == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, config.AsOf)
+	if err != nil {
+		return fmt.Errorf("as-of must be an RFC 3339 time: %w", err)
+	}
+	// A view of the future would keep changing.
+	if t.After(time.Now()) {
+		return fmt.Errorf("as-of %s is in the future", config.AsOf)
+	}
+	return nil
+}
+
+func isValidBucketMountsConfig(config *Config) error {
+	if len(config.FileSystem.BucketMounts) == 0 {
+		return nil
+	}
+	if config.OnlyDir != "" {
+		return fmt.Errorf("only-dir can't be used with bucket-mounts; give each entry its prefix instead")
+	}
+	_, err := ParseBucketMounts(config.FileSystem.BucketMounts)
+	return err
+}
+
+func isValidUnionLayersConfig(config *Config) error {
+	if len(config.FileSystem.UnionLayers) == 0 {
+		return nil
+	}
+	// Writes go to the mounted bucket, which a point-in-time view can't take.
+	if config.AsOf != "" {
+		return fmt.Errorf("union-layers can't be used with as-of")
+	}
+	if len(config.FileSystem.BucketMounts) > 0 {
+		return fmt.Errorf("union-layers can't be used with bucket-mounts")
+	}
+	for _, l := range config.FileSystem.UnionLayers {
+		if bucket, _, _ := strings.Cut(l, "/"); bucket == "" {
+			return fmt.Errorf("invalid union layer %q; should be <bucket>[/<prefix>]", l)
+		}
+	}
+	return nil
+}
+
+func isValidOverlayDirConfig(config *Config) error {
+	if config.FileSystem.OverlayDir == "" {
+		return nil
+	}
+	if config.AsOf != "" {
+		return fmt.Errorf("overlay-dir can't be used with as-of")
+	}
+	// A directory holds the overlay of a single bucket.
+	if len(config.FileSystem.BucketMounts) > 0 {
+		return fmt.Errorf("overlay-dir can't be used with bucket-mounts")
+	}
+	return nil
+}
+
 func isValidOptimizationProfile(config *Config) error {
 	if config.Profile == "" {
 		return nil
@@ -389,6 +537,54 @@ func ValidateConfig(v *viper.Viper, config *Config) error {
 		return fmt.Errorf("error parsing mrd config: %w", err)
 	}
 
+	if err = isValidQuotaConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing quota config: %w", err)
+	}
+
+	if err = isValidWriteQuotaConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing write quota config: %w", err)
+	}
+
+	if err = isValidEnforcePermissionsConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing enforce permissions config: %w", err)
+	}
+
+	if err = isValidPathRulesConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing path rules config: %w", err)
+	}
+
+	if err = isValidChangeDetectionConfig(&config.MetadataCache); err != nil {
+		return fmt.Errorf("error parsing change detection config: %w", err)
+	}
+
+	if err = isValidRenameJournalConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing rename journal config: %w", err)
+	}
+
+	if err = isValidStableInodeConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing stable inode config: %w", err)
+	}
+
+	if err = isValidTrashConfig(&config.FileSystem); err != nil {
+		return fmt.Errorf("error parsing trash config: %w", err)
+	}
+
+	if err = isValidAsOf(config); err != nil {
+		return fmt.Errorf("error parsing as-of config: %w", err)
+	}
+
+	if err = isValidBucketMountsConfig(config); err != nil {
+		return fmt.Errorf("error parsing bucket mounts config: %w", err)
+	}
+
+	if err = isValidUnionLayersConfig(config); err != nil {
+		return fmt.Errorf("error parsing union layers config: %w", err)
+	}
+
+	if err = isValidOverlayDirConfig(config); err != nil {
+		return fmt.Errorf("error parsing overlay dir config: %w", err)
+	}
+
 	if err = isValidOptimizationProfile(config); err != nil {
 		return fmt.Errorf("error parsing optimize profile config: %w", err)
 	}
@@ -802,6 +802,181 @@ func Test_isValidMRDConfig(t *testing.T) {
 	}
 }
 
+func Test_isValidQuotaConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsConfig FileSystemConfig
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			fsConfig: FileSystemConfig{},
+			wantErr:  false,
+		},
+		{
+			name:     "valid_quotas",
+			fsConfig: FileSystemConfig{QuotaMb: 1024, BucketQuotas: []string{"a=10", "b=0"}, UsageRefreshInterval: time.Minute},
+			wantErr:  false,
+		},
+		{
+			name:     "negative_quota",
+			fsConfig: FileSystemConfig{QuotaMb: -1},
+			wantErr:  true,
+		},
+		{
+			name:     "negative_refresh_interval",
+			fsConfig: FileSystemConfig{UsageRefreshInterval: -time.Second},
+			wantErr:  true,
+		},
+		{
+			name:     "bucket_quota_without_size",
+			fsConfig: FileSystemConfig{BucketQuotas: []string{"a"}},
+			wantErr:  true,
+		},
+		{
+			name:     "negative_bucket_quota",
+			fsConfig: FileSystemConfig{BucketQuotas: []string{"a=-5"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidQuotaConfig(&tc.fsConfig)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidWriteQuotaConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsConfig FileSystemConfig
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			fsConfig: FileSystemConfig{},
+			wantErr:  false,
+		},
+		{
+			name:     "valid_quotas",
+			fsConfig: FileSystemConfig{WriteQuotaMb: 100, WriteQuotaObjects: 1000, WriteQuotaPerTopLevelDir: true},
+			wantErr:  false,
+		},
+		{
+			name:     "negative_bytes",
+			fsConfig: FileSystemConfig{WriteQuotaMb: -1},
+			wantErr:  true,
+		},
+		{
+			name:     "negative_objects",
+			fsConfig: FileSystemConfig{WriteQuotaObjects: -1},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidWriteQuotaConfig(&tc.fsConfig)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidEnforcePermissionsConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsConfig FileSystemConfig
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			fsConfig: FileSystemConfig{},
+			wantErr:  false,
+		},
+		{
+			name:     "with_preserve_posix",
+			fsConfig: FileSystemConfig{EnforcePermissions: true, PreservePosix: true},
+			wantErr:  false,
+		},
+		{
+			name:     "without_preserve_posix",
+			fsConfig: FileSystemConfig{EnforcePermissions: true},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidEnforcePermissionsConfig(&tc.fsConfig)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidPathRulesConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsConfig FileSystemConfig
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			fsConfig: FileSystemConfig{},
+			wantErr:  false,
+		},
+		{
+			name:     "valid_rules",
+			fsConfig: FileSystemConfig{PathRules: []string{"read-only=golden", "hidden=regex:\\.tmp$", "no-delete=*/logs"}},
+			wantErr:  false,
+		},
+		{
+			name:     "unknown_action",
+			fsConfig: FileSystemConfig{PathRules: []string{"append-only=golden"}},
+			wantErr:  true,
+		},
+		{
+			name:     "missing_pattern",
+			fsConfig: FileSystemConfig{PathRules: []string{"read-only"}},
+			wantErr:  true,
+		},
+		{
+			name:     "bad_glob",
+			fsConfig: FileSystemConfig{PathRules: []string{"hidden=[a"}},
+			wantErr:  true,
+		},
+		{
+			name:     "bad_regexp",
+			fsConfig: FileSystemConfig{PathRules: []string{"hidden=regex:(a"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidPathRulesConfig(&tc.fsConfig)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func Test_isValidBufferedReadConfig_ValidScenarios(t *testing.T) {
 	var testCases = []struct {
 		testName string
@@ -1255,3 +1430,365 @@ func TestValidateProfile(t *testing.T) {
 		})
 	}
 }
+
+func Test_isValidChangeDetectionConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  MetadataCacheConfig
+		wantErr bool
+	}{
+		{
+			name:    "disabled",
+			config:  MetadataCacheConfig{},
+			wantErr: false,
+		},
+		{
+			name:    "listing_and_socket_feed",
+			config:  MetadataCacheConfig{ChangeListingInterval: time.Minute, ChangeFeed: "unix:/run/gcsfuse/changes.sock"},
+			wantErr: false,
+		},
+		{
+			name:    "file_feed",
+			config:  MetadataCacheConfig{ChangeFeed: "/var/log/changes.jsonl"},
+			wantErr: false,
+		},
+		{
+			name:    "negative_interval",
+			config:  MetadataCacheConfig{ChangeListingInterval: -time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "socket_without_path",
+			config:  MetadataCacheConfig{ChangeFeed: "unix:"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidChangeDetectionConfig(&tc.config)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidRenameJournalConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsConfig FileSystemConfig
+		wantErr  bool
+	}{
+		{
+			name:     "disabled_ignores_settings",
+			fsConfig: FileSystemConfig{RenameDirParallelism: 0, RenameJournalRecovery: "bogus"},
+			wantErr:  false,
+		},
+		{
+			name:     "valid",
+			fsConfig: FileSystemConfig{RenameJournalDir: "/tmp/journal", RenameDirParallelism: 16, RenameJournalRecovery: RenameJournalRecoveryRollback},
+			wantErr:  false,
+		},
+		{
+			name:     "invalid_parallelism",
+			fsConfig: FileSystemConfig{RenameJournalDir: "/tmp/journal", RenameDirParallelism: 0, RenameJournalRecovery: RenameJournalRecoveryResume},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_recovery",
+			fsConfig: FileSystemConfig{RenameJournalDir: "/tmp/journal", RenameDirParallelism: 16, RenameJournalRecovery: "bogus"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidRenameJournalConfig(&tc.fsConfig)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidStableInodeConfig(t *testing.T) {
+	testCases := []struct {
+		name     string
+		fsConfig FileSystemConfig
+		wantErr  bool
+	}{
+		{
+			name:     "disabled",
+			fsConfig: FileSystemConfig{},
+			wantErr:  false,
+		},
+		{
+			name:     "with_index_file",
+			fsConfig: FileSystemConfig{StableInodeNumbers: true, InodeIndexFile: "/tmp/inodes"},
+			wantErr:  false,
+		},
+		{
+			name:     "index_file_without_stable_inode_numbers",
+			fsConfig: FileSystemConfig{InodeIndexFile: "/tmp/inodes"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidStableInodeConfig(&tc.fsConfig)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidAsOf(t *testing.T) {
+	testCases := []struct {
+		name    string
+		asOf    string
+		wantErr bool
+	}{
+		{
+			name:    "unset",
+			asOf:    "",
+			wantErr: false,
+		},
+		{
+			name:    "utc",
+			asOf:    "2024-01-02T15:04:05Z",
+			wantErr: false,
+		},
+		{
+			name:    "with_offset",
+			asOf:    "2024-01-02T15:04:05.5+02:00",
+			wantErr: false,
+		},
+		{
+			name:    "date_only",
+			asOf:    "2024-01-02",
+			wantErr: true,
+		},
+		{
+			name:    "future",
+			asOf:    time.Now().Add(time.Hour).Format(time.RFC3339),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidAsOf(&Config{AsOf: tc.asOf})
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidBucketMountsConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			config:  Config{},
+			wantErr: false,
+		},
+		{
+			name:    "valid_mounts",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a=bucket-a", "b=bucket-b/some/prefix;read-only;ttl-secs=60"}}},
+			wantErr: false,
+		},
+		{
+			name:    "missing_bucket",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a="}}},
+			wantErr: true,
+		},
+		{
+			name:    "nested_dir",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a/b=bucket"}}},
+			wantErr: true,
+		},
+		{
+			name:    "dir_mounted_twice",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a=bucket-a", "a=bucket-b"}}},
+			wantErr: true,
+		},
+		{
+			name:    "bucket_mounted_twice",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a=bucket/x", "b=bucket/y"}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown_option",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a=bucket;cache"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid_ttl",
+			config:  Config{FileSystem: FileSystemConfig{BucketMounts: []string{"a=bucket;ttl-secs=-2"}}},
+			wantErr: true,
+		},
+		{
+			name:    "with_only_dir",
+			config:  Config{OnlyDir: "x", FileSystem: FileSystemConfig{BucketMounts: []string{"a=bucket"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidBucketMountsConfig(&tc.config)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidUnionLayersConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			config:  Config{},
+			wantErr: false,
+		},
+		{
+			name:    "valid_layers",
+			config:  Config{FileSystem: FileSystemConfig{UnionLayers: []string{"overrides", "dataset/v2/"}}},
+			wantErr: false,
+		},
+		{
+			name:    "missing_bucket",
+			config:  Config{FileSystem: FileSystemConfig{UnionLayers: []string{"/prefix"}}},
+			wantErr: true,
+		},
+		{
+			name:    "with_as_of",
+			config:  Config{AsOf: "2026-01-02T15:04:05Z", FileSystem: FileSystemConfig{UnionLayers: []string{"dataset"}}},
+			wantErr: true,
+		},
+		{
+			name:    "with_bucket_mounts",
+			config:  Config{FileSystem: FileSystemConfig{UnionLayers: []string{"dataset"}, BucketMounts: []string{"a=bucket"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidUnionLayersConfig(&tc.config)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidOverlayDirConfig(t *testing.T) {
+	testCases := []struct {
+		name    string
+		config  Config
+		wantErr bool
+	}{
+		{
+			name:    "defaults",
+			config:  Config{},
+			wantErr: false,
+		},
+		{
+			name:    "overlay_dir",
+			config:  Config{FileSystem: FileSystemConfig{OverlayDir: "/var/lib/overlay", UnionLayers: []string{"dataset"}}},
+			wantErr: false,
+		},
+		{
+			name:    "with_as_of",
+			config:  Config{AsOf: "2026-01-02T15:04:05Z", FileSystem: FileSystemConfig{OverlayDir: "/var/lib/overlay"}},
+			wantErr: true,
+		},
+		{
+			name:    "with_bucket_mounts",
+			config:  Config{FileSystem: FileSystemConfig{OverlayDir: "/var/lib/overlay", BucketMounts: []string{"a=bucket"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidOverlayDirConfig(&tc.config)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_isValidTrashConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		prefix    string
+		retention time.Duration
+		wantErr   bool
+	}{
+		{
+			name:    "unset",
+			wantErr: false,
+		},
+		{
+			name:      "valid",
+			prefix:    ".trash/",
+			retention: 7 * 24 * time.Hour,
+			wantErr:   false,
+		},
+		{
+			name:    "no_trailing_slash",
+			prefix:  ".trash",
+			wantErr: true,
+		},
+		{
+			name:    "absolute",
+			prefix:  "/trash/",
+			wantErr: true,
+		},
+		{
+			name:      "negative_retention",
+			prefix:    ".trash/",
+			retention: -time.Hour,
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := isValidTrashConfig(&FileSystemConfig{TrashPrefix: tc.prefix, TrashRetention: tc.retention})
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
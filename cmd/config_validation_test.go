@@ -588,6 +588,12 @@ func TestValidateConfigFile_FileSystemConfigSuccessful(t *testing.T) {
 			configFile: "testdata/empty_file.yaml",
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:           []string{},
+					BucketQuotas:           []string{},
+					UnionLayers:            []string{},
+					PathRules:              []string{},
+					RenameDirParallelism:   16,
+					RenameJournalRecovery:  "resume",
 					DirMode:                0755,
 					DisableParallelDirops:  false,
 					FileMode:               0644,
@@ -609,6 +615,12 @@ func TestValidateConfigFile_FileSystemConfigSuccessful(t *testing.T) {
 			configFile: "testdata/file_system_config/unset_file_system_config.yaml",
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:           []string{},
+					BucketQuotas:           []string{},
+					UnionLayers:            []string{},
+					PathRules:              []string{},
+					RenameDirParallelism:   16,
+					RenameJournalRecovery:  "resume",
 					DirMode:                0755,
 					DisableParallelDirops:  false,
 					FileMode:               0644,
@@ -630,6 +642,12 @@ func TestValidateConfigFile_FileSystemConfigSuccessful(t *testing.T) {
 			configFile: "testdata/valid_config.yaml",
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:           []string{},
+					BucketQuotas:           []string{},
+					UnionLayers:            []string{},
+					PathRules:              []string{},
+					RenameDirParallelism:   16,
+					RenameJournalRecovery:  "resume",
 					DirMode:                0777,
 					DisableParallelDirops:  true,
 					FileMode:               0666,
@@ -776,6 +794,7 @@ func TestValidateConfigFile_MetadataCacheConfigSuccessful(t *testing.T) {
 			configFile: "testdata/empty_file.yaml",
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         20460,
 					DeprecatedStatCacheTtl:              60 * time.Second,
 					DeprecatedTypeCacheTtl:              60 * time.Second,
@@ -796,6 +815,7 @@ func TestValidateConfigFile_MetadataCacheConfigSuccessful(t *testing.T) {
 			configFile: "testdata/valid_config.yaml",
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         200,
 					DeprecatedStatCacheTtl:              30 * time.Second,
 					DeprecatedTypeCacheTtl:              20 * time.Second,
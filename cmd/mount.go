@@ -110,6 +110,13 @@ be interacting with the file system.`)
 		DummyIOCfg:                         newConfig.DummyIo,
 		IsTypeCacheDeprecated:              newConfig.EnableTypeCacheDeprecation,
 		ImplicitDir:                        newConfig.ImplicitDirs,
+		EscapeUnsupportedPaths:             newConfig.FileSystem.EscapeUnsupportedPaths,
+		UnionLayers:                        newConfig.FileSystem.UnionLayers,
+		OverlayDir:                         string(newConfig.FileSystem.OverlayDir),
+	}
+	if newConfig.AsOf != "" {
+		// Already validated.
+		bucketCfg.AsOf, _ = time.Parse(time.RFC3339, newConfig.AsOf)
 	}
 	bm := gcsx.NewBucketManager(bucketCfg, storageHandle)
 
@@ -135,7 +142,9 @@ be interacting with the file system.`)
 		MetricHandle:               metricHandle,
 		TraceHandle:                traceHandle,
 	}
-	if serverCfg.NewConfig.FileSystem.ExperimentalEnableDentryCache {
+	// Changes to the bucket made elsewhere are pushed to the kernel too.
+	changeDetection := newConfig.MetadataCache.ChangeListingInterval > 0 || newConfig.MetadataCache.ChangeFeed != ""
+	if serverCfg.NewConfig.FileSystem.ExperimentalEnableDentryCache || changeDetection {
 		serverCfg.Notifier = fuse.NewNotifier()
 	}
 
@@ -168,6 +177,11 @@ func getFuseMountConfig(fsName string, newConfig *cfg.Config) *fuse.MountConfig
 		mount.ParseOptions(parsedOptions, o)
 	}
 
+	if newConfig.AsOf != "" {
+		// Let the kernel turn writes away before they reach us.
+		parsedOptions["ro"] = ""
+	}
+
 	mountCfg := &fuse.MountConfig{
 		FSName:     fsName,
 		Subtype:    "gcsfuse",
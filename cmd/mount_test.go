@@ -148,3 +148,11 @@ func TestGetFuseMountConfig_EnableReaddirplus(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFuseMountConfig_AsOfMountsReadOnly(t *testing.T) {
+	newConfig := &cfg.Config{AsOf: "2026-01-02T15:04:05Z"}
+
+	fuseMountCfg := getFuseMountConfig("mybucket", newConfig)
+
+	assert.Contains(t, fuseMountCfg.Options, "ro")
+}
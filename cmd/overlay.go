@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/overlay"
+	"github.com/spf13/cobra"
+)
+
+// newOverlayCmd returns the command that commits the local overlay kept in
+// --overlay-dir to a bucket. It reads the parsed config out of mountInfo.
+func newOverlayCmd(mountInfo *mountInfo, open openBucketFn) *cobra.Command {
+	overlayCmd := &cobra.Command{
+		Use:   "overlay",
+		Short: "Manage the local overlay of a bucket",
+		Long: `With --overlay-dir set, writes to the mounted bucket are kept in that local
+directory instead, and deletions as whiteouts. These commands take the same
+--overlay-dir, and can't be run while a mount is using it.`,
+	}
+
+	commitCmd := &cobra.Command{
+		Use:   "commit bucket[/prefix]",
+		Short: "Write the objects in the overlay to a bucket, and apply its deletions",
+		Long: `Applies the deletions recorded in the overlay to the bucket, under the prefix
+if one is given, and then writes the objects in the overlay to it, emptying
+the overlay as it goes. A commit that fails part way can be run again to
+finish it. The prefix should be the --only-dir of the mounts that wrote to
+the overlay.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			config := mountInfo.config
+
+			dir := string(config.FileSystem.OverlayDir)
+			if dir == "" {
+				return errors.New("--overlay-dir must be set")
+			}
+
+			bucketName, prefix, _ := strings.Cut(args[0], "/")
+			bucket, err := open(ctx, config, bucketName)
+			if err != nil {
+				return fmt.Errorf("open bucket %q: %w", bucketName, err)
+			}
+			if prefix != "" {
+				if bucket, err = gcsx.NewPrefixBucket(path.Clean(prefix)+"/", bucket); err != nil {
+					return fmt.Errorf("NewPrefixBucket: %w", err)
+				}
+			}
+
+			local, err := gcsx.NewLocalBucket(dir, bucketName)
+			if err != nil {
+				return fmt.Errorf("open overlay: %w", err)
+			}
+
+			r, err := overlay.Commit(ctx, local, bucket)
+			fmt.Fprintf(overlayCmd.OutOrStdout(), "Wrote %d objects and deleted %d\n", r.Written, r.Deleted)
+			return err
+		},
+	}
+
+	overlayCmd.AddCommand(commitCmd)
+	return overlayCmd
+}
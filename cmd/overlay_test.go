@@ -0,0 +1,67 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeOverlayCmd(t *testing.T, overlayDir string, args ...string) (string, error) {
+	t.Helper()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	mountInfo := &mountInfo{config: &cfg.Config{FileSystem: cfg.FileSystemConfig{OverlayDir: cfg.ResolvedPath(overlayDir)}}}
+	c := newOverlayCmd(mountInfo, func(_ context.Context, _ *cfg.Config, bucketName string) (gcs.Bucket, error) {
+		assert.Equal(t, bucket.Name(), bucketName)
+		return bucket, nil
+	})
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetArgs(args)
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestOverlayCmd_Commit(t *testing.T) {
+	out, err := executeOverlayCmd(t, t.TempDir(), "commit", "test-bucket/some/prefix")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Wrote 0 objects and deleted 0\n", out)
+}
+
+func TestOverlayCmd_NoOverlayDir(t *testing.T) {
+	_, err := executeOverlayCmd(t, "", "commit", "test-bucket")
+
+	assert.ErrorContains(t, err, "--overlay-dir")
+}
+
+func TestOverlayCmd_OverlayInUse(t *testing.T) {
+	dir := t.TempDir()
+	_, err := gcsx.NewLocalBucket(dir, "test-bucket")
+	require.NoError(t, err)
+
+	_, err = executeOverlayCmd(t, dir, "commit", "test-bucket")
+
+	assert.ErrorContains(t, err, "already in use")
+}
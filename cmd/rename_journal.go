@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/clock"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/renamejournal"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/spf13/cobra"
+)
+
+// openBucketFn returns the bucket with the supplied name, set up according to
+// the supplied config.
+type openBucketFn func(ctx context.Context, config *cfg.Config, bucketName string) (gcs.Bucket, error)
+
+// openBucket connects to GCS the way a mount of the bucket would.
+func openBucket(ctx context.Context, config *cfg.Config, bucketName string) (gcs.Bucket, error) {
+	userAgent := getUserAgent(config.AppName, getConfigForUserAgent(config), logger.MountInstanceID(fsName(bucketName)))
+	storageHandle, err := createStorageHandle(config, userAgent, metrics.NewNoopMetrics(), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage handle using createStorageHandle: %w", err)
+	}
+
+	return storageHandle.BucketHandle(ctx, bucketName, config.GcsConnection.BillingProject, false)
+}
+
+// newRenameJournalCmd returns the command that lists, resumes and rolls back
+// the journaled directory renames of a bucket that haven't finished. It reads
+// the parsed config out of mountInfo.
+func newRenameJournalCmd(mountInfo *mountInfo, open openBucketFn) *cobra.Command {
+	journalCmd := &cobra.Command{
+		Use:   "rename-journal",
+		Short: "Manage the journaled directory renames of a bucket",
+		Long: `Directory renames in buckets without a hierarchical namespace are journaled
+when --rename-journal-dir is set. A rename that was interrupted is recovered
+by the next mount of the bucket, or by hand with these commands.`,
+	}
+
+	// withJournal runs f on the journal of the bucket named by the first arg.
+	withJournal := func(f func(ctx context.Context, j *renamejournal.Journal, args []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			config := mountInfo.config
+
+			localDir := string(config.FileSystem.RenameJournalDir)
+			if localDir == "" {
+				// The local journal only saves work when resuming, so a
+				// throwaway one does.
+				dir, err := os.MkdirTemp("", "gcsfuse-rename-journal")
+				if err != nil {
+					return fmt.Errorf("create local journal: %w", err)
+				}
+				defer os.RemoveAll(dir)
+				localDir = dir
+			}
+
+			bucket, err := open(ctx, config, args[0])
+			if err != nil {
+				return fmt.Errorf("open bucket %q: %w", args[0], err)
+			}
+
+			j := renamejournal.New(bucket, renamejournal.Config{
+				LocalDir:          localDir,
+				Parallelism:       int(max(config.FileSystem.RenameDirParallelism, 1)),
+				HeartbeatInterval: renamejournal.DefaultHeartbeatInterval,
+				StaleAfter:        renamejournal.DefaultStaleAfter,
+				Clock:             clock.RealClock{},
+			})
+			return f(ctx, j, args[1:])
+		}
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list bucket",
+		Short: "List the directory renames of a bucket that haven't finished",
+		Args:  cobra.ExactArgs(1),
+		RunE: withJournal(func(ctx context.Context, j *renamejournal.Journal, _ []string) error {
+			intents, err := j.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			for _, in := range intents {
+				state := "running"
+				if j.Stale(in) {
+					state = "interrupted"
+				}
+				fmt.Fprintf(journalCmd.OutOrStdout(), "%s\t%s\t%d objects\t%s -> %s\t%s\n",
+					in.ID, in.Started.Format(time.RFC3339), len(in.Objects), in.OldDir, in.NewDir, state)
+			}
+			return nil
+		}),
+	}
+
+	// recoverCmd recovers the renames with the IDs passed after the bucket, or
+	// every interrupted rename if there are none. Renames named by ID are
+	// recovered even if they still seem to be running.
+	recoverCmd := func(use, short string, rollback bool) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " bucket [id...]",
+			Short: short,
+			Args:  cobra.MinimumNArgs(1),
+			RunE: withJournal(func(ctx context.Context, j *renamejournal.Journal, ids []string) error {
+				if len(ids) == 0 {
+					return j.Recover(ctx, rollback)
+				}
+
+				intents, err := j.Pending(ctx)
+				if err != nil {
+					return err
+				}
+				byID := make(map[string]*renamejournal.Intent)
+				for _, in := range intents {
+					byID[in.ID] = in
+				}
+				for _, id := range ids {
+					in, ok := byID[id]
+					if !ok {
+						return fmt.Errorf("no unfinished rename with ID %q", id)
+					}
+					if rollback {
+						err = j.Rollback(ctx, in)
+					} else {
+						err = j.Resume(ctx, in)
+					}
+					if err != nil {
+						return fmt.Errorf("rename %s: %w", id, err)
+					}
+				}
+				return nil
+			}),
+		}
+	}
+
+	journalCmd.AddCommand(
+		listCmd,
+		recoverCmd("resume", "Finish interrupted directory renames", false),
+		recoverCmd("rollback", "Undo interrupted directory renames", true),
+	)
+	return journalCmd
+}
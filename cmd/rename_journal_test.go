@@ -0,0 +1,201 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/clock"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/renamejournal"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// beginInterruptedRename records a rename of foo/ to bar/ in a new bucket
+// long enough ago for it to count as interrupted, after moving foo/a.
+func beginInterruptedRename(ctx context.Context, t *testing.T) (gcs.Bucket, *renamejournal.Intent) {
+	t.Helper()
+	simulatedClock := clock.NewSimulatedClock(time.Now().Add(-time.Hour))
+	bucket := fake.NewFakeBucket(simulatedClock, "test-bucket", gcs.BucketType{})
+	require.NoError(t, storageutil.CreateObjects(ctx, bucket, map[string][]byte{
+		"foo/":  nil,
+		"foo/a": []byte("taco"),
+		"foo/b": []byte("burrito"),
+		"bar/":  nil,
+	}))
+	var objects []*gcs.MinObject
+	for _, name := range []string{"foo/a", "foo/b"} {
+		m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: name})
+		require.NoError(t, err)
+		objects = append(objects, m)
+	}
+	j := renamejournal.New(bucket, renamejournal.Config{LocalDir: t.TempDir(), Parallelism: 1, Clock: simulatedClock})
+	in, err := j.Begin(ctx, "foo/", "bar/", true, false, objects)
+	require.NoError(t, err)
+	require.NoError(t, j.Run(ctx, &renamejournal.Intent{ID: in.ID, OldDir: in.OldDir, NewDir: in.NewDir, Objects: in.Objects[:1]}))
+	return bucket, in
+}
+
+func executeRenameJournalCmd(t *testing.T, bucket gcs.Bucket, args ...string) (string, error) {
+	t.Helper()
+	mountInfo := &mountInfo{config: &cfg.Config{FileSystem: cfg.FileSystemConfig{RenameDirParallelism: 4}}}
+	c := newRenameJournalCmd(mountInfo, func(_ context.Context, _ *cfg.Config, bucketName string) (gcs.Bucket, error) {
+		assert.Equal(t, bucket.Name(), bucketName)
+		return bucket, nil
+	})
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetArgs(args)
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestRenameJournalCmd_List(t *testing.T) {
+	ctx := context.Background()
+	bucket, in := beginInterruptedRename(ctx, t)
+
+	out, err := executeRenameJournalCmd(t, bucket, "list", "test-bucket")
+
+	require.NoError(t, err)
+	assert.Contains(t, out, in.ID)
+	assert.Contains(t, out, "foo/ -> bar/")
+	assert.Contains(t, out, "interrupted")
+}
+
+func TestRenameJournalCmd_Recover(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    func(in *renamejournal.Intent) []string
+		present []string
+		missing []string
+	}{
+		{
+			name:    "resume all",
+			args:    func(*renamejournal.Intent) []string { return []string{"resume", "test-bucket"} },
+			present: []string{"bar/a", "bar/b"},
+			missing: []string{"foo/", "foo/a", "foo/b"},
+		},
+		{
+			name:    "rollback by ID",
+			args:    func(in *renamejournal.Intent) []string { return []string{"rollback", "test-bucket", in.ID} },
+			present: []string{"foo/", "foo/a", "foo/b", "bar/"},
+			missing: []string{"bar/a", "bar/b"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			bucket, in := beginInterruptedRename(ctx, t)
+
+			_, err := executeRenameJournalCmd(t, bucket, tc.args(in)...)
+
+			require.NoError(t, err)
+			for _, name := range tc.present {
+				_, err := storageutil.ReadObject(ctx, bucket, name)
+				assert.NoError(t, err, name)
+			}
+			for _, name := range tc.missing {
+				_, err := storageutil.ReadObject(ctx, bucket, name)
+				assert.Error(t, err, name)
+			}
+			out, err := executeRenameJournalCmd(t, bucket, "list", "test-bucket")
+			require.NoError(t, err)
+			assert.Empty(t, out)
+		})
+	}
+}
+
+func TestRenameJournalCmd_UnknownID(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := beginInterruptedRename(ctx, t)
+
+	_, err := executeRenameJournalCmd(t, bucket, "resume", "test-bucket", "no-such-id")
+
+	assert.ErrorContains(t, err, "no-such-id")
+}
+
+func TestCommandArgs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "mount",
+			args:     []string{"gcsfuse", "--implicit-dirs", "bucket", "/mnt"},
+			expected: []string{"gcsfuse", "--implicit-dirs", "bucket", "/mnt"},
+		},
+		{
+			name:     "subcommand",
+			args:     []string{"gcsfuse", "rename-journal", "list", "bucket"},
+			expected: []string{"rename-journal", "list", "bucket"},
+		},
+		{
+			name:     "subcommand after flags",
+			args:     []string{"gcsfuse", "-trash-prefix", ".trash/", "trash", "list", "bucket"},
+			expected: []string{"-trash-prefix", ".trash/", "trash", "list", "bucket"},
+		},
+		{
+			name:     "subcommand help",
+			args:     []string{"gcsfuse", "trash", "--help"},
+			expected: []string{"trash", "--help"},
+		},
+		{
+			name:     "bucket named after a subcommand",
+			args:     []string{"gcsfuse", "trash", "/mnt"},
+			expected: []string{"gcsfuse", "trash", "/mnt"},
+		},
+		{
+			name:     "bucket named after a subcommand after flags",
+			args:     []string{"gcsfuse", "--trash-prefix", ".trash/", "overlay", "commit"},
+			expected: []string{"gcsfuse", "--trash-prefix", ".trash/", "overlay", "commit"},
+		},
+		{
+			name:     "bucket named after a subcommand after --",
+			args:     []string{"gcsfuse", "--", "trash", "list", "/mnt"},
+			expected: []string{"gcsfuse", "--", "trash", "list", "/mnt"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newRootCmd(func(*mountInfo, string, string) error { return nil })
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, commandArgs(tc.args, c))
+		})
+	}
+}
+
+func TestMountBucketNamedAfterSubcommand(t *testing.T) {
+	var bucket string
+	c, err := newRootCmd(func(_ *mountInfo, b string, _ string) error {
+		bucket = b
+		return nil
+	})
+	require.NoError(t, err)
+	c.SetArgs(convertToPosixArgs(commandArgs([]string{"gcsfuse", "trash", "/mnt"}, c), c))
+
+	require.NoError(t, c.Execute())
+
+	assert.Equal(t, "trash", bucket)
+}
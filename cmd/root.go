@@ -157,6 +157,11 @@ of Cloud Storage FUSE, see https://cloud.google.com/storage/docs/gcs-fuse.`,
 			return m(&mountInfo, bucket, mountPoint)
 		},
 	}
+	// Bucket names can't be told apart from subcommands, so cobra's own
+	// completion command is left out.
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.AddCommand(newRenameJournalCmd(&mountInfo, openBucket), newTrashCmd(&mountInfo, openBucket), newOverlayCmd(&mountInfo, openBucket))
+
 	rootCmd.PersistentFlags().StringVar(&cfgFile, cfg.ConfigFileFlagName, "", "The path to the config file where all gcsfuse related config needs to be specified. "+
 		"Refer to 'https://cloud.google.com/storage/docs/gcsfuse-cli#config-file' for possible configurations.")
 
@@ -212,12 +217,57 @@ func convertToPosixArgs(args []string, c *cobra.Command) []string {
 	return pArgs
 }
 
+// commandArgs returns the args to execute the root command with. The mount
+// command is executed with all of the commandline args, program name included,
+// while a subcommand is executed with the args following the program name.
+// Mounting takes at most two positional args and every subcommand action at
+// least three, so with two or fewer the args are taken for a mount even if
+// the first names a subcommand, as in gcsfuse trash /mnt, unless help is
+// asked for.
+func commandArgs(args []string, c *cobra.Command) []string {
+	if len(args) > 1 {
+		pArgs := convertToPosixArgs(args[1:], c)
+		positional, help := positionalArgs(pArgs, c)
+		if sub, _, err := c.Find(pArgs); err == nil && sub != c && (len(positional) > 2 || help) {
+			return args[1:]
+		}
+	}
+	return args
+}
+
+// positionalArgs returns the args that are neither flags of c nor their
+// values, and whether help was asked for before any "--".
+func positionalArgs(args []string, c *cobra.Command) (positional []string, help bool) {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			return append(positional, args[i+1:]...), help
+		case a == "-h", a == "--help":
+			help = true
+		case strings.HasPrefix(a, "-"):
+			name, _, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+			f := c.PersistentFlags().Lookup(name)
+			if f == nil && len(name) == 1 {
+				f = c.PersistentFlags().ShorthandLookup(name)
+			}
+			if f != nil && !hasValue && f.NoOptDefVal == "" {
+				// Skip the flag's value.
+				i++
+			}
+		default:
+			positional = append(positional, a)
+		}
+	}
+	return positional, help
+}
+
 var ExecuteMountCmd = func() {
 	rootCmd, err := newRootCmd(Mount)
 	if err != nil {
 		log.Fatalf("Error occurred while creating the root command on gcsfuse/%s: %v", common.GetVersion(), err)
 	}
-	rootCmd.SetArgs(convertToPosixArgs(os.Args, rootCmd))
+	rootCmd.SetArgs(convertToPosixArgs(commandArgs(os.Args, rootCmd), rootCmd))
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Error occurred during command execution on gcsfuse/%s: %v", common.GetVersion(), err)
 	}
@@ -934,6 +934,12 @@ func TestArgsParsing_GCSConnectionFlagsThrowsError(t *testing.T) {
 
 func TestArgsParsing_FileSystemFlags(t *testing.T) {
 	expectedDefaultFileSystemConfig := cfg.FileSystemConfig{
+		BucketMounts:                  []string{},
+		BucketQuotas:                  []string{},
+		UnionLayers:                   []string{},
+		PathRules:                     []string{},
+		RenameDirParallelism:          16,
+		RenameJournalRecovery:         "resume",
 		DirMode:                       0755,
 		DisableParallelDirops:         false,
 		ExperimentalEnableDentryCache: false,
@@ -967,6 +973,12 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--dir-mode=0777", "--disable-parallel-dirops", "--experimental-enable-dentry-cache", "--experimental-enable-readdirplus", "--file-mode=0666", "--o", "ro", "--gid=7", "--ignore-interrupts=false", "--kernel-list-cache-ttl-secs=300", "--rename-dir-limit=10", "--temp-dir=~/temp", "--uid=8", "--precondition-errors=false", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:                  []string{},
+					BucketQuotas:                  []string{},
+					UnionLayers:                   []string{},
+					PathRules:                     []string{},
+					RenameDirParallelism:          16,
+					RenameJournalRecovery:         "resume",
 					DirMode:                       0777,
 					DisableParallelDirops:         true,
 					ExperimentalEnableDentryCache: true,
@@ -990,6 +1002,12 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--dir-mode=777", "--file-mode=666", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:                  []string{},
+					BucketQuotas:                  []string{},
+					UnionLayers:                   []string{},
+					PathRules:                     []string{},
+					RenameDirParallelism:          16,
+					RenameJournalRecovery:         "resume",
 					DirMode:                       0777,
 					DisableParallelDirops:         false,
 					ExperimentalEnableDentryCache: false,
@@ -1013,6 +1031,12 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--dir-mode=777", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=false", "--file-mode=666", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:                  []string{},
+					BucketQuotas:                  []string{},
+					UnionLayers:                   []string{},
+					PathRules:                     []string{},
+					RenameDirParallelism:          16,
+					RenameJournalRecovery:         "resume",
 					DirMode:                       0777,
 					DisableParallelDirops:         false,
 					ExperimentalEnableDentryCache: false,
@@ -1038,6 +1062,12 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--dir-mode=777", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=true", "--file-mode=666", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:                  []string{},
+					BucketQuotas:                  []string{},
+					UnionLayers:                   []string{},
+					PathRules:                     []string{},
+					RenameDirParallelism:          16,
+					RenameJournalRecovery:         "resume",
 					DirMode:                       0777,
 					DisableParallelDirops:         false,
 					ExperimentalEnableDentryCache: false,
@@ -1063,6 +1093,12 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--dir-mode=777", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=false", "--rename-dir-limit=15000", "--file-mode=666", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
+					BucketMounts:                  []string{},
+					BucketQuotas:                  []string{},
+					UnionLayers:                   []string{},
+					PathRules:                     []string{},
+					RenameDirParallelism:          16,
+					RenameJournalRecovery:         "resume",
 					DirMode:                       0777,
 					DisableParallelDirops:         false,
 					ExperimentalEnableDentryCache: false,
@@ -1143,15 +1179,21 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--experimental-o-direct", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  true,
-					PreconditionErrors:   true,
-					Uid:                  -1,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   true,
+					PreconditionErrors:    true,
+					Uid:                   -1,
 				},
 			},
 		},
@@ -1160,16 +1202,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--max-read-ahead-kb=1024", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					MaxReadAheadKb:       1024,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					MaxReadAheadKb:        1024,
 				},
 			},
 		},
@@ -1178,15 +1226,21 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					MaxReadAheadKb:       0,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					MaxReadAheadKb:        0,
 				},
 			},
 		},
@@ -1195,16 +1249,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--max-background=512", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					MaxBackground:        512,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					MaxBackground:         512,
 				},
 			},
 		},
@@ -1213,16 +1273,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					MaxBackground:        0,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					MaxBackground:         0,
 				},
 			},
 		},
@@ -1231,16 +1297,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--congestion-threshold=256", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					CongestionThreshold:  256,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					CongestionThreshold:   256,
 				},
 			},
 		},
@@ -1249,16 +1321,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					CongestionThreshold:  0,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					CongestionThreshold:   0,
 				},
 			},
 		},
@@ -1267,16 +1345,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--enable-kernel-reader", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					EnableKernelReader:   true,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					EnableKernelReader:    true,
 				},
 			},
 		},
@@ -1285,16 +1369,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					EnableKernelReader:   false,
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					EnableKernelReader:    false,
 				},
 			},
 		},
@@ -1303,16 +1393,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--kernel-params-file=/tmp/params", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					KernelParamsFile:     "/tmp/params",
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					KernelParamsFile:      "/tmp/params",
 				},
 			},
 		},
@@ -1321,16 +1417,22 @@ func TestArgsParsing_FileSystemFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--config-file", createTempConfigFile(t, "file-system:\n  kernel-params-file: /tmp/config_params"), "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				FileSystem: cfg.FileSystemConfig{
-					DirMode:              0755,
-					FileMode:             0644,
-					FuseOptions:          []string{},
-					Gid:                  -1,
-					IgnoreInterrupts:     true,
-					InactiveMrdCacheSize: 1000,
-					ExperimentalODirect:  false,
-					PreconditionErrors:   true,
-					Uid:                  -1,
-					KernelParamsFile:     "/tmp/config_params",
+					BucketMounts:          []string{},
+					BucketQuotas:          []string{},
+					UnionLayers:           []string{},
+					PathRules:             []string{},
+					RenameDirParallelism:  16,
+					RenameJournalRecovery: "resume",
+					DirMode:               0755,
+					FileMode:              0644,
+					FuseOptions:           []string{},
+					Gid:                   -1,
+					IgnoreInterrupts:      true,
+					InactiveMrdCacheSize:  1000,
+					ExperimentalODirect:   false,
+					PreconditionErrors:    true,
+					Uid:                   -1,
+					KernelParamsFile:      "/tmp/config_params",
 				},
 			},
 		},
@@ -1939,6 +2041,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--stat-cache-capacity=2000", "--stat-cache-ttl=2m", "--type-cache-ttl=1m20s", "--enable-nonexistent-type-cache", "--experimental-metadata-prefetch-on-mount=async", "--metadata-prefetch-max-workers=3", "--enable-metadata-prefetch=true", "--metadata-prefetch-entries-limit=500", "--stat-cache-max-size-mb=15", "--metadata-cache-ttl-secs=25", "--metadata-cache-negative-ttl-secs=20", "--type-cache-max-size-mb=30", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         2000,
 					DeprecatedStatCacheTtl:              2 * time.Minute,
 					DeprecatedTypeCacheTtl:              80 * time.Second,
@@ -1959,6 +2062,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 			args: []string{"gcsfuse", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         20460,
 					DeprecatedStatCacheTtl:              60 * time.Second,
 					DeprecatedTypeCacheTtl:              60 * time.Second,
@@ -1979,6 +2083,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=true", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         20460,
 					DeprecatedStatCacheTtl:              60 * time.Second,
 					DeprecatedTypeCacheTtl:              60 * time.Second,
@@ -1999,6 +2104,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=false", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         20460,
 					DeprecatedStatCacheTtl:              60 * time.Second,
 					DeprecatedTypeCacheTtl:              60 * time.Second,
@@ -2019,6 +2125,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=false", "--enable-metadata-prefetch", "--stat-cache-capacity=2000", "--stat-cache-ttl=2m", "--type-cache-ttl=1m20s", "--enable-nonexistent-type-cache", "--experimental-metadata-prefetch-on-mount=async", "--stat-cache-max-size-mb=15", "--metadata-cache-ttl-secs=25", "--metadata-cache-negative-ttl-secs=20", "--type-cache-max-size-mb=30", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         2000,
 					DeprecatedStatCacheTtl:              2 * time.Minute,
 					DeprecatedTypeCacheTtl:              80 * time.Second,
@@ -2039,6 +2146,7 @@ func TestArgsParsing_MetadataCacheFlags(t *testing.T) {
 			args: []string{"gcsfuse", "--machine-type=a3-highgpu-8g", "--disable-autoconfig=false", "--stat-cache-capacity=2000", "--stat-cache-ttl=2m", "--type-cache-ttl=4m", "--enable-nonexistent-type-cache", "--experimental-metadata-prefetch-on-mount=async", "--metadata-cache-negative-ttl-secs=20", "--type-cache-max-size-mb=30", "abc", "pqr"},
 			expectedConfig: &cfg.Config{
 				MetadataCache: cfg.MetadataCacheConfig{
+					ChangeListingPrefixes:               []string{},
 					DeprecatedStatCacheCapacity:         2000,
 					DeprecatedStatCacheTtl:              2 * time.Minute,
 					DeprecatedTypeCacheTtl:              4 * time.Minute,
@@ -0,0 +1,124 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/trash"
+	"github.com/jacobsa/timeutil"
+	"github.com/spf13/cobra"
+)
+
+// newTrashCmd returns the command that lists, restores and purges the objects
+// trashed in a bucket. It reads the parsed config, which must set the trash
+// prefix, out of mountInfo.
+func newTrashCmd(mountInfo *mountInfo, open openBucketFn) *cobra.Command {
+	trashCmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage the objects trashed in a bucket",
+		Long: `With --trash-prefix set, unlinking a file or removing a directory moves its
+backing object under the trash prefix instead of deleting it. These commands
+take the same --trash-prefix.`,
+	}
+
+	// withTrash runs f on the trash of the bucket named by the first arg.
+	withTrash := func(f func(ctx context.Context, t *trash.Trash, args []string) error) func(*cobra.Command, []string) error {
+		return func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			config := mountInfo.config
+
+			if config.FileSystem.TrashPrefix == "" {
+				return errors.New("--trash-prefix must be set")
+			}
+
+			bucket, err := open(ctx, config, args[0])
+			if err != nil {
+				return fmt.Errorf("open bucket %q: %w", args[0], err)
+			}
+
+			return f(ctx, trash.New(bucket, config.FileSystem.TrashPrefix, timeutil.RealClock()), args[1:])
+		}
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list bucket",
+		Short: "List the objects in the trash, the ones trashed first first",
+		Args:  cobra.ExactArgs(1),
+		RunE: withTrash(func(ctx context.Context, t *trash.Trash, _ []string) error {
+			entries, err := t.List(ctx)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				fmt.Fprintf(trashCmd.OutOrStdout(), "%s\t%d bytes\t%s\t%s\n",
+					e.Deleted.Format(time.RFC3339), e.Size, e.OriginalName, e.Name)
+			}
+			return nil
+		}),
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore bucket path...",
+		Short: "Restore the objects last trashed under each of the given paths",
+		Long: `Restores the object that was last trashed with each of the given names, and
+treating each name as a directory, the object last trashed with each name
+under it. Objects whose names have been taken since are left in the trash.`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: withTrash(func(ctx context.Context, t *trash.Trash, paths []string) error {
+			entries, err := t.List(ctx)
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, p := range paths {
+				picked := trash.Latest(entries, p)
+				if len(picked) == 0 {
+					errs = append(errs, fmt.Errorf("nothing trashed under %q", p))
+					continue
+				}
+				for _, e := range picked {
+					if err = t.Restore(ctx, e); err != nil {
+						errs = append(errs, err)
+					}
+				}
+			}
+			return errors.Join(errs...)
+		}),
+	}
+
+	var olderThan time.Duration
+	purgeCmd := &cobra.Command{
+		Use:   "purge bucket",
+		Short: "Delete the objects in the trash for good",
+		Args:  cobra.ExactArgs(1),
+		RunE: withTrash(func(ctx context.Context, t *trash.Trash, _ []string) error {
+			n, err := t.Purge(ctx, time.Now().Add(-olderThan))
+			fmt.Fprintf(trashCmd.OutOrStdout(), "Purged %d objects\n", n)
+			return err
+		}),
+	}
+	purgeCmd.Flags().DurationVar(&olderThan, "older-than", 0, "Purge only the objects trashed longer ago than this.")
+
+	trashCmd.AddCommand(listCmd, restoreCmd, purgeCmd)
+	return trashCmd
+}
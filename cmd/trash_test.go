@@ -0,0 +1,113 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/trash"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTrashedBucket returns a bucket in which foo/ and foo/a were trashed an
+// hour ago, and bar just now.
+func newTrashedBucket(ctx context.Context, t *testing.T) gcs.Bucket {
+	t.Helper()
+	clock := &timeutil.SimulatedClock{}
+	clock.SetTime(time.Now().Add(-time.Hour))
+	bucket := fake.NewFakeBucket(clock, "test-bucket", gcs.BucketType{})
+	require.NoError(t, storageutil.CreateObjects(ctx, bucket, map[string][]byte{
+		"foo/":  nil,
+		"foo/a": []byte("taco"),
+		"bar":   []byte("burrito"),
+	}))
+	tr := trash.New(bucket, ".trash/", clock)
+	require.NoError(t, tr.Move(ctx, "foo/a"))
+	require.NoError(t, tr.Move(ctx, "foo/"))
+	clock.SetTime(time.Now())
+	require.NoError(t, tr.Move(ctx, "bar"))
+	return bucket
+}
+
+func executeTrashCmd(t *testing.T, bucket gcs.Bucket, trashPrefix string, args ...string) (string, error) {
+	t.Helper()
+	mountInfo := &mountInfo{config: &cfg.Config{FileSystem: cfg.FileSystemConfig{TrashPrefix: trashPrefix}}}
+	c := newTrashCmd(mountInfo, func(_ context.Context, _ *cfg.Config, bucketName string) (gcs.Bucket, error) {
+		assert.Equal(t, bucket.Name(), bucketName)
+		return bucket, nil
+	})
+	var out bytes.Buffer
+	c.SetOut(&out)
+	c.SetArgs(args)
+	err := c.Execute()
+	return out.String(), err
+}
+
+func TestTrashCmd_List(t *testing.T) {
+	bucket := newTrashedBucket(context.Background(), t)
+
+	out, err := executeTrashCmd(t, bucket, ".trash/", "list", "test-bucket")
+
+	require.NoError(t, err)
+	assert.Regexp(t, `(?s)\tfoo/\t.*\tfoo/a\t.*\tbar\t`, out)
+}
+
+func TestTrashCmd_Restore(t *testing.T) {
+	ctx := context.Background()
+	bucket := newTrashedBucket(ctx, t)
+
+	_, err := executeTrashCmd(t, bucket, ".trash/", "restore", "test-bucket", "foo")
+
+	require.NoError(t, err)
+	contents, err := storageutil.ReadObject(ctx, bucket, "foo/a")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	_, err = storageutil.ReadObject(ctx, bucket, "foo/")
+	assert.NoError(t, err)
+	_, err = storageutil.ReadObject(ctx, bucket, "bar")
+	assert.Error(t, err)
+	_, err = executeTrashCmd(t, bucket, ".trash/", "restore", "test-bucket", "foo")
+	assert.ErrorContains(t, err, `nothing trashed under "foo"`)
+}
+
+func TestTrashCmd_Purge(t *testing.T) {
+	bucket := newTrashedBucket(context.Background(), t)
+
+	out, err := executeTrashCmd(t, bucket, ".trash/", "purge", "test-bucket", "--older-than=30m")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Purged 2 objects\n", out)
+	out, err = executeTrashCmd(t, bucket, ".trash/", "list", "test-bucket")
+	require.NoError(t, err)
+	assert.Contains(t, out, "\tbar\t")
+	assert.NotContains(t, out, "foo/")
+}
+
+func TestTrashCmd_NoPrefix(t *testing.T) {
+	bucket := newTrashedBucket(context.Background(), t)
+
+	_, err := executeTrashCmd(t, bucket, "", "list", "test-bucket")
+
+	assert.ErrorContains(t, err, "--trash-prefix")
+}
@@ -32,6 +32,7 @@ const (
 	ExplicitDirType Type = 3
 	ImplicitDirType Type = 4
 	NonexistentType Type = 5
+	SpecialFileType Type = 6
 )
 
 // TypeCache is a (name -> Type) map.
@@ -0,0 +1,247 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changedetect
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects the names reported to a detector.
+type recorder struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (r *recorder) invalidate(_ context.Context, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.names = append(r.names, name)
+}
+
+func (r *recorder) reported() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.names...)
+}
+
+func TestParseNotification(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte(`{"bucket":"some-bucket","name":"from/data"}`))
+	testCases := []struct {
+		name       string
+		line       string
+		wantBucket string
+		wantName   string
+		wantErr    bool
+	}{
+		{
+			name:       "object_resource",
+			line:       `{"kind":"storage#object","bucket":"some-bucket","name":"foo/bar","generation":"1"}`,
+			wantBucket: "some-bucket",
+			wantName:   "foo/bar",
+		},
+		{
+			name:       "message_attributes",
+			line:       `{"message":{"attributes":{"bucketId":"some-bucket","objectId":"foo","eventType":"OBJECT_DELETE"}},"subscription":"s"}`,
+			wantBucket: "some-bucket",
+			wantName:   "foo",
+		},
+		{
+			name:       "message_data_only",
+			line:       `{"message":{"data":"` + payload + `"}}`,
+			wantBucket: "some-bucket",
+			wantName:   "from/data",
+		},
+		{
+			name:    "no_name",
+			line:    `{"bucket":"some-bucket"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not_json",
+			line:    `foo`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bucket, name, err := parseNotification([]byte(tc.line))
+
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantBucket, bucket)
+			assert.Equal(t, tc.wantName, name)
+		})
+	}
+}
+
+func TestListingSource_ReportsDifferences(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	require.NoError(t, storageutil.CreateObjects(ctx, bucket, map[string][]byte{
+		"dir/a":   []byte("taco"),
+		"dir/b":   []byte("burrito"),
+		"dir/c":   []byte("enchilada"),
+		"other/d": []byte("tostada"),
+	}))
+	s := NewListingSource(bucket, []string{"dir/"}, time.Minute).(*listingSource)
+	before, err := s.list(ctx)
+	require.NoError(t, err)
+	_, err = storageutil.CreateObject(ctx, bucket, "dir/a", []byte("quesadilla"))
+	require.NoError(t, err)
+	require.NoError(t, bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "dir/b"}))
+	_, err = storageutil.CreateObject(ctx, bucket, "dir/e", []byte("churro"))
+	require.NoError(t, err)
+	_, err = storageutil.CreateObject(ctx, bucket, "other/d", []byte("nachos"))
+	require.NoError(t, err)
+	after, err := s.list(ctx)
+	require.NoError(t, err)
+	var changed []string
+
+	reportDifferences(before, after, func(name string) { changed = append(changed, name) })
+
+	assert.ElementsMatch(t, []string{"dir/a", "dir/b", "dir/e"}, changed)
+}
+
+func TestListingSource_Watch(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	_, err := storageutil.CreateObject(ctx, bucket, "a", []byte("taco"))
+	require.NoError(t, err)
+	r := &recorder{}
+	d := Start(r.invalidate, NewListingSource(bucket, nil, 10*time.Millisecond))
+	defer d.Stop()
+	// Give the first listing, which only takes stock, time to finish.
+	time.Sleep(50 * time.Millisecond)
+
+	_, err = storageutil.CreateObject(ctx, bucket, "b", []byte("burrito"))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(r.reported()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"b"}, r.reported())
+}
+
+func TestFeedSource_Socket(t *testing.T) {
+	// Socket paths are short, which rules out t.TempDir().
+	dir, err := os.MkdirTemp("", "feed")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "changes.sock")
+	r := &recorder{}
+	d := Start(r.invalidate, NewFeedSource("some-bucket", cfg.ChangeFeedSocketPrefix+socket))
+	defer d.Stop()
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		conn, err = net.Dial("unix", socket)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte(`{"bucket":"other-bucket","name":"foo"}` + "\n" +
+		`not json` + "\n" +
+		`{"bucket":"some-bucket","name":"bar"}` + "\n"))
+
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return len(r.reported()) > 0 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"bar"}, r.reported())
+}
+
+func TestFeedSource_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(`{"bucket":"some-bucket","name":"old"}`+"\n"), 0600))
+	r := &recorder{}
+	d := Start(r.invalidate, NewFeedSource("some-bucket", path))
+	defer d.Stop()
+	// Give the feed time to be opened before appending to it.
+	time.Sleep(50 * time.Millisecond)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// The second notification is written in two parts.
+	_, err = f.WriteString(`{"bucket":"some-bucket","name":"new"}` + "\n" + `{"bucket":"some-bucket",`)
+	require.NoError(t, err)
+	_, err = f.WriteString(`"name":"newer"}` + "\n")
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(r.reported()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"new", "newer"}, r.reported())
+}
+
+func TestFeedSource_FileReplaced(t *testing.T) {
+	testCases := []struct {
+		name    string
+		replace func(path string) error
+	}{
+		{
+			name: "rotated",
+			replace: func(path string) error {
+				return os.Rename(path, path+".1")
+			},
+		},
+		{
+			name: "truncated",
+			replace: func(path string) error {
+				return os.Truncate(path, 0)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "changes.jsonl")
+			require.NoError(t, os.WriteFile(path, []byte(`{"bucket":"some-bucket","name":"old"}`+"\n"), 0600))
+			r := &recorder{}
+			d := Start(r.invalidate, NewFeedSource("some-bucket", path))
+			defer d.Stop()
+			// Give the feed time to be opened before appending to it.
+			time.Sleep(50 * time.Millisecond)
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+			require.NoError(t, err)
+			_, err = f.WriteString(`{"bucket":"some-bucket","name":"new"}` + "\n")
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+			require.Eventually(t, func() bool { return len(r.reported()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+			require.NoError(t, tc.replace(path))
+			f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+			require.NoError(t, err)
+			_, err = f.WriteString(`{"bucket":"some-bucket","name":"a"}` + "\n")
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			assert.Eventually(t, func() bool { return len(r.reported()) == 2 }, 5*time.Second, 10*time.Millisecond)
+			assert.Equal(t, []string{"new", "a"}, r.reported())
+		})
+	}
+}
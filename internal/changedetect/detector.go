@@ -0,0 +1,74 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package changedetect finds out about objects that have been changed in a
+// bucket by somebody other than this mount, so that what the mount has cached
+// about them can be thrown away before it expires.
+package changedetect
+
+import (
+	"context"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// A Source reports the names of objects that may have been created, changed or
+// deleted.
+type Source interface {
+	// Watch calls changed with the name of every object found to have changed,
+	// until ctx is done. It returns early only if it can't watch at all.
+	Watch(ctx context.Context, changed func(name string)) error
+}
+
+// Detector watches a number of sources in the background.
+type Detector struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Start watches the supplied sources until Stop is called, and calls
+// invalidate with each name they report. invalidate is called for one name at
+// a time.
+func Start(invalidate func(ctx context.Context, name string), sources ...Source) *Detector {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Detector{cancel: cancel}
+
+	var mu sync.Mutex
+	changed := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ctx.Err() == nil {
+			invalidate(ctx, name)
+		}
+	}
+
+	for _, s := range sources {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			if err := s.Watch(ctx, changed); err != nil {
+				logger.Errorf("Watching for changes to the bucket: %v", err)
+			}
+		}()
+	}
+
+	return d
+}
+
+// Stop stops watching and waits for the sources to exit.
+func (d *Detector) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
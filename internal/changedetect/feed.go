@@ -0,0 +1,254 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changedetect
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+)
+
+// How often a file feed is checked for notifications appended to it.
+const feedPollInterval = time.Second
+
+// The fields of a Cloud Storage Pub/Sub notification that are needed here. A
+// notification is either the object resource that makes up the payload of a
+// message, or a message as pushed or pulled from a subscription, whose
+// attributes name the object.
+type notification struct {
+	Bucket string `json:"bucket"`
+	Name   string `json:"name"`
+
+	Message *struct {
+		Attributes map[string]string `json:"attributes"`
+		// The payload, which encoding/json decodes from base64.
+		Data []byte `json:"data"`
+	} `json:"message"`
+}
+
+// parseNotification returns the bucket and object named by a notification.
+func parseNotification(line []byte) (bucket, name string, err error) {
+	var n notification
+	if err = json.Unmarshal(line, &n); err != nil {
+		return "", "", err
+	}
+
+	if n.Message != nil {
+		bucket, name = n.Message.Attributes["bucketId"], n.Message.Attributes["objectId"]
+		if name == "" && len(n.Message.Data) > 0 {
+			return parseNotification(n.Message.Data)
+		}
+	} else {
+		bucket, name = n.Bucket, n.Name
+	}
+
+	if name == "" {
+		return "", "", errors.New("no object name")
+	}
+	return bucket, name, nil
+}
+
+type feedSource struct {
+	bucketName string
+	location   string
+}
+
+// NewFeedSource returns a source that reads Pub/Sub notifications of changes
+// to the named bucket, one JSON notification per line, from location. That is
+// either a file, which is followed as it grows, or cfg.ChangeFeedSocketPrefix
+// followed by the path of a Unix socket to listen on. Notifications about other
+// buckets are ignored.
+func NewFeedSource(bucketName, location string) Source {
+	return &feedSource{bucketName: bucketName, location: location}
+}
+
+func (s *feedSource) Watch(ctx context.Context, changed func(name string)) error {
+	if path, ok := strings.CutPrefix(s.location, cfg.ChangeFeedSocketPrefix); ok {
+		return s.listen(ctx, path, changed)
+	}
+	return s.follow(ctx, s.location, changed)
+}
+
+// listen accepts connections on a Unix socket and reads notifications from
+// each of them.
+func (s *feedSource) listen(ctx context.Context, path string, changed func(name string)) error {
+	// A socket left behind by an earlier mount would make Listen fail.
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path)
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listen on %q: %w", path, err)
+	}
+	defer os.Remove(path)
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept on %q: %w", path, err)
+		}
+
+		go func() {
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+			defer conn.Close()
+			s.read(conn, changed)
+		}()
+	}
+}
+
+// read reports the objects named by the notifications read from r until it
+// runs out.
+func (s *feedSource) read(r io.Reader, changed func(name string)) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		s.handleLine(scanner.Bytes(), changed)
+	}
+}
+
+func (s *feedSource) handleLine(line []byte, changed func(name string)) {
+	if len(strings.TrimSpace(string(line))) == 0 {
+		return
+	}
+
+	bucket, name, err := parseNotification(line)
+	if err != nil {
+		logger.Warnf("Ignoring change notification %q: %v", line, err)
+		return
+	}
+	if bucket != "" && bucket != s.bucketName {
+		return
+	}
+	changed(name)
+}
+
+// follow reads notifications from the file at path as they are appended to
+// it, starting at its end if it is a regular file. The file need not exist
+// yet. A file that is replaced at path or truncated, as when a log is rotated,
+// is reopened and read from its start.
+func (s *feedSource) follow(ctx context.Context, path string, changed func(name string)) error {
+	var f *os.File
+	defer func() {
+		if f != nil {
+			f.Close()
+		}
+	}()
+
+	var reader *bufio.Reader
+	var partial []byte
+	// Only what was in the file before the mount is skipped.
+	atEnd := true
+	for {
+		if f == nil {
+			var err error
+			if f, err = openFeedFile(path, atEnd); err != nil {
+				if !os.IsNotExist(err) {
+					return err
+				}
+				f = nil
+			} else {
+				reader = bufio.NewReader(f)
+			}
+			atEnd = false
+		}
+
+		for f != nil {
+			// Pipes are read through the poller, and would otherwise block
+			// until written to. Regular files don't take deadlines.
+			_ = f.SetReadDeadline(time.Now().Add(feedPollInterval))
+			line, err := reader.ReadBytes('\n')
+			partial = append(partial, line...)
+			if err != nil {
+				if err != io.EOF && !errors.Is(err, os.ErrDeadlineExceeded) {
+					logger.Warnf("Reading change feed %q: %v", path, err)
+				}
+				break
+			}
+			s.handleLine(partial, changed)
+			partial = partial[:0]
+		}
+
+		if f != nil && feedReplaced(f, path) {
+			f.Close()
+			f = nil
+			partial = partial[:0]
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(feedPollInterval):
+		}
+	}
+}
+
+// feedReplaced reports whether the regular file f, which has been read to its
+// end, is no longer the file at path or has been truncated since.
+func feedReplaced(f *os.File, path string) bool {
+	open, err := f.Stat()
+	if err != nil || !open.Mode().IsRegular() {
+		return false
+	}
+
+	current, err := os.Stat(path)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	if !os.SameFile(open, current) {
+		return true
+	}
+	offset, err := f.Seek(0, io.SeekCurrent)
+	return err == nil && current.Size() < offset
+}
+
+func openFeedFile(path string, atEnd bool) (*os.File, error) {
+	// Opening a pipe without a writer would block otherwise.
+	f, err := os.OpenFile(path, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err == nil && fi.Mode().IsRegular() && atEnd {
+		// What has already been written predates the mount.
+		_, err = f.Seek(0, io.SeekEnd)
+	}
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("open change feed %q: %w", path, err)
+	}
+	return f, nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package changedetect
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/sync/errgroup"
+)
+
+type objectVersion struct {
+	generation     int64
+	metaGeneration int64
+}
+
+type listingSource struct {
+	bucket   gcs.Bucket
+	prefixes []string
+	interval time.Duration
+}
+
+// NewListingSource returns a source that lists the objects under the supplied
+// prefixes, or the whole bucket if there are none, once per interval. The
+// objects that have been created, changed or deleted since the previous
+// listing are reported, so nothing is reported until the second listing.
+func NewListingSource(bucket gcs.Bucket, prefixes []string, interval time.Duration) Source {
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	return &listingSource{bucket: bucket, prefixes: prefixes, interval: interval}
+}
+
+func (s *listingSource) Watch(ctx context.Context, changed func(name string)) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// The objects found by the last listing that succeeded, or nil before the
+	// first one.
+	var seen map[string]objectVersion
+	for {
+		current, err := s.list(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				logger.Warnf("Listing bucket %q for changes: %v", s.bucket.Name(), err)
+			}
+		case seen == nil:
+			seen = current
+		default:
+			reportDifferences(seen, current, changed)
+			seen = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *listingSource) list(ctx context.Context) (map[string]objectVersion, error) {
+	group, ctx := errgroup.WithContext(ctx)
+
+	minObjects := make(chan *gcs.MinObject, 100)
+	group.Go(func() error {
+		defer close(minObjects)
+		for _, prefix := range s.prefixes {
+			if err := storageutil.ListPrefix(ctx, s.bucket, prefix, minObjects); err != nil {
+				return fmt.Errorf("ListPrefix(%q): %w", prefix, err)
+			}
+		}
+		return nil
+	})
+
+	current := make(map[string]objectVersion)
+	group.Go(func() error {
+		for o := range minObjects {
+			current[o.Name] = objectVersion{generation: o.Generation, metaGeneration: o.MetaGeneration}
+		}
+		return nil
+	})
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// reportDifferences reports the objects that are only in one of the listings,
+// or that differ between them.
+func reportDifferences(previous, current map[string]objectVersion, changed func(name string)) {
+	for name, v := range current {
+		if old, ok := previous[name]; !ok || old != v {
+			changed(name)
+		}
+	}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			changed(name)
+		}
+	}
+}
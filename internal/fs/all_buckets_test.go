@@ -15,15 +15,25 @@
 package fs_test
 
 import (
+	"context"
 	"io"
 	"os"
 	"path"
+	"testing"
+	"time"
 
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse/fuseops"
 	. "github.com/jacobsa/oglematchers"
 	. "github.com/jacobsa/ogletest"
 	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 ////////////////////////////////////////////////////////////////////////
@@ -147,3 +157,38 @@ func (t *AllBucketsTest) SingleBucket_ReadAfterWrite() {
 	AssertEq(nil, err)
 	ExpectEq("000o111ritoenchilada222", string(fileContents))
 }
+
+// Creating a file in the root of a dynamic mount fails before there is an
+// inode for it, which must leave the file system usable.
+func TestBaseDir_FailedCreateReleasesLock(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "bucket-0", gcs.BucketType{})
+	server, err := fs.NewFileSystem(ctx, &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Write: cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:  cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		MetricHandle:  metrics.NewNoopMetrics(),
+		TraceHandle:   tracing.NewNoopTracer(),
+		CacheClock:    &timeutil.SimulatedClock{},
+		BucketManager: &fakeBucketManager{buckets: map[string]gcs.Bucket{bucket.Name(): bucket}},
+		FilePerms:     0644,
+		DirPerms:      0755,
+	})
+	require.NoError(t, err)
+	t.Cleanup(server.Destroy)
+
+	err = server.CreateFile(ctx, &fuseops.CreateFileOp{Parent: fuseops.RootInodeID, Name: "foo", Mode: 0644})
+	require.Error(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.LookUpInode(ctx, &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: bucket.Name()})
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("LookUpInode blocked after a failed CreateFile")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBucketMountsTestFileSystem returns a dynamic mount of the buckets raw,
+// ml and scratch, of which raw is mounted under "data", the prefix models/v2
+// of ml read-only under "models", and scratch under its own name. The bucket
+// other can be set up, but isn't mounted.
+func newBucketMountsTestFileSystem(ctx context.Context, t *testing.T) (server fuseutil.FileSystem, buckets map[string]gcs.Bucket) {
+	t.Helper()
+	buckets = make(map[string]gcs.Bucket)
+	for _, name := range []string{"raw", "ml", "scratch", "other"} {
+		buckets[name] = fake.NewFakeBucket(timeutil.RealClock(), name, gcs.BucketType{})
+	}
+	createWithContents(ctx, t, buckets["raw"], "day1.csv", "taco")
+	createWithContents(ctx, t, buckets["ml"], "models/v2/weights", "burrito")
+	createWithContents(ctx, t, buckets["ml"], "models/v1/weights", "enchilada")
+
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Write: cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:  cfg.ReadConfig{GlobalMaxBlocks: 1},
+			FileSystem: cfg.FileSystemConfig{BucketMounts: []string{
+				"data=raw",
+				"models=ml/models/v2;read-only;billing-project=ml-proj",
+				"scratch=scratch",
+			}},
+		},
+		MetricHandle:  metrics.NewNoopMetrics(),
+		TraceHandle:   tracing.NewNoopTracer(),
+		CacheClock:    &timeutil.SimulatedClock{},
+		BucketManager: &fakeBucketManager{buckets: buckets},
+		FilePerms:     0644,
+		DirPerms:      0755,
+	}
+	server, err := fs.NewFileSystem(ctx, serverCfg)
+	require.NoError(t, err, "NewFileSystem")
+	t.Cleanup(server.Destroy)
+	return server, buckets
+}
+
+func TestBucketMounts_ListsOnlyMountedBuckets(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newBucketMountsTestFileSystem(ctx, t)
+
+	openDirOp := &fuseops.OpenDirOp{Inode: fuseops.RootInodeID}
+	require.NoError(t, server.OpenDir(ctx, openDirOp))
+	readDirOp := &fuseops.ReadDirOp{Inode: fuseops.RootInodeID, Handle: openDirOp.Handle, Dst: make([]byte, 4096)}
+	require.NoError(t, server.ReadDir(ctx, readDirOp))
+
+	var names []string
+	for _, e := range parseDirents(readDirOp.Dst[:readDirOp.BytesRead]) {
+		names = append(names, e.Name)
+		assert.Equal(t, fuseutil.DT_Directory, e.Type)
+	}
+	assert.Equal(t, []string{"data", "models", "scratch"}, names)
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "other"))
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "raw"))
+}
+
+func TestBucketMounts_MountsBucketsUnderTheirDirectories(t *testing.T) {
+	ctx := context.Background()
+	server, buckets := newBucketMountsTestFileSystem(ctx, t)
+	data := lookUp(ctx, t, server, fuseops.RootInodeID, "data")
+	models := lookUp(ctx, t, server, fuseops.RootInodeID, "models")
+
+	lookUp(ctx, t, server, data, "day1.csv")
+	lookUp(ctx, t, server, models, "weights")
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, models, "models"))
+	createOp := &fuseops.CreateFileOp{Parent: data, Name: "day2.csv", Mode: 0644}
+	require.NoError(t, server.CreateFile(ctx, createOp))
+	require.NoError(t, server.FlushFile(ctx, &fuseops.FlushFileOp{Inode: createOp.Entry.Child, Handle: createOp.Handle}))
+	_, err := storageutil.ReadObject(ctx, buckets["raw"], "day2.csv")
+	assert.NoError(t, err)
+}
+
+func TestBucketMounts_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newBucketMountsTestFileSystem(ctx, t)
+	models := lookUp(ctx, t, server, fuseops.RootInodeID, "models")
+
+	err := server.CreateFile(ctx, &fuseops.CreateFileOp{Parent: models, Name: "new", Mode: 0644})
+	assert.ErrorIs(t, err, syscall.EROFS)
+	err = server.Unlink(ctx, &fuseops.UnlinkOp{Parent: models, Name: "weights"})
+	assert.ErrorIs(t, err, syscall.EROFS)
+}
+
+func TestBucketMounts_RenameAcrossBucketsIsCrossDevice(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newBucketMountsTestFileSystem(ctx, t)
+	data := lookUp(ctx, t, server, fuseops.RootInodeID, "data")
+	scratch := lookUp(ctx, t, server, fuseops.RootInodeID, "scratch")
+	lookUp(ctx, t, server, data, "day1.csv")
+
+	err := server.Rename(ctx, &fuseops.RenameOp{OldParent: data, OldName: "day1.csv", NewParent: scratch, NewName: "day1.csv"})
+
+	assert.ErrorIs(t, err, syscall.EXDEV)
+}
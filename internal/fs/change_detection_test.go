@@ -0,0 +1,119 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/caching"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCachingTestFileSystem serves bucket with stat and type caches that don't
+// expire during the test, and reads notifications of changes to the bucket
+// from a socket, whose connection it returns.
+func newCachingTestFileSystem(ctx context.Context, t *testing.T, bucket gcs.Bucket) (fuseutil.FileSystem, net.Conn) {
+	t.Helper()
+	// Socket paths are short, which rules out t.TempDir().
+	dir, err := os.MkdirTemp("", "changes")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "feed.sock")
+
+	clock := timeutil.RealClock()
+	statCache := metadata.NewStatCacheBucketView(lru.NewCache(1<<20), "")
+	cached := caching.NewFastStatBucket(time.Hour, statCache, clock, bucket, time.Hour, false, false)
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Write:         cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:          cfg.ReadConfig{GlobalMaxBlocks: 1},
+			MetadataCache: cfg.MetadataCacheConfig{ChangeFeed: cfg.ChangeFeedSocketPrefix + socket},
+		},
+		MetricHandle:           metrics.NewNoopMetrics(),
+		TraceHandle:            tracing.NewNoopTracer(),
+		CacheClock:             clock,
+		BucketName:             bucket.Name(),
+		BucketManager:          &fakeBucketManager{buckets: map[string]gcs.Bucket{bucket.Name(): cached}},
+		FilePerms:              0644,
+		DirPerms:               0755,
+		ImplicitDirectories:    true,
+		InodeAttributeCacheTTL: time.Hour,
+		DirTypeCacheTTL:        time.Hour,
+	}
+	server, err := fs.NewFileSystem(ctx, serverCfg)
+	require.NoError(t, err, "NewFileSystem")
+	t.Cleanup(server.Destroy)
+
+	var conn net.Conn
+	require.Eventually(t, func() bool {
+		conn, err = net.Dial("unix", socket)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	t.Cleanup(func() { conn.Close() })
+	return server, conn
+}
+
+func TestChangeDetection_DeletedObject(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo", "taco")
+	server, feed := newCachingTestFileSystem(ctx, t, bucket)
+	lookUp(ctx, t, server, fuseops.RootInodeID, "foo")
+	require.NoError(t, bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "foo"}))
+	// The deletion goes unnoticed until it is notified.
+	require.NoError(t, lookUpErr(ctx, server, fuseops.RootInodeID, "foo"))
+
+	_, err := feed.Write([]byte(`{"bucket":"test-bucket","name":"foo"}` + "\n"))
+
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return lookUpErr(ctx, server, fuseops.RootInodeID, "foo") == fuse.ENOENT
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestChangeDetection_CreatedObject(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	server, feed := newCachingTestFileSystem(ctx, t, bucket)
+	require.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "foo"))
+	createWithContents(ctx, t, bucket, "foo", "taco")
+	// The negative stat cache entry hides the new object.
+	require.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "foo"))
+
+	_, err := feed.Write([]byte(`{"message":{"attributes":{"bucketId":"test-bucket","objectId":"foo"}}}` + "\n"))
+
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return lookUpErr(ctx, server, fuseops.RootInodeID, "foo") == nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
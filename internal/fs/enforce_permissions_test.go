@@ -0,0 +1,155 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	. "github.com/jacobsa/ogletest"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	alice = 1000
+	bob   = 1001
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+// EnforcePermissionsTest mounts with permissions enforced by the file system
+// rather than the kernel, and open to other users. Each test starts with a
+// directory "pub" with mode 0777, owned by root as the root of the mount is,
+// in which alice has created the file "notes".
+//
+// The tests act as other users by changing the file system uid of the thread
+// they run on, which needs them to be run as root.
+type EnforcePermissionsTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&EnforcePermissionsTest{})
+}
+
+func (t *EnforcePermissionsTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TtlSecs:            60,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			EnforcePermissions: true,
+			PreservePosix:      true,
+		},
+		EnableNewReader: true,
+	}
+	t.mountCfg.DisableDefaultPermissions = true
+	t.mountCfg.Options = map[string]string{"allow_other": ""}
+	t.fsTest.SetUpTestSuite()
+}
+
+func (t *EnforcePermissionsTest) SetUp(ti *TestInfo) {
+	if os.Geteuid() != 0 {
+		AddFailure("EnforcePermissionsTest must be run as root")
+		AbortTest()
+	}
+	AssertEq(nil, os.Mkdir(path.Join(mntDir, "pub"), 0777))
+	AssertEq(nil, os.Chmod(path.Join(mntDir, "pub"), 0777))
+	AssertEq(nil, t.as(alice, func() error { return createAndClose(path.Join(mntDir, "pub/notes"), 0644) }))
+}
+
+// as calls f with the file system uid of the calling thread set to uid, which
+// is the uid the kernel sends on with the ops f causes.
+func (t *EnforcePermissionsTest) as(uid int, f func() error) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	AssertEq(nil, unix.Setfsuid(uid))
+	defer func() { AssertEq(nil, unix.Setfsuid(0)) }()
+	return f()
+}
+
+// createAndClose creates the file at the supplied path with the supplied mode and
+// closes it.
+func createAndClose(p string, mode uint32) error {
+	fd, err := unix.Open(p, unix.O_CREAT|unix.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	return unix.Close(fd)
+}
+
+// openAndClose opens the file at the supplied path with the supplied flags and closes
+// it.
+func openAndClose(p string, flags int) error {
+	fd, err := unix.Open(p, flags, 0)
+	if err != nil {
+		return err
+	}
+	return unix.Close(fd)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *EnforcePermissionsTest) CreatorOwnsNewFiles() {
+	dir, err := os.Stat(path.Join(mntDir, "pub"))
+	AssertEq(nil, err)
+	file, err := os.Stat(path.Join(mntDir, "pub/notes"))
+	AssertEq(nil, err)
+
+	ExpectEq(0777, dir.Mode().Perm())
+	ExpectEq(0, dir.Sys().(*syscall.Stat_t).Uid)
+	ExpectEq(0644, file.Mode().Perm())
+	ExpectEq(alice, file.Sys().(*syscall.Stat_t).Uid)
+}
+
+func (t *EnforcePermissionsTest) Directories() {
+	ExpectEq(unix.EACCES, t.as(alice, func() error { return createAndClose(path.Join(mntDir, "a"), 0644) }))
+	ExpectEq(unix.EACCES, t.as(alice, func() error { return unix.Mkdir(path.Join(mntDir, "b"), 0755) }))
+	ExpectEq(unix.EACCES, t.as(alice, func() error { return unix.Rmdir(path.Join(mntDir, "pub")) }))
+	// Without the sticky bit, anyone who can write a directory can remove its
+	// entries.
+	ExpectEq(nil, t.as(bob, func() error { return unix.Unlink(path.Join(mntDir, "pub/notes")) }))
+}
+
+func (t *EnforcePermissionsTest) Files() {
+	notes := path.Join(mntDir, "pub/notes")
+
+	ExpectEq(nil, t.as(bob, func() error { return openAndClose(notes, unix.O_RDONLY) }))
+	ExpectEq(unix.EACCES, t.as(bob, func() error { return openAndClose(notes, unix.O_WRONLY) }))
+	ExpectEq(nil, t.as(alice, func() error { return openAndClose(notes, unix.O_WRONLY) }))
+	ExpectEq(unix.EACCES, t.as(bob, func() error { return unix.Truncate(notes, 0) }))
+}
+
+func (t *EnforcePermissionsTest) OnlyOwnerChangesMode() {
+	notes := path.Join(mntDir, "pub/notes")
+
+	ExpectEq(unix.EPERM, t.as(bob, func() error { return unix.Chmod(notes, 0600) }))
+	ExpectEq(unix.EPERM, t.as(alice, func() error { return unix.Chown(notes, bob, -1) }))
+	AssertEq(nil, t.as(alice, func() error { return unix.Chmod(notes, 0600) }))
+	fi, err := os.Stat(notes)
+	AssertEq(nil, err)
+	ExpectEq(0600, fi.Mode().Perm())
+	ExpectEq(unix.EACCES, t.as(bob, func() error { return openAndClose(notes, unix.O_RDONLY) }))
+}
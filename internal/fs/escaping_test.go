@@ -0,0 +1,58 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscaping_RenameAndUnlinkUnsupportedNames(t *testing.T) {
+	ctx := context.Background()
+	wrapped := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, wrapped, "a/", "")
+	createWithContents(ctx, t, wrapped, "a//", "")
+	createWithContents(ctx, t, wrapped, "a//b", "taco")
+	createWithContents(ctx, t, wrapped, "a/..", "burrito")
+	server := newTestFileSystem(ctx, t, gcsx.NewEscapingBucket(wrapped), cfg.FileSystemConfig{EscapeUnsupportedPaths: true})
+	a := lookUp(ctx, t, server, fuseops.RootInodeID, "a")
+	empty := lookUp(ctx, t, server, a, "%")
+	lookUp(ctx, t, server, empty, "b")
+	lookUp(ctx, t, server, a, "%2E%2E")
+
+	require.NoError(t, server.Rename(ctx, &fuseops.RenameOp{OldParent: empty, OldName: "b", NewParent: a, NewName: "b"}))
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: a, Name: "%2E%2E"}))
+
+	contents, err := storageutil.ReadObject(ctx, wrapped, "a/b")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	for _, name := range []string{"a//b", "a/.."} {
+		_, err = storageutil.ReadObject(ctx, wrapped, name)
+		var notFoundErr *gcs.NotFoundError
+		assert.ErrorAs(t, err, &notFoundErr, name)
+	}
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, a, "%41"))
+}
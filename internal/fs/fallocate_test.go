@@ -0,0 +1,85 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"path"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	. "github.com/jacobsa/ogletest"
+	"golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+type FallocateTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&FallocateTest{})
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *FallocateTest) ExtendsFile() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	fd, err := unix.Open(path.Join(mntDir, "foo"), unix.O_RDWR, 0)
+	AssertEq(nil, err)
+
+	err = unix.Fallocate(fd, 0, 2, 6)
+	AssertEq(nil, err)
+
+	var st unix.Stat_t
+	AssertEq(nil, unix.Fstat(fd, &st))
+	ExpectEq(8, st.Size)
+
+	AssertEq(nil, unix.Close(fd))
+	contents, err := storageutil.ReadObject(ctx, bucket, "foo")
+	AssertEq(nil, err)
+	ExpectEq("taco\x00\x00\x00\x00", string(contents))
+}
+
+func (t *FallocateTest) KeepSize() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	fd, err := unix.Open(path.Join(mntDir, "foo"), unix.O_RDWR, 0)
+	AssertEq(nil, err)
+	defer func() { AssertEq(nil, unix.Close(fd)) }()
+
+	err = unix.Fallocate(fd, unix.FALLOC_FL_KEEP_SIZE, 0, 1<<20)
+	AssertEq(nil, err)
+
+	var st unix.Stat_t
+	AssertEq(nil, unix.Fstat(fd, &st))
+	ExpectEq(4, st.Size)
+}
+
+func (t *FallocateTest) PunchHoleIsNotSupported() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	fd, err := unix.Open(path.Join(mntDir, "foo"), unix.O_RDWR, 0)
+	AssertEq(nil, err)
+	defer func() { AssertEq(nil, unix.Close(fd)) }()
+
+	err = unix.Fallocate(fd, unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, 0, 2)
+	ExpectEq(unix.EOPNOTSUPP, err)
+
+	contents, err := storageutil.ReadObject(ctx, bucket, "foo")
+	AssertEq(nil, err)
+	ExpectEq("taco", string(contents))
+}
@@ -25,8 +25,11 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -34,11 +37,13 @@ import (
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/inodeid"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/kernelparams"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/workerpool"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
 
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
@@ -46,19 +51,28 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/file/downloader"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/lru"
 	cacheutil "github.com/googlecloudplatform/gcsfuse/v3/internal/cache/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/changedetect"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/clock"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/contentcache"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/handle"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/mount"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/pathrules"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/perms"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/renamejournal"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/trash"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/util"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/writequota"
 	"github.com/jacobsa/fuse"
 	"github.com/jacobsa/fuse/fuseops"
 	"github.com/jacobsa/fuse/fuseutil"
 	"github.com/jacobsa/timeutil"
 	"github.com/spf13/viper"
+	"golang.org/x/sys/unix"
 )
 
 type ServerConfig struct {
@@ -202,6 +216,9 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		dirTypeCacheTTL:            serverCfg.DirTypeCacheTTL,
 		kernelListCacheTTL:         cfg.ListCacheTTLSecsToDuration(serverCfg.NewConfig.FileSystem.KernelListCacheTtlSecs),
 		renameDirLimit:             serverCfg.RenameDirLimit,
+		readOnly:                   serverCfg.NewConfig.AsOf != "" || mountedReadOnly(serverCfg.NewConfig.FileSystem.FuseOptions),
+		renameJournalDir:           string(serverCfg.NewConfig.FileSystem.RenameJournalDir),
+		renameDirParallelism:       int(serverCfg.NewConfig.FileSystem.RenameDirParallelism),
 		sequentialReadSizeMb:       serverCfg.SequentialReadSizeMb,
 		uid:                        serverCfg.Uid,
 		gid:                        serverCfg.Gid,
@@ -225,6 +242,73 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 		globalMaxWriteBlocksSem:    semaphore.NewWeighted(serverCfg.NewConfig.Write.GlobalMaxBlocks),
 		globalMaxReadBlocksSem:     semaphore.NewWeighted(serverCfg.NewConfig.Read.GlobalMaxBlocks),
 		globalMetadataPrefetchSem:  semaphore.NewWeighted(serverCfg.NewConfig.MetadataCache.MetadataPrefetchMaxWorkers),
+		tempDir:                    serverCfg.TempDir,
+		nameMatching: inode.NameMatching{
+			CaseInsensitive: serverCfg.NewConfig.FileSystem.CaseInsensitiveLookup,
+			Unicode:         serverCfg.NewConfig.FileSystem.UnicodeNormalizedLookup,
+		},
+	}
+	fs.backgroundCtx, fs.cancelBackground = context.WithCancel(context.Background())
+	fs.backgroundWork.Add(1)
+	go func() {
+		defer fs.backgroundWork.Done()
+		fs.sampleLocalFreeSpace(fs.backgroundCtx, localFreeSpaceSampleInterval)
+	}()
+
+	if serverCfg.NewConfig.FileSystem.StableInodeNumbers {
+		var err error
+		fs.inodeIndex, err = inodeid.New(string(serverCfg.NewConfig.FileSystem.InodeIndexFile))
+		if err != nil {
+			return nil, fmt.Errorf("inodeid.New: %w", err)
+		}
+	}
+
+	// Dynamic mounts have no single bucket, so only the mount-wide quota applies.
+	quotaBucket := serverCfg.BucketName
+	if quotaBucket == "_" {
+		quotaBucket = ""
+	}
+	quotaMb, err := cfg.QuotaMbForBucket(&serverCfg.NewConfig.FileSystem, quotaBucket)
+	if err != nil {
+		return nil, fmt.Errorf("QuotaMbForBucket: %w", err)
+	}
+	fs.quotaMb = quotaMb
+
+	bucketMounts, err := cfg.ParseBucketMounts(serverCfg.NewConfig.FileSystem.BucketMounts)
+	if err != nil {
+		return nil, fmt.Errorf("ParseBucketMounts: %w", err)
+	}
+	// Buckets mounted read-only are protected by rules ahead of, and so taking
+	// precedence over, the configured ones, as are the rename journal objects
+	// of each bucket.
+	isDynamicMount := serverCfg.BucketName == "" || serverCfg.BucketName == "_"
+	var rules []string
+	if fs.renameJournalDir != "" {
+		// The root directories of the buckets, relative to the root of the mount.
+		var roots string
+		if len(bucketMounts) > 0 {
+			var dirs []string
+			for _, m := range bucketMounts {
+				dirs = append(dirs, regexp.QuoteMeta(m.Dir))
+			}
+			roots = "(?:" + strings.Join(dirs, "|") + ")/"
+		} else if isDynamicMount {
+			roots = "[^/]+/"
+		}
+		rules = append(rules, fmt.Sprintf("%s=regex:^%s%s$", pathrules.Hidden, roots, regexp.QuoteMeta(strings.TrimSuffix(renamejournal.ObjectPrefix, "/"))))
+	}
+	for _, m := range bucketMounts {
+		if m.ReadOnly {
+			rules = append(rules, fmt.Sprintf("%s=regex:^%s$", pathrules.ReadOnly, regexp.QuoteMeta(m.Dir)))
+		}
+	}
+	fs.pathRules, err = pathrules.Parse(append(rules, serverCfg.NewConfig.FileSystem.PathRules...))
+	if err != nil {
+		return nil, fmt.Errorf("pathrules.Parse: %w", err)
+	}
+
+	if fsCfg := serverCfg.NewConfig.FileSystem; fsCfg.WriteQuotaMb > 0 || fsCfg.WriteQuotaObjects > 0 {
+		fs.writeQuota = writequota.New(fsCfg.WriteQuotaMb<<20, fsCfg.WriteQuotaObjects, fsCfg.WriteQuotaPerTopLevelDir, fs.metricHandle)
 	}
 
 	// Initialize MRD cache if enabled
@@ -247,7 +331,24 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 
 	// Set up root bucket
 	var root inode.DirInode
-	if serverCfg.BucketName == "" || serverCfg.BucketName == "_" {
+	var changeSources []changedetect.Source
+	if isDynamicMount && len(serverCfg.NewConfig.FileSystem.UnionLayers) > 0 {
+		return nil, fmt.Errorf("union-layers requires mounting a bucket to write to")
+	}
+	if isDynamicMount && serverCfg.NewConfig.FileSystem.OverlayDir != "" {
+		return nil, fmt.Errorf("overlay-dir requires mounting a single bucket")
+	}
+	if len(bucketMounts) > 0 {
+		if !isDynamicMount {
+			return nil, fmt.Errorf("bucket-mounts can't be used when mounting bucket %q", serverCfg.BucketName)
+		}
+		logger.Infof("Set up root directory for %d mounted buckets", len(bucketMounts))
+		fs.bucketMountDirs = make(map[string]string, len(bucketMounts))
+		for _, m := range bucketMounts {
+			fs.bucketMountDirs[m.Bucket] = m.Dir
+		}
+		root = makeRootForBucketMounts(fs, bucketMounts)
+	} else if isDynamicMount {
 		logger.Info("Set up root directory for all accessible buckets")
 		root = makeRootForAllBuckets(fs)
 	} else {
@@ -291,7 +392,35 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 			kernelParams.SetMaxBackgroundRequests(int(serverCfg.NewConfig.FileSystem.MaxBackground))
 			kernelParams.ApplyGKE(string(serverCfg.NewConfig.FileSystem.KernelParamsFile))
 		}
+		if interval := serverCfg.NewConfig.FileSystem.UsageRefreshInterval; interval > 0 {
+			fs.usageTracker = gcsx.NewUsageTracker(syncerBucket, renamejournal.ObjectPrefix, interval)
+		}
+		// A read-only mount can't finish or undo a rename, so it leaves them to
+		// a mount that can.
+		if fs.renameJournalDir != "" && serverCfg.NewConfig.FileSystem.RenameJournalRecovery != cfg.RenameJournalRecoveryNone && !fs.readOnly {
+			rollback := serverCfg.NewConfig.FileSystem.RenameJournalRecovery == cfg.RenameJournalRecoveryRollback
+			// A rename that can't be recovered now is left for a later mount or
+			// for gcsfuse rename-journal, rather than failing the mount.
+			journal := fs.renameJournal(syncerBucket)
+			fs.backgroundWork.Add(1)
+			go func() {
+				defer fs.backgroundWork.Done()
+				if err := journal.Recover(fs.backgroundCtx, rollback); err != nil && fs.backgroundCtx.Err() == nil {
+					logger.Errorf("Recovering interrupted directory renames: %v", err)
+				}
+			}()
+		}
+		if fsCfg := serverCfg.NewConfig.FileSystem; fsCfg.TrashPrefix != "" && fsCfg.TrashRetention > 0 && !fs.readOnly {
+			t := trash.New(syncerBucket, fsCfg.TrashPrefix, mtimeClock)
+			before := mtimeClock.Now().Add(-fsCfg.TrashRetention)
+			fs.backgroundWork.Add(1)
+			go func() {
+				defer fs.backgroundWork.Done()
+				purgeTrash(fs.backgroundCtx, t, before)
+			}()
+		}
 		root = makeRootForBucket(fs, syncerBucket)
+		changeSources = newChangeSources(syncerBucket, &serverCfg.NewConfig.MetadataCache)
 	}
 	root.Lock()
 	root.IncrementLookupCount()
@@ -302,9 +431,50 @@ func NewFileSystem(ctx context.Context, serverCfg *ServerConfig) (fuseutil.FileS
 
 	// Set up invariant checking.
 	fs.mu = locker.New("FS", fs.checkInvariants)
+
+	if len(changeSources) > 0 {
+		fs.changeDetector = changedetect.Start(fs.invalidateChangedObject, changeSources...)
+	}
 	return fs, nil
 }
 
+// mountedReadOnly reports whether the supplied fuse options include ro, with
+// which the kernel turns modifications away before they reach us.
+func mountedReadOnly(fuseOptions []string) bool {
+	opts := make(map[string]string)
+	for _, o := range fuseOptions {
+		mount.ParseOptions(opts, o)
+	}
+	_, ok := opts["ro"]
+	return ok
+}
+
+// purgeTrash purges what was trashed before the supplied time. It is run in
+// the background so that a large trash doesn't hold up the mount, and gives
+// up once ctx is cancelled.
+func purgeTrash(ctx context.Context, t *trash.Trash, before time.Time) {
+	n, err := t.Purge(ctx, before)
+	if err != nil && ctx.Err() == nil {
+		logger.Errorf("Purging the trash: %v", err)
+	}
+	if n > 0 {
+		logger.Infof("Purged %d objects trashed before %v", n, before)
+	}
+}
+
+// newChangeSources returns the sources of changes to the mounted bucket that
+// are configured.
+func newChangeSources(bucket gcs.Bucket, c *cfg.MetadataCacheConfig) []changedetect.Source {
+	var sources []changedetect.Source
+	if c.ChangeListingInterval > 0 {
+		sources = append(sources, changedetect.NewListingSource(bucket, c.ChangeListingPrefixes, c.ChangeListingInterval))
+	}
+	if c.ChangeFeed != "" {
+		sources = append(sources, changedetect.NewFeedSource(bucket.Name(), c.ChangeFeed))
+	}
+	return sources
+}
+
 // createFileCacheHandler either returns a regular file cache handler with an in-memory LRU cache, or
 // a shared chunk cache manager that allows multiple gcsfuse instances to share the same cache directory
 // on disk, based on the configuration.
@@ -435,6 +605,26 @@ func makeRootForBucket(
 	)
 }
 
+func makeRootForBucketMounts(fs *fileSystem, mounts []cfg.BucketMount) inode.DirInode {
+	return inode.NewBucketMountsDirInode(
+		fuseops.RootInodeID,
+		fuseops.InodeAttributes{
+			Uid:  fs.uid,
+			Gid:  fs.gid,
+			Mode: fs.dirMode,
+
+			// We guarantee only that directory times be "reasonable".
+			Atime: fs.mtimeClock.Now(),
+			Ctime: fs.mtimeClock.Now(),
+			Mtime: fs.mtimeClock.Now(),
+		},
+		fs.bucketManager,
+		mounts,
+		fs.metricHandle,
+		fs.newConfig.EnableTypeCacheDeprecation,
+	)
+}
+
 func makeRootForAllBuckets(fs *fileSystem) inode.DirInode {
 	return inode.NewBaseDirInode(
 		fuseops.RootInodeID,
@@ -511,6 +701,19 @@ type fileSystem struct {
 	renameDirLimit       int64
 	sequentialReadSizeMb int32
 
+	// Set when mounted as of a point in time or with the ro option, in which
+	// case every modification fails with EROFS.
+	readOnly bool
+
+	// How names that match no child exactly are matched in Unlink, RmDir and
+	// Rename. Directory inodes match them the same way on lookup.
+	nameMatching inode.NameMatching
+
+	// Directory renames in flat buckets are journaled under this directory, if
+	// set, and move renameDirParallelism objects at once.
+	renameJournalDir     string
+	renameDirParallelism int
+
 	// The user and group owning everything in the file system.
 	uid uint32
 	gid uint32
@@ -534,10 +737,17 @@ type fileSystem struct {
 	// GUARDED_BY(mu)
 	nextInodeID fuseops.InodeID
 
+	// Hands out inode IDs derived from object names in place of nextInodeID,
+	// when stable inode numbers are enabled. Nil otherwise.
+	//
+	// GUARDED_BY(mu)
+	inodeIndex *inodeid.Index
+
 	// The collection of live inodes, keyed by inode ID. No ID less than
 	// fuseops.RootInodeID is ever used.
 	//
-	// INVARIANT: For all keys k, fuseops.RootInodeID <= k < nextInodeID
+	// INVARIANT: For all keys k, fuseops.RootInodeID <= k
+	// INVARIANT: For all keys k, k < nextInodeID unless inodeIndex is set
 	// INVARIANT: For all keys k, inodes[k].ID() == k
 	// INVARIANT: inodes[fuseops.RootInodeID] is missing or of type inode.DirInode
 	// INVARIANT: For all v, if v.Name().IsDir() then v is inode.DirInode
@@ -676,6 +886,45 @@ type fileSystem struct {
 
 	// mrdCache manages the cache of inactive MultiRangeDownloaders.
 	mrdCache *lru.Cache
+
+	// The directory where writes are staged, or empty for the system default.
+	tempDir string
+
+	// The capacity in MiB reported by statfs, or zero to report an effectively
+	// unlimited capacity.
+	quotaMb int64
+
+	// Limits the bytes written and files created through the mount. Nil when
+	// there are no write quotas.
+	writeQuota *writequota.Quota
+
+	// Restricts what may be done to paths in the mount. Nil when there are no
+	// path rules.
+	pathRules *pathrules.Rules
+
+	// The directories of the root under which buckets are mounted, by bucket
+	// name, when they are listed in bucket-mounts. Nil otherwise.
+	bucketMountDirs map[string]string
+
+	// usageTracker estimates the space in use in the mounted bucket. Nil for
+	// dynamic mounts and when usage-refresh-interval is zero.
+	usageTracker *gcsx.UsageTracker
+
+	// backgroundCtx is cancelled by Destroy, which then waits for backgroundWork,
+	// the work started in the background when mounting, to finish.
+	backgroundCtx    context.Context
+	cancelBackground context.CancelFunc
+	backgroundWork   sync.WaitGroup
+
+	// changeDetector reports objects changed by other clients of the mounted
+	// bucket, whose cached metadata is then thrown away. Nil for dynamic mounts
+	// and unless change-listing-interval or change-feed is set.
+	changeDetector *changedetect.Detector
+
+	// The free space of the cache and temp directories that was last recorded
+	// in the fs/local_free_bytes metric, which only takes deltas.
+	cacheDirFreeBytes atomic.Int64
+	tempDirFreeBytes  atomic.Int64
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -849,9 +1098,10 @@ func (fs *fileSystem) checkInvariantsForGenerationBackedInodes() {
 }
 
 func (fs *fileSystem) checkInvariantsForInodes() {
-	// INVARIANT: For all keys k, fuseops.RootInodeID <= k < nextInodeID
+	// INVARIANT: For all keys k, fuseops.RootInodeID <= k
+	// INVARIANT: For all keys k, k < nextInodeID unless inodeIndex is set
 	for id := range fs.inodes {
-		if id < fuseops.RootInodeID || id >= fs.nextInodeID {
+		if id < fuseops.RootInodeID || (fs.inodeIndex == nil && id >= fs.nextInodeID) {
 			panic(fmt.Sprintf("Illegal inode ID: %v", id))
 		}
 	}
@@ -900,11 +1150,13 @@ func (fs *fileSystem) checkInvariants() {
 	// handles
 	//////////////////////////////////
 
-	// INVARIANT: All values are of type *dirHandle or *handle.FileHandle
+	// INVARIANT: All values are of type *dirHandle, *handle.FileHandle or
+	//            *inode.VersionFileInode
 	for _, h := range fs.handles {
 		switch h.(type) {
 		case *handle.DirHandle:
 		case *handle.FileHandle:
+		case *inode.VersionFileInode:
 		default:
 			panic(fmt.Sprintf("Unexpected handle type: %T", h))
 		}
@@ -956,11 +1208,37 @@ func (fs *fileSystem) createExplicitDirInode(inodeID fuseops.InodeID, ic inode.C
 // LOCKS_REQUIRED(fs.mu)
 func (fs *fileSystem) mintInode(ic inode.Core, parInodeCtx context.Context) (in inode.Inode, err error) {
 	// Choose an ID.
-	id := fs.nextInodeID
-	fs.nextInodeID++
+	id := fs.chooseInodeID(ic)
 
 	// Create the inode.
 	switch {
+	// The versions directory of a file, and the generations in it.
+	case ic.Version && ic.FullName.IsDir():
+		in = inode.NewVersionsDirInode(
+			id,
+			ic.FullName,
+			ic.Bucket,
+			fuseops.InodeAttributes{
+				Uid:   fs.uid,
+				Gid:   fs.gid,
+				Mode:  fs.dirMode,
+				Atime: fs.mtimeClock.Now(),
+				Ctime: fs.mtimeClock.Now(),
+				Mtime: fs.mtimeClock.Now(),
+			})
+
+	case ic.Version:
+		in = inode.NewVersionFileInode(
+			id,
+			ic.FullName,
+			ic.Bucket,
+			ic.MinObject,
+			fuseops.InodeAttributes{
+				Uid:  fs.uid,
+				Gid:  fs.gid,
+				Mode: fs.fileMode,
+			})
+
 	// Explicit directories or folders in hierarchical bucket.
 	case (ic.MinObject != nil && ic.FullName.IsDir()), ic.Folder != nil:
 		in = fs.createExplicitDirInode(id, ic, parInodeCtx)
@@ -1007,6 +1285,18 @@ func (fs *fileSystem) mintInode(ic inode.Core, parInodeCtx context.Context) (in
 			return nil, err
 		}
 
+	case inode.IsSpecialFile(ic.MinObject):
+		in = inode.NewSpecialFileInode(
+			id,
+			ic.FullName,
+			ic.Bucket,
+			ic.MinObject,
+			fuseops.InodeAttributes{
+				Uid:  fs.uid,
+				Gid:  fs.gid,
+				Mode: fs.fileMode,
+			})
+
 	default:
 		in = inode.NewFileInode(
 			id,
@@ -1034,6 +1324,28 @@ func (fs *fileSystem) mintInode(ic inode.Core, parInodeCtx context.Context) (in
 	return in, nil
 }
 
+// chooseInodeID returns the ID for a new inode for the supplied core.
+//
+// LOCKS_REQUIRED(fs.mu)
+func (fs *fileSystem) chooseInodeID(ic inode.Core) fuseops.InodeID {
+	if fs.inodeIndex == nil {
+		id := fs.nextInodeID
+		fs.nextInodeID++
+		return id
+	}
+
+	return fs.inodeIndex.Assign(ic.Bucket.Name(), ic.FullName.GcsObjectName(), func(id fuseops.InodeID) (bucket, name string, ok bool) {
+		in, ok := fs.inodes[id]
+		if !ok {
+			return
+		}
+		if b, isBucketOwned := in.(inode.BucketOwnedInode); isBucketOwned {
+			bucket = b.Bucket().Name()
+		}
+		return bucket, in.Name().GcsObjectName(), true
+	})
+}
+
 // Return the dir Inode.
 //
 // LOCKS_EXCLUDED(fs.mu)
@@ -1266,6 +1578,210 @@ func (fs *fileSystem) lookUpOrCreateChildInode(
 	return
 }
 
+// resolveChildName returns the name of the child of parent that the supplied
+// name refers to, which differs from it only when the name matches a child
+// loosely under --case-insensitive-lookup or --unicode-normalized-lookup.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(parent)
+func (fs *fileSystem) resolveChildName(ctx context.Context, parent inode.DirInode, name string) (string, error) {
+	if !fs.nameMatching.Enabled() || strings.HasSuffix(name, inode.ConflictingFileNameSuffix) {
+		return name, nil
+	}
+
+	// Files that have not been synced yet are only matched exactly.
+	fs.mu.Lock()
+	_, isLocalFile := fs.localFileInodes[inode.NewFileName(parent.Name(), name)]
+	fs.mu.Unlock()
+	if isLocalFile {
+		return name, nil
+	}
+
+	parent.Lock()
+	core, err := parent.LookUpChild(ctx, name)
+	parent.Unlock()
+	if err != nil {
+		return "", err
+	}
+	if core == nil || core.FullName.IsBucketRoot() {
+		return name, nil
+	}
+	return core.FullName.BaseName(), nil
+}
+
+// nameForCreate returns the name to give a child that is created, or renamed,
+// with the supplied name.
+func (fs *fileSystem) nameForCreate(name string) string {
+	if fs.newConfig.FileSystem.NormalizeCreatedNames {
+		return inode.NormalizeName(name)
+	}
+	return name
+}
+
+// chargeCreate charges creating the named child of the supplied directory to
+// the write quota, returning a function that gives the charge back should the
+// child not be created after all.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) chargeCreate(parentID fuseops.InodeID, name string) (refund func(), err error) {
+	if fs.writeQuota == nil {
+		return func() {}, nil
+	}
+
+	fs.mu.Lock()
+	parent := fs.dirInodeOrDie(parentID)
+	fs.mu.Unlock()
+
+	localName := inode.NewFileName(parent.Name(), name).LocalName()
+	if err = fs.writeQuota.ChargeCreate(localName); err != nil {
+		return nil, err
+	}
+	return func() { fs.writeQuota.RefundCreate(localName) }, nil
+}
+
+// rulePath returns the path that path rules match for the supplied name.
+func rulePath(name inode.Name) string {
+	return strings.TrimSuffix(name.LocalName(), "/")
+}
+
+// childRulePath returns the path that path rules match for the named child
+// of the supplied directory.
+func childRulePath(parent inode.DirInode, name string) string {
+	return rulePath(inode.NewFileName(parent.Name(), name))
+}
+
+// checkCreate returns an error if path rules forbid creating the named child
+// of the supplied directory.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) checkCreate(parentID fuseops.InodeID, name string) error {
+	if fs.pathRules == nil {
+		return nil
+	}
+
+	fs.mu.Lock()
+	parent := fs.dirInodeOrDie(parentID)
+	fs.mu.Unlock()
+
+	return fs.pathRules.CheckCreate(childRulePath(parent, name))
+}
+
+// checkModify returns an error if path rules forbid changing the supplied
+// inode. contents says whether the change is to the contents of a file.
+//
+// LOCKS_REQUIRED(in)
+func (fs *fileSystem) checkModify(in inode.Inode, contents bool) error {
+	// Only files whose contents are in the bucket can be overwritten.
+	overwrite := false
+	if f, ok := in.(*inode.FileInode); ok && contents {
+		overwrite = !f.IsLocal() && f.Source().Size > 0
+	}
+	return fs.pathRules.CheckModify(rulePath(in.Name()), overwrite)
+}
+
+// checkAccess returns EACCES if permissions are enforced and those of the
+// inode with the supplied ID don't grant the caller of an op the wanted
+// access, a combination of perms.Read, perms.Write and perms.Exec.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) checkAccess(ctx context.Context, opCtx fuseops.OpContext, id fuseops.InodeID, want uint32) error {
+	if !fs.newConfig.FileSystem.EnforcePermissions {
+		return nil
+	}
+
+	fs.mu.Lock()
+	in := fs.inodeOrDie(id)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+	attrs, err := in.Attributes(ctx, false)
+	if err != nil {
+		return err
+	}
+	return perms.CheckAccess(perms.NewCaller(opCtx), attrs, want)
+}
+
+// openAccess returns the access that opening a file with the supplied flags
+// needs.
+func openAccess(flags util.OpenFlagAttributes) uint32 {
+	switch util.FileOpenMode(flags).AccessMode() {
+	case util.WriteOnly:
+		return perms.Write
+	case util.ReadWrite:
+		return perms.Read | perms.Write
+	default:
+		return perms.Read
+	}
+}
+
+// checkSetAttributes returns an error if permissions are enforced and the
+// caller of an op may not make the supplied changes to the attributes of an
+// inode, following chmod(2), chown(2), truncate(2) and utimes(2).
+//
+// LOCKS_REQUIRED(in)
+func (fs *fileSystem) checkSetAttributes(ctx context.Context, in inode.Inode, op *fuseops.SetInodeAttributesOp) error {
+	if !fs.newConfig.FileSystem.EnforcePermissions {
+		return nil
+	}
+
+	attrs, err := in.Attributes(ctx, false)
+	if err != nil {
+		return err
+	}
+	c := perms.NewCaller(op.OpContext)
+
+	if op.Uid != nil && *op.Uid != attrs.Uid && !c.IsRoot() {
+		return syscall.EPERM
+	}
+	if op.Gid != nil && *op.Gid != attrs.Gid && !(c.IsOwner(attrs) && (c.IsRoot() || c.InGroup(*op.Gid))) {
+		return syscall.EPERM
+	}
+	if op.Mode != nil && !c.IsOwner(attrs) {
+		return syscall.EPERM
+	}
+	// Handles were checked for writing when they were opened.
+	if op.Size != nil && op.Handle == nil {
+		if err := perms.CheckAccess(c, attrs, perms.Write); err != nil {
+			return err
+		}
+	}
+	// Setting the times to now only needs write access, and isn't told apart
+	// from setting them to other times.
+	if (op.Atime != nil || op.Mtime != nil) && !c.IsOwner(attrs) {
+		return perms.CheckAccess(c, attrs, perms.Write)
+	}
+	return nil
+}
+
+// recordCreator records the caller of an op as the owner of an inode it
+// created, with the supplied mode, if permissions are enforced.
+//
+// LOCKS_REQUIRED(in)
+func (fs *fileSystem) recordCreator(ctx context.Context, opCtx fuseops.OpContext, in inode.Inode, mode os.FileMode) error {
+	if !fs.newConfig.FileSystem.EnforcePermissions {
+		return nil
+	}
+
+	c := perms.NewCaller(opCtx)
+	p := inode.PosixAttributes{Mode: &mode, Uid: &c.Uid}
+	if gid, ok := c.Gid(); ok {
+		p.Gid = &gid
+	}
+
+	var err error
+	switch typed := in.(type) {
+	case *inode.FileInode:
+		err = typed.SetPosixAttributes(ctx, p)
+	case inode.ExplicitDirInode:
+		err = typed.SetPosixAttributes(ctx, p)
+	}
+	if err != nil {
+		return fmt.Errorf("SetPosixAttributes: %w", err)
+	}
+	return nil
+}
+
 // Look up the localFileInodes to check if a file with given name exists.
 // Return inode if it exists, else return nil.
 // LOCKS_EXCLUDED(fs.mu)
@@ -1737,6 +2253,8 @@ func (fs *fileSystem) coreToDirentPlus(ctx context.Context, fullName inode.Name,
 		entryPlus.Dirent.Type = fuseutil.DT_Link
 	case metadata.RegularFileType:
 		entryPlus.Dirent.Type = fuseutil.DT_File
+	case metadata.SpecialFileType:
+		entryPlus.Dirent.Type = inode.SpecialFileDirentType(core.MinObject)
 	case metadata.ImplicitDirType, metadata.ExplicitDirType:
 		entryPlus.Dirent.Type = fuseutil.DT_Directory
 	}
@@ -1857,11 +2375,70 @@ func (fs *fileSystem) invalidateCachedEntry(childID fuseops.InodeID) error {
 	return fs.notifier.InvalidateEntry(parentInodeID, childBase)
 }
 
+// invalidateChangedObject throws away what is cached about an object that has
+// been created, changed or deleted by another client of the mounted bucket:
+// its stat cache entry is refreshed from GCS, and the type cache entries and
+// kernel dentries for it and its ancestors are dropped. If the object backs an
+// inode, the kernel is also told to drop the inode's attributes and pages.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) invalidateChangedObject(ctx context.Context, objectName string) {
+	fs.mu.Lock()
+	root := fs.inodes[fuseops.RootInodeID].(inode.BucketOwnedDirInode)
+	fs.mu.Unlock()
+
+	// A missing object leaves a negative entry behind.
+	_, _, err := root.Bucket().StatObject(ctx, &gcs.StatObjectRequest{Name: objectName, ForceFetchFromGcs: true})
+	var notFoundErr *gcs.NotFoundError
+	if err != nil && !errors.As(err, &notFoundErr) {
+		logger.Warnf("Refreshing changed object %q: %v", objectName, err)
+	}
+
+	name := inode.NewDescendantName(root.Name(), objectName)
+	fs.mu.Lock()
+	changed, ok := fs.generationBackedInodes[name]
+	fs.mu.Unlock()
+	if ok && fs.notifier != nil {
+		if err := fs.notifier.InvalidateInode(changed.ID(), 0, 0); err != nil {
+			logger.Debugf("Invalidating inode of changed object %q: %v", objectName, err)
+		}
+	}
+
+	// New objects can bring implicit directories into being, and deleted ones
+	// can take them away, so the whole path is dropped.
+	for !name.IsBucketRoot() {
+		fs.mu.Lock()
+		parent := fs.findParentDirInode(name)
+		fs.mu.Unlock()
+		if parent != nil {
+			base := path.Base(name.LocalName())
+			parent.Lock()
+			parent.EraseFromTypeCache(base)
+			parent.InvalidateKernelListCache()
+			parent.Unlock()
+			if fs.notifier != nil {
+				if err := fs.notifier.InvalidateEntry(parent.ID(), base); err != nil {
+					logger.Debugf("Invalidating entry of changed object %q: %v", objectName, err)
+				}
+			}
+		}
+
+		if name, err = name.ParentName(); err != nil {
+			break
+		}
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////
 // fuse.FileSystem methods
 ////////////////////////////////////////////////////////////////////////
 
 func (fs *fileSystem) Destroy() {
+	fs.cancelBackground()
+	fs.backgroundWork.Wait()
+	if fs.changeDetector != nil {
+		fs.changeDetector.Stop()
+	}
 	fs.bucketManager.ShutDown()
 	if fs.fileCacheHandler != nil {
 		_ = fs.fileCacheHandler.Destroy()
@@ -1869,23 +2446,44 @@ func (fs *fileSystem) Destroy() {
 	if fs.bufferedReadWorkerPool != nil {
 		fs.bufferedReadWorkerPool.Stop()
 	}
+	if fs.usageTracker != nil {
+		fs.usageTracker.Stop()
+	}
+	if fs.inodeIndex != nil {
+		if err := fs.inodeIndex.Close(); err != nil {
+			logger.Warnf("Closing inode index: %v", err)
+		}
+	}
 }
 
 func (fs *fileSystem) StatFS(
 	ctx context.Context,
 	op *fuseops.StatFSOp) (err error) {
-	// Simulate a large amount of free space so that the Finder doesn't refuse to
-	// copy in files. (See issue #125.) Use 2^17 as the block size because that
-	// is the largest that OS X will pass on.
+	// Use 2^17 as the block size because that is the largest that OS X will
+	// pass on.
 	op.BlockSize = 1 << 17
+
+	// Without a quota, simulate a large amount of free space so that the Finder
+	// doesn't refuse to copy in files. (See issue #125.)
 	op.Blocks = 1 << 33
+	if fs.quotaMb > 0 {
+		op.Blocks = uint64(fs.quotaMb) * (1 << 20) / uint64(op.BlockSize)
+	}
 	op.BlocksFree = op.Blocks
-	op.BlocksAvailable = op.Blocks
 
 	// Similarly with inodes.
 	op.Inodes = 1 << 50
 	op.InodesFree = op.Inodes
 
+	if fs.usageTracker != nil {
+		if usage, ok := fs.usageTracker.Usage(); ok {
+			usedBlocks := (usage.Bytes + uint64(op.BlockSize) - 1) / uint64(op.BlockSize)
+			op.BlocksFree -= min(usedBlocks, op.BlocksFree)
+			op.InodesFree -= min(usage.Objects, op.InodesFree)
+		}
+	}
+	op.BlocksAvailable = op.BlocksFree
+
 	// Prefer large transfers. This is the largest value that OS X will
 	// faithfully pass on, according to fuseops/ops.go.
 	op.IoSize = 1 << 20
@@ -1893,9 +2491,53 @@ func (fs *fileSystem) StatFS(
 	return
 }
 
-// getInterruptlessContext returns a new context that is not cancellable by the
-// parent context if the ignore-interrupts flag is set. Otherwise, it returns
-// the original context.
+// localFreeSpaceSampleInterval is how often the fs/local_free_bytes metric is
+// brought up to date.
+const localFreeSpaceSampleInterval = 30 * time.Second
+
+// sampleLocalFreeSpace records the local free space right away and then every
+// interval, until the supplied context is cancelled.
+func (fs *fileSystem) sampleLocalFreeSpace(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fs.recordLocalFreeSpace()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordLocalFreeSpace updates the fs/local_free_bytes metric with the free
+// space of the directories that file contents are written to locally. Unlike
+// the bucket, these can actually fill up.
+func (fs *fileSystem) recordLocalFreeSpace() {
+	tempDir := fs.tempDir
+	if tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	fs.recordFreeSpace(tempDir, &fs.tempDirFreeBytes, metrics.DirTempDirAttr)
+
+	if cacheDir := string(fs.newConfig.CacheDir); cacheDir != "" {
+		fs.recordFreeSpace(cacheDir, &fs.cacheDirFreeBytes, metrics.DirCacheDirAttr)
+	}
+}
+
+func (fs *fileSystem) recordFreeSpace(dir string, last *atomic.Int64, attr metrics.Dir) {
+	free, err := diskutil.GetFreeSpace(dir)
+	if err != nil {
+		logger.Tracef("Getting free space of %q: %v", dir, err)
+		return
+	}
+
+	fs.metricHandle.FsLocalFreeBytes(int64(free)-last.Swap(int64(free)), attr)
+}
+
+// getInterruptlessContext returns a new context that is not cancellable by the
+// parent context if the ignore-interrupts flag is set. Otherwise, it returns
+// the original context.
 func (fs *fileSystem) getInterruptlessContext(ctx context.Context) context.Context {
 	if fs.newConfig.FileSystem.IgnoreInterrupts {
 		// When ignore interrupts config is set, we are creating a new context not
@@ -1912,6 +2554,10 @@ func (fs *fileSystem) LookUpInode(
 	ctx context.Context,
 	op *fuseops.LookUpInodeOp) (err error) {
 	ctx = fs.getInterruptlessContext(ctx)
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Exec); err != nil {
+		return err
+	}
+
 	// Find the parent directory in question.
 	fs.mu.Lock()
 	parent := fs.dirInodeOrDie(op.Parent)
@@ -1925,6 +2571,11 @@ func (fs *fileSystem) LookUpInode(
 
 	defer fs.unlockAndMaybeDisposeOfInode(child, &err)
 
+	// The name the child was found under may differ from the one looked up.
+	if err = fs.pathRules.CheckLookUp(rulePath(child.Name())); err != nil {
+		return err
+	}
+
 	// Fill out the response.
 	e := &op.Entry
 	e.Child = child.ID()
@@ -1976,6 +2627,22 @@ func (fs *fileSystem) SetInodeAttributes(
 	defer in.Unlock()
 	file, isFile := in.(*inode.FileInode)
 
+	// Generations in versions directories are read-only, as is everything in
+	// a read-only file system, down to ownership and atime.
+	changesAttributes := op.Size != nil || op.Mtime != nil || op.Mode != nil ||
+		op.Uid != nil || op.Gid != nil || op.Atime != nil
+	if _, ok := in.(*inode.VersionFileInode); (ok || fs.readOnly) && changesAttributes {
+		return syscall.EROFS
+	}
+	if op.Size != nil || op.Mtime != nil || op.Mode != nil {
+		if err = fs.checkModify(in, op.Size != nil); err != nil {
+			return err
+		}
+	}
+	if err = fs.checkSetAttributes(ctx, in, op); err != nil {
+		return err
+	}
+
 	// Set file mtimes.
 	if isFile && op.Mtime != nil {
 		err = file.SetMtime(ctx, *op.Mtime)
@@ -2004,7 +2671,28 @@ func (fs *fileSystem) SetInodeAttributes(
 		}
 	}
 
-	// We silently ignore updates to mode and atime.
+	// Persist mode, ownership and atime if asked to; otherwise we silently
+	// ignore them.
+	if fs.newConfig.FileSystem.PreservePosix {
+		p := inode.PosixAttributes{
+			Mode:  op.Mode,
+			Uid:   op.Uid,
+			Gid:   op.Gid,
+			Atime: op.Atime,
+		}
+
+		switch typed := in.(type) {
+		case *inode.FileInode:
+			err = typed.SetPosixAttributes(ctx, p)
+		case inode.ExplicitDirInode:
+			err = typed.SetPosixAttributes(ctx, p)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("SetPosixAttributes: %w", err)
+			return err
+		}
+	}
 
 	// Fill in the response.
 	op.Attributes, op.AttributesExpiration, err = fs.getAttributes(ctx, in)
@@ -2036,16 +2724,27 @@ func (fs *fileSystem) ForgetInode(
 func (fs *fileSystem) MkDir(
 	ctx context.Context,
 	op *fuseops.MkDirOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 	// Find the parent.
 	fs.mu.Lock()
 	parent := fs.dirInodeOrDie(op.Parent)
 	fs.mu.Unlock()
 
+	if err = fs.pathRules.CheckCreate(childRulePath(parent, fs.nameForCreate(op.Name))); err != nil {
+		return err
+	}
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Write|perms.Exec); err != nil {
+		return err
+	}
+
 	// Create an empty backing object for the child, failing if it already
 	// exists.
 	parent.Lock()
-	result, err := parent.CreateChildDir(ctx, op.Name)
+	result, err := parent.CreateChildDir(ctx, fs.nameForCreate(op.Name))
 	parent.Unlock()
 
 	// Special case: *gcs.PreconditionError means the name already exists.
@@ -2075,6 +2774,10 @@ func (fs *fileSystem) MkDir(
 
 	defer fs.unlockAndMaybeDisposeOfInode(child, &err)
 
+	if err = fs.recordCreator(ctx, op.OpContext, child, op.Mode); err != nil {
+		return err
+	}
+
 	// Fill out the response.
 	e := &op.Entry
 	e.Child = child.ID()
@@ -2092,19 +2795,44 @@ func (fs *fileSystem) MkDir(
 func (fs *fileSystem) MkNode(
 	ctx context.Context,
 	op *fuseops.MkNodeOp) (err error) {
-	ctx = fs.getInterruptlessContext(ctx)
-	if (op.Mode & (iofs.ModeNamedPipe | iofs.ModeSocket)) != 0 {
-		return syscall.ENOTSUP
+	if fs.readOnly {
+		return syscall.EROFS
 	}
 
+	ctx = fs.getInterruptlessContext(ctx)
+
 	// Create the child.
-	child, err := fs.createFile(ctx, op.Parent, op.Name)
+	if err = fs.checkCreate(op.Parent, fs.nameForCreate(op.Name)); err != nil {
+		return err
+	}
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Write|perms.Exec); err != nil {
+		return err
+	}
+	refund, err := fs.chargeCreate(op.Parent, fs.nameForCreate(op.Name))
+	if err != nil {
+		return err
+	}
+	var child inode.Inode
+	switch {
+	case inode.IsSpecialFileMode(op.Mode) && fs.newConfig.FileSystem.EnableSpecialFiles:
+		child, err = fs.createSpecialFile(ctx, op.Parent, fs.nameForCreate(op.Name), op.Mode, op.Rdev)
+	case (op.Mode & (iofs.ModeNamedPipe | iofs.ModeSocket)) != 0:
+		refund()
+		return syscall.ENOTSUP
+	default:
+		child, err = fs.createFile(ctx, op.Parent, fs.nameForCreate(op.Name))
+	}
 	if err != nil {
+		refund()
 		return err
 	}
 
 	defer fs.unlockAndMaybeDisposeOfInode(child, &err)
 
+	if err = fs.recordCreator(ctx, op.OpContext, child, op.Mode); err != nil {
+		return err
+	}
+
 	// Fill out the response.
 	e := &op.Entry
 	e.Child = child.ID()
@@ -2118,6 +2846,53 @@ func (fs *fileSystem) MkNode(
 	return
 }
 
+// Create a special file in the parent with the given ID, returning the child
+// locked and with its lookup count incremented.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCK_FUNCTION(child)
+func (fs *fileSystem) createSpecialFile(
+	ctx context.Context,
+	parentID fuseops.InodeID,
+	name string,
+	mode os.FileMode,
+	rdev uint32) (child inode.Inode, err error) {
+	// Find the parent.
+	fs.mu.Lock()
+	parent := fs.dirInodeOrDie(parentID)
+	fs.mu.Unlock()
+
+	// Create the marker object in GCS, failing if the name is taken.
+	parent.Lock()
+	result, err := parent.CreateChildSpecialFile(ctx, name, mode, rdev)
+	parent.Unlock()
+
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &preconditionErr) {
+		err = fuse.EEXIST
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("CreateChildSpecialFile: %w", err)
+		return
+	}
+
+	// Attempt to create a child inode using the object we created. If we fail to
+	// do so, it means someone beat us to the punch with a newer generation
+	// (unlikely, so we're probably okay with failing here).
+	child, err = fs.lookUpOrCreateInodeIfNotStale(parent.Context(), *result)
+	if err != nil {
+		return
+	}
+	if child == nil {
+		err = fmt.Errorf("newly-created record is already stale")
+		return
+	}
+
+	return
+}
+
 // Create a child of the parent with the given ID, returning the child locked
 // and with its lookup count incremented.
 //
@@ -2178,6 +2953,7 @@ func (fs *fileSystem) createLocalFile(ctx context.Context, parentID fuseops.Inod
 	defer func() {
 		if err != nil {
 			if child == nil {
+				fs.mu.Unlock()
 				return
 			}
 			// fs.mu lock is already taken
@@ -2232,22 +3008,42 @@ func (fs *fileSystem) createLocalFile(ctx context.Context, parentID fuseops.Inod
 func (fs *fileSystem) CreateFile(
 	ctx context.Context,
 	op *fuseops.CreateFileOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 	// Create the child.
 	var child inode.Inode
 	openMode := util.FileOpenMode(op.OpenFlags)
+	name := fs.nameForCreate(op.Name)
+	if err = fs.checkCreate(op.Parent, name); err != nil {
+		return err
+	}
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Write|perms.Exec); err != nil {
+		return err
+	}
+	refund, err := fs.chargeCreate(op.Parent, name)
+	if err != nil {
+		return err
+	}
 	if fs.newConfig.Write.CreateEmptyFile {
-		child, err = fs.createFile(ctx, op.Parent, op.Name)
+		child, err = fs.createFile(ctx, op.Parent, name)
 	} else {
-		child, err = fs.createLocalFile(ctx, op.Parent, op.Name, openMode)
+		child, err = fs.createLocalFile(ctx, op.Parent, name, openMode)
 	}
 
 	if err != nil {
+		refund()
 		return err
 	}
 
 	defer fs.unlockAndMaybeDisposeOfInode(child, &err)
 
+	if err = fs.recordCreator(ctx, op.OpContext, child, op.Mode); err != nil {
+		return err
+	}
+
 	// Allocate a handle.
 	fs.mu.Lock()
 
@@ -2289,15 +3085,26 @@ func (fs *fileSystem) CreateFile(
 func (fs *fileSystem) CreateSymlink(
 	ctx context.Context,
 	op *fuseops.CreateSymlinkOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 	// Find the parent.
 	fs.mu.Lock()
 	parent := fs.dirInodeOrDie(op.Parent)
 	fs.mu.Unlock()
 
+	if err = fs.pathRules.CheckCreate(childRulePath(parent, fs.nameForCreate(op.Name))); err != nil {
+		return err
+	}
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Write|perms.Exec); err != nil {
+		return err
+	}
+
 	// Create the object in GCS, failing if it already exists.
 	parent.Lock()
-	result, err := parent.CreateChildSymlink(ctx, op.Name, op.Target)
+	result, err := parent.CreateChildSymlink(ctx, fs.nameForCreate(op.Name), op.Target)
 	parent.Unlock()
 
 	// Special case: *gcs.PreconditionError means the name already exists.
@@ -2356,14 +3163,29 @@ func (fs *fileSystem) RmDir(
 
 	ctx context.Context,
 	op *fuseops.RmDirOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 	// Find the parent.
 	fs.mu.Lock()
 	parent := fs.dirInodeOrDie(op.Parent)
 	fs.mu.Unlock()
 
+	name, err := fs.resolveChildName(ctx, parent, op.Name)
+	if err != nil {
+		return err
+	}
+	if err = fs.pathRules.CheckDelete(childRulePath(parent, name)); err != nil {
+		return err
+	}
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Write|perms.Exec); err != nil {
+		return err
+	}
+
 	// Find or create the child inode, locked.
-	child, err := fs.lookUpOrCreateChildInode(ctx, parent, op.Name)
+	child, err := fs.lookUpOrCreateChildInode(ctx, parent, name)
 	if err != nil {
 		return
 	}
@@ -2455,11 +3277,11 @@ func (fs *fileSystem) RmDir(
 	_, isImplicitDir := fs.implicitDirInodes[child.Name()]
 	fs.mu.Unlock()
 	parent.Lock()
-	err = parent.DeleteChildDir(ctx, op.Name, isImplicitDir, childDir)
+	err = parent.TrashChildDir(ctx, name, isImplicitDir, childDir)
 	parent.Unlock()
 
 	if err != nil {
-		err = fmt.Errorf("DeleteChildDir: %w", err)
+		err = fmt.Errorf("TrashChildDir: %w", err)
 		return err
 	}
 
@@ -2470,6 +3292,10 @@ func (fs *fileSystem) RmDir(
 func (fs *fileSystem) Rename(
 	ctx context.Context,
 	op *fuseops.RenameOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 	// Find the old and new parents.
 	fs.mu.Lock()
@@ -2477,20 +3303,43 @@ func (fs *fileSystem) Rename(
 	newParent := fs.dirInodeOrDie(op.NewParent)
 	fs.mu.Unlock()
 
+	var crossBucket bool
 	if oldParentInode, ok := oldParent.(inode.BucketOwnedInode); !ok {
 		// The old parent is not owned by any bucket, which means it's the base
 		// directory that holds all the buckets' root directories. So, this op
 		// is to rename a bucket, which is not supported.
 		return fmt.Errorf("rename a bucket: %w", syscall.ENOTSUP)
 	} else {
-		// The target path must exist in the same bucket.
+		// The target path must be inside a bucket, though in a dynamic mount it
+		// may be another one.
 		oldBucket := oldParentInode.Bucket().Name()
-		if newParentInode, ok := newParent.(inode.BucketOwnedInode); !ok || oldBucket != newParentInode.Bucket().Name() {
+		newParentInode, ok := newParent.(inode.BucketOwnedInode)
+		if !ok {
 			return fmt.Errorf("move out of bucket %q: %w", oldBucket, syscall.ENOTSUP)
 		}
+		crossBucket = oldBucket != newParentInode.Bucket().Name()
+		// Objects are copied by their names in the buckets, which in an entry
+		// of bucket-mounts may be below a prefix.
+		if crossBucket && fs.bucketMountDirs != nil {
+			return fmt.Errorf("move out of bucket %q: %w", oldBucket, syscall.EXDEV)
+		}
+	}
+
+	oldName, err := fs.resolveChildName(ctx, oldParent, op.OldName)
+	if err != nil {
+		return err
+	}
+	newName := fs.nameForCreate(op.NewName)
+	if err = fs.checkRename(ctx, oldParent, oldName, newParent, newName); err != nil {
+		return err
+	}
+	for _, id := range []fuseops.InodeID{op.OldParent, op.NewParent} {
+		if err = fs.checkAccess(ctx, op.OpContext, id, perms.Write|perms.Exec); err != nil {
+			return err
+		}
 	}
 
-	child, err := fs.lookUpOrCreateChildInode(ctx, oldParent, op.OldName)
+	child, err := fs.lookUpOrCreateChildInode(ctx, oldParent, oldName)
 	if err != nil {
 		return err
 	}
@@ -2505,21 +3354,67 @@ func (fs *fileSystem) Rename(
 		return fmt.Errorf("child inode (id %v) is not owned by any bucket", child.ID())
 	}
 
+	// Everything beneath a directory moves with it, so the rules that may
+	// apply there are checked too.
+	if child.Name().IsDir() {
+		if err = fs.pathRules.CheckDeleteTree(childRulePath(oldParent, oldName)); err != nil {
+			return err
+		}
+		if err = fs.pathRules.CheckCreateTree(childRulePath(newParent, newName)); err != nil {
+			return err
+		}
+	}
+
+	if crossBucket {
+		if child.Name().IsDir() {
+			return fs.renameDirAcrossBuckets(ctx, oldParent, oldName, newParent, newName)
+		}
+		return fs.renameFileAcrossBuckets(ctx, childBktOwned, oldParent, oldName, newParent, newName)
+	}
+
 	if child.Name().IsDir() {
 		// If 'enable-hns' flag is false, the bucket type is set to 'NonHierarchical' even for HNS buckets because the control client is nil.
 		// Therefore, an additional 'enable hns' check is not required here.
 		if childBktOwned.Bucket().BucketType().Hierarchical {
-			return fs.renameHierarchicalDir(ctx, oldParent, op.OldName, newParent, op.NewName)
+			return fs.renameHierarchicalDir(ctx, oldParent, oldName, newParent, newName)
 		}
-		return fs.renameNonHierarchicalDir(ctx, oldParent, op.OldName, newParent, op.NewName)
+		return fs.renameNonHierarchicalDir(ctx, oldParent, oldName, newParent, newName)
+	}
+
+	return fs.renameFile(ctx, childBktOwned, oldParent, oldName, newParent, newName)
+}
+
+// checkRename returns an error if path rules forbid renaming the named child
+// of oldParent to the named child of newParent.
+//
+// LOCKS_EXCLUDED(oldParent)
+// LOCKS_EXCLUDED(newParent)
+func (fs *fileSystem) checkRename(ctx context.Context, oldParent inode.DirInode, oldName string, newParent inode.DirInode, newName string) error {
+	if err := fs.pathRules.CheckDelete(childRulePath(oldParent, oldName)); err != nil {
+		return err
+	}
+
+	newPath := childRulePath(newParent, newName)
+	if err := fs.pathRules.CheckCreate(newPath); err != nil {
+		return err
 	}
 
-	return fs.renameFile(ctx, op, childBktOwned, oldParent, newParent)
+	// Only look for an existing target if it matters.
+	if action, _ := fs.pathRules.Match(newPath); action != pathrules.NoOverwrite {
+		return nil
+	}
+	newParent.Lock()
+	existing, err := newParent.LookUpChild(ctx, newName)
+	newParent.Unlock()
+	if err != nil {
+		return err
+	}
+	return fs.pathRules.CheckModify(newPath, existing != nil)
 }
 
 // LOCKS_EXCLUDED(oldParent)
 // LOCKS_EXCLUDED(newParent)
-func (fs *fileSystem) renameFile(ctx context.Context, op *fuseops.RenameOp, child inode.BucketOwnedInode, oldParent, newParent inode.DirInode) error {
+func (fs *fileSystem) renameFile(ctx context.Context, child inode.BucketOwnedInode, oldParent inode.DirInode, oldName string, newParent inode.DirInode, newName string) error {
 	var updatedMinObject *gcs.MinObject
 	var err error
 
@@ -2531,13 +3426,15 @@ func (fs *fileSystem) renameFile(ctx context.Context, op *fuseops.RenameOp, chil
 		}
 	case *inode.SymlinkInode:
 		updatedMinObject = c.Source()
+	case *inode.SpecialFileInode:
+		updatedMinObject = c.Source()
 	default:
-		return fmt.Errorf("child inode (id %v) is not a file or symlink inode", child.ID())
+		return fmt.Errorf("child inode (id %v) is not a file, symlink or special file inode", child.ID())
 	}
 	if fs.enableAtomicRenameObject || child.Bucket().BucketType().Zonal {
-		return fs.atomicRename(ctx, oldParent, op.OldName, updatedMinObject, newParent, op.NewName)
+		return fs.atomicRename(ctx, oldParent, oldName, updatedMinObject, newParent, newName)
 	}
-	return fs.nonAtomicRename(ctx, oldParent, op.OldName, updatedMinObject, newParent, op.NewName)
+	return fs.nonAtomicRename(ctx, oldParent, oldName, updatedMinObject, newParent, newName)
 }
 
 // LOCKS_EXCLUDED(fileInode)
@@ -2599,8 +3496,19 @@ func (fs *fileSystem) nonAtomicRename(
 		return err
 	}
 
-	// Delete behind. Make sure to delete exactly the generation we cloned, in
-	// case the referent of the name has changed in the meantime.
+	return fs.deleteRenamedFile(ctx, oldParent, oldName, oldObject)
+}
+
+// deleteRenamedFile deletes the object a file was renamed from once it has
+// been copied to the new name. Make sure to delete exactly the generation we
+// copied, in case the referent of the name has changed in the meantime.
+//
+// LOCKS_EXCLUDED(oldParent)
+func (fs *fileSystem) deleteRenamedFile(
+	ctx context.Context,
+	oldParent inode.DirInode,
+	oldName string,
+	oldObject *gcs.MinObject) error {
 	oldParent.Lock()
 	defer oldParent.Unlock()
 
@@ -2630,78 +3538,377 @@ func (fs *fileSystem) nonAtomicRename(
 	return fmt.Errorf("DeleteChildFile: %w", deleteErr)
 }
 
-func (fs *fileSystem) releaseInodes(inodes *[]inode.DirInode) {
-	for _, in := range *inodes {
-		fs.unlockAndDecrementLookupCount(in, 1)
+// renameFileAcrossBuckets moves a file to a directory of another bucket in a
+// dynamic mount. GCS copies the object into the destination bucket, on the
+// condition that neither the source nor whatever the new name refers to has
+// changed since they were looked up, after which the source generation is
+// deleted. A writer racing with the rename thus makes it fail rather than have
+// its data dropped.
+//
+// LOCKS_EXCLUDED(oldParent)
+// LOCKS_EXCLUDED(newParent)
+func (fs *fileSystem) renameFileAcrossBuckets(ctx context.Context, child inode.BucketOwnedInode, oldParent inode.DirInode, oldName string, newParent inode.DirInode, newName string) error {
+	var oldObject *gcs.MinObject
+	var err error
+
+	switch c := child.(type) {
+	case *inode.FileInode:
+		oldObject, err = fs.flushPendingWrites(ctx, c)
+		if err != nil {
+			return fmt.Errorf("flushPendingWrites: %w", err)
+		}
+	case *inode.SymlinkInode:
+		oldObject = c.Source()
+	case *inode.SpecialFileInode:
+		oldObject = c.Source()
+	default:
+		return fmt.Errorf("child inode (id %v) is not a file, symlink or special file inode", child.ID())
 	}
-	*inodes = []inode.DirInode{}
-}
 
-func (fs *fileSystem) getBucketDirInode(ctx context.Context, parent inode.DirInode, name string) (inode.BucketOwnedDirInode, error) {
-	dir, err := fs.lookUpOrCreateChildDirInode(ctx, parent, name)
+	newParent.Lock()
+	err = fs.copyFileFromBucket(ctx, newParent, newName, child.Bucket().Name(), oldObject)
+	newParent.Unlock()
 	if err != nil {
-		return nil, fmt.Errorf("lookup directory: %w", err)
+		return err
 	}
-	return dir, nil
-}
-
-func (fs *fileSystem) ensureNoLocalFilesInDirectory(dir inode.BucketOwnedDirInode, name string) error {
-	fs.mu.Lock()
-	entries := dir.LocalFileEntries(fs.localFileInodes)
-	fs.mu.Unlock()
 
-	if len(entries) != 0 {
-		return fmt.Errorf("can't rename directory %s with open files: %w", name, syscall.ENOTSUP)
-	}
-	return nil
+	return fs.deleteRenamedFile(ctx, oldParent, oldName, oldObject)
 }
 
-func (fs *fileSystem) checkDirNotEmpty(dir inode.BucketOwnedDirInode, name string) error {
-	unexpected, err := dir.ReadDescendants(context.Background(), 1)
+// copyFileFromBucket copies src from the named bucket to the child of
+// newParent with the given name, replacing the file of that name if there is
+// one as of the time of the call.
+//
+// LOCKS_REQUIRED(newParent)
+func (fs *fileSystem) copyFileFromBucket(ctx context.Context, newParent inode.DirInode, newName string, srcBucket string, src *gcs.MinObject) error {
+	existing, err := newParent.LookUpChild(ctx, newName)
 	if err != nil {
-		return fmt.Errorf("read descendants of the new directory %q: %w", name, err)
+		return fmt.Errorf("LookUpChild: %w", err)
 	}
 
-	if len(unexpected) > 0 {
-		return fuse.ENOTEMPTY
+	var dstGeneration int64
+	if existing != nil {
+		if existing.FullName.IsDir() {
+			return syscall.EISDIR
+		}
+		if existing.MinObject != nil {
+			dstGeneration = existing.MinObject.Generation
+		}
+	}
+
+	if _, err = newParent.CopyChildFileFromBucket(ctx, newName, srcBucket, src, dstGeneration); err != nil {
+		return fmt.Errorf("CopyChildFileFromBucket: %w", err)
 	}
+
 	return nil
 }
 
-// Rename an old folder to a new folder in a hierarchical bucket. If the new folder already
-// exists and is non-empty, return ENOTEMPTY. If old folder have open files then return
-// ENOTSUP.
+// renameDirAcrossBuckets moves a directory to another bucket in a dynamic
+// mount, object by object as renameNonHierarchicalDir does within a bucket, but
+// with each object copied by renameFileAcrossBuckets' rules. If the new
+// directory already exists and is non-empty, return ENOTEMPTY. Open files in
+// the old directory are flushed first, but stay with the objects they were
+// opened on.
 //
 // LOCKS_EXCLUDED(fs.mu)
 // LOCKS_EXCLUDED(oldParent)
 // LOCKS_EXCLUDED(newParent)
-func (fs *fileSystem) renameHierarchicalDir(ctx context.Context, oldParent inode.DirInode, oldName string, newParent inode.DirInode, newName string) (err error) {
-	// Set up a function that throws away the lookup count increment from
-	// lookUpOrCreateChildInode (since the pending inodes are not sent back to
-	// the kernel) and unlocks the pending inodes, but only once.
+func (fs *fileSystem) renameDirAcrossBuckets(
+	ctx context.Context,
+	oldParent inode.DirInode,
+	oldName string,
+	newParent inode.DirInode,
+	newName string) error {
 	var pendingInodes []inode.DirInode
 	defer fs.releaseInodes(&pendingInodes)
 
-	oldDirInode, err := fs.getBucketDirInode(ctx, oldParent, oldName)
-	if err != nil {
+	if _, err := fs.flushFilesInDirectory(ctx, inode.NewDirName(oldParent.Name(), oldName)); err != nil {
 		return err
 	}
-	pendingInodes = append(pendingInodes, oldDirInode)
 
-	if err = fs.ensureNoLocalFilesInDirectory(oldDirInode, oldName); err != nil {
+	oldDir, err := fs.getBucketDirInode(ctx, oldParent, oldName)
+	if err != nil {
 		return err
 	}
+	pendingInodes = append(pendingInodes, oldDir)
 
-	oldDirName := inode.NewDirName(oldParent.Name(), oldName)
-	newDirName := inode.NewDirName(newParent.Name(), newName)
-
-	// If the call for getBucketDirInode fails it means directory does not exist.
-	newDirInode, err := fs.getBucketDirInode(ctx, newParent, newName)
-	if err == nil {
-		// If the directory exists, then check if it is empty or not.
-		if err = fs.checkDirNotEmpty(newDirInode, newName); err != nil {
-			return err
-		}
+	// Folders of a hierarchical bucket can't be removed object by object.
+	if oldDir.Bucket().BucketType().Hierarchical {
+		return fmt.Errorf("move directory out of hierarchical bucket %q: %w", oldDir.Bucket().Name(), syscall.ENOTSUP)
+	}
+
+	descendants, err := oldDir.ReadDescendants(ctx, int(fs.renameDirLimit+1))
+	if err != nil {
+		return fmt.Errorf("read descendants of the old directory %q: %w", oldName, err)
+	}
+	if len(descendants) > int(fs.renameDirLimit) {
+		return fmt.Errorf("too many objects to be renamed: %w", syscall.EMFILE)
+	}
+
+	// Create the backing object of the new directory.
+	newParent.Lock()
+	_, err = newParent.CreateChildDir(ctx, newName)
+	newParent.Unlock()
+	if err != nil {
+		var preconditionErr *gcs.PreconditionError
+		if !errors.As(err, &preconditionErr) {
+			return fmt.Errorf("CreateChildDir: %w", err)
+		}
+	}
+
+	newDir, err := fs.getBucketDirInode(ctx, newParent, newName)
+	if err != nil {
+		return err
+	}
+	pendingInodes = append(pendingInodes, newDir)
+
+	if err = fs.checkDirNotEmpty(newDir, newName); err != nil {
+		return err
+	}
+
+	// The new directory is empty, so none of the copies may overwrite anything.
+	srcBucket := oldDir.Bucket().Name()
+	for _, descendant := range descendants {
+		nameDiff := strings.TrimPrefix(descendant.FullName.GcsObjectName(), oldDir.Name().GcsObjectName())
+		if nameDiff == descendant.FullName.GcsObjectName() {
+			return fmt.Errorf("unwanted descendant %q not from dir %q", descendant.FullName, oldDir.Name())
+		}
+
+		o := descendant.MinObject
+		if _, err = newDir.CopyChildFileFromBucket(ctx, nameDiff, srcBucket, o, 0); err != nil {
+			return fmt.Errorf("copy file %q: %w", o.Name, err)
+		}
+		if err = oldDir.DeleteChildFile(ctx, nameDiff, o.Generation, &o.MetaGeneration); err != nil {
+			return fmt.Errorf("delete file %q: %w", o.Name, err)
+		}
+		if err = fs.invalidateChildFileCacheIfExist(oldDir, o.Name); err != nil {
+			return fmt.Errorf("unlink: while invalidating cache for delete file: %w", err)
+		}
+	}
+
+	fs.releaseInodes(&pendingInodes)
+
+	// Delete the backing object of the old directory.
+	fs.mu.Lock()
+	_, isImplicitDir := fs.implicitDirInodes[oldDir.Name()]
+	fs.mu.Unlock()
+	oldParent.Lock()
+	err = oldParent.DeleteChildDir(ctx, oldName, isImplicitDir, oldDir)
+	oldParent.Unlock()
+	if err != nil {
+		return fmt.Errorf("DeleteChildDir: %w", err)
+	}
+
+	return nil
+}
+
+func (fs *fileSystem) releaseInodes(inodes *[]inode.DirInode) {
+	for _, in := range *inodes {
+		fs.unlockAndDecrementLookupCount(in, 1)
+	}
+	*inodes = []inode.DirInode{}
+}
+
+func (fs *fileSystem) getBucketDirInode(ctx context.Context, parent inode.DirInode, name string) (inode.BucketOwnedDirInode, error) {
+	dir, err := fs.lookUpOrCreateChildDirInode(ctx, parent, name)
+	if err != nil {
+		return nil, fmt.Errorf("lookup directory: %w", err)
+	}
+	return dir, nil
+}
+
+// flushFilesInDirectory writes out every file inode beneath dir, including
+// local files, so that renaming the objects under dir carries along what has
+// been written to them. It returns the inodes, which have to follow the rename
+// through renameFilesInDirectory. It locks each file in turn, so it must be
+// called before the directories taking part in the rename are locked.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) flushFilesInDirectory(ctx context.Context, dir inode.Name) ([]*inode.FileInode, error) {
+	var files []*inode.FileInode
+	fs.mu.Lock()
+	for name, in := range fs.localFileInodes {
+		if f, ok := in.(*inode.FileInode); ok && name.IsDescendantOf(dir) {
+			files = append(files, f)
+		}
+	}
+	for name, in := range fs.generationBackedInodes {
+		if f, ok := in.(*inode.FileInode); ok && name.IsDescendantOf(dir) {
+			files = append(files, f)
+		}
+	}
+	fs.mu.Unlock()
+
+	for _, f := range files {
+		f.Lock()
+		err := fs.flushFile(ctx, f)
+		f.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("flush %q: %w", f.Name(), err)
+		}
+	}
+
+	return files, nil
+}
+
+// renameFilesInDirectory points the file inodes returned by
+// flushFilesInDirectory at their objects under newDir, now that oldDir has
+// been renamed to it, so that open handles keep working. Like
+// flushFilesInDirectory, it must be called once the directories have been
+// unlocked.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) renameFilesInDirectory(ctx context.Context, files []*inode.FileInode, oldDir, newDir inode.Name) {
+	for _, f := range files {
+		fs.renameFileInDirectory(ctx, f, oldDir, newDir)
+	}
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(f)
+func (fs *fileSystem) renameFileInDirectory(ctx context.Context, f *inode.FileInode, oldDir, newDir inode.Name) {
+	f.Lock()
+	defer f.Unlock()
+
+	oldName := f.Name()
+	if f.IsUnlinked() || !oldName.IsDescendantOf(oldDir) {
+		return
+	}
+
+	newName := oldName.Rebase(oldDir, newDir)
+	m, _, err := f.Bucket().StatObject(ctx, &gcs.StatObjectRequest{Name: newName.GcsObjectName()})
+	if err != nil {
+		logger.Warnf("Inode for %q stays at its old name: StatObject(%q): %v", oldName, newName, err)
+		return
+	}
+
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	// Inodes that have been replaced by newer generations are anonymous, and
+	// are left alone.
+	if fs.generationBackedInodes[oldName] != f {
+		return
+	}
+	delete(fs.generationBackedInodes, oldName)
+	f.Rename(newName, m)
+	fs.generationBackedInodes[newName] = f
+}
+
+func (fs *fileSystem) renameJournal(bucket gcs.Bucket) *renamejournal.Journal {
+	return renamejournal.New(bucket, renamejournal.Config{
+		LocalDir:          fs.renameJournalDir,
+		Parallelism:       fs.renameDirParallelism,
+		HeartbeatInterval: renamejournal.DefaultHeartbeatInterval,
+		StaleAfter:        renamejournal.DefaultStaleAfter,
+		Clock:             clock.RealClock{},
+	})
+}
+
+// moveDescendantsWithJournal moves the descendants of oldDir to the empty
+// newDir for renameNonHierarchicalDir, in parallel, after recording the
+// rename in the journal. A move that fails is rolled back right away; one
+// that is interrupted is recovered by a later mount. The caller ends the
+// returned intent once the backing object of oldDir has been deleted.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_REQUIRED(oldDir)
+// LOCKS_REQUIRED(newDir)
+func (fs *fileSystem) moveDescendantsWithJournal(
+	ctx context.Context,
+	journal *renamejournal.Journal,
+	oldDir, newDir inode.BucketOwnedDirInode,
+	descendants map[inode.Name]*inode.Core,
+	newDirCreated bool) (*renamejournal.Intent, error) {
+	fs.mu.Lock()
+	_, isImplicitDir := fs.implicitDirInodes[oldDir.Name()]
+	fs.mu.Unlock()
+
+	oldPrefix := oldDir.Name().GcsObjectName()
+	objects := make([]*gcs.MinObject, 0, len(descendants))
+	for _, descendant := range descendants {
+		objects = append(objects, descendant.MinObject)
+	}
+
+	intent, err := journal.Begin(ctx, oldPrefix, newDir.Name().GcsObjectName(), !isImplicitDir, newDirCreated, objects)
+	if err != nil {
+		return nil, fmt.Errorf("record rename: %w", err)
+	}
+
+	// Objects bypass the directory inodes on their way, so the type caches of
+	// both are brought up to date afterwards, whether or not the move went
+	// through.
+	defer func() {
+		for _, o := range objects {
+			child, _, _ := strings.Cut(strings.TrimPrefix(o.Name, oldPrefix), "/")
+			oldDir.EraseFromTypeCache(child)
+			newDir.EraseFromTypeCache(child)
+		}
+	}()
+
+	if err = journal.Run(ctx, intent); err != nil {
+		if rollbackErr := journal.Rollback(ctx, intent); rollbackErr != nil {
+			logger.Errorf("Rolling back rename of %q to %q: %v", oldDir.Name(), newDir.Name(), rollbackErr)
+		}
+		return nil, fmt.Errorf("move descendants of %q: %w", oldDir.Name(), err)
+	}
+
+	for _, o := range objects {
+		if err = fs.invalidateChildFileCacheIfExist(oldDir, o.Name); err != nil {
+			return nil, fmt.Errorf("unlink: while invalidating cache for delete file: %w", err)
+		}
+	}
+
+	return intent, nil
+}
+
+func (fs *fileSystem) checkDirNotEmpty(dir inode.BucketOwnedDirInode, name string) error {
+	unexpected, err := dir.ReadDescendants(context.Background(), 1)
+	if err != nil {
+		return fmt.Errorf("read descendants of the new directory %q: %w", name, err)
+	}
+
+	if len(unexpected) > 0 {
+		return fuse.ENOTEMPTY
+	}
+	return nil
+}
+
+// Rename an old folder to a new folder in a hierarchical bucket. If the new folder already
+// exists and is non-empty, return ENOTEMPTY. Open files in the old folder are flushed
+// first and renamed along with it.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(oldParent)
+// LOCKS_EXCLUDED(newParent)
+func (fs *fileSystem) renameHierarchicalDir(ctx context.Context, oldParent inode.DirInode, oldName string, newParent inode.DirInode, newName string) (err error) {
+	// Set up a function that throws away the lookup count increment from
+	// lookUpOrCreateChildInode (since the pending inodes are not sent back to
+	// the kernel) and unlocks the pending inodes, but only once.
+	var pendingInodes []inode.DirInode
+	defer fs.releaseInodes(&pendingInodes)
+
+	oldDirName := inode.NewDirName(oldParent.Name(), oldName)
+	newDirName := inode.NewDirName(newParent.Name(), newName)
+
+	openFiles, err := fs.flushFilesInDirectory(ctx, oldDirName)
+	if err != nil {
+		return err
+	}
+
+	oldDirInode, err := fs.getBucketDirInode(ctx, oldParent, oldName)
+	if err != nil {
+		return err
+	}
+	pendingInodes = append(pendingInodes, oldDirInode)
+
+	// If the call for getBucketDirInode fails it means directory does not exist.
+	newDirInode, err := fs.getBucketDirInode(ctx, newParent, newName)
+	if err == nil {
+		// If the directory exists, then check if it is empty or not.
+		if err = fs.checkDirNotEmpty(newDirInode, newName); err != nil {
+			return err
+		}
 
 		// This refers to an empty destination directory.
 		// The RenameFolder API does not allow renaming to an existing empty directory.
@@ -2714,24 +3921,33 @@ func (fs *fileSystem) renameHierarchicalDir(ctx context.Context, oldParent inode
 
 	// Note:The renameDirLimit is not utilized in the folder rename operation because there is no user-defined limit on new renames.
 	oldParent.Lock()
-	defer oldParent.Unlock()
-
 	if newParent != oldParent {
 		newParent.Lock()
-		defer newParent.Unlock()
 	}
 
 	// Rename old directory to the new directory, keeping both parent directories locked.
 	_, err = oldParent.RenameFolder(ctx, oldDirName.GcsObjectName(), newDirName.GcsObjectName(), oldDirInode)
+	if err == nil {
+		// The new parent has a child it hasn't been told of.
+		newParent.InvalidateKernelListCache()
+	}
+
+	if newParent != oldParent {
+		newParent.Unlock()
+	}
+	oldParent.Unlock()
+	fs.releaseInodes(&pendingInodes)
 	if err != nil {
 		return fmt.Errorf("failed to rename folder: %w", err)
 	}
 
+	fs.renameFilesInDirectory(ctx, openFiles, oldDirName, newDirName)
 	return
 }
 
 // Rename an old directory to a new directory in a non-hierarchical bucket. If the new directory already
-// exists and is non-empty, return ENOTEMPTY.
+// exists and is non-empty, return ENOTEMPTY. Open files in the old directory are flushed
+// first and renamed along with it.
 //
 // LOCKS_EXCLUDED(fs.mu)
 // LOCKS_EXCLUDED(oldParent)
@@ -2749,15 +3965,16 @@ func (fs *fileSystem) renameNonHierarchicalDir(
 	var pendingInodes []inode.DirInode
 	defer fs.releaseInodes(&pendingInodes)
 
-	oldDir, err := fs.getBucketDirInode(ctx, oldParent, oldName)
+	openFiles, err := fs.flushFilesInDirectory(ctx, inode.NewDirName(oldParent.Name(), oldName))
 	if err != nil {
 		return err
 	}
-	pendingInodes = append(pendingInodes, oldDir)
 
-	if err = fs.ensureNoLocalFilesInDirectory(oldDir, oldName); err != nil {
+	oldDir, err := fs.getBucketDirInode(ctx, oldParent, oldName)
+	if err != nil {
 		return err
 	}
+	pendingInodes = append(pendingInodes, oldDir)
 
 	// Fetch all the descendants of the old directory recursively
 	descendants, err := oldDir.ReadDescendants(ctx, int(fs.renameDirLimit+1))
@@ -2772,6 +3989,7 @@ func (fs *fileSystem) renameNonHierarchicalDir(
 	newParent.Lock()
 	_, err = newParent.CreateChildDir(ctx, newName)
 	newParent.Unlock()
+	newDirCreated := err == nil
 	if err != nil {
 		var preconditionErr *gcs.PreconditionError
 		if errors.As(err, &preconditionErr) {
@@ -2792,42 +4010,51 @@ func (fs *fileSystem) renameNonHierarchicalDir(
 		return err
 	}
 
-	// Move all the files from the old directory to the new directory, keeping both directories locked.
-	for _, descendant := range descendants {
-		nameDiff := strings.TrimPrefix(descendant.FullName.GcsObjectName(), oldDir.Name().GcsObjectName())
-		if nameDiff == descendant.FullName.GcsObjectName() {
-			return fmt.Errorf("unwanted descendant %q not from dir %q", descendant.FullName, oldDir.Name())
+	var journal *renamejournal.Journal
+	var intent *renamejournal.Intent
+	if fs.renameJournalDir != "" {
+		journal = fs.renameJournal(oldDir.Bucket())
+		if intent, err = fs.moveDescendantsWithJournal(ctx, journal, oldDir, newDir, descendants, newDirCreated); err != nil {
+			return err
 		}
-
-		o := descendant.MinObject
-		// Use copy-delete if atomic rename is disabled, or if the object is a directory or of unknown type.
-		// Otherwise, for files with atomic rename enabled, use move.
-		isDirOrUnknown := descendant.Type() == metadata.ExplicitDirType || descendant.Type() == metadata.UnknownType
-		if !fs.enableAtomicRenameObject || isDirOrUnknown {
-			if _, err = newDir.CloneToChildFile(ctx, nameDiff, o); err != nil {
-				return fmt.Errorf("copy file %q: %w", o.Name, err)
-			}
-			if err = oldDir.DeleteChildFile(ctx, nameDiff, o.Generation, &o.MetaGeneration); err != nil {
-				return fmt.Errorf("delete file %q: %w", o.Name, err)
+	} else {
+		// Move all the files from the old directory to the new directory, keeping both directories locked.
+		for _, descendant := range descendants {
+			nameDiff := strings.TrimPrefix(descendant.FullName.GcsObjectName(), oldDir.Name().GcsObjectName())
+			if nameDiff == descendant.FullName.GcsObjectName() {
+				return fmt.Errorf("unwanted descendant %q not from dir %q", descendant.FullName, oldDir.Name())
 			}
-		} else {
-			// For regular files, perform an in-place rename by constructing the new GCS object name.
-			// Standard path.Join is avoided here because object names in GCS are distinct from
-			// directory prefixes; the "/" character is *always* treated as a separate directory
-			// element, not part of the object's base name. This manual approach correctly
-			// handles those GCS naming edge cases (like objects with unsupported characters).
-			newObject := newDir.Name().GcsObjectName() + nameDiff
-			if _, err = oldDir.RenameFile(ctx, o, newObject); err != nil {
-				return fmt.Errorf("renameFile: while renaming file: %w", err)
+
+			o := descendant.MinObject
+			// Use copy-delete if atomic rename is disabled, or if the object is a directory or of unknown type.
+			// Otherwise, for files with atomic rename enabled, use move.
+			isDirOrUnknown := descendant.Type() == metadata.ExplicitDirType || descendant.Type() == metadata.UnknownType
+			if !fs.enableAtomicRenameObject || isDirOrUnknown {
+				if _, err = newDir.CloneToChildFile(ctx, nameDiff, o); err != nil {
+					return fmt.Errorf("copy file %q: %w", o.Name, err)
+				}
+				if err = oldDir.DeleteChildFile(ctx, nameDiff, o.Generation, &o.MetaGeneration); err != nil {
+					return fmt.Errorf("delete file %q: %w", o.Name, err)
+				}
+			} else {
+				// For regular files, perform an in-place rename by constructing the new GCS object name.
+				// Standard path.Join is avoided here because object names in GCS are distinct from
+				// directory prefixes; the "/" character is *always* treated as a separate directory
+				// element, not part of the object's base name. This manual approach correctly
+				// handles those GCS naming edge cases (like objects with unsupported characters).
+				newObject := newDir.Name().GcsObjectName() + nameDiff
+				if _, err = oldDir.RenameFile(ctx, o, newObject); err != nil {
+					return fmt.Errorf("renameFile: while renaming file: %w", err)
+				}
 			}
-		}
 
-		if err = fs.invalidateChildFileCacheIfExist(oldDir, o.Name); err != nil {
-			return fmt.Errorf("unlink: while invalidating cache for delete file: %w", err)
+			if err = fs.invalidateChildFileCacheIfExist(oldDir, o.Name); err != nil {
+				return fmt.Errorf("unlink: while invalidating cache for delete file: %w", err)
+			}
 		}
 	}
-
 	fs.releaseInodes(&pendingInodes)
+	fs.renameFilesInDirectory(ctx, openFiles, oldDir.Name(), newDir.Name())
 
 	// Delete the backing object of the old directory.
 	fs.mu.Lock()
@@ -2840,6 +4067,9 @@ func (fs *fileSystem) renameNonHierarchicalDir(
 		return fmt.Errorf("DeleteChildDir: %w", err)
 	}
 
+	if intent != nil {
+		return journal.End(ctx, intent)
+	}
 	return nil
 }
 
@@ -2847,13 +4077,31 @@ func (fs *fileSystem) renameNonHierarchicalDir(
 func (fs *fileSystem) Unlink(
 	ctx context.Context,
 	op *fuseops.UnlinkOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 
 	fs.mu.Lock()
-
-	// Find the parent and file name.
 	parent := fs.dirInodeOrDie(op.Parent)
-	fileName := inode.NewFileName(parent.Name(), op.Name)
+	fs.mu.Unlock()
+
+	name, err := fs.resolveChildName(ctx, parent, op.Name)
+	if err != nil {
+		return err
+	}
+	if err = fs.pathRules.CheckDelete(childRulePath(parent, name)); err != nil {
+		return err
+	}
+	if err = fs.checkAccess(ctx, op.OpContext, op.Parent, perms.Write|perms.Exec); err != nil {
+		return err
+	}
+
+	fs.mu.Lock()
+
+	// Find the file name.
+	fileName := inode.NewFileName(parent.Name(), name)
 
 	// Get the inode for the given file.
 	// Files must have an associated inode, which can be found in either:
@@ -2885,17 +4133,13 @@ func (fs *fileSystem) Unlink(
 	parent.Lock()
 	defer parent.Unlock()
 
-	err = parent.DeleteChildFile(
-		ctx,
-		op.Name,
-		0,   // Latest generation
-		nil) // No meta-generation precondition
+	err = parent.TrashChildFile(ctx, name)
 
 	var preconditionErr *gcs.PreconditionError
 	// Do not invalidate the file cache in case of error while deleting file
 	// which is not precondition error.
 	if err != nil && !errors.As(err, &preconditionErr) {
-		return fmt.Errorf("DeleteChildFile: %w", err)
+		return fmt.Errorf("TrashChildFile: %w", err)
 	}
 
 	// In case of successful delete or precondition error, we should invalidate
@@ -2913,6 +4157,10 @@ func (fs *fileSystem) Unlink(
 func (fs *fileSystem) OpenDir(
 	ctx context.Context,
 	op *fuseops.OpenDirOp) (err error) {
+	if err = fs.checkAccess(ctx, op.OpContext, op.Inode, perms.Read); err != nil {
+		return err
+	}
+
 	fs.mu.Lock()
 
 	// Make sure the inode still exists and is a directory. If not, something has
@@ -2924,7 +4172,11 @@ func (fs *fileSystem) OpenDir(
 	handleID := fs.nextHandleID
 	fs.nextHandleID++
 
-	fs.handles[handleID] = handle.NewDirHandle(in, fs.implicitDirs)
+	var hide func(string) bool
+	if fs.pathRules != nil {
+		hide = func(name string) bool { return fs.pathRules.IsHidden(childRulePath(in, name)) }
+	}
+	fs.handles[handleID] = handle.NewDirHandle(in, fs.implicitDirs, hide)
 	op.Handle = handleID
 
 	fs.mu.Unlock()
@@ -3039,8 +4291,31 @@ func (fs *fileSystem) OpenFile(
 		op.UseDirectIO = true
 	}
 
+	if fs.readOnly && util.FileOpenMode(op.OpenFlags).AccessMode() != util.ReadOnly {
+		return syscall.EROFS
+	}
+
+	if err = fs.checkAccess(ctx, op.OpContext, op.Inode, openAccess(op.OpenFlags)); err != nil {
+		return err
+	}
+
 	fs.mu.Lock()
 
+	// Generations in a versions directory are read straight from the bucket, so
+	// the inode serves as the handle.
+	if v, ok := fs.inodes[op.Inode].(*inode.VersionFileInode); ok {
+		defer fs.mu.Unlock()
+		if util.FileOpenMode(op.OpenFlags).AccessMode() != util.ReadOnly {
+			return syscall.EROFS
+		}
+
+		op.Handle = fs.nextHandleID
+		fs.nextHandleID++
+		fs.handles[op.Handle] = v
+		op.KeepPageCache = true
+		return
+	}
+
 	// Find the inode.
 	in := fs.fileInodeOrDie(op.Inode)
 	// Follow lock ordering rules to get inode lock.
@@ -3090,9 +4365,18 @@ func (fs *fileSystem) ReadFile(
 
 	// Find the handle and lock it.
 	fs.mu.Lock()
-	fh := fs.handles[op.Handle].(*handle.FileHandle)
+	h := fs.handles[op.Handle]
 	fs.mu.Unlock()
 
+	if v, ok := h.(*inode.VersionFileInode); ok {
+		op.BytesRead, err = v.Read(ctx, op.Dst, op.Offset)
+		if err == io.EOF {
+			err = nil
+		}
+		return
+	}
+
+	fh := h.(*handle.FileHandle)
 	fh.Inode().Lock()
 	if fh.Inode().IsUsingBWH() {
 		// Flush/Sync Pending streaming writes and issue read within same inode lock.
@@ -3183,6 +4467,10 @@ func (fs *fileSystem) ReadSymlink(
 func (fs *fileSystem) WriteFile(
 	ctx context.Context,
 	op *fuseops.WriteFileOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
 	ctx = fs.getInterruptlessContext(ctx)
 
 	// Find the inode( and file handle in case of appends).
@@ -3194,6 +4482,9 @@ func (fs *fileSystem) WriteFile(
 	var gcsSynced bool
 	in.Lock()
 	defer in.Unlock()
+	if err = fs.checkModify(in, true); err != nil {
+		return err
+	}
 	if err = fs.initBufferedWriteHandlerAndSyncFileIfEligible(ctx, in, fh.OpenMode()); err != nil {
 		// A FileClobberedError on write indicates the file was modified in GCS,
 		// making the kernel's dentry stale. By invalidating the cache
@@ -3209,6 +4500,16 @@ func (fs *fileSystem) WriteFile(
 		}
 		return err
 	}
+	if fs.writeQuota != nil {
+		if err = fs.writeQuota.ChargeWrite(op.Inode, in.Name().LocalName(), int64(len(op.Data))); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				fs.writeQuota.RefundWrite(in.Name().LocalName(), int64(len(op.Data)))
+			}
+		}()
+	}
 	if fs.newConfig.Write.EnableRapidAppends {
 		// Serve the request via the file handle.
 		gcsSynced, err = fh.Write(ctx, op.Data, op.Offset)
@@ -3227,6 +4528,70 @@ func (fs *fileSystem) WriteFile(
 	return
 }
 
+// Fallocate supports allocating space, with or without FALLOC_FL_KEEP_SIZE.
+// Modes that would rewrite existing contents, such as punching holes or
+// collapsing ranges, return EOPNOTSUPP.
+//
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) Fallocate(
+	ctx context.Context,
+	op *fuseops.FallocateOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
+	ctx = fs.getInterruptlessContext(ctx)
+	if op.Mode&^unix.FALLOC_FL_KEEP_SIZE != 0 {
+		return syscall.EOPNOTSUPP
+	}
+
+	// Find the inode and file handle.
+	fs.mu.Lock()
+	fh := fs.handles[op.Handle].(*handle.FileHandle)
+	in := fs.fileInodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+	if err = fs.checkModify(in, true); err != nil {
+		return
+	}
+	if err = fs.initBufferedWriteHandlerAndSyncFileIfEligible(ctx, in, fh.OpenMode()); err != nil {
+		return
+	}
+
+	keepSize := op.Mode&unix.FALLOC_FL_KEEP_SIZE != 0
+	if fs.writeQuota != nil && !keepSize {
+		// Extending the file writes zeroes past its end, so charge them as a
+		// write would be.
+		var attrs fuseops.InodeAttributes
+		if attrs, err = in.Attributes(ctx, false); err != nil {
+			return
+		}
+		if n := int64(op.Offset+op.Length) - int64(attrs.Size); n > 0 {
+			if err = fs.writeQuota.ChargeExtend(in.Name().LocalName(), n); err != nil {
+				return
+			}
+			defer func() {
+				if err != nil {
+					fs.writeQuota.RefundWrite(in.Name().LocalName(), n)
+				}
+			}()
+		}
+	}
+
+	gcsSynced, err := in.Fallocate(ctx, int64(op.Offset), int64(op.Length), keepSize)
+	// Sync the inode if finalize during fallocate is successful
+	// even if the fallocate operation later resulted in error.
+	if gcsSynced {
+		fs.promoteToGenerationBacked(in)
+	}
+	if err != nil {
+		err = fmt.Errorf("fallocate: %w", err)
+	}
+	return
+}
+
 // LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) SyncFile(
 	ctx context.Context,
@@ -3251,7 +4616,20 @@ func (fs *fileSystem) SyncFile(
 		return err
 	}
 
-	return
+	return fs.refusedWrites(file)
+}
+
+// refusedWrites returns an error wrapping EDQUOT if the write quota refused
+// writes to the supplied file since this was last called for it. With
+// writeback caching the kernel sends writes on after write(2) has returned,
+// so this is how the application learns about them.
+//
+// LOCKS_REQUIRED(f)
+func (fs *fileSystem) refusedWrites(f *inode.FileInode) error {
+	if fs.writeQuota != nil && fs.writeQuota.Refused(f.ID()) {
+		return fmt.Errorf("writes to %q exceeded the write quota: %w", f.Name(), syscall.EDQUOT)
+	}
+	return nil
 }
 
 // LOCKS_EXCLUDED(fs.mu)
@@ -3259,8 +4637,12 @@ func (fs *fileSystem) FlushFile(
 	ctx context.Context,
 	op *fuseops.FlushFileOp) (err error) {
 	ctx = fs.getInterruptlessContext(ctx)
-	// Find the inode.
+	// Find the inode. Generations in versions directories have nothing to flush.
 	fs.mu.Lock()
+	if _, ok := fs.inodes[op.Inode].(*inode.VersionFileInode); ok {
+		fs.mu.Unlock()
+		return
+	}
 	in := fs.fileInodeOrDie(op.Inode)
 	fs.mu.Unlock()
 
@@ -3272,7 +4654,7 @@ func (fs *fileSystem) FlushFile(
 		return err
 	}
 
-	return
+	return fs.refusedWrites(in)
 }
 
 // LOCKS_EXCLUDED(fs.mu)
@@ -3281,12 +4663,18 @@ func (fs *fileSystem) ReleaseFileHandle(
 	op *fuseops.ReleaseFileHandleOp) (err error) {
 	fs.mu.Lock()
 
-	fileHandle := fs.handles[op.Handle].(*handle.FileHandle)
+	h := fs.handles[op.Handle]
 	// Update the map. We are okay updating the map before destroy is called
 	// since destroy is doing only internal cleanup.
 	delete(fs.handles, op.Handle)
 	fs.mu.Unlock()
 
+	fileHandle, ok := h.(*handle.FileHandle)
+	if !ok {
+		// A generation in a versions directory holds nothing to release.
+		return
+	}
+
 	// Destroy the handle.
 	fileHandle.Lock()
 	defer fileHandle.Unlock()
@@ -3295,20 +4683,222 @@ func (fs *fileSystem) ReleaseFileHandle(
 	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) GetXattr(
 	ctx context.Context,
 	op *fuseops.GetXattrOp) (err error) {
-	return syscall.ENOSYS
+	if !fs.newConfig.FileSystem.ExperimentalEnableXattrs {
+		return syscall.ENOSYS
+	}
+
+	ctx = fs.getInterruptlessContext(ctx)
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+
+	// Only files carry extended attributes.
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return fuse.ENOATTR
+	}
+
+	value, err := file.Xattr(ctx, op.Name)
+	if err != nil {
+		return err
+	}
+
+	// An empty buffer is a request for the size of the value.
+	op.BytesRead = len(value)
+	if len(op.Dst) == 0 {
+		return
+	}
+	if len(op.Dst) < len(value) {
+		return syscall.ERANGE
+	}
+	copy(op.Dst, value)
+
+	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) ListXattr(
 	ctx context.Context,
 	op *fuseops.ListXattrOp) error {
-	return syscall.ENOSYS
+	if !fs.newConfig.FileSystem.ExperimentalEnableXattrs {
+		return syscall.ENOSYS
+	}
+
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+
+	var names []string
+	if file, ok := in.(*inode.FileInode); ok {
+		names = file.ListXattrs()
+	}
+
+	// The result is a sequence of NUL-terminated names.
+	var buf []byte
+	for _, name := range names {
+		buf = append(buf, name...)
+		buf = append(buf, 0)
+	}
+
+	op.BytesRead = len(buf)
+	if len(op.Dst) == 0 {
+		return nil
+	}
+	if len(op.Dst) < len(buf) {
+		return syscall.ERANGE
+	}
+	copy(op.Dst, buf)
+
+	return nil
+}
+
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) SetXattr(
+	ctx context.Context,
+	op *fuseops.SetXattrOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
+	if !fs.newConfig.FileSystem.ExperimentalEnableXattrs {
+		return syscall.ENOSYS
+	}
+
+	if err = fs.checkAccess(ctx, op.OpContext, op.Inode, perms.Write); err != nil {
+		return err
+	}
+
+	ctx = fs.getInterruptlessContext(ctx)
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return syscall.ENOTSUP
+	}
+
+	if err = fs.checkModify(file, false); err != nil {
+		return
+	}
+
+	// Errors are returned as is, since most are errnos meant for the caller.
+	err = file.SetXattr(ctx, op.Name, op.Value, op.Flags)
+	return
 }
 
+// LOCKS_EXCLUDED(fs.mu)
+func (fs *fileSystem) RemoveXattr(
+	ctx context.Context,
+	op *fuseops.RemoveXattrOp) (err error) {
+	if fs.readOnly {
+		return syscall.EROFS
+	}
+
+	if !fs.newConfig.FileSystem.ExperimentalEnableXattrs {
+		return syscall.ENOSYS
+	}
+
+	if err = fs.checkAccess(ctx, op.OpContext, op.Inode, perms.Write); err != nil {
+		return err
+	}
+
+	ctx = fs.getInterruptlessContext(ctx)
+	// Find the inode.
+	fs.mu.Lock()
+	in := fs.inodeOrDie(op.Inode)
+	fs.mu.Unlock()
+
+	in.Lock()
+	defer in.Unlock()
+
+	file, ok := in.(*inode.FileInode)
+	if !ok {
+		return fuse.ENOATTR
+	}
+
+	if err = fs.checkModify(file, false); err != nil {
+		return
+	}
+
+	err = file.RemoveXattr(ctx, op.Name)
+	return
+}
+
+// syncFSParallelism bounds the number of files SyncFS writes out at a time.
+const syncFSParallelism = 16
+
+// SyncFS writes out every dirty file in the mount, as fsync(2) on each of
+// them would. All files are attempted even if some fail, and the first error
+// is returned.
+//
+// The kernel only sends this op to virtiofs mounts, so syncfs(2) and sync -f
+// on a mount made through /dev/fuse return without reaching it.
+//
+// LOCKS_EXCLUDED(fs.mu)
 func (fs *fileSystem) SyncFS(
 	ctx context.Context,
 	op *fuseops.SyncFSOp) error {
-	return syscall.ENOSYS
+	ctx = fs.getInterruptlessContext(ctx)
+
+	fs.mu.Lock()
+	var files []*inode.FileInode
+	for _, in := range fs.inodes {
+		if f, ok := in.(*inode.FileInode); ok {
+			files = append(files, f)
+		}
+	}
+	fs.mu.Unlock()
+
+	var group errgroup.Group
+	group.SetLimit(syncFSParallelism)
+	for _, f := range files {
+		group.Go(func() error {
+			return fs.syncDirtyFile(ctx, f)
+		})
+	}
+
+	return group.Wait()
+}
+
+// syncDirtyFile syncs f if it holds modifications that have not been written
+// to GCS yet, recording them in the SyncFS metrics.
+//
+// LOCKS_EXCLUDED(fs.mu)
+// LOCKS_EXCLUDED(f)
+func (fs *fileSystem) syncDirtyFile(ctx context.Context, f *inode.FileInode) error {
+	f.Lock()
+	defer f.Unlock()
+
+	dirty, size, err := f.DirtyBytes()
+	if err != nil {
+		return fmt.Errorf("DirtyBytes %q: %w", f.Name(), err)
+	}
+	if !dirty || f.IsUnlinked() {
+		return nil
+	}
+
+	if err := fs.syncFile(ctx, f); err != nil {
+		return err
+	}
+
+	fs.metricHandle.FsSyncFsFileCount(1)
+	fs.metricHandle.FsSyncFsBytesCount(size)
+	return nil
 }
@@ -373,14 +373,27 @@ type fakeBucketManager struct {
 	chunkRetryDeadlineSecs   int64
 	chunkTransferTimeoutSecs int64
 	tmpObjectPrefix          string
+	onlyDir                  string
 }
 
 func (bm *fakeBucketManager) ShutDown() {}
 
+func (bm *fakeBucketManager) ForBucketMount(m cfg.BucketMount) gcsx.BucketManager {
+	mounted := *bm
+	mounted.onlyDir = m.OnlyDir
+	return &mounted
+}
+
 func (bm *fakeBucketManager) SetUpBucket(
 	ctx context.Context,
 	name string, isMultibucketMount bool, _ metrics.MetricHandle) (sb gcsx.SyncerBucket, err error) {
 	bucket, ok := bm.buckets[name]
+	if ok && bm.onlyDir != "" {
+		bucket, err = gcsx.NewPrefixBucket(bm.onlyDir+"/", bucket)
+		if err != nil {
+			return
+		}
+	}
 	if ok {
 		sb = gcsx.NewSyncerBucket(
 			bm.appendThreshold,
@@ -68,6 +68,10 @@ func (bm *fakeBucketManagerWithMetrics) SetUpBucket(
 
 func (bm *fakeBucketManagerWithMetrics) ShutDown() {}
 
+func (bm *fakeBucketManagerWithMetrics) ForBucketMount(cfg.BucketMount) gcsx.BucketManager {
+	return bm
+}
+
 func createTestFileSystemWithMonitoredBucket(ctx context.Context, t *testing.T, params *serverConfigParams) (gcs.Bucket, fuseutil.FileSystem, metrics.MetricHandle, *metric.ManualReader) {
 	t.Helper()
 	origProvider := otel.GetMeterProvider()
@@ -16,6 +16,8 @@ package gcsfuse_errors
 
 import (
 	"fmt"
+	"sort"
+	"syscall"
 )
 
 // FileClobberedError represents a file clobbering scenario where a file was
@@ -32,3 +34,21 @@ func (fce *FileClobberedError) Error() string {
 func (fce *FileClobberedError) Unwrap() error {
 	return fce.Err
 }
+
+// NameConflictError is returned when a name that matches no child of a
+// directory exactly matches several of them loosely, e.g. ignoring case.
+type NameConflictError struct {
+	// The name that was looked up, and the names of the children it matches.
+	Name       string
+	Candidates []string
+}
+
+func (nce *NameConflictError) Error() string {
+	candidates := append([]string(nil), nce.Candidates...)
+	sort.Strings(candidates)
+	return fmt.Sprintf("%q is ambiguous: it matches each of %q", nce.Name, candidates)
+}
+
+func (nce *NameConflictError) Unwrap() error {
+	return syscall.ENOTUNIQ
+}
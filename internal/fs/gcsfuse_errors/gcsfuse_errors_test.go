@@ -17,6 +17,7 @@ package gcsfuse_errors
 import (
 	"errors"
 	"fmt"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -59,3 +60,13 @@ func TestFileClobberedError(t *testing.T) {
 		})
 	}
 }
+
+func TestNameConflictError(t *testing.T) {
+	conflictErr := &NameConflictError{
+		Name:       "foo",
+		Candidates: []string{"Foo", "FOO"},
+	}
+
+	assert.Equal(t, `"foo" is ambiguous: it matches each of ["FOO" "Foo"]`, conflictErr.Error())
+	assert.ErrorIs(t, conflictErr, syscall.ENOTUNIQ)
+}
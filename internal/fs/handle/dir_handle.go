@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
@@ -61,6 +62,10 @@ type DirHandle struct {
 	in           inode.DirInode
 	implicitDirs bool
 
+	// Reports whether the entry with the supplied name is left out of
+	// listings. Nil if none are.
+	hide func(name string) bool
+
 	/////////////////////////
 	// Mutable state
 	/////////////////////////
@@ -96,14 +101,18 @@ type DirHandle struct {
 	entriesPlusValid bool
 }
 
-// NewDirHandle creates a directory handle that obtains listings from the supplied inode.
+// NewDirHandle creates a directory handle that obtains listings from the
+// supplied inode, leaving out the entries for which hide, if non-nil, returns
+// true.
 func NewDirHandle(
 	in inode.DirInode,
-	implicitDirs bool) (dh *DirHandle) {
+	implicitDirs bool,
+	hide func(name string) bool) (dh *DirHandle) {
 	// Set up the basic struct.
 	dh = &DirHandle{
 		in:           in,
 		implicitDirs: implicitDirs,
+		hide:         hide,
 	}
 
 	// Set up invariant checking.
@@ -220,12 +229,12 @@ func fixConflictingNames[T DirEntry](entries []T, localEntries map[string]T) (ou
 }
 
 // sortAndResolveEntries is a generic helper function that takes a list of
-// directory entries, sorts them, resolves name conflicts, and sets their
-// offsets.
+// directory entries, sorts them, resolves name conflicts, drops those for
+// which hide, if non-nil, returns true, and sets their offsets.
 //
 // This generic function supports both fuseutil.Dirent and fuseutil.DirentPlus
 // by wrapping/ unwrapping them into DirEntry interface-compatible types.
-func sortAndResolveEntries[Entry any, WrappedEntry DirEntry](entries []Entry, localEntries map[string]Entry, wrap func(Entry) WrappedEntry, unwrap func(WrappedEntry) Entry) ([]Entry, error) {
+func sortAndResolveEntries[Entry any, WrappedEntry DirEntry](entries []Entry, localEntries map[string]Entry, hide func(string) bool, wrap func(Entry) WrappedEntry, unwrap func(WrappedEntry) Entry) ([]Entry, error) {
 	// Wrap and append local file entry (not synced to GCS).
 	wrappedLocalEntries := make(map[string]WrappedEntry)
 	for name, localEntry := range localEntries {
@@ -254,8 +263,11 @@ func sortAndResolveEntries[Entry any, WrappedEntry DirEntry](entries []Entry, lo
 
 	// Fix up offset fields and unwrap.
 	finalEntries := make([]Entry, 0, len(fixedEntries))
-	for i, fe := range fixedEntries {
-		fe.SetOffset(fuseops.DirOffset(i) + 1)
+	for _, fe := range fixedEntries {
+		if hide != nil && hide(strings.TrimSuffix(fe.EntryName(), inode.ConflictingFileNameSuffix)) {
+			continue
+		}
+		fe.SetOffset(fuseops.DirOffset(len(finalEntries)) + 1)
 		finalEntries = append(finalEntries, unwrap(fe))
 	}
 
@@ -269,7 +281,8 @@ func sortAndResolveEntries[Entry any, WrappedEntry DirEntry](entries []Entry, lo
 func readAllEntries(
 	ctx context.Context,
 	in inode.DirInode,
-	localEntries map[string]fuseutil.Dirent) (entries []fuseutil.Dirent, err error) {
+	localEntries map[string]fuseutil.Dirent,
+	hide func(string) bool) (entries []fuseutil.Dirent, err error) {
 	// Read entries from GCS.
 	// Read one batch at a time.
 	var tok string
@@ -293,7 +306,7 @@ func readAllEntries(
 	}
 
 	// Sort, resolve conflicts, and set offsets.
-	entries, err = sortAndResolveEntries(entries, localEntries, func(e fuseutil.Dirent) *dirent { d := dirent(e); return &d }, func(w *dirent) fuseutil.Dirent { return fuseutil.Dirent(*w) })
+	entries, err = sortAndResolveEntries(entries, localEntries, hide, func(e fuseutil.Dirent) *dirent { d := dirent(e); return &d }, func(w *dirent) fuseutil.Dirent { return fuseutil.Dirent(*w) })
 	if err != nil {
 		return nil, err
 	}
@@ -353,7 +366,7 @@ func (dh *DirHandle) ensureEntries(ctx context.Context, localFileEntries map[str
 
 	// Read entries.
 	var entries []fuseutil.Dirent
-	entries, err = readAllEntries(ctx, dh.in, localFileEntries)
+	entries, err = readAllEntries(ctx, dh.in, localFileEntries, dh.hide)
 	if err != nil {
 		err = fmt.Errorf("readAllEntries: %w", err)
 		return
@@ -454,7 +467,7 @@ func (dh *DirHandle) ReadDirPlus(op *fuseops.ReadDirPlusOp, entries []fuseutil.D
 	// If entriesPlus has not been populated yet, populate it.
 	if !dh.entriesPlusValid {
 		// Sort, resolve conflicts, and set offsets.
-		entries, err = sortAndResolveEntries(entries, localEntries, func(e fuseutil.DirentPlus) *direntPlus { dp := direntPlus(e); return &dp }, func(w *direntPlus) fuseutil.DirentPlus { return fuseutil.DirentPlus(*w) })
+		entries, err = sortAndResolveEntries(entries, localEntries, dh.hide, func(e fuseutil.DirentPlus) *direntPlus { dp := direntPlus(e); return &dp }, func(w *direntPlus) fuseutil.DirentPlus { return fuseutil.DirentPlus(*w) })
 		if err != nil {
 			return
 		}
@@ -101,6 +101,7 @@ func (t *DirHandleTest) resetDirHandle() {
 	t.dh = NewDirHandle(
 		dirInode,
 		true,
+		nil,
 	)
 }
 
@@ -200,6 +201,29 @@ func (t *DirHandleTest) EnsureEntriesWithOnlyGCSFiles() {
 	t.validateEntry(t.dh.entries[1], "gcsObject2", fuseutil.DT_File)
 }
 
+func (t *DirHandleTest) EnsureEntriesLeavesOutHiddenEntries() {
+	var err error
+	// Set up empty GCS objects.
+	// DirHandle holds a DirInode pointing to "testDir".
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "testDir/gcsObject1", nil)
+	AssertEq(nil, err)
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "testDir/gcsObject2", nil)
+	AssertEq(nil, err)
+	localFileEntries := map[string]fuseutil.Dirent{
+		"localFile1": {Offset: 0, Inode: 10, Name: "localFile1", Type: fuseutil.DT_File},
+	}
+	t.dh.hide = func(name string) bool { return name == "gcsObject1" || name == "localFile1" }
+
+	// Ensure entries.
+	err = t.dh.ensureEntries(t.ctx, localFileEntries)
+
+	// Validations
+	AssertEq(nil, err)
+	AssertEq(1, len(t.dh.entries))
+	t.validateEntry(t.dh.entries[0], "gcsObject2", fuseutil.DT_File)
+	AssertEq(1, t.dh.entries[0].Offset)
+}
+
 func (t *DirHandleTest) EnsureEntriesWithOnlyLocalFiles() {
 	var err error
 	localFileName1 := "localFile1"
@@ -0,0 +1,132 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helpers shared by the tests that call the file system's methods directly,
+// rather than through a mount.
+
+// newTestFileSystem mounts bucket with the given file system config, without
+// going through the kernel.
+func newTestFileSystem(ctx context.Context, t *testing.T, bucket gcs.Bucket, fsConfig cfg.FileSystemConfig) fuseutil.FileSystem {
+	t.Helper()
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Write:      cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:       cfg.ReadConfig{GlobalMaxBlocks: 1},
+			FileSystem: fsConfig,
+		},
+		MetricHandle:   metrics.NewNoopMetrics(),
+		TraceHandle:    tracing.NewNoopTracer(),
+		CacheClock:     &timeutil.SimulatedClock{},
+		BucketName:     bucket.Name(),
+		BucketManager:  &fakeBucketManager{buckets: map[string]gcs.Bucket{bucket.Name(): bucket}},
+		FilePerms:      0644,
+		DirPerms:       0755,
+		RenameDirLimit: fsConfig.RenameDirLimit,
+	}
+	server, err := fs.NewFileSystem(ctx, serverCfg)
+	require.NoError(t, err, "NewFileSystem")
+	t.Cleanup(server.Destroy)
+	return server
+}
+
+func lookUp(ctx context.Context, t *testing.T, server fuseutil.FileSystem, parent fuseops.InodeID, name string) fuseops.InodeID {
+	t.Helper()
+	op := &fuseops.LookUpInodeOp{Parent: parent, Name: name}
+	require.NoError(t, server.LookUpInode(ctx, op))
+	return op.Entry.Child
+}
+
+func lookUpErr(ctx context.Context, server fuseutil.FileSystem, parent fuseops.InodeID, name string) error {
+	return server.LookUpInode(ctx, &fuseops.LookUpInodeOp{Parent: parent, Name: name})
+}
+
+// parseDirents decodes the fuse_dirent records written by
+// fuseutil.WriteDirent.
+func parseDirents(buf []byte) (entries []fuseutil.Dirent) {
+	const headerSize = 24
+	for len(buf) >= headerSize {
+		nameLen := int(binary.LittleEndian.Uint32(buf[16:]))
+		entries = append(entries, fuseutil.Dirent{
+			Inode:  fuseops.InodeID(binary.LittleEndian.Uint64(buf[0:])),
+			Offset: fuseops.DirOffset(binary.LittleEndian.Uint64(buf[8:])),
+			Type:   fuseutil.DirentType(binary.LittleEndian.Uint32(buf[20:])),
+			Name:   string(buf[headerSize : headerSize+nameLen]),
+		})
+		recordLen := (headerSize + nameLen + 7) &^ 7
+		buf = buf[min(recordLen, len(buf)):]
+	}
+
+	return
+}
+
+func readDirNames(ctx context.Context, t *testing.T, server fuseutil.FileSystem, dir fuseops.InodeID) (names []string) {
+	t.Helper()
+	openDirOp := &fuseops.OpenDirOp{Inode: dir}
+	require.NoError(t, server.OpenDir(ctx, openDirOp))
+	readDirOp := &fuseops.ReadDirOp{Inode: dir, Handle: openDirOp.Handle, Dst: make([]byte, 4096)}
+	require.NoError(t, server.ReadDir(ctx, readDirOp))
+	for _, e := range parseDirents(readDirOp.Dst[:readDirOp.BytesRead]) {
+		names = append(names, e.Name)
+	}
+	return
+}
+
+func createWithContents(ctx context.Context, t *testing.T, bucket gcs.Bucket, name string, contents string) {
+	err := storageutil.CreateObjects(ctx, bucket, map[string][]byte{name: []byte(contents)})
+	require.NoError(t, err, "CreateObjects")
+}
+
+func assertObjectMissing(ctx context.Context, t *testing.T, bucket gcs.Bucket, name string) {
+	t.Helper()
+	_, err := storageutil.ReadObject(ctx, bucket, name)
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr, name)
+}
+
+// newVersionedBucket returns a versioned bucket holding the supplied
+// generations of foo, along with their generation numbers.
+func newVersionedBucket(ctx context.Context, t *testing.T, contents ...string) (gcs.Bucket, []int64) {
+	t.Helper()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	fake.EnableVersioning(bucket)
+	var gens []int64
+	for _, c := range contents {
+		o, err := storageutil.CreateObject(ctx, bucket, "foo", []byte(c))
+		require.NoError(t, err)
+		gens = append(gens, o.Generation)
+	}
+
+	return bucket, gens
+}
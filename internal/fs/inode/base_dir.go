@@ -15,9 +15,11 @@
 package inode
 
 import (
+	"os"
 	"syscall"
 	"time"
 
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 
@@ -62,6 +64,11 @@ type baseDirInode struct {
 	// GUARDED_BY(mu)
 	buckets map[string]gcsx.SyncerBucket
 
+	// The entries of bucket-mounts by directory, if the buckets are mounted
+	// explicitly. Nil for dynamic mounts, in which any bucket can be looked up
+	// by name.
+	mounts map[string]cfg.BucketMount
+
 	metricHandle metrics.MetricHandle
 
 	isEnableTypeCacheDeprecation bool
@@ -92,6 +99,23 @@ func NewBaseDirInode(
 	return
 }
 
+// NewBucketMountsDirInode returns a baseDirInode that acts as the directory
+// of the supplied entries of bucket-mounts, and of no other buckets.
+func NewBucketMountsDirInode(
+	id fuseops.InodeID,
+	attrs fuseops.InodeAttributes,
+	bm gcsx.BucketManager,
+	mounts []cfg.BucketMount,
+	metricHandle metrics.MetricHandle,
+	isEnableTypeCacheDeprecation bool) DirInode {
+	d := NewBaseDirInode(id, NewRootName(""), attrs, bm, metricHandle, isEnableTypeCacheDeprecation).(*baseDirInode)
+	d.mounts = make(map[string]cfg.BucketMount, len(mounts))
+	for _, m := range mounts {
+		d.mounts[m.Dir] = m
+	}
+	return d
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Public interface
 ////////////////////////////////////////////////////////////////////////
@@ -168,16 +192,30 @@ func (d *baseDirInode) LookUpChild(ctx context.Context, name string) (*Core, err
 	var err error
 	bucket, ok := d.buckets[name]
 	if !ok {
-		bucket, err = d.bucketManager.SetUpBucket(ctx, name, true, d.metricHandle)
+		bm, bucketName := d.bucketManager, name
+		if d.mounts != nil {
+			m, ok := d.mounts[name]
+			if !ok {
+				return nil, fuse.ENOENT
+			}
+			bm, bucketName = d.bucketManager.ForBucketMount(m), m.Bucket
+		}
+		bucket, err = bm.SetUpBucket(ctx, bucketName, true, d.metricHandle)
 		if err != nil {
 			return nil, err
 		}
 		d.buckets[name] = bucket
 	}
 
+	// Explicitly mounted buckets are named after their directories, as a
+	// bucket may be mounted under another name.
+	rootName := NewRootName(bucket.Name())
+	if d.mounts != nil {
+		rootName = NewRootName(name)
+	}
 	return &Core{
 		Bucket:    &bucket,
-		FullName:  NewRootName(bucket.Name()),
+		FullName:  rootName,
 		MinObject: nil,
 	}, nil
 }
@@ -191,6 +229,13 @@ func (d *baseDirInode) ReadDescendants(ctx context.Context, limit int) (map[Name
 func (d *baseDirInode) ReadEntries(
 	ctx context.Context,
 	tok string) (entries []fuseutil.Dirent, unsupportedPaths []string, newTok string, err error) {
+	// Explicitly mounted buckets are listed without setting them up.
+	if d.mounts != nil {
+		for dir := range d.mounts {
+			entries = append(entries, fuseutil.Dirent{Name: dir, Type: fuseutil.DT_Directory})
+		}
+		return entries, nil, "", nil
+	}
 
 	// The subdirectories of the base directory should be all the accessible
 	// buckets. Although the user is allowed to visit each individual
@@ -201,6 +246,17 @@ func (d *baseDirInode) ReadEntries(
 
 // LOCKS_REQUIRED(d)
 func (d *baseDirInode) ReadEntryCores(ctx context.Context, tok string) (cores map[Name]*Core, unsupportedPaths []string, newTok string, err error) {
+	if d.mounts != nil {
+		cores = make(map[Name]*Core, len(d.mounts))
+		for dir := range d.mounts {
+			var c *Core
+			if c, err = d.LookUpChild(ctx, dir); err != nil {
+				return nil, nil, "", err
+			}
+			cores[c.FullName] = c
+		}
+		return cores, nil, "", nil
+	}
 
 	// The subdirectories of the base directory should be all the accessible
 	// buckets. Although the user is allowed to visit each individual
@@ -234,10 +290,18 @@ func (d *baseDirInode) CloneToChildFile(ctx context.Context, name string, src *g
 	return nil, fuse.ENOSYS
 }
 
+func (d *baseDirInode) CopyChildFileFromBucket(ctx context.Context, name string, srcBucket string, src *gcs.MinObject, dstGeneration int64) (*Core, error) {
+	return nil, fuse.ENOSYS
+}
+
 func (d *baseDirInode) CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error) {
 	return nil, fuse.ENOSYS
 }
 
+func (d *baseDirInode) CreateChildSpecialFile(ctx context.Context, name string, mode os.FileMode, rdev uint32) (*Core, error) {
+	return nil, fuse.ENOSYS
+}
+
 func (d *baseDirInode) CreateChildDir(ctx context.Context, name string) (*Core, error) {
 	return nil, fuse.ENOSYS
 }
@@ -260,6 +324,18 @@ func (d *baseDirInode) DeleteChildDir(
 	return
 }
 
+func (d *baseDirInode) TrashChildFile(ctx context.Context, name string) error {
+	return fuse.ENOSYS
+}
+
+func (d *baseDirInode) TrashChildDir(
+	ctx context.Context,
+	name string,
+	isImplicitDir bool,
+	dirInode DirInode) error {
+	return fuse.ENOSYS
+}
+
 func (d *baseDirInode) DeleteObjects(ctx context.Context, objectNames []string) error {
 	return fuse.ENOSYS
 }
@@ -21,6 +21,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
@@ -112,6 +113,10 @@ func (bm *fakeBucketManager) SetUpBucket(
 
 func (bm *fakeBucketManager) ShutDown() {}
 
+func (bm *fakeBucketManager) ForBucketMount(cfg.BucketMount) gcsx.BucketManager {
+	return bm
+}
+
 func (bm *fakeBucketManager) SetUpTimes() int {
 	return bm.setupTimes
 }
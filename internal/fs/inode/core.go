@@ -40,6 +40,10 @@ type Core struct {
 
 	// Specifies a local object which is not yet synced to GCS.
 	Local bool
+
+	// Specifies a versions directory, or one of the generations listed in it,
+	// whose MinObject is named after the versioned object rather than FullName.
+	Version bool
 }
 
 // Exists returns true iff the back object exists implicitly or explicitly.
@@ -57,6 +61,8 @@ func (c *Core) Type() metadata.Type {
 		return metadata.ExplicitDirType
 	case IsSymlink(c.MinObject):
 		return metadata.SymlinkType
+	case IsSpecialFile(c.MinObject):
+		return metadata.SpecialFileType
 	default:
 		return metadata.RegularFileType
 	}
@@ -69,7 +75,7 @@ func (c Core) SanityCheck() error {
 		return fmt.Errorf("inode name %q mismatches folder name %q", c.FullName, c.Folder.Name)
 	}
 
-	if c.MinObject != nil && c.FullName.objectName != c.MinObject.Name {
+	if c.MinObject != nil && !c.Version && c.FullName.objectName != c.MinObject.Name {
 		return fmt.Errorf("inode name %q mismatches object name %q", c.FullName, c.MinObject.Name)
 	}
 
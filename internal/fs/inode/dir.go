@@ -18,19 +18,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/cache/metadata"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/caching"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/trash"
 	"github.com/jacobsa/fuse/fuseops"
 	"github.com/jacobsa/fuse/fuseutil"
 	"github.com/jacobsa/timeutil"
@@ -67,6 +71,11 @@ type DirInode interface {
 	// named "foo/bar/baz" and this is the directory "foo", a child directory
 	// named "bar" will be implied. In this case, result.ImplicitDir will be
 	// true.
+	//
+	// If this inode was created with loose name matching and no child has
+	// exactly the given name, the child whose name matches it loosely is
+	// returned instead. If several do, a *gcsfuse_errors.NameConflictError
+	// naming them is returned.
 	LookUpChild(ctx context.Context, name string) (*Core, error)
 
 	// Rename the file.
@@ -125,12 +134,24 @@ type DirInode interface {
 	// Return the full name of the child and the GCS object it backs up.
 	CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error)
 
+	// Like CloneToChildFile, except that the source object lives in the bucket
+	// with the supplied name, and the clone fails with *gcs.PreconditionError
+	// unless the backing object in GCS is of generation dstGeneration, zero
+	// meaning that there is none.
+	CopyChildFileFromBucket(ctx context.Context, name string, srcBucket string, src *gcs.MinObject, dstGeneration int64) (*Core, error)
+
 	// Create a symlink object with the supplied (relative) name and the supplied
 	// target, failing with *gcs.PreconditionError if a backing object already
 	// exists in GCS.
 	// Return the full name of the child and the GCS object it backs up.
 	CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error)
 
+	// Create an empty object marking a named pipe, socket or device node with
+	// the supplied (relative) name, mode and device number, failing with
+	// *gcs.PreconditionError if a backing object already exists in GCS.
+	// Return the full name of the child and the GCS object it backs up.
+	CreateChildSpecialFile(ctx context.Context, name string, mode os.FileMode, rdev uint32) (*Core, error)
+
 	// Create a backing object for a child directory with the supplied (relative)
 	// name, failing with *gcs.PreconditionError if a backing object already
 	// exists in GCS.
@@ -157,6 +178,21 @@ type DirInode interface {
 		isImplicitDir bool,
 		dirInode DirInode) (err error)
 
+	// Move the backing object for the child file or symlink with the given
+	// (relative) name to the trash, if trash mode is on. Otherwise, or if the
+	// child is itself in the trash, this is DeleteChildFile for the latest
+	// generation.
+	TrashChildFile(ctx context.Context, name string) (err error)
+
+	// Like DeleteChildDir, but moves the object backing the child directory to
+	// the trash in trash mode. Hierarchical buckets have nothing to keep, as
+	// their folders can't be moved alone.
+	TrashChildDir(
+		ctx context.Context,
+		name string,
+		isImplicitDir bool,
+		dirInode DirInode) (err error)
+
 	// DeleteObjects recursively deletes the given objects and prefixes.
 	DeleteObjects(ctx context.Context, objectNames []string) error
 
@@ -276,6 +312,31 @@ type dirInode struct {
 
 	isUnsupportedPathSupportEnabled bool
 
+	enableVersionsDirs bool
+
+	// Unlinked children are moved under this prefix of the bucket, if set.
+	trashPrefix string
+
+	// How names that match no child exactly are matched.
+	nameMatching NameMatching
+
+	// The names of the children found by the last listing for a loose lookup,
+	// by their keys under nameMatching, so that loose lookups don't each list
+	// the directory. Like the type cache, the index expires after typeCacheTTL,
+	// and it is thrown away whenever a child is created or removed through the
+	// inode. Nil if there is none.
+	//
+	// GUARDED_BY(looseNamesMu)
+	looseNames map[string][]string
+
+	// GUARDED_BY(looseNamesMu)
+	looseNamesExpiry time.Time
+
+	// Loose lookups build the index holding d.mu only for reading.
+	looseNamesMu sync.Mutex
+
+	typeCacheTTL time.Duration
+
 	isEnableTypeCacheDeprecation bool
 
 	// Represents if folder has been unlinked in hierarchical bucket. This is not getting used in
@@ -349,11 +410,18 @@ func NewDirInode(
 		isHNSEnabled:                           cfg.EnableHns,
 		isStandardSymlinkRepresentationEnabled: cfg.EnableStandardSymlinks,
 		isUnsupportedPathSupportEnabled:        cfg.EnableUnsupportedPathSupport,
+		enableVersionsDirs:                     cfg.FileSystem.EnableVersionsDirs,
+		trashPrefix:                            cfg.FileSystem.TrashPrefix,
 		isEnableTypeCacheDeprecation:           cfg.EnableTypeCacheDeprecation,
 		unlinked:                               false,
 		ctx:                                    ctx,
 		cancel:                                 cancel,
 		metadataCacheTtlSecs:                   cfg.MetadataCache.TtlSecs,
+		nameMatching: NameMatching{
+			CaseInsensitive: cfg.FileSystem.CaseInsensitiveLookup,
+			Unicode:         cfg.FileSystem.UnicodeNormalizedLookup,
+		},
+		typeCacheTTL: typeCacheTTL,
 	}
 
 	// Init Prefetcher only if it is enabled, stat cache ttl != 0 and stat cache size != 0.
@@ -671,11 +739,111 @@ const ConflictingFileNameSuffix = "\n"
 
 // LOCKS_REQUIRED(d.mu.RLock)
 func (d *dirInode) LookUpChild(ctx context.Context, name string) (*Core, error) {
+	result, err := d.lookUpChildExactly(ctx, name)
+	if result != nil || err != nil || !d.nameMatching.Enabled() {
+		return result, err
+	}
+
+	return d.lookUpChildLoosely(ctx, name)
+}
+
+// lookUpChildLoosely looks up the child whose name matches the supplied one
+// under d.nameMatching, if there is exactly one.
+func (d *dirInode) lookUpChildLoosely(ctx context.Context, name string) (*Core, error) {
+	names, err := d.looseNameIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := names[d.nameMatching.Key(name)]
+	switch len(candidates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return d.lookUpChildExactly(ctx, candidates[0])
+	default:
+		return nil, &gcsfuse_errors.NameConflictError{Name: name, Candidates: candidates}
+	}
+}
+
+// looseNameIndex returns the names of the children of d by their keys under
+// d.nameMatching, listing the directory unless the last listing is fresh.
+func (d *dirInode) looseNameIndex(ctx context.Context) (map[string][]string, error) {
+	d.looseNamesMu.Lock()
+	defer d.looseNamesMu.Unlock()
+
+	now := d.cacheClock.Now()
+	if d.looseNames != nil && now.Before(d.looseNamesExpiry) {
+		return d.looseNames, nil
+	}
+
+	// A file and a directory with the same name are the same candidate, and
+	// are told apart as for an exact lookup.
+	dirName := d.Name().GcsObjectName()
+	names := make(map[string][]string)
+	seen := make(map[string]bool)
+	add := func(objectName string) {
+		base := strings.TrimSuffix(strings.TrimPrefix(objectName, dirName), "/")
+		if base == "" || seen[base] {
+			return
+		}
+		seen[base] = true
+		key := d.nameMatching.Key(base)
+		names[key] = append(names[key], base)
+	}
+
+	req := &gcs.ListObjectsRequest{
+		Prefix:                   dirName,
+		Delimiter:                "/",
+		IncludeTrailingDelimiter: true,
+		IncludeFoldersAsPrefixes: true,
+		MaxResults:               MaxResultsForListObjectsCall,
+		ProjectionVal:            gcs.NoAcl,
+	}
+	for {
+		listing, err := d.bucket.ListObjects(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("ListObjects: %w", err)
+		}
+		for _, o := range listing.MinObjects {
+			add(o.Name)
+		}
+		for _, p := range listing.CollapsedRuns {
+			add(p)
+		}
+		if listing.ContinuationToken == "" {
+			break
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+
+	if d.typeCacheTTL > 0 {
+		d.looseNames = names
+		d.looseNamesExpiry = now.Add(d.typeCacheTTL)
+	}
+	return names, nil
+}
+
+// forgetLooseNames throws away the index of the names of the children of d
+// under d.nameMatching, as its children have changed.
+func (d *dirInode) forgetLooseNames() {
+	d.looseNamesMu.Lock()
+	defer d.looseNamesMu.Unlock()
+	d.looseNames = nil
+}
+
+// lookUpChildExactly looks up the child with exactly the supplied name.
+func (d *dirInode) lookUpChildExactly(ctx context.Context, name string) (*Core, error) {
 	// Is this a conflict marker name?
 	if strings.HasSuffix(name, ConflictingFileNameSuffix) {
 		return d.lookUpConflicting(ctx, name)
 	}
 
+	// Is this the versions directory of a file?
+	if fileName, ok := strings.CutSuffix(name, VersionsDirSuffix); ok && fileName != "" && d.enableVersionsDirs {
+		return d.lookUpVersions(ctx, fileName)
+	}
+
 	cachedType := metadata.UnknownType
 
 	// 1. Optimization: If Type Cache is deprecated, attempt a lookup via the Stat Cache first.
@@ -774,7 +942,7 @@ func (d *dirInode) fetchCoreEntity(ctx context.Context, name string, cachedType
 		} else {
 			group.Go(lookUpExplicitDir)
 		}
-	case metadata.RegularFileType, metadata.SymlinkType:
+	case metadata.RegularFileType, metadata.SymlinkType, metadata.SpecialFileType:
 		group.Go(lookUpFile)
 
 	case metadata.NonexistentType:
@@ -1049,6 +1217,8 @@ func (d *dirInode) ReadEntries(
 			entry.Type = fuseutil.DT_Link
 		case metadata.RegularFileType:
 			entry.Type = fuseutil.DT_File
+		case metadata.SpecialFileType:
+			entry.Type = SpecialFileDirentType(core.MinObject)
 		case metadata.ImplicitDirType, metadata.ExplicitDirType:
 			entry.Type = fuseutil.DT_Directory
 		}
@@ -1087,6 +1257,7 @@ func (d *dirInode) CreateChildFile(ctx context.Context, name string) (*Core, err
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Insert(d.cacheClock.Now(), name, metadata.RegularFileType)
 	}
+	d.forgetLooseNames()
 	return &Core{
 		Bucket:    d.Bucket(),
 		FullName:  fullName,
@@ -1108,6 +1279,7 @@ func (d *dirInode) InsertFileIntoTypeCache(name string) {
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Insert(d.cacheClock.Now(), name, metadata.RegularFileType)
 	}
+	d.forgetLooseNames()
 }
 
 // LOCKS_REQUIRED(d)
@@ -1115,10 +1287,35 @@ func (d *dirInode) EraseFromTypeCache(name string) {
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Erase(name)
 	}
+	d.forgetLooseNames()
 }
 
 // LOCKS_REQUIRED(d)
 func (d *dirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error) {
+	// Clone over anything that might already exist for the name.
+	return d.copyToChildFile(ctx, name, &gcs.CopyObjectRequest{
+		SrcName:                       src.Name,
+		SrcGeneration:                 src.Generation,
+		SrcMetaGenerationPrecondition: &src.MetaGeneration,
+	})
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) CopyChildFileFromBucket(ctx context.Context, name string, srcBucket string, src *gcs.MinObject, dstGeneration int64) (*Core, error) {
+	return d.copyToChildFile(ctx, name, &gcs.CopyObjectRequest{
+		SrcBucket:                     srcBucket,
+		SrcName:                       src.Name,
+		SrcGeneration:                 src.Generation,
+		SrcMetaGenerationPrecondition: &src.MetaGeneration,
+		DstGenerationPrecondition:     &dstGeneration,
+	})
+}
+
+// copyToChildFile carries out the supplied copy request, with the destination
+// set to the child with the given name.
+//
+// LOCKS_REQUIRED(d)
+func (d *dirInode) copyToChildFile(ctx context.Context, name string, req *gcs.CopyObjectRequest) (*Core, error) {
 	// Increment active writers on the directory so no new prefetch gets triggered until the write operation completes.
 	d.IncrementActiveWriters()
 	defer d.DecrementActiveWriters()
@@ -1129,17 +1326,11 @@ func (d *dirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.M
 		// Erase any existing type information for this name.
 		d.cache.Erase(name)
 	}
+	d.forgetLooseNames()
 	fullName := NewFileName(d.Name(), name)
 
-	// Clone over anything that might already exist for the name.
-	o, err := d.bucket.CopyObject(
-		ctx,
-		&gcs.CopyObjectRequest{
-			SrcName:                       src.Name,
-			SrcGeneration:                 src.Generation,
-			SrcMetaGenerationPrecondition: &src.MetaGeneration,
-			DstName:                       fullName.GcsObjectName(),
-		})
+	req.DstName = fullName.GcsObjectName()
+	o, err := d.bucket.CopyObject(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -1153,6 +1344,7 @@ func (d *dirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.M
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Insert(d.cacheClock.Now(), name, c.Type())
 	}
+	d.forgetLooseNames()
 	return c, nil
 }
 
@@ -1185,6 +1377,35 @@ func (d *dirInode) CreateChildSymlink(ctx context.Context, name string, target s
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Insert(d.cacheClock.Now(), name, metadata.SymlinkType)
 	}
+	d.forgetLooseNames()
+
+	return &Core{
+		Bucket:    d.Bucket(),
+		FullName:  fullName,
+		MinObject: m,
+	}, nil
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) CreateChildSpecialFile(ctx context.Context, name string, mode os.FileMode, rdev uint32) (*Core, error) {
+	if !IsSpecialFileMode(mode) {
+		return nil, fmt.Errorf("not a special file mode: %v", mode)
+	}
+
+	fullName := NewFileName(d.Name(), name)
+	childMetadata := specialFileMetadata(mode, rdev)
+	childMetadata[FileMtimeMetadataKey] = d.mtimeClock.Now().UTC().Format(time.RFC3339Nano)
+
+	o, err := d.createNewObject(ctx, fullName, childMetadata, "")
+	if err != nil {
+		return nil, err
+	}
+	m := storageutil.ConvertObjToMinObject(o)
+
+	if !d.IsTypeCacheDeprecated() {
+		d.cache.Insert(d.cacheClock.Now(), name, metadata.SpecialFileType)
+	}
+	d.forgetLooseNames()
 
 	return &Core{
 		Bucket:    d.Bucket(),
@@ -1224,6 +1445,7 @@ func (d *dirInode) CreateChildDir(ctx context.Context, name string) (*Core, erro
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Insert(d.cacheClock.Now(), name, metadata.ExplicitDirType)
 	}
+	d.forgetLooseNames()
 
 	return &Core{
 		Bucket:    d.Bucket(),
@@ -1259,6 +1481,7 @@ func (d *dirInode) DeleteChildFile(
 		if !d.IsTypeCacheDeprecated() {
 			d.cache.Erase(name)
 		}
+		d.forgetLooseNames()
 		return
 	}
 	var notFoundError *gcs.NotFoundError
@@ -1268,6 +1491,7 @@ func (d *dirInode) DeleteChildFile(
 		if !d.IsTypeCacheDeprecated() {
 			d.cache.Erase(name)
 		}
+		d.forgetLooseNames()
 	}
 	return fmt.Errorf("DeleteObject: %w", err)
 }
@@ -1292,6 +1516,7 @@ func (d *dirInode) DeleteChildDir(
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Erase(name)
 	}
+	d.forgetLooseNames()
 
 	childName := NewDirName(d.Name(), name)
 	req := &gcs.DeleteObjectRequest{
@@ -1338,6 +1563,83 @@ func (d *dirInode) DeleteChildDir(
 	return nil
 }
 
+// trashes returns the trash of the bucket, and whether the named object is
+// moved to it rather than deleted.
+func (d *dirInode) trashes(objectName string) (*trash.Trash, bool) {
+	if d.trashPrefix == "" {
+		return nil, false
+	}
+	t := trash.New(d.bucket, d.trashPrefix, d.mtimeClock)
+	return t, !t.Contains(objectName)
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) TrashChildFile(ctx context.Context, name string) error {
+	childName := NewFileName(d.Name(), name)
+	t, ok := d.trashes(childName.GcsObjectName())
+	if !ok {
+		return d.DeleteChildFile(ctx, name, 0, nil)
+	}
+
+	// Increment active writers on the directory so no new prefetch gets triggered until the write operation completes.
+	d.IncrementActiveWriters()
+	defer d.DecrementActiveWriters()
+	// Cancel prefetch of the current directory only.
+	d.CancelCurrDirPrefetcher()
+
+	err := t.Move(ctx, childName.GcsObjectName())
+	if !d.IsTypeCacheDeprecated() {
+		d.cache.Erase(name)
+	}
+	d.forgetLooseNames()
+	if err != nil {
+		return fmt.Errorf("move to trash: %w", err)
+	}
+	return nil
+}
+
+// LOCKS_REQUIRED(d)
+func (d *dirInode) TrashChildDir(
+	ctx context.Context,
+	name string,
+	isImplicitDir bool,
+	dirInode DirInode) error {
+	childName := NewDirName(d.Name(), name)
+	t, ok := d.trashes(childName.GcsObjectName())
+	if !ok {
+		return d.DeleteChildDir(ctx, name, isImplicitDir, dirInode)
+	}
+
+	// Increment active writers on the directory so no new prefetch gets triggered until the write operation completes.
+	d.IncrementActiveWriters()
+	defer d.DecrementActiveWriters()
+	// Cancel prefetch of the current directory only.
+	d.CancelCurrDirPrefetcher()
+
+	if dirInode != nil {
+		// Recursively cancel prefetches for the deleted directory and its children.
+		dirInode.CancelSubdirectoryPrefetches()
+	}
+
+	if !d.IsTypeCacheDeprecated() {
+		d.cache.Erase(name)
+	}
+	d.forgetLooseNames()
+
+	if err := t.MoveDir(ctx, childName.GcsObjectName()); err != nil {
+		return fmt.Errorf("move to trash: %w", err)
+	}
+	if isImplicitDir && d.IsTypeCacheDeprecated() {
+		// As in DeleteChildDir, there is no backing object, but the cache needs
+		// to learn that the directory is gone.
+		_ = d.bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: childName.GcsObjectName(), OnlyDeleteFromCache: true})
+	}
+	if d.isBucketHierarchical() && dirInode != nil {
+		dirInode.Unlink()
+	}
+	return nil
+}
+
 // LOCKS_REQUIRED(d)
 func (d *dirInode) DeleteObjects(ctx context.Context, objectNames []string) error {
 	for _, objectName := range objectNames {
@@ -1500,6 +1802,7 @@ func (d *dirInode) RenameFile(ctx context.Context, fileToRename *gcs.MinObject,
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Erase(fileToRename.Name)
 	}
+	d.forgetLooseNames()
 
 	return o, err
 }
@@ -1526,6 +1829,7 @@ func (d *dirInode) RenameFolder(ctx context.Context, folderName string, destinat
 	if !d.IsTypeCacheDeprecated() {
 		d.cache.Erase(folderName)
 	}
+	d.forgetLooseNames()
 
 	return folder, nil
 }
@@ -1533,6 +1837,7 @@ func (d *dirInode) RenameFolder(ctx context.Context, folderName string, destinat
 func (d *dirInode) InvalidateKernelListCache() {
 	// Set prevDirListingTimeStamp to Zero time so that cache is invalidated.
 	d.prevDirListingTimeStamp = time.Time{}
+	d.forgetLooseNames()
 }
 
 func (d *dirInode) isBucketHierarchical() bool {
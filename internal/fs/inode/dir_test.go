@@ -346,6 +346,38 @@ func (t *DirTest) TestLookUpChild_NonExistent() {
 	}
 }
 
+func (t *DirTest) TestLookUpChild_LooseIndex() {
+	t.in.(*dirInode).nameMatching = NameMatching{CaseInsensitive: true}
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, "Taco"), []byte("taco"))
+	require.NoError(t.T(), err)
+	result, err := t.in.LookUpChild(t.ctx, "taco")
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), result)
+	// Created behind the inode's back, so not in the index yet.
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, "Burrito"), []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	result, err = t.in.LookUpChild(t.ctx, "burrito")
+	require.NoError(t.T(), err)
+	assert.Nil(t.T(), result)
+
+	// Creating a child throws the index away.
+	_, err = t.in.CreateChildFile(t.ctx, "enchilada")
+	require.NoError(t.T(), err)
+	result, err = t.in.LookUpChild(t.ctx, "burrito")
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), result)
+	assert.Equal(t.T(), path.Join(dirInodeName, "Burrito"), result.MinObject.Name)
+
+	// And so does the index expiring.
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, "Queso"), []byte("queso"))
+	require.NoError(t.T(), err)
+	t.clock.AdvanceTime(typeCacheTTL + time.Millisecond)
+	result, err = t.in.LookUpChild(t.ctx, "queso")
+	require.NoError(t.T(), err)
+	assert.NotNil(t.T(), result)
+}
+
 func (t *DirTest) TestLookUpChild_FileOnly() {
 	const name = "qux"
 	objName := path.Join(dirInodeName, name)
@@ -1437,6 +1469,34 @@ func (t *DirTest) TestCloneToChildFile_DestinationExists() {
 	}
 }
 
+func (t *DirTest) TestCopyChildFileFromBucket_DestinationPrecondition() {
+	const srcName = "blah/baz"
+	dstName := path.Join(dirInodeName, "qux")
+	src, err := storageutil.CreateObject(t.ctx, t.bucket, srcName, []byte("taco"))
+	require.NoError(t.T(), err)
+	dst, err := storageutil.CreateObject(t.ctx, t.bucket, dstName, []byte(""))
+	require.NoError(t.T(), err)
+	srcMinObject := storageutil.ConvertObjToMinObject(src)
+
+	// The destination exists, so a copy that expects none fails.
+	_, err = t.in.CopyChildFileFromBucket(t.ctx, path.Base(dstName), t.bucket.Name(), srcMinObject, 0)
+
+	var preconditionErr *gcs.PreconditionError
+	assert.True(t.T(), errors.As(err, &preconditionErr))
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, dstName)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "", string(contents))
+
+	// Naming its generation overwrites it.
+	result, err := t.in.CopyChildFileFromBucket(t.ctx, path.Base(dstName), t.bucket.Name(), srcMinObject, dst.Generation)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), dstName, result.MinObject.Name)
+	contents, err = storageutil.ReadObject(t.ctx, t.bucket, dstName)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco", string(contents))
+}
+
 func (t *DirTest) TestCloneToChildFile_TypeCaching() {
 	if t.in.IsTypeCacheDeprecated() {
 		return
@@ -1506,6 +1566,63 @@ func (t *DirTest) TestCreateChildSymlink_DoesntExist() {
 	assert.Equal(t.T(), target, result.MinObject.Metadata[SymlinkMetadataKey])
 }
 
+func (t *DirTest) TestCreateChildSpecialFile() {
+	const name = "qux"
+	objName := path.Join(dirInodeName, name)
+
+	result, err := t.in.CreateChildSpecialFile(t.ctx, name, os.ModeDevice|os.ModeCharDevice|0600, 0x0103)
+
+	require.NoError(t.T(), err)
+	require.NotNil(t.T(), result)
+	require.NotNil(t.T(), result.MinObject)
+	assert.Equal(t.T(), objName, result.MinObject.Name)
+	assert.Equal(t.T(), uint64(0), result.MinObject.Size)
+	assert.Equal(t.T(), "char", result.MinObject.Metadata[SpecialFileTypeMetadataKey])
+	assert.Equal(t.T(), "259", result.MinObject.Metadata[SpecialFileRdevMetadataKey])
+	assert.Equal(t.T(), metadata.SpecialFileType, result.Type())
+	if !t.in.IsTypeCacheDeprecated() {
+		assert.Equal(t.T(), metadata.SpecialFileType, t.getTypeFromCache(name))
+	}
+}
+
+func (t *DirTest) TestCreateChildSpecialFile_Exists() {
+	const name = "qux"
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, path.Join(dirInodeName, name), []byte(""))
+	require.NoError(t.T(), err)
+
+	_, err = t.in.CreateChildSpecialFile(t.ctx, name, os.ModeNamedPipe, 0)
+
+	var preconditionErr *gcs.PreconditionError
+	assert.ErrorAs(t.T(), err, &preconditionErr)
+}
+
+func (t *DirTest) TestCreateChildSpecialFile_RegularFileMode() {
+	_, err := t.in.CreateChildSpecialFile(t.ctx, "qux", 0644, 0)
+
+	assert.Error(t.T(), err)
+}
+
+func (t *DirTest) TestReadEntries_SpecialFiles() {
+	for name, mode := range map[string]os.FileMode{
+		"block":  os.ModeDevice,
+		"char":   os.ModeDevice | os.ModeCharDevice,
+		"fifo":   os.ModeNamedPipe,
+		"socket": os.ModeSocket,
+	} {
+		_, err := t.in.CreateChildSpecialFile(t.ctx, name, mode, 0)
+		require.NoError(t.T(), err)
+	}
+
+	entries, err := t.readAllEntries()
+
+	require.NoError(t.T(), err)
+	require.Len(t.T(), entries, 4)
+	assert.Equal(t.T(), fuseutil.DT_Block, entries[0].Type)
+	assert.Equal(t.T(), fuseutil.DT_Char, entries[1].Type)
+	assert.Equal(t.T(), fuseutil.DT_FIFO, entries[2].Type)
+	assert.Equal(t.T(), fuseutil.DT_Socket, entries[3].Type)
+}
+
 func (t *DirTest) TestCreateChildSymlink_Exists() {
 	const name = "qux"
 	const target = "taco"
@@ -16,9 +16,12 @@ package inode
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
 	"github.com/jacobsa/fuse/fuseops"
@@ -31,6 +34,11 @@ import (
 type ExplicitDirInode interface {
 	DirInode
 	SourceGeneration() Generation
+
+	// Record the supplied attributes in the metadata of the backing object and
+	// report them from now on. A no-op for folders in hierarchical buckets,
+	// which have no metadata.
+	SetPosixAttributes(ctx context.Context, p PosixAttributes) error
 }
 
 // Create an explicit dir inode backed by the supplied object. See notes on
@@ -49,6 +57,10 @@ func NewExplicitDirInode(
 	cacheClock timeutil.Clock,
 	prefetchSem *semaphore.Weighted,
 	cfg *cfg.Config) (d ExplicitDirInode) {
+	if m != nil && cfg.FileSystem.PreservePosix {
+		applyPosixMetadata(&attrs, m.Metadata)
+	}
+
 	wrapped := NewDirInode(
 		id,
 		name,
@@ -92,3 +104,38 @@ func (d *explicitDirInode) SourceGeneration() (gen Generation) {
 func (d *explicitDirInode) UpdateSize(size uint64) {
 	// No-op for directories.
 }
+
+// LOCKS_REQUIRED(d)
+func (d *explicitDirInode) SetPosixAttributes(
+	ctx context.Context,
+	p PosixAttributes) (err error) {
+	if p.IsEmpty() || d.generation.Object == 0 {
+		return
+	}
+
+	o, err := d.bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name:                       d.Name().GcsObjectName(),
+		Generation:                 d.generation.Object,
+		MetaGenerationPrecondition: &d.generation.Metadata,
+		Metadata:                   p.metadata(),
+	})
+
+	var preconditionErr *gcs.PreconditionError
+	var notFoundErr *gcs.NotFoundError
+	if errors.As(err, &preconditionErr) || errors.As(err, &notFoundErr) {
+		err = &gcsfuse_errors.FileClobberedError{
+			Err:        fmt.Errorf("UpdateObject: %w", err),
+			ObjectName: d.Name().GcsObjectName(),
+		}
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("UpdateObject: %w", err)
+		return
+	}
+
+	d.generation.Metadata = o.MetaGeneration
+	applyPosixMetadata(&d.attrs, o.Metadata)
+	return
+}
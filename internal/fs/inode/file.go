@@ -20,6 +20,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
@@ -63,7 +64,6 @@ type FileInode struct {
 	/////////////////////////
 
 	id           fuseops.InodeID
-	name         Name
 	attrs        fuseops.InodeAttributes
 	contentCache *contentcache.ContentCache
 	// TODO (#640) remove bool flag and refactor contentCache to support two implementations:
@@ -81,6 +81,10 @@ type FileInode struct {
 	// GUARDED_BY(mu)
 	lc lookupCount
 
+	// The name of the inode. It only changes when a directory above the inode is
+	// renamed, and is read through Name without holding any lock.
+	name atomic.Pointer[Name]
+
 	// The source object from which this inode derives.
 	//
 	// INVARIANT: for non local files,  src.Name == name.GcsObjectName()
@@ -92,6 +96,14 @@ type FileInode struct {
 	// authoritative.
 	content gcsx.TempFile
 
+	// Metadata updates made while the inode was dirty, in the format of
+	// gcs.UpdateObjectRequest.Metadata. Staged contents are synced with them
+	// in a single request; streaming writes, whose upload starts before they
+	// are made, have them written to the backing object once finalized.
+	//
+	// GUARDED_BY(mu)
+	pendingMetadata map[string]*string
+
 	// Has Destroy been called?
 	//
 	// GUARDED_BY(mu)
@@ -164,7 +176,6 @@ func NewFileInode(
 		bucket:                  bucket,
 		mtimeClock:              mtimeClock,
 		id:                      id,
-		name:                    name,
 		attrs:                   attrs,
 		localFileCache:          localFileCache,
 		contentCache:            contentCache,
@@ -175,6 +186,7 @@ func NewFileInode(
 		globalMaxWriteBlocksSem: globalMaxBlocksSem,
 		traceHandle:             traceHandle,
 	}
+	f.name.Store(&name)
 
 	if f.bucket.BucketType().Zonal {
 		var err error
@@ -245,7 +257,7 @@ func (f *FileInode) clobbered(ctx context.Context, forceFetchFromGcs bool, inclu
 	// Stat the object in GCS. ForceFetchFromGcs ensures object is fetched from
 	// gcs and not cache.
 	req := &gcs.StatObjectRequest{
-		Name:                           f.name.GcsObjectName(),
+		Name:                           f.Name().GcsObjectName(),
 		ForceFetchFromGcs:              forceFetchFromGcs,
 		ReturnExtendedObjectAttributes: includeExtendedObjectAttributes,
 	}
@@ -325,7 +337,7 @@ func (f *FileInode) ensureContent(ctx context.Context) (err error) {
 	if f.localFileCache {
 		// Fetch content from the cache after validating generation numbers again
 		// Generation validation first occurs at inode creation/destruction
-		cacheObjectKey := &contentcache.CacheObjectKey{BucketName: f.bucket.Name(), ObjectName: f.name.objectName}
+		cacheObjectKey := &contentcache.CacheObjectKey{BucketName: f.bucket.Name(), ObjectName: f.Name().objectName}
 		if cacheObject, exists := f.contentCache.Get(cacheObjectKey); exists {
 			if cacheObject.ValidateGeneration(f.src.Generation, f.src.MetaGeneration) {
 				f.content = cacheObject.CacheFile
@@ -389,7 +401,7 @@ func (f *FileInode) ID() fuseops.InodeID {
 }
 
 func (f *FileInode) Name() Name {
-	return f.name
+	return *f.name.Load()
 }
 
 func (f *FileInode) IsLocal() bool {
@@ -408,6 +420,17 @@ func (f *FileInode) Unlink() {
 	}
 }
 
+// Rename moves the inode to the given name after a directory above it has been
+// renamed, with src being the object that now backs it. Contents that have not
+// been synced yet stay dirty, and are later written out under the new name.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) Rename(name Name, src *gcs.MinObject) {
+	f.name.Store(&name)
+	f.src = *src
+	f.updateMRD()
+}
+
 // Returns true if the fileInode is using Buffered Write Handler.
 //
 // LOCKS_REQUIRED(f.mu)
@@ -519,7 +542,7 @@ func (f *FileInode) UpdateSize(size uint64) {
 func (f *FileInode) Destroy() (err error) {
 	f.destroyed = true
 	if f.localFileCache {
-		cacheObjectKey := &contentcache.CacheObjectKey{BucketName: f.bucket.Name(), ObjectName: f.name.objectName}
+		cacheObjectKey := &contentcache.CacheObjectKey{BucketName: f.bucket.Name(), ObjectName: f.Name().objectName}
 		f.contentCache.Remove(cacheObjectKey)
 	} else if f.content != nil {
 		f.content.Destroy()
@@ -581,6 +604,10 @@ func (f *FileInode) Attributes(
 	attrs.Atime = attrs.Mtime
 	attrs.Ctime = attrs.Mtime
 
+	if f.config != nil && f.config.FileSystem.PreservePosix {
+		applyPosixMetadata(&attrs, f.metadataWithPendingUpdates())
+	}
+
 	if clobberedCheck {
 		// If the object has been clobbered, we reflect that as the inode being
 		// unlinked.
@@ -713,7 +740,7 @@ func (f *FileInode) writeUsingBufferedWrites(ctx context.Context, data []byte, o
 	}
 	// Fall back to temp file for Out-Of-Order Writes.
 	if errors.Is(err, bufferedwrites.ErrOutOfOrderWrite) {
-		logger.Infof("Out of order write detected. File %s will now use legacy staged writes. "+StreamingWritesSemantics, f.name.String())
+		logger.Infof("Out of order write detected. File %s will now use legacy staged writes. "+StreamingWritesSemantics, f.Name().String())
 		// Finalize the object.
 		err = f.flushUsingBufferedWriteHandler(ctx)
 		if err != nil {
@@ -745,7 +772,7 @@ func (f *FileInode) flushUsingBufferedWriteHandler(ctx context.Context) error {
 	}
 	// If we finalized the object, we need to update our state.
 	f.updateInodeStateAfterFlush(obj)
-	return nil
+	return f.syncPendingMetadata(ctx)
 }
 
 // SyncPendingBufferedWrites flushes any pending writes on the bwh to GCS.
@@ -866,6 +893,183 @@ func (f *FileInode) SetMtime(
 	return
 }
 
+// SetPosixAttributes records the supplied attributes in the metadata of the
+// backing object, from where Attributes reports them when POSIX metadata is
+// enabled. May involve a round trip to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetPosixAttributes(
+	ctx context.Context,
+	p PosixAttributes) (err error) {
+	if p.IsEmpty() {
+		return
+	}
+
+	err = f.updateMetadata(ctx, p.metadata())
+	return
+}
+
+// metadataWithPendingUpdates returns the custom metadata of the inode as it
+// will look once pending metadata updates have been written out.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) metadataWithPendingUpdates() map[string]string {
+	m := make(map[string]string, len(f.src.Metadata)+len(f.pendingMetadata))
+	for k, v := range f.src.Metadata {
+		m[k] = v
+	}
+
+	for k, v := range f.pendingMetadata {
+		if v == nil {
+			delete(m, k)
+			continue
+		}
+		m[k] = *v
+	}
+
+	return m
+}
+
+// updateMetadata applies the supplied updates, in the format of
+// gcs.UpdateObjectRequest.Metadata, to the backing object. If the file has
+// local modifications, they are instead held in memory and written out along
+// with the contents by Sync or Flush.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) updateMetadata(
+	ctx context.Context,
+	updates map[string]*string) (err error) {
+	dirty, err := f.isDirty()
+	if err != nil {
+		return
+	}
+
+	if dirty || f.IsUnlinked() {
+		if f.pendingMetadata == nil {
+			f.pendingMetadata = make(map[string]*string)
+		}
+		for k, v := range updates {
+			f.pendingMetadata[k] = v
+		}
+		return
+	}
+
+	err = f.updateSourceMetadata(ctx, updates)
+	return
+}
+
+// isDirty reports whether the inode holds modifications that have not yet
+// been written to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) isDirty() (bool, error) {
+	if f.IsLocal() || f.bwh != nil {
+		return true, nil
+	}
+
+	if f.content == nil {
+		return false, nil
+	}
+
+	sr, err := f.content.Stat()
+	if err != nil {
+		return false, fmt.Errorf("stat: %w", err)
+	}
+
+	return sr.Mtime != nil, nil
+}
+
+// DirtyBytes reports whether the inode holds modifications that have not yet
+// been written to GCS and, if so, the size of the object that writing them
+// out produces.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) DirtyBytes() (dirty bool, size int64, err error) {
+	dirty, err = f.isDirty()
+	if err != nil || !dirty {
+		return
+	}
+
+	switch {
+	case f.bwh != nil:
+		size = f.bwh.WriteFileInfo().TotalSize
+	case f.content != nil:
+		var sr gcsx.StatResult
+		sr, err = f.content.Stat()
+		if err != nil {
+			err = fmt.Errorf("stat: %w", err)
+			return
+		}
+		size = sr.Size
+	}
+
+	return
+}
+
+// syncPendingMetadata writes out metadata updates that were deferred while
+// the file was dirty and didn't go out with its contents. It is a no-op until
+// the contents have been written out and the inode is backed by a finalized
+// object again.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) syncPendingMetadata(ctx context.Context) (err error) {
+	if len(f.pendingMetadata) == 0 || f.IsUnlinked() {
+		return
+	}
+
+	if dirty, err := f.isDirty(); err != nil || dirty {
+		return err
+	}
+
+	err = f.updateSourceMetadata(ctx, f.pendingMetadata)
+	if err != nil {
+		return
+	}
+
+	f.pendingMetadata = nil
+	return
+}
+
+// updateSourceMetadata updates the custom metadata of the backing object,
+// failing if it has been modified since the inode last saw it.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) updateSourceMetadata(
+	ctx context.Context,
+	metadata map[string]*string) (err error) {
+	srcGen := f.SourceGeneration()
+	req := &gcs.UpdateObjectRequest{
+		Name:                       f.src.Name,
+		Generation:                 srcGen.Object,
+		MetaGenerationPrecondition: &srcGen.Metadata,
+		Metadata:                   metadata,
+	}
+
+	o, err := f.bucket.UpdateObject(ctx, req)
+
+	var notFoundErr *gcs.NotFoundError
+	var preconditionErr *gcs.PreconditionError
+	if errors.As(err, &notFoundErr) || errors.As(err, &preconditionErr) {
+		err = &gcsfuse_errors.FileClobberedError{
+			Err:        fmt.Errorf("UpdateObject: %w", err),
+			ObjectName: f.src.Name,
+		}
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("UpdateObject: %w", err)
+		return
+	}
+
+	if minObj := storageutil.ConvertObjToMinObject(o); minObj != nil {
+		f.src = *minObj
+		f.updateMRD()
+	}
+
+	return
+}
+
 func (f *FileInode) fetchLatestGcsObject(ctx context.Context) (*gcs.Object, error) {
 	// When listObjects call is made, we fetch data with projection set as noAcl
 	// which means acls and owner properties are not returned. So the f.src object
@@ -909,8 +1113,10 @@ func (f *FileInode) fetchLatestGcsObject(ctx context.Context) (*gcs.Object, erro
 //
 // LOCKS_REQUIRED(f.mu)
 func (f *FileInode) Sync(ctx context.Context) (gcsSynced bool, err error) {
-	// If we have not been dirtied, there is nothing to do.
+	// If we have not been dirtied, there is nothing to do beyond retrying
+	// metadata updates that failed to go out with the contents.
 	if f.content == nil && f.bwh == nil {
+		err = f.syncPendingMetadata(ctx)
 		return
 	}
 
@@ -962,7 +1168,7 @@ func (f *FileInode) syncUsingContent(ctx context.Context) (err error) {
 	// Write out the contents if they are dirty.
 	// Object properties are also synced as part of content sync. Hence, passing
 	// the latest object fetched from gcs which has all the properties populated.
-	newObj, err := f.bucket.SyncObject(ctx, f.Name().GcsObjectName(), latestGcsObj, f.content)
+	newObj, err := f.bucket.SyncObject(ctx, f.Name().GcsObjectName(), latestGcsObj, f.pendingMetadata, f.content)
 
 	var preconditionErr *gcs.PreconditionError
 	if errors.As(err, &preconditionErr) {
@@ -976,10 +1182,15 @@ func (f *FileInode) syncUsingContent(ctx context.Context) (err error) {
 	if err != nil {
 		return fmt.Errorf("SyncObject: %w", err)
 	}
+	if newObj != nil {
+		f.pendingMetadata = nil
+	}
 	minObj := storageutil.ConvertObjToMinObject(newObj)
 	// If we wrote out a new object, we need to update our state.
 	f.updateInodeStateAfterFlush(minObj)
-	return nil
+	// Contents that turned out to be clean leave the updates to be made on
+	// their own.
+	return f.syncPendingMetadata(ctx)
 }
 
 // Flush writes out contents to GCS. If this fails due to the generation
@@ -992,9 +1203,10 @@ func (f *FileInode) syncUsingContent(ctx context.Context) (err error) {
 //
 // LOCKS_REQUIRED(f.mu)
 func (f *FileInode) Flush(ctx context.Context) (err error) {
-	// If we have not been dirtied, there is nothing to do.
+	// If we have not been dirtied, there is nothing to do beyond retrying
+	// metadata updates that failed to go out with the contents.
 	if f.content == nil && f.bwh == nil {
-		return
+		return f.syncPendingMetadata(ctx)
 	}
 
 	// Flush using the appropriate method based on whether we're using a
@@ -1057,7 +1269,7 @@ func (f *FileInode) truncateUsingBufferedWriteHandler(ctx context.Context, size
 	err := f.bwh.Truncate(size)
 	// If truncate size is less than the total file size resulting in OutOfOrder write, finalize and fall back to temp file.
 	if errors.Is(err, bufferedwrites.ErrOutOfOrderWrite) {
-		logger.Infof("Out of order write detected. File %s will now use legacy staged writes. "+StreamingWritesSemantics, f.name.String())
+		logger.Infof("Out of order write detected. File %s will now use legacy staged writes. "+StreamingWritesSemantics, f.Name().String())
 		// Finalize the object.
 		err = f.flushUsingBufferedWriteHandler(ctx)
 		if err != nil {
@@ -1098,6 +1310,44 @@ func (f *FileInode) Truncate(
 	return false, f.truncateUsingTempFile(ctx, size)
 }
 
+// Fallocate allocates space for the byte range [offset, offset+length),
+// extending the file if the range ends beyond its size unless keepSize is set.
+// Space can only be reserved on the local disk, so when the contents are not
+// staged in a temp file a request that doesn't extend the file is a no-op.
+// It returns true if the file has been successfully synced to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) Fallocate(
+	ctx context.Context,
+	offset int64,
+	length int64,
+	keepSize bool) (bool, error) {
+	attrs, err := f.Attributes(ctx, false)
+	if err != nil {
+		return false, err
+	}
+	extends := !keepSize && uint64(offset+length) > attrs.Size
+
+	if f.bwh != nil {
+		if !extends {
+			return false, nil
+		}
+		return f.truncateUsingBufferedWriteHandler(ctx, offset+length)
+	}
+
+	if f.content == nil && !extends {
+		return false, nil
+	}
+
+	// Make sure f.content != nil.
+	err = f.ensureContent(ctx)
+	if err != nil {
+		return false, fmt.Errorf("ensureContent: %w", err)
+	}
+
+	return false, f.content.Allocate(offset, length, keepSize)
+}
+
 // Ensures cache content on read if content cache enabled
 func (f *FileInode) CacheEnsureContent(ctx context.Context) (err error) {
 	if f.localFileCache {
@@ -1167,7 +1417,7 @@ func (f *FileInode) InitBufferedWriteHandlerIfEligible(ctx context.Context, open
 	if f.bwh == nil {
 		f.bwh, err = bufferedwrites.NewBWHandler(&bufferedwrites.CreateBWHandlerRequest{
 			Object:                   latestGcsObj,
-			ObjectName:               f.name.GcsObjectName(),
+			ObjectName:               f.Name().GcsObjectName(),
 			Bucket:                   f.bucket,
 			BlockSize:                f.config.Write.BlockSizeMb * util.MiB,
 			MaxBlocksPerFile:         f.config.Write.MaxBlocksPerFile,
@@ -1180,7 +1430,7 @@ func (f *FileInode) InitBufferedWriteHandlerIfEligible(ctx context.Context, open
 			logger.Warnf("File %s will use legacy staged writes because concurrent streaming write "+
 				"limit (set by --write-global-max-blocks) has been reached. To allow more concurrent files "+
 				"to use streaming writes, consider increasing this limit if sufficient memory is available. "+
-				"For more details on memory usage, see: https://github.com/GoogleCloudPlatform/gcsfuse/blob/master/docs/semantics.md#writes", f.name.String())
+				"For more details on memory usage, see: https://github.com/GoogleCloudPlatform/gcsfuse/blob/master/docs/semantics.md#writes", f.Name().String())
 			return false, nil
 		}
 		if err != nil {
@@ -1200,6 +1450,6 @@ func (f *FileInode) areBufferedWritesSupported(openMode util.OpenMode, obj *gcs.
 	if f.config.Write.EnableRapidAppends && openMode.IsAppend() && f.bucket.BucketType().Zonal && obj.Finalized.IsZero() {
 		return true
 	}
-	logger.Infof("Existing file %s of size %d bytes (non-zero) will use legacy staged writes. "+StreamingWritesSemantics, f.name.String(), obj.Size)
+	logger.Infof("Existing file %s of size %d bytes (non-zero) will use legacy staged writes. "+StreamingWritesSemantics, f.Name().String(), obj.Size)
 	return false
 }
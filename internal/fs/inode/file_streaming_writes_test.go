@@ -214,6 +214,34 @@ func (t *FileStreamingWritesCommon) TestTruncateNegative() {
 	assert.False(t.T(), gcsSynced)
 }
 
+func (t *FileStreamingWritesCommon) TestFallocateExtendsBufferedWrites() {
+	t.createBufferedWriteHandler()
+	_, err := t.in.Write(t.ctx, []byte("taco"), 0, WriteMode)
+	require.NoError(t.T(), err)
+
+	gcsSynced, err := t.in.Fallocate(t.ctx, 4, 6, false)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	assert.True(t.T(), t.in.IsUsingBWH())
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(10), attrs.Size)
+}
+
+func (t *FileStreamingWritesCommon) TestFallocateKeepSizeWithBufferedWrites() {
+	t.createBufferedWriteHandler()
+
+	gcsSynced, err := t.in.Fallocate(t.ctx, 0, 1<<20, true)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	assert.True(t.T(), t.in.IsUsingBWH())
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(0), attrs.Size)
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Tests (Zonal Bucket)
 ////////////////////////////////////////////////////////////////////////
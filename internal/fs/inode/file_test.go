@@ -578,6 +578,104 @@ func (t *FileTest) TestTruncateNegative() {
 	assert.False(t.T(), gcsSynced)
 }
 
+func (t *FileTest) TestFallocateExtendsFile() {
+	gcsSynced, err := t.in.Fallocate(t.ctx, 4, 4, false)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(8), attrs.Size)
+	_, err = t.in.Sync(t.ctx)
+	require.NoError(t.T(), err)
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, t.in.Name().GcsObjectName())
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco\x00\x00\x00\x00", string(contents))
+}
+
+func (t *FileTest) TestFallocateKeepSizeOnCleanFileIsNoop() {
+	gcsSynced, err := t.in.Fallocate(t.ctx, 0, 1<<20, true)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	assert.Nil(t.T(), t.in.content)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len(t.initialContents)), attrs.Size)
+}
+
+func (t *FileTest) TestFallocateKeepSizeOnDirtyFile() {
+	_, err := t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+
+	gcsSynced, err := t.in.Fallocate(t.ctx, 0, 1<<20, true)
+
+	require.NoError(t.T(), err)
+	assert.False(t.T(), gcsSynced)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(len("burrito")), attrs.Size)
+}
+
+func (t *FileTest) TestDirtyBytes() {
+	dirty, _, err := t.in.DirtyBytes()
+	require.NoError(t.T(), err)
+	assert.False(t.T(), dirty)
+
+	_, err = t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+	dirty, size, err := t.in.DirtyBytes()
+
+	require.NoError(t.T(), err)
+	assert.True(t.T(), dirty)
+	assert.Equal(t.T(), int64(len("burrito")), size)
+}
+
+func (t *FileTest) TestRenameThenSync() {
+	_, err := t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+	// Move the object into a renamed directory, the way renaming one does.
+	newName := NewFileName(NewDirName(NewRootName(""), "dir"), fileName)
+	o, err := t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: fileName, DstName: newName.GcsObjectName()})
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: fileName}))
+
+	t.in.Rename(newName, storageutil.ConvertObjToMinObject(o))
+	gcsSynced, err := t.in.Sync(t.ctx)
+
+	require.NoError(t.T(), err)
+	assert.True(t.T(), gcsSynced)
+	assert.Equal(t.T(), newName, t.in.Name())
+	assert.Equal(t.T(), newName.GcsObjectName(), t.in.Source().Name)
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, newName.GcsObjectName())
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", string(contents))
+	_, err = storageutil.ReadObject(t.ctx, t.bucket, fileName)
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFoundErr)
+}
+
+func (t *FileTest) TestName_ReadWhileRenaming() {
+	newName := NewFileName(NewDirName(NewRootName(""), "dir"), fileName)
+	src := *t.in.Source()
+	src.Name = newName.GcsObjectName()
+	done := make(chan struct{})
+
+	// The inode lock is held by the test; readers of the name take no lock.
+	go func() {
+		defer close(done)
+		for range 100 {
+			t.in.Rename(newName, &src)
+		}
+	}()
+	for range 100 {
+		assert.Contains(t.T(), []string{fileName, newName.GcsObjectName()}, t.in.Name().GcsObjectName())
+	}
+	<-done
+
+	assert.Equal(t.T(), newName, t.in.Name())
+}
+
 func (t *FileTest) TestDestroy_MrdInstanceDestroyed() {
 	if !t.in.bucket.BucketType().Zonal {
 		return
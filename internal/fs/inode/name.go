@@ -100,6 +100,13 @@ func (name Name) LocalName() string {
 	return name.bucketName + "/" + name.objectName
 }
 
+// BaseName returns the last component of the name, without the trailing
+// slash of a directory. It is empty for a bucket root.
+func (name Name) BaseName() string {
+	objectName := strings.TrimSuffix(name.objectName, "/")
+	return objectName[strings.LastIndex(objectName, "/")+1:]
+}
+
 // String returns LocalName.
 func (name Name) String() string {
 	return name.LocalName()
@@ -125,6 +132,26 @@ func (name Name) IsDirectChildOf(parent Name) bool {
 	return !strings.Contains(cleanDiff, "/")
 }
 
+// IsDescendantOf returns true if the name lies anywhere beneath the supplied
+// directory.
+func (name Name) IsDescendantOf(dir Name) bool {
+	return dir.IsDir() &&
+		name.bucketName == dir.bucketName &&
+		len(name.objectName) > len(dir.objectName) &&
+		strings.HasPrefix(name.objectName, dir.objectName)
+}
+
+// Rebase returns the name that the current Name takes when its ancestor oldDir
+// is renamed to newDir.
+//
+// REQUIRES: name.IsDescendantOf(oldDir)
+func (name Name) Rebase(oldDir, newDir Name) Name {
+	return Name{
+		bucketName: newDir.bucketName,
+		objectName: newDir.objectName + strings.TrimPrefix(name.objectName, oldDir.objectName),
+	}
+}
+
 // ParentName returns the Name of the parent directory of the current Name.
 func (name Name) ParentName() (Name, error) {
 	if name.IsBucketRoot() {
@@ -0,0 +1,56 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NameMatching says how loosely a name that matches no child of a directory
+// exactly may match one. The zero value matches names exactly.
+type NameMatching struct {
+	// Ignore case, using Unicode case folding.
+	CaseInsensitive bool
+
+	// Compare names in Unicode normalization form C, so that composed and
+	// decomposed spellings of the same characters match.
+	Unicode bool
+}
+
+// Enabled reports whether names may match other than exactly.
+func (m NameMatching) Enabled() bool {
+	return m.CaseInsensitive || m.Unicode
+}
+
+// Key returns the string that names matching the supplied one have in common.
+func (m NameMatching) Key(name string) string {
+	if m.Unicode {
+		name = norm.NFC.String(name)
+	}
+	if m.CaseInsensitive {
+		// Folding may decompose a character, so normalize again afterwards.
+		name = cases.Fold().String(name)
+		if m.Unicode {
+			name = norm.NFC.String(name)
+		}
+	}
+	return name
+}
+
+// NormalizeName returns the supplied name in Unicode normalization form C.
+func NormalizeName(name string) string {
+	return norm.NFC.String(name)
+}
@@ -150,3 +150,65 @@ func TestParentNameReturnsErrorOnBucketRoot(t *testing.T) {
 		ExpectThat(err, Error(HasSubstr("root has no parent")))
 	}
 }
+
+func TestIsDescendantOf(t *testing.T) {
+	for _, bucketName := range []string{"", "bucketx"} {
+		root := inode.NewRootName(bucketName) // ""
+		foo := inode.NewDirName(root, "foo")  // "foo/"
+		bar := inode.NewDirName(foo, "bar")   // "foo/bar/"
+		qux := inode.NewFileName(bar, "qux")  // "foo/bar/qux"
+		fooBaz := inode.NewFileName(root, "foobaz")
+
+		ExpectTrue(foo.IsDescendantOf(root))
+		ExpectTrue(bar.IsDescendantOf(foo))
+		ExpectTrue(qux.IsDescendantOf(foo))
+		ExpectFalse(foo.IsDescendantOf(foo))
+		ExpectFalse(foo.IsDescendantOf(bar))
+		ExpectFalse(fooBaz.IsDescendantOf(foo))
+		ExpectFalse(qux.IsDescendantOf(inode.NewRootName("bucket-y")))
+	}
+}
+
+func TestRebase(t *testing.T) {
+	for _, bucketName := range []string{"", "bucketx"} {
+		root := inode.NewRootName(bucketName) // ""
+		foo := inode.NewDirName(root, "foo")  // "foo/"
+		qux := inode.NewFileName(inode.NewDirName(foo, "bar"), "qux")
+		baz := inode.NewDirName(inode.NewDirName(root, "a"), "baz") // "a/baz/"
+
+		rebased := qux.Rebase(foo, baz)
+
+		ExpectEq("a/baz/bar/qux", rebased.GcsObjectName())
+		ExpectTrue(rebased.IsFile())
+		ExpectTrue(rebased.IsDescendantOf(baz))
+	}
+}
+
+func TestBaseName(t *testing.T) {
+	for _, bucketName := range []string{"", "bucketx"} {
+		root := inode.NewRootName(bucketName) // ""
+		foo := inode.NewDirName(root, "foo")  // "foo/"
+		bar := inode.NewFileName(foo, "bar")  // "foo/bar"
+
+		ExpectEq("", root.BaseName())
+		ExpectEq("foo", foo.BaseName())
+		ExpectEq("bar", bar.BaseName())
+	}
+}
+
+func TestNameMatchingKey(t *testing.T) {
+	const composed, decomposed = "caf\u00e9", "cafe\u0301"
+	exact := inode.NameMatching{}
+	caseInsensitive := inode.NameMatching{CaseInsensitive: true}
+	unicode := inode.NameMatching{Unicode: true}
+	both := inode.NameMatching{CaseInsensitive: true, Unicode: true}
+
+	ExpectFalse(exact.Enabled())
+	ExpectNe(exact.Key("Foo"), exact.Key("foo"))
+	ExpectEq(caseInsensitive.Key("Foo"), caseInsensitive.Key("fOO"))
+	ExpectNe(caseInsensitive.Key(composed), caseInsensitive.Key(decomposed))
+	ExpectEq(unicode.Key(composed), unicode.Key(decomposed))
+	ExpectNe(unicode.Key("Foo"), unicode.Key("foo"))
+	ExpectEq(both.Key("CAF\u00c9"), both.Key(decomposed))
+	ExpectEq(composed, inode.NormalizeName(decomposed))
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// Metadata keys under which `gcloud storage cp --preserve-posix` (and
+// `gsutil cp -P`) record POSIX attributes. The mode holds the permission bits
+// in octal, the rest are decimal integers; atime is in seconds since the
+// epoch.
+const (
+	PosixModeMetadataKey = "goog-reserved-posix-mode"
+	PosixUidMetadataKey  = "goog-reserved-posix-uid"
+	PosixGidMetadataKey  = "goog-reserved-posix-gid"
+	FileAtimeMetadataKey = "goog-reserved-file-atime"
+)
+
+// PosixAttributes holds the attributes that can be persisted in object
+// metadata when POSIX metadata is enabled. Nil fields are left unchanged.
+type PosixAttributes struct {
+	Mode  *os.FileMode
+	Uid   *uint32
+	Gid   *uint32
+	Atime *time.Time
+}
+
+// IsEmpty reports whether p contains no changes.
+func (p PosixAttributes) IsEmpty() bool {
+	return p.Mode == nil && p.Uid == nil && p.Gid == nil && p.Atime == nil
+}
+
+// metadata returns p in the format of gcs.UpdateObjectRequest.Metadata.
+func (p PosixAttributes) metadata() map[string]*string {
+	m := make(map[string]*string)
+	set := func(key string, value string) {
+		m[key] = &value
+	}
+
+	if p.Mode != nil {
+		set(PosixModeMetadataKey, fmt.Sprintf("%o", p.Mode.Perm()))
+	}
+	if p.Uid != nil {
+		set(PosixUidMetadataKey, strconv.FormatUint(uint64(*p.Uid), 10))
+	}
+	if p.Gid != nil {
+		set(PosixGidMetadataKey, strconv.FormatUint(uint64(*p.Gid), 10))
+	}
+	if p.Atime != nil {
+		set(FileAtimeMetadataKey, strconv.FormatInt(p.Atime.Unix(), 10))
+	}
+
+	return m
+}
+
+// applyPosixMetadata overrides the permission bits, ownership and atime in
+// attrs with any values recorded in the supplied object metadata. Values that
+// don't parse are ignored, leaving the mount-wide defaults in place.
+func applyPosixMetadata(attrs *fuseops.InodeAttributes, metadata map[string]string) {
+	if s, ok := metadata[PosixModeMetadataKey]; ok {
+		if perm, err := strconv.ParseUint(s, 8, 32); err == nil {
+			attrs.Mode = attrs.Mode&^os.ModePerm | os.FileMode(perm)&os.ModePerm
+		}
+	}
+
+	if s, ok := metadata[PosixUidMetadataKey]; ok {
+		if uid, err := strconv.ParseUint(s, 10, 32); err == nil {
+			attrs.Uid = uint32(uid)
+		}
+	}
+
+	if s, ok := metadata[PosixGidMetadataKey]; ok {
+		if gid, err := strconv.ParseUint(s, 10, 32); err == nil {
+			attrs.Gid = uint32(gid)
+		}
+	}
+
+	if s, ok := metadata[FileAtimeMetadataKey]; ok {
+		if timestamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+			attrs.Atime = time.Unix(timestamp, 0)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/semaphore"
+)
+
+func TestApplyPosixMetadata(t *testing.T) {
+	defaults := fuseops.InodeAttributes{
+		Uid:   uid,
+		Gid:   gid,
+		Mode:  os.ModeDir | 0755,
+		Atime: time.Unix(1, 0),
+	}
+	testCases := []struct {
+		name     string
+		metadata map[string]string
+		expected fuseops.InodeAttributes
+	}{
+		{
+			name:     "no_metadata",
+			metadata: nil,
+			expected: defaults,
+		},
+		{
+			name: "all_keys",
+			metadata: map[string]string{
+				PosixModeMetadataKey: "700",
+				PosixUidMetadataKey:  "1000",
+				PosixGidMetadataKey:  "1001",
+				FileAtimeMetadataKey: "1700000000",
+			},
+			expected: fuseops.InodeAttributes{
+				Uid:   1000,
+				Gid:   1001,
+				Mode:  os.ModeDir | 0700,
+				Atime: time.Unix(1700000000, 0),
+			},
+		},
+		{
+			name: "invalid_values",
+			metadata: map[string]string{
+				PosixModeMetadataKey: "rwx",
+				PosixUidMetadataKey:  "-1",
+				FileAtimeMetadataKey: "yesterday",
+			},
+			expected: defaults,
+		},
+		{
+			name: "decimal_atime_with_leading_zero",
+			metadata: map[string]string{
+				FileAtimeMetadataKey: "010",
+			},
+			expected: fuseops.InodeAttributes{
+				Uid:   uid,
+				Gid:   gid,
+				Mode:  os.ModeDir | 0755,
+				Atime: time.Unix(10, 0),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := defaults
+
+			applyPosixMetadata(&attrs, tc.metadata)
+
+			assert.Equal(t, tc.expected, attrs)
+		})
+	}
+}
+
+func TestPosixAttributesMetadata(t *testing.T) {
+	mode := os.FileMode(0640)
+	uid := uint32(1000)
+	atime := time.Unix(1700000000, 5)
+
+	m := PosixAttributes{Mode: &mode, Uid: &uid, Atime: &atime}.metadata()
+
+	require.Len(t, m, 3)
+	assert.Equal(t, "640", *m[PosixModeMetadataKey])
+	assert.Equal(t, "1000", *m[PosixUidMetadataKey])
+	assert.Equal(t, "1700000000", *m[FileAtimeMetadataKey])
+}
+
+func (t *FileTest) TestSetPosixAttributes_ContentClean() {
+	t.in.config = &cfg.Config{FileSystem: cfg.FileSystemConfig{PreservePosix: true}}
+	mode := os.FileMode(0600)
+	owner := uint32(1000)
+
+	err := t.in.SetPosixAttributes(t.ctx, PosixAttributes{Mode: &mode, Uid: &owner})
+
+	require.NoError(t.T(), err)
+	m := t.statBackingObject()
+	assert.Equal(t.T(), "600", m.Metadata[PosixModeMetadataKey])
+	assert.Equal(t.T(), "1000", m.Metadata[PosixUidMetadataKey])
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), os.FileMode(0600), attrs.Mode)
+	assert.Equal(t.T(), owner, attrs.Uid)
+	assert.Equal(t.T(), uint32(gid), attrs.Gid)
+}
+
+func (t *FileTest) TestSetPosixAttributes_ContentDirty() {
+	t.in.config = &cfg.Config{FileSystem: cfg.FileSystemConfig{PreservePosix: true}}
+	_, err := t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+	mode := os.FileMode(0444)
+
+	err = t.in.SetPosixAttributes(t.ctx, PosixAttributes{Mode: &mode})
+
+	require.NoError(t.T(), err)
+	assert.NotContains(t.T(), t.statBackingObject().Metadata, PosixModeMetadataKey)
+	attrs, err := t.in.Attributes(t.ctx, true)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), mode, attrs.Mode)
+	_, err = t.in.Sync(t.ctx)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "444", t.statBackingObject().Metadata[PosixModeMetadataKey])
+}
+
+func (t *FileTest) TestAttributes_PosixMetadataIgnoredWhenDisabled() {
+	mode := os.FileMode(0600)
+	err := t.in.SetPosixAttributes(t.ctx, PosixAttributes{Mode: &mode})
+	require.NoError(t.T(), err)
+
+	attrs, err := t.in.Attributes(t.ctx, true)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), fileMode, attrs.Mode)
+}
+
+func TestExplicitDirInodePosixAttributes(t *testing.T) {
+	ctx := context.Background()
+	var clock timeutil.SimulatedClock
+	bucket := fake.NewFakeBucket(&clock, "some_bucket", gcs.BucketType{})
+	o, err := bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     "dir/",
+		Contents: strings.NewReader(""),
+		Metadata: map[string]string{PosixModeMetadataKey: "750", PosixGidMetadataKey: "1001"},
+	})
+	require.NoError(t, err)
+	syncerBucket := gcsx.NewSyncerBucket(1, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs, ".gcsfuse_tmp/", bucket)
+	d := NewExplicitDirInode(
+		dirInodeID,
+		NewDirName(NewRootName(""), "dir/"),
+		ctx,
+		storageutil.ConvertObjToMinObject(o),
+		fuseops.InodeAttributes{Uid: uid, Gid: gid, Mode: os.ModeDir | 0755},
+		false,
+		false,
+		typeCacheTTL,
+		&syncerBucket,
+		&clock,
+		&clock,
+		semaphore.NewWeighted(math.MaxInt64),
+		&cfg.Config{FileSystem: cfg.FileSystemConfig{PreservePosix: true}})
+	d.Lock()
+	defer d.Unlock()
+
+	attrs, err := d.Attributes(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeDir|0750, attrs.Mode)
+	assert.Equal(t, uint32(uid), attrs.Uid)
+	assert.Equal(t, uint32(1001), attrs.Gid)
+
+	mode := os.FileMode(0700)
+	err = d.SetPosixAttributes(ctx, PosixAttributes{Mode: &mode})
+
+	require.NoError(t, err)
+	attrs, err = d.Attributes(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeDir|0700, attrs.Mode)
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "dir/"})
+	require.NoError(t, err)
+	assert.Equal(t, "700", m.Metadata[PosixModeMetadataKey])
+}
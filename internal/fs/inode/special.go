@@ -0,0 +1,255 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+)
+
+// When SpecialFileTypeMetadataKey is present in an object record, the object
+// is an empty marker for a named pipe, socket or device node, of the type given
+// by the value. Device nodes record their device number in decimal under
+// SpecialFileRdevMetadataKey.
+const (
+	SpecialFileTypeMetadataKey = "gcsfuse_file_type"
+	SpecialFileRdevMetadataKey = "gcsfuse_rdev"
+)
+
+// The values of SpecialFileTypeMetadataKey, by the type bits they stand for.
+var specialFileTypes = map[string]os.FileMode{
+	"fifo":   os.ModeNamedPipe,
+	"socket": os.ModeSocket,
+	"char":   os.ModeDevice | os.ModeCharDevice,
+	"block":  os.ModeDevice,
+}
+
+// specialFileTypeName returns the value of SpecialFileTypeMetadataKey for a
+// file of the given mode, or false if it isn't a special file.
+func specialFileTypeName(mode os.FileMode) (string, bool) {
+	typ := mode.Type()
+	for name, bits := range specialFileTypes {
+		if typ == bits {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// IsSpecialFileMode reports whether mode is that of a named pipe, socket or
+// device node.
+func IsSpecialFileMode(mode os.FileMode) bool {
+	_, ok := specialFileTypeName(mode)
+	return ok
+}
+
+// IsSpecialFile reports whether the supplied object represents a special file.
+func IsSpecialFile(m *gcs.MinObject) bool {
+	return SpecialFileMode(m) != 0
+}
+
+// SpecialFileMode returns the type bits of the special file represented by m,
+// or zero if m isn't one.
+func SpecialFileMode(m *gcs.MinObject) os.FileMode {
+	if m == nil {
+		return 0
+	}
+
+	return specialFileTypes[m.Metadata[SpecialFileTypeMetadataKey]]
+}
+
+// SpecialFileDirentType returns the directory entry type of the special file
+// represented by m.
+func SpecialFileDirentType(m *gcs.MinObject) fuseutil.DirentType {
+	mode := SpecialFileMode(m)
+	switch {
+	case mode&os.ModeNamedPipe != 0:
+		return fuseutil.DT_FIFO
+	case mode&os.ModeSocket != 0:
+		return fuseutil.DT_Socket
+	case mode&os.ModeCharDevice != 0:
+		return fuseutil.DT_Char
+	case mode&os.ModeDevice != 0:
+		return fuseutil.DT_Block
+	default:
+		return fuseutil.DT_Unknown
+	}
+}
+
+// specialFileMetadata returns the metadata recording a special file of the
+// given mode and device number.
+//
+// REQUIRES: IsSpecialFileMode(mode)
+func specialFileMetadata(mode os.FileMode, rdev uint32) map[string]string {
+	name, _ := specialFileTypeName(mode)
+	m := map[string]string{
+		SpecialFileTypeMetadataKey: name,
+	}
+	if mode&os.ModeDevice != 0 {
+		m[SpecialFileRdevMetadataKey] = strconv.FormatUint(uint64(rdev), 10)
+	}
+
+	return m
+}
+
+// SpecialFileInode is a named pipe, socket or device node. Nothing but its
+// type and device number is stored; opening it is left to the kernel.
+type SpecialFileInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id               fuseops.InodeID
+	name             Name
+	bucket           *gcsx.SyncerBucket
+	sourceGeneration Generation
+	attrs            fuseops.InodeAttributes
+	metadata         map[string]string
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	lc lookupCount
+}
+
+var _ Inode = &SpecialFileInode{}
+
+// Create a special file inode for the supplied object record. The permission
+// bits and ownership are taken from attrs.
+//
+// REQUIRES: IsSpecialFile(m)
+func NewSpecialFileInode(
+	id fuseops.InodeID,
+	name Name,
+	bucket *gcsx.SyncerBucket,
+	m *gcs.MinObject,
+	attrs fuseops.InodeAttributes) (s *SpecialFileInode) {
+	s = &SpecialFileInode{
+		id:     id,
+		name:   name,
+		bucket: bucket,
+		sourceGeneration: Generation{
+			Object:   m.Generation,
+			Metadata: m.MetaGeneration,
+			Size:     m.Size,
+		},
+		attrs: fuseops.InodeAttributes{
+			Nlink: 1,
+			Uid:   attrs.Uid,
+			Gid:   attrs.Gid,
+			Mode:  attrs.Mode.Perm() | SpecialFileMode(m),
+			Atime: m.Updated,
+			Ctime: m.Updated,
+			Mtime: m.Updated,
+		},
+		metadata: m.Metadata,
+	}
+
+	if rdev, err := strconv.ParseUint(m.Metadata[SpecialFileRdevMetadataKey], 10, 32); err == nil {
+		s.attrs.Rdev = uint32(rdev)
+	}
+
+	// Set up lookup counting.
+	s.lc.Init(id)
+
+	return s
+}
+
+////////////////////////////////////////////////////////////////////////
+// Public interface
+////////////////////////////////////////////////////////////////////////
+
+func (s *SpecialFileInode) Lock() {
+	s.mu.Lock()
+}
+
+func (s *SpecialFileInode) Unlock() {
+	s.mu.Unlock()
+}
+
+func (s *SpecialFileInode) ID() fuseops.InodeID {
+	return s.id
+}
+
+func (s *SpecialFileInode) Name() Name {
+	return s.name
+}
+
+// SourceGeneration returns the object generation from which this inode was branched.
+//
+// LOCKS_REQUIRED(s)
+func (s *SpecialFileInode) SourceGeneration() Generation {
+	return s.sourceGeneration
+}
+
+func (s *SpecialFileInode) UpdateSize(size uint64) {
+	// Special files are always empty; keep the generation info consistent only.
+	s.sourceGeneration.Size = size
+}
+
+// LOCKS_REQUIRED(s.mu)
+func (s *SpecialFileInode) IncrementLookupCount() {
+	s.lc.Inc()
+}
+
+// LOCKS_REQUIRED(s.mu)
+func (s *SpecialFileInode) DecrementLookupCount(n uint64) (destroy bool) {
+	destroy = s.lc.Dec(n)
+	return
+}
+
+// LOCKS_REQUIRED(s.mu)
+func (s *SpecialFileInode) Destroy() (err error) {
+	// Nothing to do.
+	return
+}
+
+func (s *SpecialFileInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (attrs fuseops.InodeAttributes, err error) {
+	attrs = s.attrs
+	return
+}
+
+func (s *SpecialFileInode) Unlink() {
+}
+
+// Bucket returns the bucket that owns this inode.
+func (s *SpecialFileInode) Bucket() *gcsx.SyncerBucket {
+	return s.bucket
+}
+
+// Source returns the MinObject from which this inode was created.
+func (s *SpecialFileInode) Source() *gcs.MinObject {
+	return &gcs.MinObject{
+		Name:           s.name.GcsObjectName(),
+		Generation:     s.sourceGeneration.Object,
+		MetaGeneration: s.sourceGeneration.Metadata,
+		Size:           s.sourceGeneration.Size,
+		Metadata:       s.metadata,
+		Updated:        s.attrs.Mtime,
+	}
+}
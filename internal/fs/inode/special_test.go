@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpecialFileMetadataRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mode       os.FileMode
+		rdev       uint32
+		direntType fuseutil.DirentType
+	}{
+		{"fifo", os.ModeNamedPipe | 0644, 0, fuseutil.DT_FIFO},
+		{"socket", os.ModeSocket | 0755, 0, fuseutil.DT_Socket},
+		{"char", os.ModeDevice | os.ModeCharDevice | 0600, 0x0501, fuseutil.DT_Char},
+		{"block", os.ModeDevice | 0600, 0x0801, fuseutil.DT_Block},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.True(t, IsSpecialFileMode(tc.mode))
+			m := &gcs.MinObject{Name: "foo", Metadata: specialFileMetadata(tc.mode, tc.rdev)}
+
+			assert.True(t, IsSpecialFile(m))
+			assert.Equal(t, tc.mode.Type(), SpecialFileMode(m))
+			assert.Equal(t, tc.direntType, SpecialFileDirentType(m))
+		})
+	}
+}
+
+func TestIsSpecialFile_OtherObjects(t *testing.T) {
+	assert.False(t, IsSpecialFile(nil))
+	assert.False(t, IsSpecialFile(&gcs.MinObject{Name: "foo"}))
+	assert.False(t, IsSpecialFile(&gcs.MinObject{Name: "foo", Metadata: map[string]string{SpecialFileTypeMetadataKey: "door"}}))
+	assert.False(t, IsSpecialFileMode(0644))
+	assert.False(t, IsSpecialFileMode(os.ModeDir|0755))
+	assert.False(t, IsSpecialFileMode(os.ModeSymlink|0777))
+}
+
+func TestSpecialFileInodeAttributes(t *testing.T) {
+	updated := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := &gcs.MinObject{
+		Name:       "dir/dev",
+		Generation: 17,
+		Updated:    updated,
+		Metadata:   specialFileMetadata(os.ModeDevice|os.ModeCharDevice, 0x0103),
+	}
+	in := NewSpecialFileInode(
+		fuseops.InodeID(42),
+		NewFileName(NewRootName(""), m.Name),
+		nil,
+		m,
+		fuseops.InodeAttributes{Uid: 7, Gid: 8, Mode: 0640})
+
+	attrs, err := in.Attributes(context.Background(), true)
+
+	require.NoError(t, err)
+	assert.Equal(t, os.ModeDevice|os.ModeCharDevice|0640, attrs.Mode)
+	assert.Equal(t, uint32(0x0103), attrs.Rdev)
+	assert.Equal(t, uint32(7), attrs.Uid)
+	assert.Equal(t, uint32(8), attrs.Gid)
+	assert.Equal(t, uint64(0), attrs.Size)
+	assert.Equal(t, updated, attrs.Mtime)
+	assert.Equal(t, int64(17), in.SourceGeneration().Object)
+	assert.Equal(t, m.Name, in.Source().Name)
+}
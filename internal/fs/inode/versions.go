@@ -0,0 +1,534 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/locker"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+)
+
+// VersionsDirSuffix marks the name of the virtual directory that lists the
+// generations of a file, when versions directories are enabled. For a file
+// foo, the generations are the entries of foo@versions, each named after its
+// generation number. A real file or directory whose name ends with the suffix
+// is hidden by the virtual one.
+const VersionsDirSuffix = "@versions"
+
+// listVersions returns every generation of the named object, oldest first.
+func listVersions(ctx context.Context, bucket *gcsx.SyncerBucket, objectName string) ([]*gcs.MinObject, error) {
+	var versions []*gcs.MinObject
+	req := &gcs.ListObjectsRequest{
+		Prefix:    objectName,
+		Delimiter: "/",
+		Versions:  true,
+	}
+
+	for {
+		listing, err := bucket.ListObjects(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("ListObjects: %w", err)
+		}
+
+		for _, o := range listing.MinObjects {
+			switch {
+			case o.Name == objectName:
+				versions = append(versions, o)
+			case o.Name > objectName:
+				// Names are listed in order, so there is nothing more to find.
+				return versions, nil
+			}
+		}
+
+		if listing.ContinuationToken == "" {
+			return versions, nil
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
+
+// lookUpVersions returns the core of the versions directory of the named
+// child file, or nil if the file has no generations at all.
+//
+// LOCKS_REQUIRED(d.mu.RLock)
+func (d *dirInode) lookUpVersions(ctx context.Context, fileName string) (*Core, error) {
+	versions, err := listVersions(ctx, d.Bucket(), NewFileName(d.Name(), fileName).GcsObjectName())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	return &Core{
+		FullName: NewDirName(d.Name(), fileName+VersionsDirSuffix),
+		Bucket:   d.Bucket(),
+		Version:  true,
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Versions directories
+////////////////////////////////////////////////////////////////////////
+
+// An inode for the read-only directory listing the generations of a file.
+type versionsDirInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id fuseops.InodeID
+
+	// INVARIANT: name.IsDir()
+	name Name
+
+	// The name of the object whose generations are listed.
+	objectName string
+
+	bucket *gcsx.SyncerBucket
+	attrs  fuseops.InodeAttributes
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu locker.RWLocker
+
+	// GUARDED_BY(mu)
+	lc lookupCount
+}
+
+var _ BucketOwnedDirInode = &versionsDirInode{}
+
+// NewVersionsDirInode returns the versions directory with the supplied name,
+// which must end with VersionsDirSuffix and a slash.
+func NewVersionsDirInode(
+	id fuseops.InodeID,
+	name Name,
+	bucket *gcsx.SyncerBucket,
+	attrs fuseops.InodeAttributes) DirInode {
+	if !name.IsDir() || !strings.HasSuffix(name.GcsObjectName(), VersionsDirSuffix+"/") {
+		panic(fmt.Sprintf("Unexpected name: %s", name))
+	}
+
+	attrs.Mode &^= 0222
+	vd := &versionsDirInode{
+		id:         id,
+		name:       name,
+		objectName: strings.TrimSuffix(name.GcsObjectName(), VersionsDirSuffix+"/"),
+		bucket:     bucket,
+		attrs:      attrs,
+	}
+	vd.lc.Init(id)
+	vd.mu = locker.NewRW("VersionsDirInode"+name.GcsObjectName(), func() {})
+
+	return vd
+}
+
+func (vd *versionsDirInode) Lock() {
+	vd.mu.Lock()
+}
+
+func (vd *versionsDirInode) Unlock() {
+	vd.mu.Unlock()
+}
+
+func (vd *versionsDirInode) RLock() {
+	vd.mu.RLock()
+}
+
+func (vd *versionsDirInode) RUnlock() {
+	vd.mu.RUnlock()
+}
+
+func (vd *versionsDirInode) LockForChildLookup() {
+	vd.mu.RLock()
+}
+
+func (vd *versionsDirInode) UnlockForChildLookup() {
+	vd.mu.RUnlock()
+}
+
+func (vd *versionsDirInode) ID() fuseops.InodeID {
+	return vd.id
+}
+
+func (vd *versionsDirInode) Name() Name {
+	return vd.name
+}
+
+func (vd *versionsDirInode) Bucket() *gcsx.SyncerBucket {
+	return vd.bucket
+}
+
+// LOCKS_REQUIRED(vd)
+func (vd *versionsDirInode) IncrementLookupCount() {
+	vd.lc.Inc()
+}
+
+// LOCKS_REQUIRED(vd)
+func (vd *versionsDirInode) DecrementLookupCount(n uint64) (destroy bool) {
+	return vd.lc.Dec(n)
+}
+
+func (vd *versionsDirInode) Destroy() error {
+	return nil
+}
+
+func (vd *versionsDirInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (fuseops.InodeAttributes, error) {
+	attrs := vd.attrs
+	attrs.Nlink = 1
+	return attrs, nil
+}
+
+func (vd *versionsDirInode) UpdateSize(size uint64) {}
+
+// LookUpChild accepts only the decimal generation numbers of the file.
+//
+// LOCKS_REQUIRED(vd.mu.RLock)
+func (vd *versionsDirInode) LookUpChild(ctx context.Context, name string) (*Core, error) {
+	generation, err := strconv.ParseInt(name, 10, 64)
+	if err != nil || generation <= 0 || strconv.FormatInt(generation, 10) != name {
+		return nil, nil
+	}
+
+	versions, err := listVersions(ctx, vd.bucket, vd.objectName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range versions {
+		if m.Generation == generation {
+			return vd.versionCore(m), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (vd *versionsDirInode) versionCore(m *gcs.MinObject) *Core {
+	return &Core{
+		FullName:  NewFileName(vd.name, strconv.FormatInt(m.Generation, 10)),
+		Bucket:    vd.bucket,
+		MinObject: m,
+		Version:   true,
+	}
+}
+
+func (vd *versionsDirInode) ReadDescendants(ctx context.Context, limit int) (map[Name]*Core, error) {
+	return nil, fuse.ENOSYS
+}
+
+// LOCKS_REQUIRED(vd)
+func (vd *versionsDirInode) ReadEntries(
+	ctx context.Context,
+	tok string) (entries []fuseutil.Dirent, unsupportedPaths []string, newTok string, err error) {
+	versions, err := listVersions(ctx, vd.bucket, vd.objectName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	for _, m := range versions {
+		entries = append(entries, fuseutil.Dirent{
+			Name: strconv.FormatInt(m.Generation, 10),
+			Type: fuseutil.DT_File,
+		})
+	}
+
+	return entries, nil, "", nil
+}
+
+// LOCKS_REQUIRED(vd)
+func (vd *versionsDirInode) ReadEntryCores(ctx context.Context, tok string) (cores map[Name]*Core, unsupportedPaths []string, newTok string, err error) {
+	versions, err := listVersions(ctx, vd.bucket, vd.objectName)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	cores = make(map[Name]*Core)
+	for _, m := range versions {
+		c := vd.versionCore(m)
+		cores[c.FullName] = c
+	}
+
+	return cores, nil, "", nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Forbidden Public interface
+////////////////////////////////////////////////////////////////////////
+
+// The generations of a file can't be changed through its versions directory.
+// Mutations fail with EROFS.
+
+func (vd *versionsDirInode) CreateChildFile(ctx context.Context, name string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) CreateLocalChildFileCore(name string) (Core, error) {
+	return Core{}, syscall.EROFS
+}
+
+func (vd *versionsDirInode) InsertFileIntoTypeCache(_ string) {}
+
+func (vd *versionsDirInode) EraseFromTypeCache(_ string) {}
+
+func (vd *versionsDirInode) CloneToChildFile(ctx context.Context, name string, src *gcs.MinObject) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) CopyChildFileFromBucket(ctx context.Context, name string, srcBucket string, src *gcs.MinObject, dstGeneration int64) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) CreateChildSymlink(ctx context.Context, name string, target string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) CreateChildSpecialFile(ctx context.Context, name string, mode os.FileMode, rdev uint32) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) CreateChildDir(ctx context.Context, name string) (*Core, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) DeleteChildFile(
+	ctx context.Context,
+	name string,
+	generation int64,
+	metaGeneration *int64) error {
+	return syscall.EROFS
+}
+
+func (vd *versionsDirInode) DeleteChildDir(
+	ctx context.Context,
+	name string,
+	isImplicitDir bool,
+	dirInode DirInode) error {
+	return syscall.EROFS
+}
+
+func (vd *versionsDirInode) TrashChildFile(ctx context.Context, name string) error {
+	return syscall.EROFS
+}
+
+func (vd *versionsDirInode) TrashChildDir(
+	ctx context.Context,
+	name string,
+	isImplicitDir bool,
+	dirInode DirInode) error {
+	return syscall.EROFS
+}
+
+func (vd *versionsDirInode) DeleteObjects(ctx context.Context, objectNames []string) error {
+	return syscall.EROFS
+}
+
+func (vd *versionsDirInode) RenameFile(ctx context.Context, fileToRename *gcs.MinObject, destinationFileName string) (*gcs.Object, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) RenameFolder(ctx context.Context, folderName string, destinationFolderId string, folderInode DirInode) (*gcs.Folder, error) {
+	return nil, syscall.EROFS
+}
+
+func (vd *versionsDirInode) LocalFileEntries(localFileInodes map[Name]Inode) map[string]fuseutil.Dirent {
+	return nil
+}
+
+// New generations can turn up at any time, so the listing is never cached.
+func (vd *versionsDirInode) ShouldInvalidateKernelListCache(ttl time.Duration) bool {
+	return true
+}
+
+func (vd *versionsDirInode) InvalidateKernelListCache() {}
+
+func (vd *versionsDirInode) IsUnlinked() bool {
+	return false
+}
+
+func (vd *versionsDirInode) Unlink() {}
+
+func (vd *versionsDirInode) IsTypeCacheDeprecated() bool {
+	return true
+}
+
+func (vd *versionsDirInode) CancelCurrDirPrefetcher() {}
+
+func (vd *versionsDirInode) CancelSubdirectoryPrefetches() {}
+
+func (vd *versionsDirInode) Context() context.Context {
+	return context.Background()
+}
+
+func (vd *versionsDirInode) IncrementActiveWriters() {}
+
+func (vd *versionsDirInode) DecrementActiveWriters() {}
+
+////////////////////////////////////////////////////////////////////////
+// Version files
+////////////////////////////////////////////////////////////////////////
+
+// VersionFileInode is a read-only file holding one generation of an object,
+// which need not be the live one.
+type VersionFileInode struct {
+	/////////////////////////
+	// Constant data
+	/////////////////////////
+
+	id     fuseops.InodeID
+	name   Name
+	bucket *gcsx.SyncerBucket
+	src    gcs.MinObject
+	attrs  fuseops.InodeAttributes
+
+	/////////////////////////
+	// Mutable state
+	/////////////////////////
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	lc lookupCount
+}
+
+var _ GenerationBackedInode = &VersionFileInode{}
+var _ BucketOwnedInode = &VersionFileInode{}
+
+// NewVersionFileInode returns a file holding the generation of the object
+// described by m. Write permissions in attrs are dropped.
+func NewVersionFileInode(
+	id fuseops.InodeID,
+	name Name,
+	bucket *gcsx.SyncerBucket,
+	m *gcs.MinObject,
+	attrs fuseops.InodeAttributes) *VersionFileInode {
+	f := &VersionFileInode{
+		id:     id,
+		name:   name,
+		bucket: bucket,
+		src:    *m,
+		attrs: fuseops.InodeAttributes{
+			Size:  m.Size,
+			Nlink: 1,
+			Uid:   attrs.Uid,
+			Gid:   attrs.Gid,
+			Mode:  attrs.Mode &^ 0222,
+			Atime: m.Updated,
+			Ctime: m.Updated,
+			Mtime: m.Updated,
+		},
+	}
+	f.lc.Init(id)
+
+	return f
+}
+
+func (f *VersionFileInode) Lock() {
+	f.mu.Lock()
+}
+
+func (f *VersionFileInode) Unlock() {
+	f.mu.Unlock()
+}
+
+func (f *VersionFileInode) ID() fuseops.InodeID {
+	return f.id
+}
+
+func (f *VersionFileInode) Name() Name {
+	return f.name
+}
+
+func (f *VersionFileInode) Bucket() *gcsx.SyncerBucket {
+	return f.bucket
+}
+
+// SourceGeneration returns the generation held by the file.
+func (f *VersionFileInode) SourceGeneration() Generation {
+	return Generation{
+		Object:   f.src.Generation,
+		Metadata: f.src.MetaGeneration,
+		Size:     f.src.Size,
+	}
+}
+
+// A generation's contents never change.
+func (f *VersionFileInode) UpdateSize(size uint64) {}
+
+// LOCKS_REQUIRED(f.mu)
+func (f *VersionFileInode) IncrementLookupCount() {
+	f.lc.Inc()
+}
+
+// LOCKS_REQUIRED(f.mu)
+func (f *VersionFileInode) DecrementLookupCount(n uint64) (destroy bool) {
+	return f.lc.Dec(n)
+}
+
+func (f *VersionFileInode) Destroy() error {
+	return nil
+}
+
+func (f *VersionFileInode) Attributes(
+	ctx context.Context, clobberedCheck bool) (fuseops.InodeAttributes, error) {
+	return f.attrs, nil
+}
+
+func (f *VersionFileInode) Unlink() {}
+
+// Read reads the generation at the supplied offset into dst, returning io.EOF
+// if the end of the generation is reached. The inode lock isn't required.
+func (f *VersionFileInode) Read(ctx context.Context, dst []byte, offset int64) (int, error) {
+	if offset >= int64(f.src.Size) {
+		return 0, io.EOF
+	}
+
+	limit := min(uint64(offset)+uint64(len(dst)), f.src.Size)
+	rd, err := f.bucket.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+		Name:       f.src.Name,
+		Generation: f.src.Generation,
+		Range: &gcs.ByteRange{
+			Start: uint64(offset),
+			Limit: limit,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("NewReaderWithReadHandle: %w", err)
+	}
+	defer rd.Close()
+
+	n, err := io.ReadFull(rd, dst[:limit-uint64(offset)])
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse"
+)
+
+// Extended attributes in this namespace are stored as custom metadata on the
+// backing object, keyed by the attribute name with the namespace stripped. A
+// file tagged with "user.label" therefore carries a "label" metadata entry
+// that other GCS clients can read.
+const UserXattrPrefix = "user."
+
+// Extended attributes in this namespace are read-only and describe the GCS
+// object backing a file as of its last sync. They are not returned by
+// ListXattrs, so that tools copying every attribute don't try to carry them
+// over to other file systems.
+const GcsfuseXattrPrefix = "gcsfuse."
+
+// Names of the virtual attributes in the gcsfuse namespace. Checksums are
+// base64 encoded in big-endian byte order, as in the GCS JSON API.
+const (
+	GenerationXattr      = GcsfuseXattrPrefix + "generation"
+	MetaGenerationXattr  = GcsfuseXattrPrefix + "metageneration"
+	CRC32CXattr          = GcsfuseXattrPrefix + "crc32c"
+	MD5Xattr             = GcsfuseXattrPrefix + "md5"
+	StorageClassXattr    = GcsfuseXattrPrefix + "storage-class"
+	ContentTypeXattr     = GcsfuseXattrPrefix + "content-type"
+	ContentEncodingXattr = GcsfuseXattrPrefix + "content-encoding"
+	BucketXattr          = GcsfuseXattrPrefix + "bucket"
+	ObjectXattr          = GcsfuseXattrPrefix + "object"
+)
+
+const (
+	// Flags accepted by setxattr(2).
+	xattrCreate  = 0x1
+	xattrReplace = 0x2
+
+	// GCS limits the combined size of custom metadata keys and values on an
+	// object.
+	maxCustomMetadataBytes = 8 * 1024
+
+	// Metadata keys with these prefixes are owned by gcsfuse, e.g.
+	// gcsfuse_mtime, or by GCS and gcloud, e.g. goog-reserved-posix-mode.
+	gcsfuseMetadataKeyPrefix  = "gcsfuse_"
+	reservedMetadataKeyPrefix = "goog-reserved-"
+)
+
+// isReservedMetadataKey reports whether the supplied metadata key is owned by
+// gcsfuse or GCS and must therefore not be exposed as an extended attribute.
+func isReservedMetadataKey(key string) bool {
+	return strings.HasPrefix(key, gcsfuseMetadataKeyPrefix) ||
+		strings.HasPrefix(key, reservedMetadataKeyPrefix)
+}
+
+// xattrMetadataKey returns the metadata key under which the named extended
+// attribute is stored. Names outside the user namespace are not supported.
+func xattrMetadataKey(name string) (key string, err error) {
+	if strings.HasPrefix(name, GcsfuseXattrPrefix) {
+		err = syscall.EPERM
+		return
+	}
+
+	key, ok := strings.CutPrefix(name, UserXattrPrefix)
+	if !ok {
+		err = syscall.ENOTSUP
+		return
+	}
+
+	if key == "" || isReservedMetadataKey(key) {
+		err = syscall.EPERM
+		return
+	}
+
+	return
+}
+
+// Xattr returns the value of the named extended attribute, or ENOATTR if it
+// is not set. Reading some of the attributes in the gcsfuse namespace may
+// involve a round trip to GCS.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) Xattr(
+	ctx context.Context,
+	name string) (value []byte, err error) {
+	if strings.HasPrefix(name, GcsfuseXattrPrefix) {
+		var v string
+		v, err = f.virtualXattr(ctx, name)
+		value = []byte(v)
+		return
+	}
+
+	key, ok := strings.CutPrefix(name, UserXattrPrefix)
+	if !ok || isReservedMetadataKey(key) {
+		err = fuse.ENOATTR
+		return
+	}
+
+	v, ok := f.metadataWithPendingUpdates()[key]
+	if !ok {
+		err = fuse.ENOATTR
+		return
+	}
+
+	value = []byte(v)
+	return
+}
+
+// virtualXattr returns the value of the named attribute in the gcsfuse
+// namespace.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) virtualXattr(
+	ctx context.Context,
+	name string) (value string, err error) {
+	switch name {
+	case BucketXattr:
+		return f.bucket.Name(), nil
+	case ObjectXattr:
+		return f.Name().GcsObjectName(), nil
+	}
+
+	// The remaining attributes describe the backing object, which local files
+	// don't have yet.
+	if f.IsLocal() {
+		err = fuse.ENOATTR
+		return
+	}
+
+	switch name {
+	case GenerationXattr:
+		value = strconv.FormatInt(f.src.Generation, 10)
+	case MetaGenerationXattr:
+		value = strconv.FormatInt(f.src.MetaGeneration, 10)
+	case ContentEncodingXattr:
+		value = f.src.ContentEncoding
+	case CRC32CXattr:
+		if f.src.CRC32C == nil {
+			err = fuse.ENOATTR
+			return
+		}
+		value = base64.StdEncoding.EncodeToString(binary.BigEndian.AppendUint32(nil, *f.src.CRC32C))
+	case MD5Xattr, StorageClassXattr, ContentTypeXattr:
+		value, err = f.extendedObjectAttribute(ctx, name)
+	default:
+		err = fuse.ENOATTR
+	}
+
+	if err == nil && value == "" {
+		err = fuse.ENOATTR
+	}
+
+	return
+}
+
+// extendedObjectAttribute fetches the named attribute from the
+// gcs.ExtendedObjectAttributes of the backing object, which aren't kept in
+// the inode.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) extendedObjectAttribute(
+	ctx context.Context,
+	name string) (value string, err error) {
+	m, e, err := f.bucket.StatObject(ctx, &gcs.StatObjectRequest{
+		Name:                           f.src.Name,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+
+	var notFoundErr *gcs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		err = fuse.ENOATTR
+		return
+	}
+
+	if err != nil {
+		err = fmt.Errorf("StatObject: %w", err)
+		return
+	}
+
+	// Don't describe a generation other than the one the inode is backed by.
+	if m == nil || m.Generation != f.src.Generation {
+		err = &gcsfuse_errors.FileClobberedError{
+			Err:        fmt.Errorf("generation changed from %d", f.src.Generation),
+			ObjectName: f.src.Name,
+		}
+		return
+	}
+
+	if e == nil {
+		err = fuse.ENOATTR
+		return
+	}
+
+	switch name {
+	case MD5Xattr:
+		if e.MD5 != nil {
+			value = base64.StdEncoding.EncodeToString(e.MD5[:])
+		}
+	case StorageClassXattr:
+		value = e.StorageClass
+	case ContentTypeXattr:
+		value = e.ContentType
+	}
+
+	return
+}
+
+// ListXattrs returns the names of all extended attributes set on the file,
+// in sorted order.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) ListXattrs() (names []string) {
+	for k := range f.metadataWithPendingUpdates() {
+		if isReservedMetadataKey(k) {
+			continue
+		}
+		names = append(names, UserXattrPrefix+k)
+	}
+
+	sort.Strings(names)
+	return
+}
+
+// SetXattr sets the named extended attribute. flags has the semantics of the
+// setxattr(2) flags argument.
+//
+// Updates to files with local modifications are deferred until they are
+// synced, see updateMetadata.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) SetXattr(
+	ctx context.Context,
+	name string,
+	value []byte,
+	flags uint32) (err error) {
+	key, err := xattrMetadataKey(name)
+	if err != nil {
+		return
+	}
+
+	// GCS metadata values are strings, so refuse anything we couldn't hand
+	// back unchanged.
+	if !utf8.Valid(value) {
+		err = syscall.EINVAL
+		return
+	}
+
+	m := f.metadataWithPendingUpdates()
+	_, exists := m[key]
+	if flags&xattrCreate != 0 && exists {
+		err = syscall.EEXIST
+		return
+	}
+	if flags&xattrReplace != 0 && !exists {
+		err = fuse.ENOATTR
+		return
+	}
+
+	v := string(value)
+	m[key] = v
+	size := 0
+	for k, v := range m {
+		size += len(k) + len(v)
+	}
+	if size > maxCustomMetadataBytes {
+		err = syscall.ENOSPC
+		return
+	}
+
+	err = f.updateMetadata(ctx, map[string]*string{key: &v})
+	return
+}
+
+// RemoveXattr removes the named extended attribute, returning ENOATTR if it
+// is not set.
+//
+// LOCKS_REQUIRED(f.mu)
+func (f *FileInode) RemoveXattr(ctx context.Context, name string) (err error) {
+	key, err := xattrMetadataKey(name)
+	if err != nil {
+		return
+	}
+
+	if _, ok := f.metadataWithPendingUpdates()[key]; !ok {
+		err = fuse.ENOATTR
+		return
+	}
+
+	err = f.updateMetadata(ctx, map[string]*string{key: nil})
+	return
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inode
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/gcsfuse_errors"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (t *FileTest) statBackingObject() *gcs.MinObject {
+	t.T().Helper()
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: t.in.Name().GcsObjectName()})
+	require.NoError(t.T(), err)
+	return m
+}
+
+func (t *FileTest) TestSetXattr_ContentClean() {
+	err := t.in.SetXattr(t.ctx, "user.label", []byte("cat"), 0)
+	require.NoError(t.T(), err)
+
+	value, err := t.in.Xattr(t.ctx, "user.label")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "cat", string(value))
+	assert.Equal(t.T(), "cat", t.statBackingObject().Metadata["label"])
+	assert.Equal(t.T(), t.backingObj.Generation, t.in.SourceGeneration().Object)
+	assert.Greater(t.T(), t.in.SourceGeneration().Metadata, t.backingObj.MetaGeneration)
+}
+
+func (t *FileTest) TestSetXattr_ContentDirty() {
+	_, err := t.in.Write(t.ctx, []byte("burrito"), 0, WriteMode)
+	require.NoError(t.T(), err)
+
+	err = t.in.SetXattr(t.ctx, "user.label", []byte("cat"), 0)
+	require.NoError(t.T(), err)
+
+	// The attribute is visible through the inode but not yet in GCS.
+	value, err := t.in.Xattr(t.ctx, "user.label")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "cat", string(value))
+	assert.NotContains(t.T(), t.statBackingObject().Metadata, "label")
+
+	gcsSynced, err := t.in.Sync(t.ctx)
+
+	require.NoError(t.T(), err)
+	assert.True(t.T(), gcsSynced)
+	m := t.statBackingObject()
+	assert.Equal(t.T(), uint64(len("burrito")), m.Size)
+	assert.Equal(t.T(), "cat", m.Metadata["label"])
+	// The attribute went out with the contents rather than in an update.
+	assert.Equal(t.T(), int64(1), m.MetaGeneration)
+	assert.Nil(t.T(), t.in.pendingMetadata)
+}
+
+func (t *FileTest) TestSetXattr_LocalFile() {
+	t.createInodeWithLocalParam("local", true)
+	err := t.in.CreateEmptyTempFile(t.ctx)
+	require.NoError(t.T(), err)
+
+	err = t.in.SetXattr(t.ctx, "user.label", []byte("cat"), 0)
+	require.NoError(t.T(), err)
+	_, err = t.in.Sync(t.ctx)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "cat", t.statBackingObject().Metadata["label"])
+}
+
+func (t *FileTest) TestSetXattr_Flags() {
+	err := t.in.SetXattr(t.ctx, "user.label", []byte("cat"), xattrReplace)
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+
+	err = t.in.SetXattr(t.ctx, "user.label", []byte("cat"), xattrCreate)
+	require.NoError(t.T(), err)
+
+	err = t.in.SetXattr(t.ctx, "user.label", []byte("dog"), xattrCreate)
+	assert.ErrorIs(t.T(), err, syscall.EEXIST)
+
+	err = t.in.SetXattr(t.ctx, "user.label", []byte("dog"), xattrReplace)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "dog", t.statBackingObject().Metadata["label"])
+}
+
+func (t *FileTest) TestSetXattr_InvalidNamesAndValues() {
+	testCases := []struct {
+		name  string
+		value []byte
+		err   error
+	}{
+		{name: "security.selinux", value: []byte("x"), err: syscall.ENOTSUP},
+		{name: "user.", value: []byte("x"), err: syscall.EPERM},
+		{name: "user." + FileMtimeMetadataKey, value: []byte("x"), err: syscall.EPERM},
+		{name: "user.goog-reserved-posix-mode", value: []byte("x"), err: syscall.EPERM},
+		{name: "user." + SpecialFileTypeMetadataKey, value: []byte("fifo"), err: syscall.EPERM},
+		{name: "user." + SpecialFileRdevMetadataKey, value: []byte("259"), err: syscall.EPERM},
+		{name: "user.gcsfuse_trash_deleted", value: []byte("2026-01-01T00:00:00Z"), err: syscall.EPERM},
+		{name: "user.gcsfuse_not_yet_used", value: []byte("x"), err: syscall.EPERM},
+		{name: "user.goog-reserved-not-yet-used", value: []byte("x"), err: syscall.EPERM},
+		{name: "user.label", value: []byte{0xff, 0xfe}, err: syscall.EINVAL},
+		{name: "user.label", value: make([]byte, maxCustomMetadataBytes), err: syscall.ENOSPC},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func() {
+			err := t.in.SetXattr(t.ctx, tc.name, tc.value, 0)
+
+			assert.ErrorIs(t.T(), err, tc.err)
+		})
+	}
+}
+
+func (t *FileTest) TestListXattrs_HidesReservedMetadata() {
+	err := t.in.SetMtime(t.ctx, time.Now())
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.b", []byte("2"), 0))
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.a", []byte("1"), 0))
+
+	assert.Equal(t.T(), []string{"user.a", "user.b"}, t.in.ListXattrs())
+	_, err = t.in.Xattr(t.ctx, "user."+FileMtimeMetadataKey)
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+}
+
+func (t *FileTest) TestRemoveXattr() {
+	require.NoError(t.T(), t.in.SetXattr(t.ctx, "user.label", []byte("cat"), 0))
+
+	err := t.in.RemoveXattr(t.ctx, "user.label")
+
+	require.NoError(t.T(), err)
+	assert.NotContains(t.T(), t.statBackingObject().Metadata, "label")
+	_, err = t.in.Xattr(t.ctx, "user.label")
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+	err = t.in.RemoveXattr(t.ctx, "user.label")
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+}
+
+func (t *FileTest) TestSetXattr_ClobberedObject() {
+	other := "x"
+	_, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:     t.in.Name().GcsObjectName(),
+		Metadata: map[string]*string{"other": &other},
+	})
+	require.NoError(t.T(), err)
+
+	err = t.in.SetXattr(t.ctx, "user.label", []byte("cat"), 0)
+
+	var clobberedErr *gcsfuse_errors.FileClobberedError
+	assert.ErrorAs(t.T(), err, &clobberedErr)
+	assert.NotContains(t.T(), t.statBackingObject().Metadata, "label")
+}
+
+func (t *FileTest) TestXattr_GcsfuseNamespace() {
+	o, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{
+		Name:                           fileName,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+	require.NoError(t.T(), err)
+	crc := make([]byte, 4)
+	binary.BigEndian.PutUint32(crc, *o.CRC32C)
+	md5Sum := md5.Sum([]byte(t.initialContents))
+
+	testCases := []struct {
+		name  string
+		value string
+	}{
+		{name: GenerationXattr, value: strconv.FormatInt(o.Generation, 10)},
+		{name: MetaGenerationXattr, value: strconv.FormatInt(o.MetaGeneration, 10)},
+		{name: CRC32CXattr, value: base64.StdEncoding.EncodeToString(crc)},
+		{name: MD5Xattr, value: base64.StdEncoding.EncodeToString(md5Sum[:])},
+		{name: StorageClassXattr, value: "STANDARD"},
+		{name: BucketXattr, value: "some_bucket"},
+		{name: ObjectXattr, value: fileName},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func() {
+			value, err := t.in.Xattr(t.ctx, tc.name)
+
+			require.NoError(t.T(), err)
+			assert.Equal(t.T(), tc.value, string(value))
+		})
+	}
+}
+
+func (t *FileTest) TestXattr_GcsfuseNamespaceUnsetValues() {
+	_, err := t.in.Xattr(t.ctx, ContentEncodingXattr)
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+
+	_, err = t.in.Xattr(t.ctx, GcsfuseXattrPrefix+"unknown")
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+}
+
+func (t *FileTest) TestXattr_GcsfuseNamespaceLocalFile() {
+	t.createInodeWithLocalParam("local", true)
+
+	_, err := t.in.Xattr(t.ctx, GenerationXattr)
+	assert.ErrorIs(t.T(), err, fuse.ENOATTR)
+
+	value, err := t.in.Xattr(t.ctx, ObjectXattr)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "local", string(value))
+}
+
+func (t *FileTest) TestXattr_GcsfuseNamespaceIsReadOnly() {
+	err := t.in.SetXattr(t.ctx, GenerationXattr, []byte("1"), 0)
+	assert.ErrorIs(t.T(), err, syscall.EPERM)
+
+	err = t.in.RemoveXattr(t.ctx, GenerationXattr)
+	assert.ErrorIs(t.T(), err, syscall.EPERM)
+
+	assert.Empty(t.T(), t.in.ListXattrs())
+}
+
+func (t *FileTest) TestXattr_GcsfuseNamespaceClobbered() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, fileName, []byte("burrito"))
+	require.NoError(t.T(), err)
+
+	_, err = t.in.Xattr(t.ctx, StorageClassXattr)
+
+	var clobberedErr *gcsfuse_errors.FileClobberedError
+	assert.ErrorAs(t.T(), err, &clobberedErr)
+}
@@ -504,7 +504,7 @@ func (t *LocalFileTest) TestRenameOfLocalFile() {
 	t.validateObjectNotFoundErr(FileName)
 }
 
-func (t *LocalFileTest) TestRenameOfDirectoryWithLocalFileFails() {
+func (t *LocalFileTest) TestRenameOfDirectoryWithLocalFile() {
 	// Create directory foo.
 	require.NoError(t.T(),
 		t.createObjects(
@@ -517,31 +517,38 @@ func (t *LocalFileTest) TestRenameOfDirectoryWithLocalFileFails() {
 	_, err := t.f1.WriteString(FileContents)
 	require.NoError(t.T(), err)
 
-	// Attempt to rename directory containing local file.
+	// Rename directory containing local file.
 	err = os.Rename(path.Join(mntDir, "foo/"), path.Join(mntDir, "bar/"))
 
-	// Verify rename operation fails.
-	require.Error(t.T(), err)
-	assert.True(t.T(), strings.Contains(err.Error(), "operation not supported"))
-	// write more content to local file.
+	// Verify that the local file was written out and moved with the directory.
+	require.NoError(t.T(), err)
+	t.validateObjectContents("bar/"+FileName, FileContents)
+	t.validateObjectNotFoundErr("foo/" + FileName)
+	t.validateObjectContents("bar/gcsFile", "")
+	t.validateObjectNotFoundErr("foo/gcsFile")
+	// write more content to the file, which now goes to its new name.
 	_, err = t.f1.WriteString(FileContents)
 	require.NoError(t.T(), err)
-	// Close the local file.
-	t.closeFileAndValidateObjectContents(&t.f1, "foo/"+FileName, FileContents+FileContents)
+	// Close the file.
+	t.closeFileAndValidateObjectContents(&t.f1, "bar/"+FileName, FileContents+FileContents)
+	t.validateObjectNotFoundErr("foo/" + FileName)
 }
 
-func (t *LocalFileTest) TestRenameOfDirectoryWithLocalFileSucceedsAfterSync() {
-	t.TestRenameOfDirectoryWithLocalFileFails()
+func (t *LocalFileTest) TestRenameOfDirectoryWithLocalFileAfterSync() {
+	// Create directory foo with a local file, and close it.
+	require.NoError(t.T(), t.createObjects(map[string]string{"foo/": ""}))
+	_, t.f1 = t.createLocalFile("foo/" + FileName)
+	_, err := t.f1.WriteString(FileContents)
+	require.NoError(t.T(), err)
+	t.closeFileAndValidateObjectContents(&t.f1, "foo/"+FileName, FileContents)
 
-	// Attempt to rename directory again after sync.
-	err := os.Rename(path.Join(mntDir, "foo/"), path.Join(mntDir, "bar/"))
+	// Rename directory after sync.
+	err = os.Rename(path.Join(mntDir, "foo/"), path.Join(mntDir, "bar/"))
 
 	// Validate.
 	require.NoError(t.T(), err)
-	t.validateObjectContents("bar/"+FileName, FileContents+FileContents)
+	t.validateObjectContents("bar/"+FileName, FileContents)
 	t.validateObjectNotFoundErr("foo/" + FileName)
-	t.validateObjectContents("bar/gcsFile", "")
-	t.validateObjectNotFoundErr("foo/gcsFile")
 }
 
 func (t *LocalFileTest) ReadLocalFile() {
@@ -37,6 +37,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 // serverConfigParams holds parameters for creating a test file system.
@@ -131,11 +132,6 @@ func createTestFileSystemWithMetrics(ctx context.Context, t *testing.T, params *
 	return bucket, server, mh, reader
 }
 
-func createWithContents(ctx context.Context, t *testing.T, bucket gcs.Bucket, name string, contents string) {
-	err := storageutil.CreateObjects(ctx, bucket, map[string][]byte{name: []byte(contents)})
-	require.NoError(t, err, "CreateObjects")
-}
-
 func waitForMetricsProcessing() {
 	time.Sleep(5 * time.Millisecond)
 }
@@ -846,8 +842,7 @@ func TestFallocate_Metrics(t *testing.T) {
 	err = server.Fallocate(ctx, op)
 	waitForMetricsProcessing()
 
-	// The operation is not implemented, so we expect an error.
-	assert.Error(t, err)
+	require.NoError(t, err)
 	attrs := attribute.NewSet(attribute.String("fs_op", "Others"))
 	metrics.VerifyCounterMetric(t, ctx, reader, "fs/ops_count", attrs, 1)
 	metrics.VerifyHistogramMetric(t, ctx, reader, "fs/ops_latency", attrs, 1)
@@ -1015,6 +1010,44 @@ func TestWriteFile_Metrics(t *testing.T) {
 	metrics.VerifyHistogramMetric(t, ctx, reader, "fs/ops_latency", attrs, 1)
 }
 
+func TestSyncFS_Metrics(t *testing.T) {
+	ctx := context.Background()
+	bucket, server, mh, reader := createTestFileSystemWithMetrics(ctx, t, defaultServerConfigParams(), false)
+	server = wrappers.WithMonitoring(server, mh)
+	createWithContents(ctx, t, bucket, "clean", "taco")
+	createWithContents(ctx, t, bucket, "dirty", "")
+	var dirtyInode fuseops.InodeID
+	for _, name := range []string{"clean", "dirty"} {
+		lookUpOp := &fuseops.LookUpInodeOp{
+			Parent: fuseops.RootInodeID,
+			Name:   name,
+		}
+		require.NoError(t, server.LookUpInode(ctx, lookUpOp))
+		dirtyInode = lookUpOp.Entry.Child
+	}
+	openOp := &fuseops.OpenFileOp{
+		Inode: dirtyInode,
+	}
+	require.NoError(t, server.OpenFile(ctx, openOp))
+	writeOp := &fuseops.WriteFileOp{
+		Inode:  dirtyInode,
+		Handle: openOp.Handle,
+		Offset: 0,
+		Data:   []byte("burrito"),
+	}
+	require.NoError(t, server.WriteFile(ctx, writeOp))
+
+	err := server.SyncFS(ctx, &fuseops.SyncFSOp{Inode: fuseops.RootInodeID})
+	waitForMetricsProcessing()
+
+	require.NoError(t, err)
+	contents, err := storageutil.ReadObject(ctx, bucket, "dirty")
+	require.NoError(t, err)
+	assert.Equal(t, "burrito", string(contents))
+	metrics.VerifyCounterMetric(t, ctx, reader, "fs/sync_fs_file_count", attribute.NewSet(), 1)
+	metrics.VerifyCounterMetric(t, ctx, reader, "fs/sync_fs_bytes_count", attribute.NewSet(), int64(len("burrito")))
+}
+
 func TestReadSymlink_Metrics(t *testing.T) {
 	ctx := context.Background()
 	_, server, mh, reader := createTestFileSystemWithMetrics(ctx, t, defaultServerConfigParams(), false)
@@ -1382,3 +1415,38 @@ func TestSetInodeAttributes_Metrics(t *testing.T) {
 	metrics.VerifyCounterMetric(t, ctx, reader, "fs/ops_count", attrs, 1)
 	metrics.VerifyHistogramMetric(t, ctx, reader, "fs/ops_latency", attrs, 1)
 }
+
+func TestLocalFreeBytes_SampledWithoutStatFS(t *testing.T) {
+	ctx := context.Background()
+	params := defaultServerConfigParams()
+	params.enableFileCache = true
+	_, server, _, reader := createTestFileSystemWithMetrics(ctx, t, params, false)
+	t.Cleanup(server.Destroy)
+
+	for _, dir := range []string{"temp_dir", "cache_dir"} {
+		attrs := attribute.NewSet(attribute.String("dir", dir))
+		assert.Eventually(t, func() bool {
+			return localFreeBytes(ctx, t, reader, attrs) > 0
+		}, time.Second, 10*time.Millisecond, dir)
+	}
+}
+
+// localFreeBytes returns the value of the fs/local_free_bytes metric for the
+// supplied attributes, or zero if it hasn't been recorded.
+func localFreeBytes(ctx context.Context, t *testing.T, reader *metric.ManualReader, attrs attribute.Set) int64 {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "fs/local_free_bytes" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				if dp.Attributes.Equals(&attrs) {
+					return dp.Value
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameMatching_CaseInsensitiveLookup(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "Dir/", "")
+	createWithContents(ctx, t, bucket, "Dir/Foo", "taco")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{CaseInsensitiveLookup: true})
+
+	dir := lookUp(ctx, t, server, fuseops.RootInodeID, "DIR")
+	assert.Equal(t, dir, lookUp(ctx, t, server, fuseops.RootInodeID, "Dir"))
+	lookUp(ctx, t, server, dir, "foo")
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: dir, Name: "FOO"}))
+
+	_, err := storageutil.ReadObject(ctx, bucket, "Dir/Foo")
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestNameMatching_ExactMatchWins(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo", "taco")
+	createWithContents(ctx, t, bucket, "FOO", "burrito")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{CaseInsensitiveLookup: true})
+
+	lower := lookUp(ctx, t, server, fuseops.RootInodeID, "foo")
+	upper := lookUp(ctx, t, server, fuseops.RootInodeID, "FOO")
+
+	assert.NotEqual(t, lower, upper)
+}
+
+func TestNameMatching_AmbiguousNameFails(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo", "taco")
+	createWithContents(ctx, t, bucket, "FOO/", "")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{CaseInsensitiveLookup: true})
+
+	err := lookUpErr(ctx, server, fuseops.RootInodeID, "Foo")
+
+	assert.ErrorIs(t, err, syscall.ENOTUNIQ)
+	assert.ErrorContains(t, err, `["FOO" "foo"]`)
+}
+
+func TestNameMatching_UnicodeNormalizedLookup(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "cafe\u0301", "taco")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{UnicodeNormalizedLookup: true})
+
+	lookUp(ctx, t, server, fuseops.RootInodeID, "caf\u00e9")
+
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "CAF\u00c9"))
+}
+
+func TestNameMatching_NormalizeCreatedNames(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{NormalizeCreatedNames: true})
+
+	require.NoError(t, server.MkDir(ctx, &fuseops.MkDirOp{Parent: fuseops.RootInodeID, Name: "cafe\u0301", Mode: 0755}))
+
+	_, err := storageutil.ReadObject(ctx, bucket, "caf\u00e9/")
+	assert.NoError(t, err)
+}
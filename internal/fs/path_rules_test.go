@@ -0,0 +1,181 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"os"
+	"path"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+	"golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+// PathRulesTest mounts with a rule of each kind. The rules keep the tests
+// from cleaning up all they create, so each test creates its objects afresh
+// and nothing is cached.
+type PathRulesTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&PathRulesTest{})
+}
+
+func (t *PathRulesTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			PathRules: []string{
+				"hidden=golden/.staging",
+				"read-only=golden",
+				"no-delete=*/logs",
+				`no-overwrite=regex:\.ckpt$`,
+			},
+		},
+		EnableNewReader: true,
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+func (t *PathRulesTest) SetUp(ti *TestInfo) {
+	AssertEq(nil, t.createObjects(map[string]string{
+		"golden/":              "",
+		"golden/data":          "taco",
+		"golden/.staging/":     "",
+		"golden/.staging/part": "burrito",
+		"jobs/":                "",
+		"jobs/logs/":           "",
+		"jobs/logs/today":      "enchilada",
+		"jobs/model.ckpt":      "queso",
+		"jobs/scratch":         "salsa",
+	}))
+}
+
+// write opens the named file in the mount for writing, writes to it and
+// syncs it, returning the first error.
+func (t *PathRulesTest) write(name string, flags int) error {
+	fd, err := unix.Open(path.Join(mntDir, name), flags|unix.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer unix.Close(fd)
+	if _, err = unix.Write(fd, []byte("x")); err != nil {
+		return err
+	}
+	return unix.Fsync(fd)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *PathRulesTest) Hidden() {
+	_, err := os.Lstat(path.Join(mntDir, "golden/.staging"))
+	ExpectTrue(os.IsNotExist(err), "err: %v", err)
+
+	entries, err := os.ReadDir(path.Join(mntDir, "golden"))
+	AssertEq(nil, err)
+	AssertEq(1, len(entries))
+	ExpectEq("data", entries[0].Name())
+}
+
+func (t *PathRulesTest) ReadOnly() {
+	ExpectEq(unix.EROFS, t.write("golden/new", unix.O_CREAT))
+	ExpectEq(unix.EROFS, t.write("golden/data", 0))
+	ExpectEq(unix.EROFS, unix.Unlink(path.Join(mntDir, "golden/data")))
+	ExpectEq(unix.EROFS, unix.Rename(path.Join(mntDir, "golden/data"), path.Join(mntDir, "jobs/data")))
+	ExpectEq(unix.EROFS, unix.Rename(path.Join(mntDir, "jobs/scratch"), path.Join(mntDir, "golden/scratch")))
+}
+
+func (t *PathRulesTest) NoDelete() {
+	ExpectEq(unix.EACCES, unix.Unlink(path.Join(mntDir, "jobs/logs/today")))
+	ExpectEq(unix.EACCES, unix.Rename(path.Join(mntDir, "jobs/logs/today"), path.Join(mntDir, "jobs/today")))
+	ExpectEq(nil, t.write("jobs/logs/tomorrow", unix.O_CREAT))
+	ExpectEq(nil, unix.Unlink(path.Join(mntDir, "jobs/scratch")))
+}
+
+func (t *PathRulesTest) NoOverwrite() {
+	ExpectEq(unix.EACCES, t.write("jobs/model.ckpt", 0))
+	ExpectEq(unix.EACCES, unix.Rename(path.Join(mntDir, "jobs/scratch"), path.Join(mntDir, "jobs/model.ckpt")))
+	ExpectEq(nil, t.write("jobs/new.ckpt", unix.O_CREAT))
+	ExpectEq(nil, unix.Rename(path.Join(mntDir, "jobs/scratch"), path.Join(mntDir, "jobs/old.ckpt")))
+}
+
+////////////////////////////////////////////////////////////////////////
+// Renaming directories
+////////////////////////////////////////////////////////////////////////
+
+// PathRulesRenameDirTest mounts with read-only rules alone, as any other rule
+// that might apply beneath a directory would keep it from being renamed too.
+type PathRulesRenameDirTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&PathRulesRenameDirTest{})
+}
+
+func (t *PathRulesRenameDirTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			PathRules: []string{"read-only=data/golden", "read-only=archive/*"},
+		},
+		EnableNewReader: true,
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+func (t *PathRulesRenameDirTest) RenameDirectoryAbove() {
+	AssertEq(nil, t.createObjects(map[string]string{
+		"data/":         "",
+		"data/golden/":  "",
+		"data/golden/a": "",
+		"data/other/":   "",
+		"data/other/b":  "",
+		"scratch/":      "",
+		"scratch/c":     "",
+	}))
+
+	ExpectEq(unix.EROFS, unix.Rename(path.Join(mntDir, "data"), path.Join(mntDir, "moved")))
+	// scratch/c would become archive/c.
+	ExpectEq(unix.EROFS, unix.Rename(path.Join(mntDir, "scratch"), path.Join(mntDir, "archive")))
+	AssertEq(nil, unix.Rename(path.Join(mntDir, "data/other"), path.Join(mntDir, "data/renamed")))
+	entries, err := os.ReadDir(path.Join(mntDir, "data"))
+	AssertEq(nil, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	ExpectThat(names, ElementsAre("golden", "renamed"))
+	_, err = os.Lstat(path.Join(mntDir, "data/golden/a"))
+	ExpectEq(nil, err)
+	_, err = os.Lstat(path.Join(mntDir, "scratch"))
+	ExpectEq(nil, err)
+}
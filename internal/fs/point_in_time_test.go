@@ -0,0 +1,87 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPointInTime_ReadsPastAndRefusesWrites(t *testing.T) {
+	ctx := context.Background()
+	bucket, _ := newVersionedBucket(ctx, t, "taco")
+	asOf := time.Now()
+	time.Sleep(time.Millisecond)
+	createWithContents(ctx, t, bucket, "foo", "burrito")
+	createWithContents(ctx, t, bucket, "bar", "enchilada")
+	past, err := gcsx.NewPointInTimeBucket(asOf, bucket)
+	require.NoError(t, err)
+	server, err := fs.NewFileSystem(ctx, &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			AsOf:  asOf.Format(time.RFC3339Nano),
+			Write: cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:  cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		MetricHandle:         metrics.NewNoopMetrics(),
+		TraceHandle:          tracing.NewNoopTracer(),
+		CacheClock:           &timeutil.SimulatedClock{},
+		BucketName:           bucket.Name(),
+		BucketManager:        &fakeBucketManager{buckets: map[string]gcs.Bucket{bucket.Name(): past}},
+		FilePerms:            0644,
+		DirPerms:             0755,
+		SequentialReadSizeMb: 1,
+	})
+	require.NoError(t, err)
+	t.Cleanup(server.Destroy)
+	foo := lookUp(ctx, t, server, fuseops.RootInodeID, "foo")
+	openOp := &fuseops.OpenFileOp{Inode: foo}
+	require.NoError(t, server.OpenFile(ctx, openOp))
+	readOp := &fuseops.ReadFileOp{Inode: foo, Handle: openOp.Handle, Dst: make([]byte, len("taco"))}
+
+	require.NoError(t, server.ReadFile(ctx, readOp))
+
+	assert.Equal(t, "taco", string(readOp.Dst[:readOp.BytesRead]))
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "bar"))
+	err = server.OpenFile(ctx, &fuseops.OpenFileOp{Inode: foo, OpenFlags: syscall.O_RDWR})
+	assert.Equal(t, syscall.EROFS, err)
+	err = server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: foo, Handle: openOp.Handle, Data: []byte("x")})
+	assert.Equal(t, syscall.EROFS, err)
+	err = server.CreateFile(ctx, &fuseops.CreateFileOp{Parent: fuseops.RootInodeID, Name: "baz", Mode: 0644})
+	assert.Equal(t, syscall.EROFS, err)
+	err = server.Unlink(ctx, &fuseops.UnlinkOp{Parent: fuseops.RootInodeID, Name: "foo"})
+	assert.Equal(t, syscall.EROFS, err)
+	err = server.MkDir(ctx, &fuseops.MkDirOp{Parent: fuseops.RootInodeID, Name: "dir", Mode: 0755})
+	assert.Equal(t, syscall.EROFS, err)
+	uid := uint32(1000)
+	err = server.SetInodeAttributes(ctx, &fuseops.SetInodeAttributesOp{Inode: foo, Uid: &uid, Gid: &uid})
+	assert.Equal(t, syscall.EROFS, err)
+	atime := time.Now()
+	err = server.SetInodeAttributes(ctx, &fuseops.SetInodeAttributesOp{Inode: foo, Atime: &atime})
+	assert.Equal(t, syscall.EROFS, err)
+}
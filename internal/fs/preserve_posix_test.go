@@ -0,0 +1,139 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	. "github.com/jacobsa/ogletest"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+type PreservePosixTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&PreservePosixTest{})
+}
+
+func (t *PreservePosixTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TtlSecs:            60,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			PreservePosix: true,
+		},
+		EnableNewReader: true,
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *PreservePosixTest) ReadsGcloudMetadata() {
+	_, err := bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     "foo",
+		Contents: strings.NewReader("taco"),
+		Metadata: map[string]string{
+			inode.PosixModeMetadataKey: "604",
+			inode.PosixUidMetadataKey:  "1234",
+			inode.PosixGidMetadataKey:  "5678",
+			inode.FileAtimeMetadataKey: "1700000000",
+		},
+	})
+	AssertEq(nil, err)
+
+	fi, err := os.Stat(path.Join(mntDir, "foo"))
+	AssertEq(nil, err)
+
+	ExpectEq(os.FileMode(0604), fi.Mode())
+	stat := fi.Sys().(*syscall.Stat_t)
+	ExpectEq(1234, stat.Uid)
+	ExpectEq(5678, stat.Gid)
+	ExpectEq(1700000000, stat.Atim.Sec)
+}
+
+func (t *PreservePosixTest) ChmodFile() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+
+	err := os.Chmod(p, 0604)
+	AssertEq(nil, err)
+
+	fi, err := os.Stat(p)
+	AssertEq(nil, err)
+	ExpectEq(os.FileMode(0604), fi.Mode())
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	AssertEq(nil, err)
+	ExpectEq("604", m.Metadata[inode.PosixModeMetadataKey])
+}
+
+func (t *PreservePosixTest) ChownFile() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+
+	err := os.Chown(p, -1, int(currentGid()))
+	AssertEq(nil, err)
+
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	AssertEq(nil, err)
+	ExpectEq(strconv.FormatUint(uint64(currentGid()), 10), m.Metadata[inode.PosixGidMetadataKey])
+}
+
+func (t *PreservePosixTest) ChtimesRecordsAtime() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+	atime := time.Unix(1700000000, 0)
+
+	err := os.Chtimes(p, atime, time.Now())
+	AssertEq(nil, err)
+
+	fi, err := os.Stat(p)
+	AssertEq(nil, err)
+	ExpectEq(atime.Unix(), fi.Sys().(*syscall.Stat_t).Atim.Sec)
+}
+
+func (t *PreservePosixTest) ChmodExplicitDir() {
+	AssertEq(nil, t.createEmptyObjects([]string{"dir/"}))
+	p := path.Join(mntDir, "dir")
+
+	err := os.Chmod(p, 0700)
+	AssertEq(nil, err)
+
+	fi, err := os.Stat(p)
+	AssertEq(nil, err)
+	ExpectEq(os.ModeDir|0700, fi.Mode())
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "dir/"})
+	AssertEq(nil, err)
+	ExpectEq("700", m.Metadata[inode.PosixModeMetadataKey])
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDynamicTestFileSystem mounts all of the supplied buckets, which can copy
+// objects from each other, without going through the kernel.
+func newDynamicTestFileSystem(ctx context.Context, t *testing.T, buckets ...gcs.Bucket) fuseutil.FileSystem {
+	t.Helper()
+	fake.LinkBuckets(buckets...)
+	bm := &fakeBucketManager{buckets: make(map[string]gcs.Bucket)}
+	for _, b := range buckets {
+		bm.buckets[b.Name()] = b
+	}
+	serverCfg := &fs.ServerConfig{
+		NewConfig: &cfg.Config{
+			Write: cfg.WriteConfig{GlobalMaxBlocks: 1},
+			Read:  cfg.ReadConfig{GlobalMaxBlocks: 1},
+		},
+		MetricHandle:   metrics.NewNoopMetrics(),
+		TraceHandle:    tracing.NewNoopTracer(),
+		CacheClock:     &timeutil.SimulatedClock{},
+		BucketManager:  bm,
+		FilePerms:      0644,
+		DirPerms:       0755,
+		RenameDirLimit: 10,
+	}
+	server, err := fs.NewFileSystem(ctx, serverCfg)
+	require.NoError(t, err, "NewFileSystem")
+	t.Cleanup(server.Destroy)
+	return server
+}
+
+func TestRename_FileAcrossBuckets(t *testing.T) {
+	ctx := context.Background()
+	src := fake.NewFakeBucket(timeutil.RealClock(), "src-bucket", gcs.BucketType{})
+	dst := fake.NewFakeBucket(timeutil.RealClock(), "dst-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, src, "foo", "taco")
+	createWithContents(ctx, t, dst, "bar", "burrito")
+	server := newDynamicTestFileSystem(ctx, t, src, dst)
+	srcID := lookUp(ctx, t, server, fuseops.RootInodeID, "src-bucket")
+	dstID := lookUp(ctx, t, server, fuseops.RootInodeID, "dst-bucket")
+	lookUp(ctx, t, server, srcID, "foo")
+
+	err := server.Rename(ctx, &fuseops.RenameOp{OldParent: srcID, OldName: "foo", NewParent: dstID, NewName: "bar"})
+
+	require.NoError(t, err)
+	contents, err := storageutil.ReadObject(ctx, dst, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	assertObjectMissing(ctx, t, src, "foo")
+}
+
+func TestRename_FileAcrossBucketsWithUnsyncedWrites(t *testing.T) {
+	ctx := context.Background()
+	src := fake.NewFakeBucket(timeutil.RealClock(), "src-bucket", gcs.BucketType{})
+	dst := fake.NewFakeBucket(timeutil.RealClock(), "dst-bucket", gcs.BucketType{})
+	server := newDynamicTestFileSystem(ctx, t, src, dst)
+	srcID := lookUp(ctx, t, server, fuseops.RootInodeID, "src-bucket")
+	dstID := lookUp(ctx, t, server, fuseops.RootInodeID, "dst-bucket")
+	createOp := &fuseops.CreateFileOp{Parent: srcID, Name: "foo", Mode: 0644}
+	require.NoError(t, server.CreateFile(ctx, createOp))
+	require.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: createOp.Entry.Child, Handle: createOp.Handle, Data: []byte("taco")}))
+
+	err := server.Rename(ctx, &fuseops.RenameOp{OldParent: srcID, OldName: "foo", NewParent: dstID, NewName: "foo"})
+
+	require.NoError(t, err)
+	contents, err := storageutil.ReadObject(ctx, dst, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(contents))
+	assertObjectMissing(ctx, t, src, "foo")
+}
+
+func TestRename_FileAcrossBucketsOntoDirectory(t *testing.T) {
+	ctx := context.Background()
+	src := fake.NewFakeBucket(timeutil.RealClock(), "src-bucket", gcs.BucketType{})
+	dst := fake.NewFakeBucket(timeutil.RealClock(), "dst-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, src, "foo", "taco")
+	createWithContents(ctx, t, dst, "bar/", "")
+	server := newDynamicTestFileSystem(ctx, t, src, dst)
+	srcID := lookUp(ctx, t, server, fuseops.RootInodeID, "src-bucket")
+	dstID := lookUp(ctx, t, server, fuseops.RootInodeID, "dst-bucket")
+	lookUp(ctx, t, server, srcID, "foo")
+
+	err := server.Rename(ctx, &fuseops.RenameOp{OldParent: srcID, OldName: "foo", NewParent: dstID, NewName: "bar"})
+
+	assert.ErrorIs(t, err, syscall.EISDIR)
+	_, err = storageutil.ReadObject(ctx, src, "foo")
+	assert.NoError(t, err)
+}
+
+func TestRename_DirectoryAcrossBuckets(t *testing.T) {
+	ctx := context.Background()
+	src := fake.NewFakeBucket(timeutil.RealClock(), "src-bucket", gcs.BucketType{})
+	dst := fake.NewFakeBucket(timeutil.RealClock(), "dst-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, src, "foo/", "")
+	createWithContents(ctx, t, src, "foo/a", "taco")
+	createWithContents(ctx, t, src, "foo/sub/", "")
+	createWithContents(ctx, t, src, "foo/sub/b", "burrito")
+	server := newDynamicTestFileSystem(ctx, t, src, dst)
+	srcID := lookUp(ctx, t, server, fuseops.RootInodeID, "src-bucket")
+	dstID := lookUp(ctx, t, server, fuseops.RootInodeID, "dst-bucket")
+	lookUp(ctx, t, server, srcID, "foo")
+
+	err := server.Rename(ctx, &fuseops.RenameOp{OldParent: srcID, OldName: "foo", NewParent: dstID, NewName: "bar"})
+
+	require.NoError(t, err)
+	for name, expected := range map[string]string{"bar/": "", "bar/a": "taco", "bar/sub/": "", "bar/sub/b": "burrito"} {
+		contents, err := storageutil.ReadObject(ctx, dst, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, string(contents))
+	}
+	for _, name := range []string{"foo/", "foo/a", "foo/sub/", "foo/sub/b"} {
+		assertObjectMissing(ctx, t, src, name)
+	}
+}
+
+func TestRename_DirectoryOutOfHierarchicalBucket(t *testing.T) {
+	ctx := context.Background()
+	src := fake.NewFakeBucket(timeutil.RealClock(), "src-bucket", gcs.BucketType{Hierarchical: true})
+	dst := fake.NewFakeBucket(timeutil.RealClock(), "dst-bucket", gcs.BucketType{})
+	_, err := src.CreateFolder(ctx, "foo/")
+	require.NoError(t, err)
+	createWithContents(ctx, t, src, "foo/", "")
+	server := newDynamicTestFileSystem(ctx, t, src, dst)
+	srcID := lookUp(ctx, t, server, fuseops.RootInodeID, "src-bucket")
+	dstID := lookUp(ctx, t, server, fuseops.RootInodeID, "dst-bucket")
+	lookUp(ctx, t, server, srcID, "foo")
+
+	err = server.Rename(ctx, &fuseops.RenameOp{OldParent: srcID, OldName: "foo", NewParent: dstID, NewName: "bar"})
+
+	assert.ErrorIs(t, err, syscall.ENOTSUP)
+}
@@ -112,17 +112,24 @@ func (t *RenameDirTests) TestRenameFolderWithSourceDirectoryHaveLocalFiles() {
 	_, err := os.Stat(oldDirPath)
 	assert.NoError(t.T(), err)
 	file, err := os.OpenFile(path.Join(oldDirPath, "file4.txt"), os.O_RDWR|os.O_CREATE, filePerms)
-	assert.NoError(t.T(), err)
+	require.NoError(t.T(), err)
 	defer file.Close()
+	_, err = file.WriteString("abc")
+	require.NoError(t.T(), err)
 	newDirPath := path.Join(mntDir, "bar", "foo_rename")
 
 	err = os.Rename(oldDirPath, newDirPath)
 
-	assert.Error(t.T(), err)
-	// In the logs, we encountered the following error:
-	// "Rename: operation not supported, can't rename directory 'test' with open files: operation not supported."
-	// This was translated to an "operation not supported" error at the kernel level.
-	assert.True(t.T(), strings.Contains(err.Error(), "operation not supported"))
+	require.NoError(t.T(), err)
+	// The open file has moved along with the directory.
+	_, err = file.WriteString("def")
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), file.Close())
+	contents, err := os.ReadFile(path.Join(newDirPath, "file4.txt"))
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "abcdef", string(contents))
+	_, err = os.Stat(oldDirPath)
+	assert.True(t.T(), os.IsNotExist(err))
 }
 
 func (t *RenameDirTests) TestRenameFolderWithSameParent() {
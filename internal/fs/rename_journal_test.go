@@ -0,0 +1,172 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/clock"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/renamejournal"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func journaledRenameConfig(journalDir string, recovery string) cfg.FileSystemConfig {
+	return cfg.FileSystemConfig{
+		RenameDirLimit:        10,
+		RenameDirParallelism:  4,
+		RenameJournalDir:      cfg.ResolvedPath(journalDir),
+		RenameJournalRecovery: recovery,
+	}
+}
+
+func assertNoRenameRecords(ctx context.Context, t *testing.T, bucket gcs.Bucket, journalDir string) {
+	t.Helper()
+	listing, err := bucket.ListObjects(ctx, &gcs.ListObjectsRequest{Prefix: renamejournal.ObjectPrefix})
+	require.NoError(t, err)
+	assert.Empty(t, listing.MinObjects)
+	entries, err := os.ReadDir(filepath.Join(journalDir, bucket.Name()))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRename_JournaledDirectory(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo/", "")
+	createWithContents(ctx, t, bucket, "foo/a", "taco")
+	createWithContents(ctx, t, bucket, "foo/sub/b", "burrito")
+	journalDir := t.TempDir()
+	server := newTestFileSystem(ctx, t, bucket, journaledRenameConfig(journalDir, cfg.RenameJournalRecoveryResume))
+	fooID := lookUp(ctx, t, server, fuseops.RootInodeID, "foo")
+	lookUp(ctx, t, server, fooID, "a")
+
+	err := server.Rename(ctx, &fuseops.RenameOp{OldParent: fuseops.RootInodeID, OldName: "foo", NewParent: fuseops.RootInodeID, NewName: "bar"})
+
+	require.NoError(t, err)
+	for name, expected := range map[string]string{"bar/": "", "bar/a": "taco", "bar/sub/b": "burrito"} {
+		contents, err := storageutil.ReadObject(ctx, bucket, name)
+		require.NoError(t, err, name)
+		assert.Equal(t, expected, string(contents))
+	}
+	for _, name := range []string{"foo/", "foo/a", "foo/sub/b"} {
+		assertObjectMissing(ctx, t, bucket, name)
+	}
+	barID := lookUp(ctx, t, server, fuseops.RootInodeID, "bar")
+	lookUp(ctx, t, server, barID, "a")
+	assertNoRenameRecords(ctx, t, bucket, journalDir)
+}
+
+// interruptRename returns a bucket in which a rename of foo/ to bar/ that has
+// copied foo/a was recorded long enough ago to count as abandoned, and the
+// directory of its local journal.
+func interruptRename(ctx context.Context, t *testing.T) (gcs.Bucket, string) {
+	t.Helper()
+	simulatedClock := clock.NewSimulatedClock(time.Now().Add(-time.Hour))
+	bucket := fake.NewFakeBucket(simulatedClock, "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo/", "")
+	createWithContents(ctx, t, bucket, "foo/a", "taco")
+	createWithContents(ctx, t, bucket, "foo/b", "burrito")
+	createWithContents(ctx, t, bucket, "bar/", "")
+	var objects []*gcs.MinObject
+	for _, name := range []string{"foo/a", "foo/b"} {
+		m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: name})
+		require.NoError(t, err)
+		objects = append(objects, m)
+	}
+	journalDir := t.TempDir()
+	journal := renamejournal.New(bucket, renamejournal.Config{LocalDir: journalDir, Parallelism: 1, Clock: simulatedClock})
+	_, err := journal.Begin(ctx, "foo/", "bar/", true, true, objects)
+	require.NoError(t, err)
+	_, err = bucket.CopyObject(ctx, &gcs.CopyObjectRequest{SrcName: "foo/a", DstName: "bar/a"})
+	require.NoError(t, err)
+	return bucket, journalDir
+}
+
+func TestNewFileSystem_RecoversInterruptedRename(t *testing.T) {
+	testCases := []struct {
+		name     string
+		recovery string
+		present  []string
+		missing  []string
+	}{
+		{"resume", cfg.RenameJournalRecoveryResume, []string{"bar/a", "bar/b"}, []string{"foo/", "foo/a", "foo/b"}},
+		{"rollback", cfg.RenameJournalRecoveryRollback, []string{"foo/", "foo/a", "foo/b"}, []string{"bar/", "bar/a", "bar/b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			bucket, journalDir := interruptRename(ctx, t)
+
+			newTestFileSystem(ctx, t, bucket, journaledRenameConfig(journalDir, tc.recovery))
+
+			// Recovery runs in the background.
+			assert.Eventually(t, func() bool {
+				listing, err := bucket.ListObjects(ctx, &gcs.ListObjectsRequest{Prefix: renamejournal.ObjectPrefix})
+				return err == nil && len(listing.MinObjects) == 0
+			}, 5*time.Second, 10*time.Millisecond)
+			for _, name := range tc.present {
+				_, err := storageutil.ReadObject(ctx, bucket, name)
+				assert.NoError(t, err, name)
+			}
+			for _, name := range tc.missing {
+				assertObjectMissing(ctx, t, bucket, name)
+			}
+			assertNoRenameRecords(ctx, t, bucket, journalDir)
+		})
+	}
+}
+
+func TestNewFileSystem_ReadOnlyLeavesInterruptedRename(t *testing.T) {
+	ctx := context.Background()
+	bucket, journalDir := interruptRename(ctx, t)
+	fsConfig := journaledRenameConfig(journalDir, cfg.RenameJournalRecoveryResume)
+	fsConfig.FuseOptions = []string{"ro"}
+
+	newTestFileSystem(ctx, t, bucket, fsConfig)
+
+	assert.Never(t, func() bool {
+		listing, err := bucket.ListObjects(ctx, &gcs.ListObjectsRequest{Prefix: renamejournal.ObjectPrefix})
+		return err != nil || len(listing.MinObjects) == 0
+	}, 200*time.Millisecond, 10*time.Millisecond)
+	_, err := storageutil.ReadObject(ctx, bucket, "foo/b")
+	assert.NoError(t, err)
+}
+
+func TestRenameJournalObjectsHidden(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "foo", "taco")
+	createWithContents(ctx, t, bucket, renamejournal.ObjectPrefix, "")
+	createWithContents(ctx, t, bucket, renamejournal.ObjectPrefix+"some-id", "{}")
+	server := newTestFileSystem(ctx, t, bucket, journaledRenameConfig(t.TempDir(), cfg.RenameJournalRecoveryNone))
+
+	assert.Equal(t, []string{"foo"}, readDirNames(ctx, t, server, fuseops.RootInodeID))
+	err := lookUpErr(ctx, server, fuseops.RootInodeID, strings.TrimSuffix(renamejournal.ObjectPrefix, "/"))
+	assert.ErrorIs(t, err, syscall.ENOENT)
+}
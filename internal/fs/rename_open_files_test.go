@@ -0,0 +1,154 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRename_DirectoryWithOpenFiles(t *testing.T) {
+	testCases := []struct {
+		name       string
+		bucketType gcs.BucketType
+	}{
+		{"flat", gcs.BucketType{}},
+		{"hierarchical", gcs.BucketType{Hierarchical: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", tc.bucketType)
+			if tc.bucketType.Hierarchical {
+				_, err := bucket.CreateFolder(ctx, "foo/")
+				require.NoError(t, err)
+			}
+			createWithContents(ctx, t, bucket, "foo/", "")
+			createWithContents(ctx, t, bucket, "foo/synced", "taco")
+			server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{RenameDirLimit: 10})
+			fooID := lookUp(ctx, t, server, fuseops.RootInodeID, "foo")
+			// A file that only exists locally so far.
+			createOp := &fuseops.CreateFileOp{Parent: fooID, Name: "local", Mode: 0644}
+			require.NoError(t, server.CreateFile(ctx, createOp))
+			local := createOp.Entry.Child
+			require.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: local, Handle: createOp.Handle, Data: []byte("burrito")}))
+			// A file backed by an object, with writes that have not been synced.
+			synced := lookUp(ctx, t, server, fooID, "synced")
+			openOp := &fuseops.OpenFileOp{Inode: synced}
+			require.NoError(t, server.OpenFile(ctx, openOp))
+			require.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: synced, Handle: openOp.Handle, Data: []byte("enchilada")}))
+
+			err := server.Rename(ctx, &fuseops.RenameOp{OldParent: fuseops.RootInodeID, OldName: "foo", NewParent: fuseops.RootInodeID, NewName: "bar"})
+			require.NoError(t, err)
+
+			// The handles keep writing to the files under their new names.
+			require.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: local, Handle: createOp.Handle, Data: []byte("!"), Offset: 7}))
+			require.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: synced, Handle: openOp.Handle, Data: []byte("!"), Offset: 9}))
+			require.NoError(t, server.FlushFile(ctx, &fuseops.FlushFileOp{Inode: local, Handle: createOp.Handle}))
+			require.NoError(t, server.FlushFile(ctx, &fuseops.FlushFileOp{Inode: synced, Handle: openOp.Handle}))
+			for name, expected := range map[string]string{"bar/local": "burrito!", "bar/synced": "enchilada!"} {
+				contents, err := storageutil.ReadObject(ctx, bucket, name)
+				require.NoError(t, err)
+				assert.Equal(t, expected, string(contents))
+			}
+			for _, name := range []string{"foo/local", "foo/synced"} {
+				_, err := storageutil.ReadObject(ctx, bucket, name)
+				var notFoundErr *gcs.NotFoundError
+				assert.ErrorAs(t, err, &notFoundErr, name)
+			}
+			barID := lookUp(ctx, t, server, fuseops.RootInodeID, "bar")
+			assert.Equal(t, local, lookUp(ctx, t, server, barID, "local"))
+			assert.Equal(t, synced, lookUp(ctx, t, server, barID, "synced"))
+		})
+	}
+}
+
+func TestRename_DirectoryWhileOpenFileIsWritten(t *testing.T) {
+	testCases := []struct {
+		name       string
+		bucketType gcs.BucketType
+	}{
+		{"flat", gcs.BucketType{}},
+		{"hierarchical", gcs.BucketType{Hierarchical: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", tc.bucketType)
+			if tc.bucketType.Hierarchical {
+				_, err := bucket.CreateFolder(ctx, "foo/")
+				require.NoError(t, err)
+			}
+			createWithContents(ctx, t, bucket, "foo/", "")
+			createWithContents(ctx, t, bucket, "foo/synced", "taco")
+			server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{RenameDirLimit: 10})
+			fooID := lookUp(ctx, t, server, fuseops.RootInodeID, "foo")
+			synced := lookUp(ctx, t, server, fooID, "synced")
+			openOp := &fuseops.OpenFileOp{Inode: synced}
+			require.NoError(t, server.OpenFile(ctx, openOp))
+			// Write the file over and over while its directory is renamed. The
+			// rename flushes the file first, and neither may hold up the other.
+			stop := make(chan struct{})
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for offset := int64(0); ; offset += int64(len("burrito")) {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					assert.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: synced, Handle: openOp.Handle, Data: []byte("burrito"), Offset: offset}))
+				}
+			}()
+			renamed := make(chan error, 1)
+			go func() {
+				renamed <- server.Rename(ctx, &fuseops.RenameOp{OldParent: fuseops.RootInodeID, OldName: "foo", NewParent: fuseops.RootInodeID, NewName: "bar"})
+			}()
+
+			var err error
+			select {
+			case err = <-renamed:
+			case <-time.After(10 * time.Second):
+				t.Fatal("Rename didn't return while the open file was being written")
+			}
+			close(stop)
+			wg.Wait()
+
+			require.NoError(t, err)
+			require.NoError(t, server.FlushFile(ctx, &fuseops.FlushFileOp{Inode: synced, Handle: openOp.Handle}))
+			contents, err := storageutil.ReadObject(ctx, bucket, "bar/synced")
+			require.NoError(t, err)
+			assert.Contains(t, string(contents), "burrito")
+			_, err = storageutil.ReadObject(ctx, bucket, "foo/synced")
+			var notFoundErr *gcs.NotFoundError
+			assert.ErrorAs(t, err, &notFoundErr)
+		})
+	}
+}
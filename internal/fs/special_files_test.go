@@ -0,0 +1,89 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMkNode_SpecialFilesDisabled(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{})
+
+	err := server.MkNode(ctx, &fuseops.MkNodeOp{Parent: fuseops.RootInodeID, Name: "fifo", Mode: os.ModeNamedPipe | 0644})
+
+	assert.ErrorIs(t, err, syscall.ENOTSUP)
+}
+
+func TestMkNode_SpecialFiles(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mode       os.FileMode
+		rdev       uint32
+		direntType fuseutil.DirentType
+	}{
+		{"block", os.ModeDevice | 0600, 0x0801, fuseutil.DT_Block},
+		{"char", os.ModeDevice | os.ModeCharDevice | 0600, 0x0103, fuseutil.DT_Char},
+		{"fifo", os.ModeNamedPipe | 0644, 0, fuseutil.DT_FIFO},
+		{"socket", os.ModeSocket | 0755, 0, fuseutil.DT_Socket},
+	}
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "test-bucket", gcs.BucketType{})
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{EnableSpecialFiles: true})
+
+	for _, tc := range testCases {
+		op := &fuseops.MkNodeOp{Parent: fuseops.RootInodeID, Name: tc.name, Mode: tc.mode, Rdev: tc.rdev}
+		require.NoError(t, server.MkNode(ctx, op))
+		assert.Equal(t, tc.mode.Type()|0644, op.Entry.Attributes.Mode)
+		assert.Equal(t, tc.rdev, op.Entry.Attributes.Rdev)
+		o, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: tc.name})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), o.Size)
+		assert.Equal(t, tc.name, o.Metadata[inode.SpecialFileTypeMetadataKey])
+	}
+
+	// A fresh mount finds them again.
+	server = newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{})
+	for _, tc := range testCases {
+		lookUpOp := &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: tc.name}
+		require.NoError(t, server.LookUpInode(ctx, lookUpOp))
+		assert.Equal(t, tc.mode.Type()|0644, lookUpOp.Entry.Attributes.Mode)
+		assert.Equal(t, tc.rdev, lookUpOp.Entry.Attributes.Rdev)
+	}
+	openDirOp := &fuseops.OpenDirOp{Inode: fuseops.RootInodeID}
+	require.NoError(t, server.OpenDir(ctx, openDirOp))
+	readDirOp := &fuseops.ReadDirOp{Inode: fuseops.RootInodeID, Handle: openDirOp.Handle, Dst: make([]byte, 4096)}
+	require.NoError(t, server.ReadDir(ctx, readDirOp))
+	entries := parseDirents(readDirOp.Dst[:readDirOp.BytesRead])
+	require.Len(t, entries, len(testCases))
+	for i, tc := range testCases {
+		assert.Equal(t, tc.name, entries[i].Name)
+		assert.Equal(t, tc.direntType, entries[i].Type)
+	}
+}
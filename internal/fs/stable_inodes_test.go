@@ -0,0 +1,47 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableInodeNumbers_SameAcrossMounts(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "dir/", "")
+	createWithContents(ctx, t, bucket, "dir/foo", "taco")
+	createWithContents(ctx, t, bucket, "bar", "burrito")
+	fsConfig := cfg.FileSystemConfig{StableInodeNumbers: true}
+	first := newTestFileSystem(ctx, t, bucket, fsConfig)
+	second := newTestFileSystem(ctx, t, bucket, fsConfig)
+
+	// Look the files up in a different order each time.
+	dir := lookUp(ctx, t, first, fuseops.RootInodeID, "dir")
+	foo := lookUp(ctx, t, first, dir, "foo")
+	bar := lookUp(ctx, t, first, fuseops.RootInodeID, "bar")
+
+	assert.Equal(t, bar, lookUp(ctx, t, second, fuseops.RootInodeID, "bar"))
+	assert.Equal(t, dir, lookUp(ctx, t, second, fuseops.RootInodeID, "dir"))
+	assert.Equal(t, foo, lookUp(ctx, t, second, dir, "foo"))
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/fuse/fuseops"
+	. "github.com/jacobsa/ogletest"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+// StatFSTest mounts with a quota of 1 MiB, which is 8 blocks, and refreshes
+// the bucket usage often enough for the tests to wait for it.
+type StatFSTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&StatFSTest{})
+}
+
+func (t *StatFSTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TtlSecs:            60,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			QuotaMb:              1,
+			UsageRefreshInterval: 10 * time.Millisecond,
+		},
+		EnableNewReader: true,
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+// statfsOnceRefreshed calls statfs(2) on the mount until the usage it reports
+// satisfies done, or gives up after a few seconds.
+func (t *StatFSTest) statfsOnceRefreshed(done func(st *unix.Statfs_t) bool) (st unix.Statfs_t) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		AssertEq(nil, unix.Statfs(mntDir, &st))
+		if done(&st) || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *StatFSTest) ReportsQuota() {
+	st := t.statfsOnceRefreshed(func(st *unix.Statfs_t) bool { return st.Ffree == st.Files })
+
+	ExpectEq(1<<17, st.Frsize)
+	ExpectEq(8, st.Blocks)
+	ExpectEq(8, st.Bfree)
+	ExpectEq(8, st.Bavail)
+	ExpectEq(st.Files, st.Ffree)
+}
+
+func (t *StatFSTest) ReportsBucketUsage() {
+	AssertEq(nil, t.createObjects(map[string]string{
+		"foo": strings.Repeat("a", 1<<17+1),
+		"bar": "taco",
+	}))
+
+	st := t.statfsOnceRefreshed(func(st *unix.Statfs_t) bool { return st.Ffree == st.Files-2 })
+
+	ExpectEq(8, st.Blocks)
+	// The bytes in use are rounded up to whole blocks.
+	ExpectEq(6, st.Bfree)
+	ExpectEq(6, st.Bavail)
+	ExpectEq(st.Files-2, st.Ffree)
+}
+
+func (t *StatFSTest) UsageBeyondQuota() {
+	AssertEq(nil, t.createWithContents("foo", strings.Repeat("a", 2<<20)))
+
+	st := t.statfsOnceRefreshed(func(st *unix.Statfs_t) bool { return st.Ffree == st.Files-1 })
+
+	ExpectEq(0, st.Bfree)
+	ExpectEq(0, st.Bavail)
+}
+
+func statFS(ctx context.Context, t *testing.T, fsConfig cfg.FileSystemConfig, bucket gcs.Bucket) func() *fuseops.StatFSOp {
+	t.Helper()
+	server := newTestFileSystem(ctx, t, bucket, fsConfig)
+	return func() *fuseops.StatFSOp {
+		op := &fuseops.StatFSOp{}
+		require.NoError(t, server.StatFS(ctx, op))
+		return op
+	}
+}
+
+func TestStatFS_Quota(t *testing.T) {
+	testCases := []struct {
+		name           string
+		fsConfig       cfg.FileSystemConfig
+		expectedBlocks uint64
+	}{
+		{
+			name:           "no_quota",
+			fsConfig:       cfg.FileSystemConfig{},
+			expectedBlocks: 1 << 33,
+		},
+		{
+			name:           "mount_quota",
+			fsConfig:       cfg.FileSystemConfig{QuotaMb: 1024},
+			expectedBlocks: 1024 * 8,
+		},
+		{
+			name:           "bucket_quota",
+			fsConfig:       cfg.FileSystemConfig{QuotaMb: 1024, BucketQuotas: []string{"other=1", "some_bucket=10"}},
+			expectedBlocks: 10 * 8,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+
+			op := statFS(ctx, t, tc.fsConfig, bucket)()
+
+			assert.Equal(t, uint32(1<<17), op.BlockSize)
+			assert.Equal(t, tc.expectedBlocks, op.Blocks)
+			assert.Equal(t, op.Blocks, op.BlocksFree)
+			assert.Equal(t, op.Blocks, op.BlocksAvailable)
+			assert.Equal(t, op.Inodes, op.InodesFree)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeToObject opens the existing object name through server and writes
+// contents to it without syncing.
+func writeToObject(ctx context.Context, t *testing.T, server fuseutil.FileSystem, name string, contents string) {
+	t.Helper()
+	lookUpOp := &fuseops.LookUpInodeOp{Parent: fuseops.RootInodeID, Name: name}
+	require.NoError(t, server.LookUpInode(ctx, lookUpOp))
+	openOp := &fuseops.OpenFileOp{Inode: lookUpOp.Entry.Child}
+	require.NoError(t, server.OpenFile(ctx, openOp))
+	writeOp := &fuseops.WriteFileOp{
+		Inode:  lookUpOp.Entry.Child,
+		Handle: openOp.Handle,
+		Data:   []byte(contents),
+	}
+	require.NoError(t, server.WriteFile(ctx, writeOp))
+}
+
+func TestSyncFS_WritesOutAllDirtyFiles(t *testing.T) {
+	ctx := context.Background()
+	bucket, server, _, _ := createTestFileSystemWithMetrics(ctx, t, defaultServerConfigParams(), false)
+	const numFiles = 40
+	for i := range numFiles {
+		name := fmt.Sprintf("file_%d", i)
+		createWithContents(ctx, t, bucket, name, "")
+		writeToObject(ctx, t, server, name, name)
+	}
+
+	err := server.SyncFS(ctx, &fuseops.SyncFSOp{Inode: fuseops.RootInodeID})
+
+	require.NoError(t, err)
+	for i := range numFiles {
+		name := fmt.Sprintf("file_%d", i)
+		contents, err := storageutil.ReadObject(ctx, bucket, name)
+		require.NoError(t, err)
+		assert.Equal(t, name, string(contents))
+	}
+}
+
+func TestSyncFS_ReportsErrorAfterSyncingOtherFiles(t *testing.T) {
+	ctx := context.Background()
+	bucket, server, _, _ := createTestFileSystemWithMetrics(ctx, t, defaultServerConfigParams(), false)
+	createWithContents(ctx, t, bucket, "clobbered", "")
+	createWithContents(ctx, t, bucket, "ok", "")
+	writeToObject(ctx, t, server, "clobbered", "taco")
+	writeToObject(ctx, t, server, "ok", "burrito")
+	// Replace the object behind the first file, so that syncing it fails.
+	createWithContents(ctx, t, bucket, "clobbered", "enchilada")
+
+	err := server.SyncFS(ctx, &fuseops.SyncFSOp{Inode: fuseops.RootInodeID})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "clobbered")
+	contents, err := storageutil.ReadObject(ctx, bucket, "ok")
+	require.NoError(t, err)
+	assert.Equal(t, "burrito", string(contents))
+	contents, err = storageutil.ReadObject(ctx, bucket, "clobbered")
+	require.NoError(t, err)
+	assert.Equal(t, "enchilada", string(contents))
+}
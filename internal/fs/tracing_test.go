@@ -25,6 +25,7 @@ import (
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/wrappers"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
 	"github.com/googlecloudplatform/gcsfuse/v3/tracing"
 	"github.com/jacobsa/fuse/fuseops"
 	"github.com/jacobsa/fuse/fuseutil"
@@ -80,6 +81,7 @@ func createTestFileSystemWithTraces(ctx context.Context, t *testing.T, ignoreInt
 			},
 		},
 		SequentialReadSizeMb: 200,
+		MetricHandle:         metrics.NewNoopMetrics(),
 		TraceHandle:          tracing.NewOTELTracer(),
 	}
 	server, err := fs.NewFileSystem(ctx, serverCfg)
@@ -1302,7 +1304,7 @@ func (s *TracingTestSuite) TestTraceFallocate() {
 			}
 
 			err = m.Fallocate(ctx, op)
-			assert.Error(t, err) // The operation is not implemented, so we expect an error.
+			require.NoError(t, err)
 
 			ss := s.globalExporter.GetSpans()
 			require.Len(t, ss, len(tt.spans))
@@ -1344,7 +1346,7 @@ func (s *TracingTestSuite) TestTraceSyncFS() {
 			}
 
 			err = m.SyncFS(ctx, op)
-			assert.Error(t, err) // The operation is not implemented, so we expect an error.
+			assert.NoError(t, err)
 
 			ss := s.globalExporter.GetSpans()
 			require.Len(t, ss, len(tt.spans))
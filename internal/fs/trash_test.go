@@ -0,0 +1,145 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/trash"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash_UnlinkAndRmDirMoveToTrash(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, "dir/", "")
+	createWithContents(ctx, t, bucket, "dir/foo", "taco")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{TrashPrefix: ".trash/"})
+	dir := lookUp(ctx, t, server, fuseops.RootInodeID, "dir")
+	lookUp(ctx, t, server, dir, "foo")
+
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: dir, Name: "foo"}))
+	require.NoError(t, server.RmDir(ctx, &fuseops.RmDirOp{Parent: fuseops.RootInodeID, Name: "dir"}))
+
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "dir"))
+	entries, err := trash.New(bucket, ".trash/", timeutil.RealClock()).List(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.OriginalName)
+	}
+	assert.Equal(t, []string{"dir/foo", "dir/"}, names)
+}
+
+func TestTrash_RmDirMovesToTrash_Hierarchical(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{Hierarchical: true})
+	_, err := bucket.CreateFolder(ctx, "dir/")
+	require.NoError(t, err)
+	createWithContents(ctx, t, bucket, "dir/foo", "taco")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{TrashPrefix: ".trash/"})
+	dir := lookUp(ctx, t, server, fuseops.RootInodeID, "dir")
+	lookUp(ctx, t, server, dir, "foo")
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: dir, Name: "foo"}))
+
+	err = server.RmDir(ctx, &fuseops.RmDirOp{Parent: fuseops.RootInodeID, Name: "dir"})
+
+	require.NoError(t, err)
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, fuseops.RootInodeID, "dir"))
+	var notFoundErr *gcs.NotFoundError
+	_, err = bucket.GetFolder(ctx, &gcs.GetFolderRequest{Name: "dir/"})
+	assert.ErrorAs(t, err, &notFoundErr)
+	entries, err := trash.New(bucket, ".trash/", timeutil.RealClock()).List(ctx)
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.OriginalName)
+	}
+	assert.Equal(t, []string{"dir/foo", "dir/"}, names)
+}
+
+func TestTrash_UnlinkInTrashDeletes(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some-bucket", gcs.BucketType{})
+	createWithContents(ctx, t, bucket, ".trash/", "")
+	createWithContents(ctx, t, bucket, ".trash/foo", "taco")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{TrashPrefix: ".trash/"})
+	dir := lookUp(ctx, t, server, fuseops.RootInodeID, ".trash")
+	lookUp(ctx, t, server, dir, "foo")
+
+	err := server.Unlink(ctx, &fuseops.UnlinkOp{Parent: dir, Name: "foo"})
+
+	require.NoError(t, err)
+	listing, err := bucket.ListObjects(ctx, &gcs.ListObjectsRequest{})
+	require.NoError(t, err)
+	require.Len(t, listing.MinObjects, 1)
+	assert.Equal(t, ".trash/", listing.MinObjects[0].Name)
+}
+
+func TestTrash_PurgeOnMount(t *testing.T) {
+	testCases := []struct {
+		name        string
+		fuseOptions []string
+		purged      bool
+	}{
+		{
+			name:   "read_write",
+			purged: true,
+		},
+		{
+			name:        "read_only",
+			fuseOptions: []string{"ro"},
+			purged:      false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := &timeutil.SimulatedClock{}
+			clock.SetTime(time.Now().Add(-time.Hour))
+			bucket := fake.NewFakeBucket(clock, "some-bucket", gcs.BucketType{})
+			createWithContents(ctx, t, bucket, "foo", "taco")
+			tr := trash.New(bucket, ".trash/", clock)
+			require.NoError(t, tr.Move(ctx, "foo"))
+			entries, err := tr.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+
+			newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{TrashPrefix: ".trash/", TrashRetention: time.Minute, FuseOptions: tc.fuseOptions})
+
+			// Purging runs in the background.
+			purged := func() bool {
+				_, err := storageutil.ReadObject(ctx, bucket, entries[0].Name)
+				return err != nil
+			}
+			if tc.purged {
+				assert.Eventually(t, purged, 5*time.Second, 10*time.Millisecond)
+			} else {
+				assert.Never(t, purged, 200*time.Millisecond, 10*time.Millisecond)
+			}
+		})
+	}
+}
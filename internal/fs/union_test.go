@@ -0,0 +1,127 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUnionTestFileSystem(ctx context.Context, t *testing.T) (server fuseutil.FileSystem, top, dataset gcs.Bucket) {
+	t.Helper()
+	top = fake.NewFakeBucket(timeutil.RealClock(), "overrides", gcs.BucketType{})
+	dataset = fake.NewFakeBucket(timeutil.RealClock(), "dataset", gcs.BucketType{})
+	createWithContents(ctx, t, dataset, "labels/", "")
+	createWithContents(ctx, t, dataset, "labels/a", "cat")
+	createWithContents(ctx, t, dataset, "labels/b", "dog")
+	createWithContents(ctx, t, top, "labels/b", "wolf")
+	union, err := gcsx.NewUnionBucket([]gcs.Bucket{top, dataset}, 0, timeutil.RealClock())
+	require.NoError(t, err)
+
+	server = newTestFileSystem(ctx, t, union, cfg.FileSystemConfig{})
+	return
+}
+
+func TestUnion_MergesLayers(t *testing.T) {
+	ctx := context.Background()
+	server, _, _ := newUnionTestFileSystem(ctx, t)
+	labels := lookUp(ctx, t, server, fuseops.RootInodeID, "labels")
+	b := lookUp(ctx, t, server, labels, "b")
+
+	attrOp := &fuseops.GetInodeAttributesOp{Inode: b}
+	require.NoError(t, server.GetInodeAttributes(ctx, attrOp))
+
+	assert.Equal(t, uint64(len("wolf")), attrOp.Attributes.Size)
+	assert.Equal(t, []string{"a", "b"}, readDirNames(ctx, t, server, labels))
+}
+
+func TestUnion_UnlinkHidesLowerLayer(t *testing.T) {
+	ctx := context.Background()
+	server, top, dataset := newUnionTestFileSystem(ctx, t)
+	labels := lookUp(ctx, t, server, fuseops.RootInodeID, "labels")
+	lookUp(ctx, t, server, labels, "a")
+
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: labels, Name: "a"}))
+
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, server, labels, "a"))
+	assert.Equal(t, []string{"b"}, readDirNames(ctx, t, server, labels))
+	_, err := storageutil.ReadObject(ctx, top, "labels/.wh.a")
+	assert.NoError(t, err)
+	contents, err := storageutil.ReadObject(ctx, dataset, "labels/a")
+	require.NoError(t, err)
+	assert.Equal(t, "cat", string(contents))
+}
+
+func TestUnion_RenameCopiesUp(t *testing.T) {
+	ctx := context.Background()
+	server, top, dataset := newUnionTestFileSystem(ctx, t)
+	labels := lookUp(ctx, t, server, fuseops.RootInodeID, "labels")
+	lookUp(ctx, t, server, labels, "a")
+
+	require.NoError(t, server.Rename(ctx, &fuseops.RenameOp{OldParent: labels, OldName: "a", NewParent: labels, NewName: "c"}))
+
+	assert.Equal(t, []string{"b", "c"}, readDirNames(ctx, t, server, labels))
+	contents, err := storageutil.ReadObject(ctx, top, "labels/c")
+	require.NoError(t, err)
+	assert.Equal(t, "cat", string(contents))
+	_, err = storageutil.ReadObject(ctx, dataset, "labels/c")
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestUnion_LocalOverlay(t *testing.T) {
+	ctx := context.Background()
+	dataset := fake.NewFakeBucket(timeutil.RealClock(), "dataset", gcs.BucketType{})
+	createWithContents(ctx, t, dataset, "labels/", "")
+	createWithContents(ctx, t, dataset, "labels/a", "cat")
+	overlay, err := gcsx.NewLocalBucket(t.TempDir(), "dataset")
+	require.NoError(t, err)
+	union, err := gcsx.NewUnionBucket([]gcs.Bucket{overlay, dataset}, 0, timeutil.RealClock())
+	require.NoError(t, err)
+	server := newTestFileSystem(ctx, t, union, cfg.FileSystemConfig{})
+	labels := lookUp(ctx, t, server, fuseops.RootInodeID, "labels")
+	lookUp(ctx, t, server, labels, "a")
+
+	createOp := &fuseops.CreateFileOp{Parent: labels, Name: "b", Mode: 0644}
+	require.NoError(t, server.CreateFile(ctx, createOp))
+	require.NoError(t, server.WriteFile(ctx, &fuseops.WriteFileOp{Inode: createOp.Entry.Child, Handle: createOp.Handle, Data: []byte("dog")}))
+	require.NoError(t, server.FlushFile(ctx, &fuseops.FlushFileOp{Inode: createOp.Entry.Child, Handle: createOp.Handle}))
+	require.NoError(t, server.Unlink(ctx, &fuseops.UnlinkOp{Parent: labels, Name: "a"}))
+
+	assert.Equal(t, []string{"b"}, readDirNames(ctx, t, server, labels))
+	contents, err := storageutil.ReadObject(ctx, overlay, "labels/b")
+	require.NoError(t, err)
+	assert.Equal(t, "dog", string(contents))
+	_, err = storageutil.ReadObject(ctx, overlay, "labels/.wh.a")
+	assert.NoError(t, err)
+	_, err = storageutil.ReadObject(ctx, dataset, "labels/b")
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+	contents, err = storageutil.ReadObject(ctx, dataset, "labels/a")
+	require.NoError(t, err)
+	assert.Equal(t, "cat", string(contents))
+}
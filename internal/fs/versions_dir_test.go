@@ -0,0 +1,90 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"context"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/jacobsa/fuse"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/jacobsa/fuse/fuseutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionsDir_ListAndRead(t *testing.T) {
+	ctx := context.Background()
+	bucket, gens := newVersionedBucket(ctx, t, "taco", "burrito")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{EnableVersionsDirs: true})
+	versions := lookUp(ctx, t, server, fuseops.RootInodeID, "foo@versions")
+	openDirOp := &fuseops.OpenDirOp{Inode: versions}
+	require.NoError(t, server.OpenDir(ctx, openDirOp))
+	readDirOp := &fuseops.ReadDirOp{Inode: versions, Handle: openDirOp.Handle, Dst: make([]byte, 4096)}
+	require.NoError(t, server.ReadDir(ctx, readDirOp))
+	var names []string
+	for _, e := range parseDirents(readDirOp.Dst[:readDirOp.BytesRead]) {
+		assert.Equal(t, fuseutil.DT_File, e.Type)
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{strconv.FormatInt(gens[0], 10), strconv.FormatInt(gens[1], 10)}, names)
+	first := lookUp(ctx, t, server, versions, strconv.FormatInt(gens[0], 10))
+	attrsOp := &fuseops.GetInodeAttributesOp{Inode: first}
+	require.NoError(t, server.GetInodeAttributes(ctx, attrsOp))
+	openOp := &fuseops.OpenFileOp{Inode: first}
+	require.NoError(t, server.OpenFile(ctx, openOp))
+	readOp := &fuseops.ReadFileOp{Inode: first, Handle: openOp.Handle, Dst: make([]byte, 16)}
+
+	err := server.ReadFile(ctx, readOp)
+
+	require.NoError(t, err)
+	assert.Equal(t, "taco", string(readOp.Dst[:readOp.BytesRead]))
+	assert.Equal(t, uint64(len("taco")), attrsOp.Attributes.Size)
+	assert.Zero(t, attrsOp.Attributes.Mode&0222)
+	require.NoError(t, server.ReleaseFileHandle(ctx, &fuseops.ReleaseFileHandleOp{Handle: openOp.Handle}))
+}
+
+func TestVersionsDir_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	bucket, gens := newVersionedBucket(ctx, t, "taco", "burrito")
+	server := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{EnableVersionsDirs: true})
+	versions := lookUp(ctx, t, server, fuseops.RootInodeID, "foo@versions")
+	first := lookUp(ctx, t, server, versions, strconv.FormatInt(gens[0], 10))
+
+	err := server.OpenFile(ctx, &fuseops.OpenFileOp{Inode: first, OpenFlags: syscall.O_RDWR})
+
+	assert.Equal(t, syscall.EROFS, err)
+	err = server.CreateFile(ctx, &fuseops.CreateFileOp{Parent: versions, Name: "bar", Mode: 0644})
+	assert.ErrorIs(t, err, syscall.EROFS)
+	size := uint64(0)
+	err = server.SetInodeAttributes(ctx, &fuseops.SetInodeAttributesOp{Inode: first, Size: &size})
+	assert.Equal(t, syscall.EROFS, err)
+}
+
+func TestVersionsDir_Missing(t *testing.T) {
+	ctx := context.Background()
+	bucket, gens := newVersionedBucket(ctx, t, "taco")
+	enabled := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{EnableVersionsDirs: true})
+	disabled := newTestFileSystem(ctx, t, bucket, cfg.FileSystemConfig{})
+	versions := lookUp(ctx, t, enabled, fuseops.RootInodeID, "foo@versions")
+
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, enabled, fuseops.RootInodeID, "bar@versions"))
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, enabled, versions, strconv.FormatInt(gens[0]+1, 10)))
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, enabled, versions, "0"+strconv.FormatInt(gens[0], 10)))
+	assert.Equal(t, fuse.ENOENT, lookUpErr(ctx, disabled, fuseops.RootInodeID, "foo@versions"))
+}
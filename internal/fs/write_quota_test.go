@@ -0,0 +1,120 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"os"
+	"path"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	. "github.com/jacobsa/ogletest"
+	"golang.org/x/sys/unix"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+// WriteQuotaTest mounts with a write quota of 1 MiB and one file for each
+// top-level directory. The quota is never given back, so each test works in
+// top-level directories of its own.
+type WriteQuotaTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&WriteQuotaTest{})
+}
+
+func (t *WriteQuotaTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TtlSecs:            60,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			WriteQuotaMb:             1,
+			WriteQuotaObjects:        1,
+			WriteQuotaPerTopLevelDir: true,
+		},
+		EnableNewReader: true,
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *WriteQuotaTest) RefusesWritesPastTheLimit() {
+	AssertEq(nil, os.Mkdir(path.Join(mntDir, "writes"), 0755))
+	fd, err := unix.Open(path.Join(mntDir, "writes/foo"), unix.O_CREAT|unix.O_WRONLY, 0644)
+	AssertEq(nil, err)
+	// The kernel caches writes and sends them on in parallel, so sync each
+	// one to control the order in which they are charged.
+	data := make([]byte, 1<<19)
+	for range 2 {
+		_, err = unix.Write(fd, data)
+		AssertEq(nil, err)
+		AssertEq(nil, unix.Fsync(fd))
+	}
+	_, err = unix.Write(fd, data)
+	AssertEq(nil, err)
+
+	err = unix.Fsync(fd)
+
+	// The kernel reports the write it failed to send on, and then SyncFile
+	// reports it once more.
+	ExpectEq(unix.EDQUOT, err)
+	ExpectEq(unix.EDQUOT, unix.Fsync(fd))
+	ExpectEq(nil, unix.Fsync(fd))
+	ExpectEq(nil, unix.Close(fd))
+	contents, err := storageutil.ReadObject(ctx, bucket, "writes/foo")
+	AssertEq(nil, err)
+	ExpectEq(1<<20, len(contents))
+}
+
+func (t *WriteQuotaTest) RefusesCreatesPastTheLimitOfEachTopLevelDir() {
+	AssertEq(nil, os.Mkdir(path.Join(mntDir, "creates_a"), 0755))
+	AssertEq(nil, os.Mkdir(path.Join(mntDir, "creates_b"), 0755))
+	AssertEq(nil, createAndClose(path.Join(mntDir, "creates_a/foo"), 0644))
+
+	err := createAndClose(path.Join(mntDir, "creates_a/bar"), 0644)
+
+	ExpectEq(unix.EDQUOT, err)
+	ExpectEq(nil, createAndClose(path.Join(mntDir, "creates_b/foo"), 0644))
+	ExpectEq(nil, createAndClose(path.Join(mntDir, "foo"), 0644))
+}
+
+func (t *WriteQuotaTest) RefusesFallocatePastTheLimit() {
+	AssertEq(nil, os.Mkdir(path.Join(mntDir, "fallocate"), 0755))
+	fd, err := unix.Open(path.Join(mntDir, "fallocate/foo"), unix.O_CREAT|unix.O_WRONLY, 0644)
+	AssertEq(nil, err)
+	AssertEq(nil, unix.Fallocate(fd, 0, 0, 1<<19))
+
+	err = unix.Fallocate(fd, 0, 1<<19, 1<<20)
+
+	ExpectEq(unix.EDQUOT, err)
+	// Reserving space without extending the file isn't charged.
+	ExpectEq(nil, unix.Fallocate(fd, unix.FALLOC_FL_KEEP_SIZE, 1<<19, 1<<20))
+	ExpectEq(nil, unix.Fallocate(fd, 0, 0, 1<<20))
+	var st unix.Stat_t
+	AssertEq(nil, unix.Fstat(fd, &st))
+	ExpectEq(1<<20, st.Size)
+	ExpectEq(nil, unix.Close(fd))
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fs_test
+
+import (
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/fs/inode"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	. "github.com/jacobsa/oglematchers"
+	. "github.com/jacobsa/ogletest"
+)
+
+////////////////////////////////////////////////////////////////////////
+// Boilerplate
+////////////////////////////////////////////////////////////////////////
+
+type XattrTest struct {
+	fsTest
+}
+
+func init() {
+	RegisterTestSuite(&XattrTest{})
+}
+
+func (t *XattrTest) SetUpTestSuite() {
+	t.serverCfg.NewConfig = &cfg.Config{
+		FileCache: defaultFileCacheConfig(),
+		MetadataCache: cfg.MetadataCacheConfig{
+			StatCacheMaxSizeMb: 33,
+			TtlSecs:            60,
+			TypeCacheMaxSizeMb: 4,
+		},
+		FileSystem: cfg.FileSystemConfig{
+			ExperimentalEnableXattrs: true,
+		},
+		EnableNewReader: true,
+	}
+	t.fsTest.SetUpTestSuite()
+}
+
+func getXattr(p string, name string) (string, error) {
+	buf := make([]byte, 1024)
+	n, err := syscall.Getxattr(p, name, buf)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf[:n]), nil
+}
+
+func listXattr(p string) ([]string, error) {
+	buf := make([]byte, 1024)
+	n, err := syscall.Listxattr(p, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	start := 0
+	for i := 0; i < n; i++ {
+		if buf[i] == 0 {
+			names = append(names, string(buf[start:i]))
+			start = i + 1
+		}
+	}
+
+	return names, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+// Tests
+////////////////////////////////////////////////////////////////////////
+
+func (t *XattrTest) SetAndGetOnSyncedFile() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+
+	err := syscall.Setxattr(p, "user.label", []byte("cat"), 0)
+	AssertEq(nil, err)
+
+	value, err := getXattr(p, "user.label")
+	AssertEq(nil, err)
+	ExpectEq("cat", value)
+
+	// The attribute should have been written through to the object.
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	AssertEq(nil, err)
+	ExpectEq("cat", m.Metadata["label"])
+}
+
+func (t *XattrTest) ReadsExistingCustomMetadata() {
+	_, err := bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     "foo",
+		Contents: strings.NewReader(""),
+		Metadata: map[string]string{"label": "dog", "gcsfuse_mtime": "2015-04-05T02:15:00Z"},
+	})
+	AssertEq(nil, err)
+	p := path.Join(mntDir, "foo")
+
+	value, err := getXattr(p, "user.label")
+	AssertEq(nil, err)
+	ExpectEq("dog", value)
+
+	names, err := listXattr(p)
+	AssertEq(nil, err)
+	ExpectThat(names, ElementsAre("user.label"))
+}
+
+func (t *XattrTest) SizeQueryAndSmallBuffer() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+	AssertEq(nil, syscall.Setxattr(p, "user.label", []byte("burrito"), 0))
+
+	n, err := syscall.Getxattr(p, "user.label", nil)
+	AssertEq(nil, err)
+	ExpectEq(len("burrito"), n)
+
+	_, err = syscall.Getxattr(p, "user.label", make([]byte, 2))
+	ExpectEq(syscall.ERANGE, err)
+}
+
+func (t *XattrTest) DirtyFileWritesAttributesOnClose() {
+	p := path.Join(mntDir, "foo")
+	f, err := os.Create(p)
+	AssertEq(nil, err)
+	_, err = f.Write([]byte("taco"))
+	AssertEq(nil, err)
+
+	AssertEq(nil, syscall.Setxattr(p, "user.label", []byte("cat"), 0))
+	value, err := getXattr(p, "user.label")
+	AssertEq(nil, err)
+	ExpectEq("cat", value)
+
+	AssertEq(nil, f.Close())
+
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	AssertEq(nil, err)
+	ExpectEq(4, m.Size)
+	ExpectEq("cat", m.Metadata["label"])
+}
+
+func (t *XattrTest) RemoveAttribute() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+	AssertEq(nil, syscall.Setxattr(p, "user.label", []byte("cat"), 0))
+
+	err := syscall.Removexattr(p, "user.label")
+	AssertEq(nil, err)
+
+	_, err = getXattr(p, "user.label")
+	ExpectEq(syscall.ENODATA, err)
+	err = syscall.Removexattr(p, "user.label")
+	ExpectEq(syscall.ENODATA, err)
+}
+
+func (t *XattrTest) OtherNamespacesAreUnsupported() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+
+	_, err := getXattr(p, "security.selinux")
+	ExpectEq(syscall.ENODATA, err)
+
+	err = syscall.Setxattr(p, "trusted.label", []byte("cat"), 0)
+	ExpectThat(err, AnyOf(syscall.ENOTSUP, syscall.EPERM))
+}
+
+func (t *XattrTest) DirectoriesHaveNoAttributes() {
+	AssertEq(nil, os.Mkdir(path.Join(mntDir, "dir"), 0700))
+	p := path.Join(mntDir, "dir")
+
+	names, err := listXattr(p)
+	AssertEq(nil, err)
+	ExpectThat(names, ElementsAre())
+
+	err = syscall.Setxattr(p, "user.label", []byte("cat"), 0)
+	ExpectEq(syscall.ENOTSUP, err)
+}
+
+func (t *XattrTest) GcsfuseNamespaceDescribesBackingObject() {
+	AssertEq(nil, t.createWithContents("foo", "taco"))
+	p := path.Join(mntDir, "foo")
+	m, _, err := bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	AssertEq(nil, err)
+
+	value, err := getXattr(p, inode.GenerationXattr)
+	AssertEq(nil, err)
+	ExpectEq(strconv.FormatInt(m.Generation, 10), value)
+
+	value, err = getXattr(p, inode.BucketXattr)
+	AssertEq(nil, err)
+	ExpectEq(bucket.Name(), value)
+
+	err = syscall.Setxattr(p, inode.GenerationXattr, []byte("1"), 0)
+	ExpectEq(syscall.EPERM, err)
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/googlecloudplatform/gcsfuse/v3/cfg"
@@ -83,6 +84,21 @@ type BucketConfig struct {
 	IsTypeCacheDeprecated bool
 
 	ImplicitDir bool
+
+	// Expose objects whose names can't be file system paths under escaped
+	// names.
+	EscapeUnsupportedPaths bool
+
+	// If non-zero, the bucket is served read-only as it was at this time.
+	AsOf time.Time
+
+	// The <bucket>[/<prefix>] layers that the bucket is overlaid on, in
+	// decreasing order of priority. See NewUnionBucket.
+	UnionLayers []string
+
+	// If set, writes go to a local bucket kept in this directory, overlaid on
+	// the bucket and its union layers. See NewLocalBucket.
+	OverlayDir string
 }
 
 // BucketManager manages the lifecycle of buckets.
@@ -91,6 +107,10 @@ type BucketManager interface {
 		ctx context.Context,
 		name string, isMultibucketMount bool, metricHandle metrics.MetricHandle) (b SyncerBucket, err error)
 
+	// ForBucketMount returns a BucketManager that sets up the bucket of the
+	// supplied entry of bucket-mounts, with the settings it overrides.
+	ForBucketMount(m cfg.BucketMount) BucketManager
+
 	// Shuts down the bucket manager and its buckets
 	ShutDown()
 }
@@ -174,13 +194,14 @@ func setUpRateLimiting(
 	return
 }
 
-func (bm *bucketManager) SetUpBucket(
+// setUpLayer sets up the named bucket, limited to onlyDir if it isn't empty,
+// as a layer that SetUpBucket builds on.
+func (bm *bucketManager) setUpLayer(
 	ctx context.Context,
 	name string,
-	isMultibucketMount bool,
+	onlyDir string,
 	metricHandle metrics.MetricHandle,
-) (sb SyncerBucket, err error) {
-	var b gcs.Bucket
+) (b gcs.Bucket, err error) {
 	// Set up the appropriate backing bucket.
 	if name == canned.FakeBucketName {
 		b = canned.MakeFakeBucket(ctx)
@@ -209,15 +230,78 @@ func (bm *bucketManager) SetUpBucket(
 		b = storage.NewDebugBucket(b)
 	}
 
+	// Go back in time, if requested.
+	if !bm.config.AsOf.IsZero() {
+		b, err = NewPointInTimeBucket(bm.config.AsOf, b)
+		if err != nil {
+			err = fmt.Errorf("NewPointInTimeBucket: %w", err)
+			return
+		}
+	}
+
 	// Limit to a requested prefix of the bucket, if any.
-	if bm.config.OnlyDir != "" {
-		b, err = NewPrefixBucket(path.Clean(bm.config.OnlyDir)+"/", b)
+	if onlyDir != "" {
+		b, err = NewPrefixBucket(path.Clean(onlyDir)+"/", b)
 		if err != nil {
 			err = fmt.Errorf("NewPrefixBucket: %w", err)
 			return
 		}
 	}
 
+	return
+}
+
+func (bm *bucketManager) SetUpBucket(
+	ctx context.Context,
+	name string,
+	isMultibucketMount bool,
+	metricHandle metrics.MetricHandle,
+) (sb SyncerBucket, err error) {
+	b, err := bm.setUpLayer(ctx, name, bm.config.OnlyDir, metricHandle)
+	if err != nil {
+		return
+	}
+
+	// Overlay the bucket on the union layers, if any, and the local overlay
+	// on both.
+	if len(bm.config.UnionLayers) > 0 || bm.config.OverlayDir != "" {
+		layers := []gcs.Bucket{b}
+		if bm.config.OverlayDir != "" {
+			var overlay gcs.Bucket
+			if overlay, err = NewLocalBucket(bm.config.OverlayDir, b.Name()); err != nil {
+				err = fmt.Errorf("NewLocalBucket: %w", err)
+				return
+			}
+			layers = []gcs.Bucket{overlay, b}
+		}
+		for _, l := range bm.config.UnionLayers {
+			layerName, onlyDir, _ := strings.Cut(l, "/")
+			var layer gcs.Bucket
+			if layer, err = bm.setUpLayer(ctx, layerName, onlyDir, metricHandle); err != nil {
+				err = fmt.Errorf("union layer %q: %w", l, err)
+				return
+			}
+			layers = append(layers, layer)
+		}
+
+		// Whiteouts are cached along with stats, if at all.
+		var ttl time.Duration
+		if bm.sharedStatCache != nil {
+			ttl = bm.config.StatCacheTTL
+		}
+		b, err = NewUnionBucket(layers, ttl, timeutil.RealClock())
+		if err != nil {
+			err = fmt.Errorf("NewUnionBucket: %w", err)
+			return
+		}
+	}
+
+	// Escape the names of objects that can't be file system paths, if
+	// requested. Below the prefix bucket, the prefix would be escaped too.
+	if bm.config.EscapeUnsupportedPaths {
+		b = NewEscapingBucket(b)
+	}
+
 	// Enable rate limiting, if requested.
 	b, err = setUpRateLimiting(
 		b,
@@ -277,12 +361,38 @@ func (bm *bucketManager) SetUpBucket(
 		}
 	}
 
-	// Periodically garbage collect temporary objects
-	go garbageCollect(bm.gcCtx, bm.config.TmpObjectPrefix, sb)
+	// Periodically garbage collect temporary objects, which can't be written
+	// when looking at the past.
+	if bm.config.AsOf.IsZero() {
+		go garbageCollect(bm.gcCtx, bm.config.TmpObjectPrefix, sb)
+	}
 
 	return
 }
 
+// The returned manager shares the stat cache and garbage collection of bm.
+func (bm *bucketManager) ForBucketMount(m cfg.BucketMount) BucketManager {
+	config := bm.config
+	config.OnlyDir = m.OnlyDir
+	if m.BillingProject != "" {
+		config.BillingProject = m.BillingProject
+	}
+	if m.StatCacheTTL != nil {
+		config.StatCacheTTL = *m.StatCacheTTL
+	}
+	if m.NegativeStatCacheTTL != nil {
+		config.NegativeStatCacheTTL = *m.NegativeStatCacheTTL
+	}
+
+	return &bucketManager{
+		config:                config,
+		storageHandle:         bm.storageHandle,
+		sharedStatCache:       bm.sharedStatCache,
+		gcCtx:                 bm.gcCtx,
+		stopGarbageCollecting: bm.stopGarbageCollecting,
+	}
+}
+
 func (bm *bucketManager) ShutDown() {
 	bm.stopGarbageCollecting()
 }
@@ -89,6 +89,27 @@ func (t *BucketManagerTest) TestNewBucketManagerMethod() {
 	ExpectNe(nil, bm)
 }
 
+func (t *BucketManagerTest) TestForBucketMountMethod() {
+	bm := NewBucketManager(BucketConfig{
+		BillingProject:       "BillingProject",
+		OnlyDir:              "OnlyDir",
+		StatCacheMaxSizeMB:   1,
+		StatCacheTTL:         20 * time.Second,
+		NegativeStatCacheTTL: 5 * time.Second,
+		TmpObjectPrefix:      "TmpObjectPrefix",
+	}, t.storageHandle).(*bucketManager)
+	ttl := time.Minute
+
+	mounted := bm.ForBucketMount(cfg.BucketMount{Dir: "data", Bucket: TestBucketName, OnlyDir: "some/prefix", StatCacheTTL: &ttl}).(*bucketManager)
+
+	ExpectEq("some/prefix", mounted.config.OnlyDir)
+	ExpectEq("BillingProject", mounted.config.BillingProject)
+	ExpectEq(time.Minute, mounted.config.StatCacheTTL)
+	ExpectEq(5*time.Second, mounted.config.NegativeStatCacheTTL)
+	ExpectEq(bm.sharedStatCache, mounted.sharedStatCache)
+	ExpectEq("OnlyDir", bm.config.OnlyDir)
+}
+
 func (t *BucketManagerTest) TestSetUpBucketMethod() {
 	var bm bucketManager
 	bucketConfig := BucketConfig{
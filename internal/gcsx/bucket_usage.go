@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/sync/errgroup"
+)
+
+// BucketUsage is the space taken up by the objects in a bucket.
+type BucketUsage struct {
+	Bytes   uint64
+	Objects uint64
+}
+
+// measureUsage lists the whole bucket and adds up the sizes of its objects,
+// leaving out those under ignorePrefix unless it is empty.
+func measureUsage(ctx context.Context, bucket gcs.Bucket, ignorePrefix string) (usage BucketUsage, err error) {
+	group, ctx := errgroup.WithContext(ctx)
+
+	minObjects := make(chan *gcs.MinObject, 100)
+	group.Go(func() error {
+		defer close(minObjects)
+		if err := storageutil.ListPrefix(ctx, bucket, "", minObjects); err != nil {
+			return fmt.Errorf("ListPrefix: %w", err)
+		}
+
+		return nil
+	})
+
+	group.Go(func() error {
+		for o := range minObjects {
+			if ignorePrefix != "" && strings.HasPrefix(o.Name, ignorePrefix) {
+				continue
+			}
+			usage.Bytes += o.Size
+			usage.Objects++
+		}
+
+		return nil
+	})
+
+	err = group.Wait()
+	return
+}
+
+// UsageTracker keeps an estimate of the usage of a bucket by listing it in the
+// background every so often.
+type UsageTracker struct {
+	bucket       gcs.Bucket
+	ignorePrefix string
+	cancel       context.CancelFunc
+	done         chan struct{}
+
+	mu sync.Mutex
+
+	// The result of the last listing that succeeded.
+	//
+	// GUARDED_BY(mu)
+	usage BucketUsage
+
+	// Whether any listing has succeeded yet.
+	//
+	// GUARDED_BY(mu)
+	measured bool
+}
+
+// NewUsageTracker starts measuring the usage of the supplied bucket right away
+// and then once per period, until Stop is called. Objects under ignorePrefix,
+// if it isn't empty, aren't counted.
+func NewUsageTracker(bucket gcs.Bucket, ignorePrefix string, period time.Duration) *UsageTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &UsageTracker{
+		bucket:       bucket,
+		ignorePrefix: ignorePrefix,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+
+	go t.run(ctx, period)
+	return t
+}
+
+func (t *UsageTracker) run(ctx context.Context, period time.Duration) {
+	defer close(t.done)
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		t.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *UsageTracker) refresh(ctx context.Context) {
+	startTime := time.Now()
+	usage, err := measureUsage(ctx, t.bucket, t.ignorePrefix)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warnf("Measuring usage of bucket %q failed after %v: %v", t.bucket.Name(), time.Since(startTime), err)
+		}
+		return
+	}
+
+	logger.Debugf("Bucket %q holds %d objects totalling %d bytes, listed in %v.", t.bucket.Name(), usage.Objects, usage.Bytes, time.Since(startTime))
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage = usage
+	t.measured = true
+}
+
+// Usage returns the usage found by the last listing, or false if none has
+// finished yet.
+func (t *UsageTracker) Usage() (BucketUsage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.usage, t.measured
+}
+
+// Stop cancels any listing in progress and waits for the tracker to exit.
+func (t *UsageTracker) Stop() {
+	t.cancel()
+	<-t.done
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageTracker(t *testing.T) {
+	ctx := context.Background()
+	bucket := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	for name, contents := range map[string]string{
+		"foo":     "taco",
+		"dir/":    "",
+		"dir/bar": "burrito",
+		".x/baz":  "enchilada",
+	} {
+		_, err := storageutil.CreateObject(ctx, bucket, name, []byte(contents))
+		require.NoError(t, err)
+	}
+
+	tracker := gcsx.NewUsageTracker(bucket, ".x/", time.Hour)
+	defer tracker.Stop()
+
+	var usage gcsx.BucketUsage
+	assert.Eventually(t, func() bool {
+		var ok bool
+		usage, ok = tracker.Usage()
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, gcsx.BucketUsage{Bytes: 11, Objects: 3}, usage)
+}
@@ -87,6 +87,7 @@ func (oc *composeObjectCreator) Create(
 	objectName string,
 	srcObject *gcs.Object,
 	mtime *time.Time,
+	metadata map[string]*string,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	r io.Reader) (o *gcs.Object, err error) {
@@ -124,6 +125,7 @@ func (oc *composeObjectCreator) Create(
 
 	/* Copy Metadata fields from src object to new object generated by compose. */
 	maps.Copy(MetadataMap, srcObject.Metadata)
+	applyMetadataUpdates(MetadataMap, metadata)
 
 	if mtime != nil {
 		MetadataMap[gcs.MtimeMetadataKey] = mtime.UTC().Format(time.RFC3339Nano)
@@ -93,6 +93,7 @@ func (t *ComposeObjectCreatorTest) call() (o *gcs.Object, err error) {
 		t.srcObject.Name,
 		&t.srcObject,
 		&t.mtime,
+		nil,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -0,0 +1,389 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/net/context"
+)
+
+// NewEscapingBucket creates a view on the wrapped bucket that exposes each
+// object under the name storageutil.EscapeUnsupportedPath gives it, so that
+// objects with names that can't be file system paths, such as a//b or ../c,
+// can be reached.
+//
+// Names that begin a path segment with storageutil.EscapeChar without being
+// escaped names don't refer to any object, and objects can't be created with
+// them.
+func NewEscapingBucket(wrapped gcs.Bucket) gcs.Bucket {
+	return &escapingBucket{
+		wrapped: wrapped,
+	}
+}
+
+type escapingBucket struct {
+	wrapped gcs.Bucket
+}
+
+// wrappedName returns the name of the existing object that is exposed under
+// the supplied name.
+func (b *escapingBucket) wrappedName(n string) (string, error) {
+	wrapped, ok := storageutil.UnescapeUnsupportedPath(n)
+	if !ok {
+		return "", &gcs.NotFoundError{Err: fmt.Errorf("%q is not an escaped object name", n)}
+	}
+	return wrapped, nil
+}
+
+// newWrappedName returns the name of the object to create so that it is
+// exposed under the supplied name.
+func (b *escapingBucket) newWrappedName(n string) (string, error) {
+	wrapped, ok := storageutil.UnescapeUnsupportedPath(n)
+	if !ok {
+		return "", fmt.Errorf("%q: names beginning with %q are reserved for escaped object names: %w", n, storageutil.EscapeChar, syscall.EINVAL)
+	}
+	return wrapped, nil
+}
+
+func (b *escapingBucket) localName(n string) string {
+	return storageutil.EscapeUnsupportedPath(n)
+}
+
+func (b *escapingBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *escapingBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *escapingBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (rd gcs.StorageReader, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.ReadObjectRequest)
+	*mReq = *req
+	if mReq.Name, err = b.wrappedName(req.Name); err != nil {
+		return
+	}
+
+	rd, err = b.wrapped.NewReaderWithReadHandle(ctx, mReq)
+	return
+}
+
+func (b *escapingBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (o *gcs.Object, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.CreateObjectRequest)
+	*mReq = *req
+	if mReq.Name, err = b.newWrappedName(req.Name); err != nil {
+		return
+	}
+
+	o, err = b.wrapped.CreateObject(ctx, mReq)
+
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+
+	return
+}
+
+func (b *escapingBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	// Modify the request and call through.
+	mReq := new(gcs.CreateObjectRequest)
+	*mReq = *req
+	var err error
+	if mReq.Name, err = b.newWrappedName(req.Name); err != nil {
+		return nil, err
+	}
+
+	return b.wrapped.CreateObjectChunkWriter(ctx, mReq, chunkSize, callBack)
+}
+
+func (b *escapingBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	// Modify the request and call through.
+	mReq := new(gcs.CreateObjectChunkWriterRequest)
+	*mReq = *req
+	var err error
+	if mReq.Name, err = b.newWrappedName(req.Name); err != nil {
+		return nil, err
+	}
+
+	return b.wrapped.CreateAppendableObjectWriter(ctx, mReq)
+}
+
+func (b *escapingBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (o *gcs.MinObject, err error) {
+	o, err = b.wrapped.FinalizeUpload(ctx, w)
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+	return
+}
+
+func (b *escapingBucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (o *gcs.MinObject, err error) {
+	o, err = b.wrapped.FlushPendingWrites(ctx, w)
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+	return
+}
+
+func (b *escapingBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (o *gcs.Object, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.CopyObjectRequest)
+	*mReq = *req
+	if mReq.SrcName, err = b.wrappedName(req.SrcName); err != nil {
+		return
+	}
+	if mReq.DstName, err = b.newWrappedName(req.DstName); err != nil {
+		return
+	}
+
+	o, err = b.wrapped.CopyObject(ctx, mReq)
+
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+
+	return
+}
+
+func (b *escapingBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (o *gcs.Object, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.ComposeObjectsRequest)
+	*mReq = *req
+	if mReq.DstName, err = b.newWrappedName(req.DstName); err != nil {
+		return
+	}
+
+	mReq.Sources = nil
+	for _, s := range req.Sources {
+		if s.Name, err = b.wrappedName(s.Name); err != nil {
+			return
+		}
+		mReq.Sources = append(mReq.Sources, s)
+	}
+
+	o, err = b.wrapped.ComposeObjects(ctx, mReq)
+
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+
+	return
+}
+
+func (b *escapingBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (m *gcs.MinObject, e *gcs.ExtendedObjectAttributes, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.StatObjectRequest)
+	*mReq = *req
+	if mReq.Name, err = b.wrappedName(req.Name); err != nil {
+		return
+	}
+
+	m, e, err = b.wrapped.StatObject(ctx, mReq)
+
+	// Modify the returned object.
+	if m != nil {
+		m.Name = b.localName(m.Name)
+	}
+
+	return
+}
+
+func (b *escapingBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (l *gcs.Listing, err error) {
+	// Modify the request and call through. A prefix that isn't an escaped name
+	// matches nothing; a start offset that isn't one is only used for ordering.
+	mReq := new(gcs.ListObjectsRequest)
+	*mReq = *req
+	var ok bool
+	if mReq.Prefix, ok = storageutil.UnescapeUnsupportedPath(req.Prefix); !ok {
+		l = &gcs.Listing{}
+		return
+	}
+	if mReq.StartOffset, ok = storageutil.UnescapeUnsupportedPath(req.StartOffset); !ok {
+		mReq.StartOffset = req.StartOffset
+	}
+
+	l, err = b.wrapped.ListObjects(ctx, mReq)
+
+	// Modify the returned listing.
+	if l != nil {
+		for _, o := range l.MinObjects {
+			o.Name = b.localName(o.Name)
+		}
+
+		for i, n := range l.CollapsedRuns {
+			l.CollapsedRuns[i] = b.localName(n)
+		}
+	}
+
+	return
+}
+
+func (b *escapingBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (o *gcs.Object, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.UpdateObjectRequest)
+	*mReq = *req
+	if mReq.Name, err = b.wrappedName(req.Name); err != nil {
+		return
+	}
+
+	o, err = b.wrapped.UpdateObject(ctx, mReq)
+
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+
+	return
+}
+
+func (b *escapingBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) (err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.DeleteObjectRequest)
+	*mReq = *req
+	if mReq.Name, err = b.wrappedName(req.Name); err != nil {
+		return
+	}
+
+	err = b.wrapped.DeleteObject(ctx, mReq)
+	return
+}
+
+func (b *escapingBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	// Modify the request and call through.
+	mReq := new(gcs.MoveObjectRequest)
+	*mReq = *req
+	var err error
+	if mReq.SrcName, err = b.wrappedName(req.SrcName); err != nil {
+		return nil, err
+	}
+	if mReq.DstName, err = b.newWrappedName(req.DstName); err != nil {
+		return nil, err
+	}
+
+	o, err := b.wrapped.MoveObject(ctx, mReq)
+
+	// Modify the returned object.
+	if o != nil {
+		o.Name = b.localName(o.Name)
+	}
+
+	return o, err
+}
+
+func (b *escapingBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	mFolderName, err := b.wrappedName(folderName)
+	if err != nil {
+		return err
+	}
+	return b.wrapped.DeleteFolder(ctx, mFolderName)
+}
+
+func (b *escapingBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (folder *gcs.Folder, err error) {
+	mReq := new(gcs.GetFolderRequest)
+	*mReq = *req
+	if mReq.Name, err = b.wrappedName(req.Name); err != nil {
+		return
+	}
+
+	f, err := b.wrapped.GetFolder(ctx, mReq)
+
+	// Modify the returned folder.
+	if f != nil {
+		f.Name = b.localName(f.Name)
+	}
+
+	return f, err
+}
+
+func (b *escapingBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	mFolderName, err := b.newWrappedName(folderName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := b.wrapped.CreateFolder(ctx, mFolderName)
+
+	// Modify the returned folder.
+	if f != nil {
+		f.Name = b.localName(f.Name)
+	}
+
+	return f, err
+}
+
+func (b *escapingBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	mFolderName, err := b.wrappedName(folderName)
+	if err != nil {
+		return nil, err
+	}
+	mDestinationFolderId, err := b.newWrappedName(destinationFolderId)
+	if err != nil {
+		return nil, err
+	}
+	f, err := b.wrapped.RenameFolder(ctx, mFolderName, mDestinationFolderId)
+
+	// Modify the returned folder.
+	if f != nil {
+		f.Name = b.localName(f.Name)
+	}
+
+	return f, err
+}
+
+func (b *escapingBucket) NewMultiRangeDownloader(
+	ctx context.Context, req *gcs.MultiRangeDownloaderRequest) (mrd gcs.MultiRangeDownloader, err error) {
+	// Modify the request and call through.
+	mReq := new(gcs.MultiRangeDownloaderRequest)
+	*mReq = *req
+	if mReq.Name, err = b.wrappedName(req.Name); err != nil {
+		return
+	}
+
+	mrd, err = b.wrapped.NewMultiRangeDownloader(ctx, mReq)
+	return
+}
+
+func (b *escapingBucket) GCSName(object *gcs.MinObject) string {
+	o := *object
+	if name, ok := storageutil.UnescapeUnsupportedPath(o.Name); ok {
+		o.Name = name
+	}
+	return b.wrapped.GCSName(&o)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type EscapingBucketTest struct {
+	suite.Suite
+	ctx     context.Context
+	wrapped gcs.Bucket
+	bucket  gcs.Bucket
+}
+
+func TestEscapingBucket(t *testing.T) {
+	suite.Run(t, new(EscapingBucketTest))
+}
+
+func (t *EscapingBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	t.wrapped = fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	t.bucket = gcsx.NewEscapingBucket(t.wrapped)
+
+	require.NoError(t.T(), storageutil.CreateObjects(t.ctx, t.wrapped, map[string][]byte{
+		"a//b":  []byte("taco"),
+		"../c":  []byte("burrito"),
+		"%d":    []byte("enchilada"),
+		"plain": []byte("queso"),
+	}))
+}
+
+func (t *EscapingBucketTest) TestListObjects() {
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{Delimiter: "/"})
+
+	require.NoError(t.T(), err)
+	var names []string
+	for _, o := range listing.MinObjects {
+		names = append(names, o.Name)
+	}
+	assert.ElementsMatch(t.T(), []string{"%25d", "plain"}, names)
+	assert.ElementsMatch(t.T(), []string{"%2E%2E/", "a/"}, listing.CollapsedRuns)
+
+	listing, err = t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{Prefix: "a/%/"})
+
+	require.NoError(t.T(), err)
+	require.Len(t.T(), listing.MinObjects, 1)
+	assert.Equal(t.T(), "a/%/b", listing.MinObjects[0].Name)
+}
+
+func (t *EscapingBucketTest) TestReadEscapedName() {
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, "%2E%2E/c")
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", string(contents))
+}
+
+func (t *EscapingBucketTest) TestStatNameThatIsNotEscaped() {
+	_, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "%d"})
+
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFoundErr)
+}
+
+func (t *EscapingBucketTest) TestMoveEscapedName() {
+	o, err := t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: "a/%/b", DstName: "%2E/b"})
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "a/%/b"}))
+
+	assert.Equal(t.T(), "%2E/b", o.Name)
+	contents, err := storageutil.ReadObject(t.ctx, t.wrapped, "./b")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco", string(contents))
+	_, err = storageutil.ReadObject(t.ctx, t.wrapped, "a//b")
+	assert.Error(t.T(), err)
+}
+
+func (t *EscapingBucketTest) TestCreateNameThatIsNotEscaped() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "%e", []byte("taco"))
+
+	assert.ErrorIs(t.T(), err, syscall.EINVAL)
+}
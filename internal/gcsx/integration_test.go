@@ -145,7 +145,7 @@ func (t *IntegrationTest) objectGeneration(name string) (gen int64) {
 }
 
 func (t *IntegrationTest) sync(src *gcs.Object) (o *gcs.Object, err error) {
-	o, err = t.syncer.SyncObject(t.ctx, src.Name, src, t.tf)
+	o, err = t.syncer.SyncObject(t.ctx, src.Name, src, nil, t.tf)
 	if err == nil && o != nil {
 		t.tf = nil
 	}
@@ -185,7 +185,7 @@ func (t *IntegrationTest) SyncEmptyLocalFile() {
 	AssertEq(nil, err)
 
 	// Sync should update the object in GCS.
-	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, tf)
+	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, nil, tf)
 
 	AssertEq(nil, err)
 	ExpectEq(t.objectGeneration("test"), newObj.Generation)
@@ -219,7 +219,7 @@ func (t *IntegrationTest) SyncNonEmptyLocalFile() {
 	t.clock.AdvanceTime(time.Second)
 
 	// Sync should update the object in GCS.
-	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, tf)
+	newObj, err := t.syncer.SyncObject(t.ctx, "test", nil, nil, tf)
 
 	AssertEq(nil, err)
 	ExpectEq(t.objectGeneration("test"), newObj.Generation)
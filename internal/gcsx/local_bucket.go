@@ -0,0 +1,729 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	storagev2 "cloud.google.com/go/storage"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"golang.org/x/net/context"
+)
+
+// NewLocalBucket creates a bucket, with the supplied name, that keeps its
+// objects in a local directory, created if need be, so that they outlive the
+// process. The directory is locked for as long as the process runs, and
+// creating a second bucket on it fails.
+//
+// It supports what the top layer of a union bucket needs: objects have a
+// single generation, and there are no folders or appendable objects.
+func NewLocalBucket(dir string, name string) (b gcs.Bucket, err error) {
+	lb := &localBucket{
+		name:       name,
+		objectsDir: filepath.Join(dir, "objects"),
+		tmpDir:     filepath.Join(dir, "tmp"),
+		objects:    make(map[string]*gcs.Object),
+	}
+
+	for _, d := range []string{lb.objectsDir, lb.tmpDir} {
+		if err = os.MkdirAll(d, 0700); err != nil {
+			return
+		}
+	}
+
+	// The lock is released when the process exits.
+	lock, err := os.OpenFile(filepath.Join(dir, "lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return
+	}
+	if err = syscall.Flock(int(lock.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		lock.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			err = fmt.Errorf("%s is already in use", dir)
+		}
+		return
+	}
+	lb.lock = lock
+
+	if err = lb.load(); err != nil {
+		lock.Close()
+		err = fmt.Errorf("loading objects from %s: %w", dir, err)
+		return
+	}
+
+	b = lb
+	return
+}
+
+// Each object is kept as a record, <key>.json, holding its gcs.Object, and a
+// data file, <key>.<generation>, where the key is the hex SHA-256 of its name.
+// A new generation is written to a data file of its own before its record
+// replaces the old one, so that readers of the old one are undisturbed.
+type localBucket struct {
+	name       string
+	objectsDir string
+	tmpDir     string
+	lock       *os.File
+
+	mu sync.Mutex
+
+	// GUARDED_BY(mu)
+	objects map[string]*gcs.Object
+
+	// The last generation handed out.
+	//
+	// GUARDED_BY(mu)
+	lastGeneration int64
+}
+
+var localCRC32CTable = crc32.MakeTable(crc32.Castagnoli)
+
+func localKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+func (b *localBucket) recordPath(name string) string {
+	return filepath.Join(b.objectsDir, localKey(name)+".json")
+}
+
+func (b *localBucket) dataPath(o *gcs.Object) string {
+	return filepath.Join(b.objectsDir, localKey(o.Name)+"."+strconv.FormatInt(o.Generation, 10))
+}
+
+// load reads the records in b.objectsDir, and removes the files that were
+// left behind by writes that didn't finish.
+func (b *localBucket) load() error {
+	entries, err := os.ReadDir(b.objectsDir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		contents, err := os.ReadFile(filepath.Join(b.objectsDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		o := new(gcs.Object)
+		if err = json.Unmarshal(contents, o); err != nil {
+			return fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		b.objects[o.Name] = o
+		b.lastGeneration = max(b.lastGeneration, o.Generation)
+	}
+
+	live := make(map[string]bool)
+	for _, o := range b.objects {
+		live[filepath.Base(b.dataPath(o))] = true
+	}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".json") && !live[e.Name()] {
+			os.Remove(filepath.Join(b.objectsDir, e.Name()))
+		}
+	}
+
+	if entries, err = os.ReadDir(b.tmpDir); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		os.Remove(filepath.Join(b.tmpDir, e.Name()))
+	}
+
+	return nil
+}
+
+// LOCKS_REQUIRED(b.mu)
+func (b *localBucket) nextGeneration() int64 {
+	// Generations are creation times in microseconds, as in GCS.
+	b.lastGeneration = max(b.lastGeneration+1, time.Now().UnixMicro())
+	return b.lastGeneration
+}
+
+// LOCKS_REQUIRED(b.mu)
+func (b *localBucket) writeRecord(o *gcs.Object) error {
+	contents, err := json.Marshal(o)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(b.tmpDir, "record")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(contents)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), b.recordPath(o.Name))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// LOCKS_REQUIRED(b.mu)
+func (b *localBucket) checkPreconditions(name string, generation *int64, metaGeneration *int64) error {
+	o := b.objects[name]
+	switch {
+	case generation != nil && *generation == 0 && o != nil:
+		return &gcs.PreconditionError{Err: fmt.Errorf("object %q exists", name)}
+	case generation != nil && *generation != 0 && (o == nil || o.Generation != *generation):
+		return &gcs.PreconditionError{Err: fmt.Errorf("object %q is not at generation %d", name, *generation)}
+	case metaGeneration != nil && (o == nil || o.MetaGeneration != *metaGeneration):
+		return &gcs.PreconditionError{Err: fmt.Errorf("object %q is not at meta-generation %d", name, *metaGeneration)}
+	}
+	return nil
+}
+
+// LOCKS_REQUIRED(b.mu)
+func (b *localBucket) find(name string, generation int64) (*gcs.Object, error) {
+	o := b.objects[name]
+	if o == nil || (generation != 0 && generation != o.Generation) {
+		return nil, &gcs.NotFoundError{Err: fmt.Errorf("object %q not found", name)}
+	}
+	return o, nil
+}
+
+func copyObject(o *gcs.Object) *gcs.Object {
+	c := *o
+	c.Metadata = maps.Clone(o.Metadata)
+	return &c
+}
+
+// localWriter writes the contents of a new object to a temporary file,
+// keeping their checksums.
+type localWriter struct {
+	b    *localBucket
+	req  *gcs.CreateObjectRequest
+	f    *os.File
+	size uint64
+	crc  uint32
+	md5  io.Writer
+	sum  func([]byte) []byte
+
+	// Set by Close.
+	o *gcs.Object
+}
+
+func (b *localBucket) newWriter(req *gcs.CreateObjectRequest) (*localWriter, error) {
+	f, err := os.CreateTemp(b.tmpDir, "data")
+	if err != nil {
+		return nil, err
+	}
+
+	h := md5.New()
+	return &localWriter{b: b, req: req, f: f, md5: h, sum: h.Sum}, nil
+}
+
+func (w *localWriter) Write(p []byte) (n int, err error) {
+	if n, err = w.f.Write(p); n > 0 {
+		w.size += uint64(n)
+		w.crc = crc32.Update(w.crc, localCRC32CTable, p[:n])
+		w.md5.Write(p[:n])
+	}
+	return
+}
+
+// Close creates the object, unless it was closed before.
+func (w *localWriter) Close() (err error) {
+	if w.o != nil {
+		return
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(w.f.Name())
+		}
+	}()
+
+	if err = w.f.Close(); err != nil {
+		return
+	}
+
+	var sum [md5.Size]byte
+	copy(sum[:], w.sum(nil))
+	if (w.req.CRC32C != nil && *w.req.CRC32C != w.crc) || (w.req.MD5 != nil && *w.req.MD5 != sum) {
+		return fmt.Errorf("checksum mismatch for object %q", w.req.Name)
+	}
+
+	o := &gcs.Object{
+		Name:               w.req.Name,
+		ContentType:        w.req.ContentType,
+		ContentLanguage:    w.req.ContentLanguage,
+		CacheControl:       w.req.CacheControl,
+		Size:               w.size,
+		ContentEncoding:    w.req.ContentEncoding,
+		MD5:                &sum,
+		CRC32C:             &w.crc,
+		Metadata:           maps.Clone(w.req.Metadata),
+		MetaGeneration:     1,
+		StorageClass:       w.req.StorageClass,
+		ComponentCount:     1,
+		ContentDisposition: w.req.ContentDisposition,
+		CustomTime:         w.req.CustomTime,
+		EventBasedHold:     w.req.EventBasedHold,
+	}
+
+	b := w.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err = b.checkPreconditions(w.req.Name, w.req.GenerationPrecondition, w.req.MetaGenerationPrecondition); err != nil {
+		return
+	}
+	o.Generation = b.nextGeneration()
+	o.Updated = time.Now()
+	o.Finalized = o.Updated
+
+	if err = os.Rename(w.f.Name(), b.dataPath(o)); err != nil {
+		return
+	}
+	if err = b.writeRecord(o); err != nil {
+		os.Remove(b.dataPath(o))
+		return
+	}
+	if old := b.objects[o.Name]; old != nil {
+		os.Remove(b.dataPath(old))
+	}
+	b.objects[o.Name] = o
+	w.o = copyObject(o)
+
+	return
+}
+
+func (w *localWriter) Flush() (int64, error) {
+	return 0, fmt.Errorf("flushing object %q to a local bucket: %w", w.req.Name, syscall.ENOTSUP)
+}
+
+func (w *localWriter) ObjectName() string {
+	return w.req.Name
+}
+
+func (w *localWriter) Attrs() *storagev2.ObjectAttrs {
+	return &storagev2.ObjectAttrs{Name: w.req.Name}
+}
+
+// localReader reads the data file of an object.
+type localReader struct {
+	io.Reader
+	io.Closer
+}
+
+func (r *localReader) ReadHandle() storagev2.ReadHandle {
+	return nil
+}
+
+// create creates an object out of the supplied request.
+func (b *localBucket) create(req *gcs.CreateObjectRequest) (o *gcs.Object, err error) {
+	w, err := b.newWriter(req)
+	if err != nil {
+		return
+	}
+
+	if _, err = io.Copy(w, req.Contents); err != nil {
+		w.f.Close()
+		os.Remove(w.f.Name())
+		return
+	}
+	if err = w.Close(); err != nil {
+		return
+	}
+
+	o = w.o
+	return
+}
+
+// open opens the data file of the named object, if it is at the supplied
+// generation or that is zero.
+func (b *localBucket) open(name string, generation int64) (*gcs.Object, *os.File, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, err := b.find(name, generation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(b.dataPath(o))
+	if err != nil {
+		return nil, nil, err
+	}
+	return copyObject(o), f, nil
+}
+
+func (b *localBucket) Name() string {
+	return b.name
+}
+
+func (b *localBucket) BucketType() gcs.BucketType {
+	return gcs.BucketType{}
+}
+
+func (b *localBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (rd gcs.StorageReader, err error) {
+	o, f, err := b.open(req.Name, req.Generation)
+	if err != nil {
+		return
+	}
+
+	start, limit := uint64(0), o.Size
+	if req.Range != nil {
+		start = min(req.Range.Start, o.Size)
+		limit = max(min(req.Range.Limit, o.Size), start)
+	}
+	if _, err = f.Seek(int64(start), io.SeekStart); err != nil {
+		f.Close()
+		return
+	}
+
+	rd = &localReader{
+		Reader: io.LimitReader(f, int64(limit-start)),
+		Closer: f,
+	}
+	return
+}
+
+// Multi-range downloads are for zonal buckets.
+func (b *localBucket) NewMultiRangeDownloader(
+	ctx context.Context, req *gcs.MultiRangeDownloaderRequest) (gcs.MultiRangeDownloader, error) {
+	return nil, fmt.Errorf("multi-range download of %q from a local bucket: %w", req.Name, syscall.ENOTSUP)
+}
+
+func (b *localBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	return b.create(req)
+}
+
+func (b *localBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	return b.newWriter(req)
+}
+
+func (b *localBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, fmt.Errorf("appending to %q in a local bucket: %w", req.Name, syscall.ENOTSUP)
+}
+
+func (b *localBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	lw, ok := w.(*localWriter)
+	if !ok {
+		return nil, fmt.Errorf("writer for %q wasn't created by a local bucket", w.ObjectName())
+	}
+	if err := lw.Close(); err != nil {
+		return nil, err
+	}
+	return storageutil.ConvertObjToMinObject(lw.o), nil
+}
+
+func (b *localBucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	_, err := w.Flush()
+	return nil, err
+}
+
+// createRequest returns a request to create an object with the supplied
+// name and the attributes of o.
+func createRequest(o *gcs.Object, name string) *gcs.CreateObjectRequest {
+	return &gcs.CreateObjectRequest{
+		Name:               name,
+		ContentType:        o.ContentType,
+		ContentLanguage:    o.ContentLanguage,
+		ContentEncoding:    o.ContentEncoding,
+		CacheControl:       o.CacheControl,
+		Metadata:           o.Metadata,
+		ContentDisposition: o.ContentDisposition,
+		CustomTime:         o.CustomTime,
+		EventBasedHold:     o.EventBasedHold,
+		StorageClass:       o.StorageClass,
+	}
+}
+
+func (b *localBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	if req.SrcBucket != "" && req.SrcBucket != b.name {
+		return nil, fmt.Errorf("copying from bucket %q to a local bucket: %w", req.SrcBucket, syscall.ENOTSUP)
+	}
+
+	src, f, err := b.open(req.SrcName, req.SrcGeneration)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if req.SrcMetaGenerationPrecondition != nil && *req.SrcMetaGenerationPrecondition != src.MetaGeneration {
+		return nil, &gcs.PreconditionError{Err: fmt.Errorf("object %q is not at meta-generation %d", req.SrcName, *req.SrcMetaGenerationPrecondition)}
+	}
+
+	cReq := createRequest(src, req.DstName)
+	cReq.Contents = f
+	cReq.GenerationPrecondition = req.DstGenerationPrecondition
+	return b.create(cReq)
+}
+
+func (b *localBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	var readers []io.Reader
+	for _, src := range req.Sources {
+		_, f, err := b.open(src.Name, src.Generation)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+
+	return b.create(&gcs.CreateObjectRequest{
+		Name:                       req.DstName,
+		ContentType:                req.ContentType,
+		ContentLanguage:            req.ContentLanguage,
+		ContentEncoding:            req.ContentEncoding,
+		CacheControl:               req.CacheControl,
+		Metadata:                   req.Metadata,
+		ContentDisposition:         req.ContentDisposition,
+		CustomTime:                 req.CustomTime,
+		EventBasedHold:             req.EventBasedHold,
+		StorageClass:               req.StorageClass,
+		Contents:                   io.MultiReader(readers...),
+		GenerationPrecondition:     req.DstGenerationPrecondition,
+		MetaGenerationPrecondition: req.DstMetaGenerationPrecondition,
+	})
+}
+
+func (b *localBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (m *gcs.MinObject, e *gcs.ExtendedObjectAttributes, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, err := b.find(req.Name, 0)
+	if err != nil {
+		return
+	}
+
+	o = copyObject(o)
+	m = storageutil.ConvertObjToMinObject(o)
+	if req.ReturnExtendedObjectAttributes {
+		e = storageutil.ConvertObjToExtendedObjectAttributes(o)
+	}
+	return
+}
+
+// ListObjects lists the objects as GCS would, continuation tokens being the
+// names that the next pages start at.
+func (b *localBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (l *gcs.Listing, err error) {
+	if req.Versions {
+		err = fmt.Errorf("listing generations in a local bucket: %w", syscall.ENOTSUP)
+		return
+	}
+
+	start := max(req.Prefix, req.StartOffset, req.ContinuationToken)
+	maxResults := req.MaxResults
+	if maxResults == 0 {
+		maxResults = 1000
+	}
+
+	b.mu.Lock()
+	var objects []*gcs.Object
+	for name, o := range b.objects {
+		if name >= start && strings.HasPrefix(name, req.Prefix) {
+			objects = append(objects, copyObject(o))
+		}
+	}
+	b.mu.Unlock()
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+
+	l = new(gcs.Listing)
+	var lastRun string
+	for _, o := range objects {
+		// Runs are only listed once, even across pages.
+		if lastRun != "" && strings.HasPrefix(o.Name, lastRun) && o.Name != lastRun {
+			continue
+		}
+		if len(l.MinObjects)+len(l.CollapsedRuns) >= maxResults {
+			l.ContinuationToken = o.Name
+			break
+		}
+
+		if i := strings.Index(o.Name[len(req.Prefix):], req.Delimiter); req.Delimiter != "" && i >= 0 {
+			run := o.Name[:len(req.Prefix)+i+len(req.Delimiter)]
+			if run != lastRun {
+				l.CollapsedRuns = append(l.CollapsedRuns, run)
+				lastRun = run
+			}
+			if o.Name != run || !req.IncludeTrailingDelimiter {
+				continue
+			}
+		}
+
+		l.MinObjects = append(l.MinObjects, storageutil.ConvertObjToMinObject(o))
+	}
+
+	return
+}
+
+func (b *localBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, err := b.find(req.Name, req.Generation)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.checkPreconditions(req.Name, nil, req.MetaGenerationPrecondition); err != nil {
+		return nil, err
+	}
+
+	o = copyObject(o)
+	for _, f := range []struct {
+		dst *string
+		src *string
+	}{
+		{&o.ContentType, req.ContentType},
+		{&o.ContentEncoding, req.ContentEncoding},
+		{&o.ContentLanguage, req.ContentLanguage},
+		{&o.CacheControl, req.CacheControl},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+	for k, v := range req.Metadata {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		if v == nil {
+			delete(o.Metadata, k)
+		} else {
+			o.Metadata[k] = *v
+		}
+	}
+	o.MetaGeneration++
+	o.Updated = time.Now()
+
+	if err = b.writeRecord(o); err != nil {
+		return nil, err
+	}
+	b.objects[o.Name] = o
+
+	return copyObject(o), nil
+}
+
+func (b *localBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	// There is no cache to delete from.
+	if req.OnlyDeleteFromCache {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o, err := b.find(req.Name, req.Generation)
+	if err != nil {
+		return err
+	}
+	if err = b.checkPreconditions(req.Name, nil, req.MetaGenerationPrecondition); err != nil {
+		return err
+	}
+
+	if err = os.Remove(b.recordPath(o.Name)); err != nil {
+		return err
+	}
+	os.Remove(b.dataPath(o))
+	delete(b.objects, o.Name)
+
+	return nil
+}
+
+func (b *localBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	src, err := b.find(req.SrcName, req.SrcGeneration)
+	if err != nil {
+		return nil, err
+	}
+	if err = b.checkPreconditions(req.SrcName, nil, req.SrcMetaGenerationPrecondition); err != nil {
+		return nil, err
+	}
+
+	dst := copyObject(src)
+	dst.Name = req.DstName
+	dst.Generation = b.nextGeneration()
+	dst.MetaGeneration = 1
+	if err = os.Rename(b.dataPath(src), b.dataPath(dst)); err != nil {
+		return nil, err
+	}
+	if err = b.writeRecord(dst); err != nil {
+		os.Rename(b.dataPath(dst), b.dataPath(src))
+		return nil, err
+	}
+	os.Remove(b.recordPath(src.Name))
+	delete(b.objects, src.Name)
+	if old := b.objects[dst.Name]; old != nil {
+		os.Remove(b.dataPath(old))
+	}
+	b.objects[dst.Name] = dst
+
+	return copyObject(dst), nil
+}
+
+// Local buckets have no folders.
+
+func (b *localBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return fmt.Errorf("folder %q in a local bucket: %w", folderName, syscall.ENOTSUP)
+}
+
+func (b *localBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	return nil, fmt.Errorf("folder %q in a local bucket: %w", req.Name, syscall.ENOTSUP)
+}
+
+func (b *localBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return nil, fmt.Errorf("folder %q in a local bucket: %w", folderName, syscall.ENOTSUP)
+}
+
+func (b *localBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return nil, fmt.Errorf("folder %q in a local bucket: %w", folderName, syscall.ENOTSUP)
+}
+
+func (b *localBucket) GCSName(object *gcs.MinObject) string {
+	return object.Name
+}
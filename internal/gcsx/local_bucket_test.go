@@ -0,0 +1,229 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type LocalBucketTest struct {
+	suite.Suite
+	ctx    context.Context
+	dir    string
+	bucket gcs.Bucket
+}
+
+func TestLocalBucket(t *testing.T) {
+	suite.Run(t, new(LocalBucketTest))
+}
+
+func (t *LocalBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	t.dir = t.T().TempDir()
+	t.bucket = t.open()
+}
+
+func (t *LocalBucketTest) TearDownTest() {
+	t.close()
+}
+
+func (t *LocalBucketTest) open() gcs.Bucket {
+	b, err := NewLocalBucket(t.dir, "bucket")
+	require.NoError(t.T(), err)
+	return b
+}
+
+// close releases the lock on t.dir, as exiting would.
+func (t *LocalBucketTest) close() {
+	t.bucket.(*localBucket).lock.Close()
+}
+
+func (t *LocalBucketTest) read(name string) string {
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, name)
+	require.NoError(t.T(), err)
+	return string(contents)
+}
+
+func (t *LocalBucketTest) assertNotFound(name string) {
+	_, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+	var notFound *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFound, name)
+}
+
+func (t *LocalBucketTest) TestCreateAndRead() {
+	o, err := storageutil.CreateObject(t.ctx, t.bucket, "dir/a", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), uint64(4), o.Size)
+	assert.NotZero(t.T(), o.Generation)
+	assert.False(t.T(), storageutil.ConvertObjToMinObject(o).IsUnfinalized())
+	assert.Equal(t.T(), "taco", t.read("dir/a"))
+	rd, err := t.bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{
+		Name:  "dir/a",
+		Range: &gcs.ByteRange{Start: 1, Limit: 3},
+	})
+	require.NoError(t.T(), err)
+	defer rd.Close()
+	contents, err := io.ReadAll(rd)
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "ac", string(contents))
+}
+
+func (t *LocalBucketTest) TestCreateObject_Preconditions() {
+	o, err := storageutil.CreateObject(t.ctx, t.bucket, "a", []byte("taco"))
+	require.NoError(t.T(), err)
+	var zero int64
+	stale := o.Generation - 1
+
+	for _, gen := range []*int64{&zero, &stale} {
+		_, err = t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+			Name:                   "a",
+			Contents:               strings.NewReader("burrito"),
+			GenerationPrecondition: gen,
+		})
+		var precondition *gcs.PreconditionError
+		assert.ErrorAs(t.T(), err, &precondition)
+	}
+
+	_, err = t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:                   "a",
+		Contents:               strings.NewReader("burrito"),
+		GenerationPrecondition: &o.Generation,
+	})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "burrito", t.read("a"))
+}
+
+func (t *LocalBucketTest) TestChunkWriter() {
+	w, err := t.bucket.CreateObjectChunkWriter(t.ctx, &gcs.CreateObjectRequest{Name: "a"}, 0, nil)
+	require.NoError(t.T(), err)
+	_, err = w.Write([]byte("taco"))
+	require.NoError(t.T(), err)
+	t.assertNotFound("a")
+
+	m, err := t.bucket.FinalizeUpload(t.ctx, w)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), uint64(4), m.Size)
+	assert.Equal(t.T(), "taco", t.read("a"))
+}
+
+func (t *LocalBucketTest) TestListObjects() {
+	err := storageutil.CreateObjects(t.ctx, t.bucket, map[string][]byte{
+		"a":       nil,
+		"dir/":    nil,
+		"dir/b":   nil,
+		"dir/c/d": nil,
+		"e":       nil,
+	})
+	require.NoError(t.T(), err)
+
+	objects, runs, err := storageutil.ListAll(t.ctx, t.bucket, &gcs.ListObjectsRequest{Delimiter: "/", MaxResults: 1})
+	require.NoError(t.T(), err)
+	var names []string
+	for _, m := range objects {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t.T(), []string{"a", "e"}, names)
+	assert.Equal(t.T(), []string{"dir/"}, runs)
+
+	objects, runs, err = storageutil.ListAll(t.ctx, t.bucket, &gcs.ListObjectsRequest{
+		Prefix:                   "dir/",
+		Delimiter:                "/",
+		IncludeTrailingDelimiter: true,
+	})
+	require.NoError(t.T(), err)
+	names = nil
+	for _, m := range objects {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t.T(), []string{"dir/", "dir/b"}, names)
+	assert.Equal(t.T(), []string{"dir/c/"}, runs)
+}
+
+func (t *LocalBucketTest) TestUpdateObject() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "a", []byte("taco"))
+	require.NoError(t.T(), err)
+	value := "value"
+
+	o, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:     "a",
+		Metadata: map[string]*string{"key": &value},
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), int64(2), o.MetaGeneration)
+	assert.Equal(t.T(), "value", o.Metadata["key"])
+}
+
+func (t *LocalBucketTest) TestDeleteObject() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "a", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "a"}))
+
+	t.assertNotFound("a")
+	err = t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "a"})
+	var notFound *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFound)
+}
+
+func (t *LocalBucketTest) TestMoveAndComposeObjects() {
+	err := storageutil.CreateObjects(t.ctx, t.bucket, map[string][]byte{"a": []byte("ta"), "b": []byte("co")})
+	require.NoError(t.T(), err)
+
+	_, err = t.bucket.ComposeObjects(t.ctx, &gcs.ComposeObjectsRequest{
+		DstName: "a",
+		Sources: []gcs.ComposeSource{{Name: "a"}, {Name: "b"}},
+	})
+	require.NoError(t.T(), err)
+	_, err = t.bucket.MoveObject(t.ctx, &gcs.MoveObjectRequest{SrcName: "a", DstName: "c"})
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), "taco", t.read("c"))
+	t.assertNotFound("a")
+}
+
+func (t *LocalBucketTest) TestPersistsAcrossOpens() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "a", []byte("taco"))
+	require.NoError(t.T(), err)
+	// Left behind by an interrupted write.
+	require.NoError(t.T(), os.WriteFile(filepath.Join(t.dir, "tmp", "data123"), nil, 0600))
+
+	t.close()
+	t.bucket = t.open()
+
+	assert.Equal(t.T(), "taco", t.read("a"))
+	entries, err := os.ReadDir(filepath.Join(t.dir, "tmp"))
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), entries)
+}
+
+func (t *LocalBucketTest) TestDirectoryInUse() {
+	_, err := NewLocalBucket(t.dir, "bucket")
+
+	assert.ErrorContains(t.T(), err, "already in use")
+}
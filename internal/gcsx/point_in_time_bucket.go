@@ -0,0 +1,313 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/net/context"
+)
+
+// NewPointInTimeBucket creates a read-only view on the wrapped bucket that
+// shows each object as it was at the supplied time: the generation that was
+// live then, if any. This relies on object versioning, which keeps overwritten
+// and deleted generations around; generations that have since been removed
+// for good are missing from the view.
+//
+// Hierarchical buckets, which don't support object versioning, are rejected.
+func NewPointInTimeBucket(
+	asOf time.Time,
+	wrapped gcs.Bucket) (b gcs.Bucket, err error) {
+	if wrapped.BucketType().Hierarchical {
+		err = errors.New("hierarchical buckets don't keep noncurrent generations")
+		return
+	}
+
+	b = &pointInTimeBucket{
+		asOf:    asOf,
+		wrapped: wrapped,
+	}
+
+	return
+}
+
+type pointInTimeBucket struct {
+	asOf    time.Time
+	wrapped gcs.Bucket
+}
+
+// existed reports whether the generation had been created by b.asOf.
+func (b *pointInTimeBucket) existed(m *gcs.MinObject) bool {
+	return m.Lifetime != nil && !m.Lifetime.Created.After(b.asOf)
+}
+
+// live reports whether the generation was the live one at b.asOf.
+func (b *pointInTimeBucket) live(m *gcs.MinObject) bool {
+	return b.existed(m) && (m.Lifetime.Noncurrent.IsZero() || m.Lifetime.Noncurrent.After(b.asOf))
+}
+
+func (b *pointInTimeBucket) readOnly(name string) error {
+	return fmt.Errorf("%q is mounted as of %v: %w", name, b.asOf, syscall.EROFS)
+}
+
+// find returns the generation of the named object that was live at b.asOf.
+func (b *pointInTimeBucket) find(ctx context.Context, name string) (*gcs.MinObject, error) {
+	req := &gcs.ListObjectsRequest{
+		Prefix:    name,
+		Delimiter: "/",
+		Versions:  true,
+	}
+
+	for {
+		listing, err := b.wrapped.ListObjects(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range listing.MinObjects {
+			if m.Name > name {
+				listing.ContinuationToken = ""
+				break
+			}
+			if m.Name == name && b.live(m) {
+				m.Lifetime = nil
+				return m, nil
+			}
+		}
+
+		if listing.ContinuationToken == "" {
+			return nil, &gcs.NotFoundError{
+				Err: fmt.Errorf("object %q not found as of %v", name, b.asOf),
+			}
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
+
+// anyLive reports whether any object under the supplied prefix was live at
+// b.asOf, so that the directory it stands for existed then.
+func (b *pointInTimeBucket) anyLive(ctx context.Context, prefix string) (bool, error) {
+	req := &gcs.ListObjectsRequest{
+		Prefix:   prefix,
+		Versions: true,
+	}
+
+	for {
+		listing, err := b.wrapped.ListObjects(ctx, req)
+		if err != nil {
+			return false, err
+		}
+
+		for _, m := range listing.MinObjects {
+			if b.live(m) {
+				return true, nil
+			}
+		}
+
+		if listing.ContinuationToken == "" {
+			return false, nil
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+}
+
+func (b *pointInTimeBucket) Name() string {
+	return b.wrapped.Name()
+}
+
+func (b *pointInTimeBucket) BucketType() gcs.BucketType {
+	return b.wrapped.BucketType()
+}
+
+func (b *pointInTimeBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (rd gcs.StorageReader, err error) {
+	// Pin the generation, unless the caller already did.
+	mReq := new(gcs.ReadObjectRequest)
+	*mReq = *req
+	if mReq.Generation == 0 {
+		var m *gcs.MinObject
+		if m, err = b.find(ctx, req.Name); err != nil {
+			return
+		}
+		mReq.Generation = m.Generation
+	}
+
+	rd, err = b.wrapped.NewReaderWithReadHandle(ctx, mReq)
+	return
+}
+
+func (b *pointInTimeBucket) NewMultiRangeDownloader(
+	ctx context.Context, req *gcs.MultiRangeDownloaderRequest) (mrd gcs.MultiRangeDownloader, err error) {
+	// Pin the generation, unless the caller already did.
+	mReq := new(gcs.MultiRangeDownloaderRequest)
+	*mReq = *req
+	if mReq.Generation == 0 {
+		var m *gcs.MinObject
+		if m, err = b.find(ctx, req.Name); err != nil {
+			return
+		}
+		mReq.Generation = m.Generation
+	}
+
+	mrd, err = b.wrapped.NewMultiRangeDownloader(ctx, mReq)
+	return
+}
+
+func (b *pointInTimeBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (m *gcs.MinObject, e *gcs.ExtendedObjectAttributes, err error) {
+	if m, err = b.find(ctx, req.Name); err != nil {
+		return
+	}
+
+	if req.ReturnExtendedObjectAttributes {
+		// Only the live generation can be stat'ed, so the older ones go without.
+		e = &gcs.ExtendedObjectAttributes{}
+		live, liveExtended, statErr := b.wrapped.StatObject(ctx, req)
+		if statErr == nil && live.Generation == m.Generation {
+			e = liveExtended
+		}
+	}
+
+	return
+}
+
+// ListObjects lists the generations that were live at b.asOf, leaving out
+// collapsed runs under which nothing was live then. If req.Versions is set,
+// every generation created by then is listed instead.
+func (b *pointInTimeBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (l *gcs.Listing, err error) {
+	mReq := new(gcs.ListObjectsRequest)
+	*mReq = *req
+	mReq.Versions = true
+
+	if l, err = b.wrapped.ListObjects(ctx, mReq); err != nil {
+		return
+	}
+
+	minObjects := l.MinObjects[:0]
+	for _, m := range l.MinObjects {
+		switch {
+		case req.Versions && b.existed(m):
+		case !req.Versions && b.live(m):
+			m.Lifetime = nil
+		default:
+			continue
+		}
+		minObjects = append(minObjects, m)
+	}
+	l.MinObjects = minObjects
+
+	if req.Versions {
+		return
+	}
+
+	collapsedRuns := l.CollapsedRuns[:0]
+	for _, run := range l.CollapsedRuns {
+		var ok bool
+		if ok, err = b.anyLive(ctx, run); err != nil {
+			return nil, err
+		}
+		if ok {
+			collapsedRuns = append(collapsedRuns, run)
+		}
+	}
+	l.CollapsedRuns = collapsedRuns
+
+	return
+}
+
+func (b *pointInTimeBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	return b.wrapped.GetFolder(ctx, req)
+}
+
+func (b *pointInTimeBucket) GCSName(object *gcs.MinObject) string {
+	return b.wrapped.GCSName(object)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Mutations
+////////////////////////////////////////////////////////////////////////
+
+// The past can't be changed. Mutations fail with an error wrapping EROFS.
+
+func (b *pointInTimeBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (*gcs.Object, error) {
+	return nil, b.readOnly(req.Name)
+}
+
+func (b *pointInTimeBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (gcs.Writer, error) {
+	return nil, b.readOnly(req.Name)
+}
+
+func (b *pointInTimeBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, b.readOnly(req.Name)
+}
+
+// No writer can have been created, so there is nothing to finish.
+func (b *pointInTimeBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	return nil, b.readOnly("")
+}
+
+func (b *pointInTimeBucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	return nil, b.readOnly("")
+}
+
+func (b *pointInTimeBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (*gcs.Object, error) {
+	return nil, b.readOnly(req.DstName)
+}
+
+func (b *pointInTimeBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (*gcs.Object, error) {
+	return nil, b.readOnly(req.DstName)
+}
+
+func (b *pointInTimeBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (*gcs.Object, error) {
+	return nil, b.readOnly(req.Name)
+}
+
+func (b *pointInTimeBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	return b.readOnly(req.Name)
+}
+
+func (b *pointInTimeBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*gcs.Object, error) {
+	return nil, b.readOnly(req.SrcName)
+}
+
+func (b *pointInTimeBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return b.readOnly(folderName)
+}
+
+func (b *pointInTimeBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return nil, b.readOnly(folderName)
+}
+
+func (b *pointInTimeBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return nil, b.readOnly(folderName)
+}
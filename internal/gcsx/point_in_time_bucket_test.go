@@ -0,0 +1,148 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type PointInTimeBucketTest struct {
+	suite.Suite
+	ctx     context.Context
+	wrapped gcs.Bucket
+	bucket  gcs.Bucket
+
+	// The generation of "foo" that was live at the chosen time.
+	fooGeneration int64
+}
+
+func TestPointInTimeBucket(t *testing.T) {
+	suite.Run(t, new(PointInTimeBucketTest))
+}
+
+func (t *PointInTimeBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	var clock timeutil.SimulatedClock
+	clock.SetTime(time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC))
+	t.wrapped = fake.NewFakeBucket(&clock, "some_bucket", gcs.BucketType{})
+	fake.EnableVersioning(t.wrapped)
+
+	// The past.
+	o, err := storageutil.CreateObject(t.ctx, t.wrapped, "foo", []byte("taco"))
+	require.NoError(t.T(), err)
+	t.fooGeneration = o.Generation
+	for _, name := range []string{"deleted", "dir/bar", "kept/baz"} {
+		_, err = storageutil.CreateObject(t.ctx, t.wrapped, name, []byte(name))
+		require.NoError(t.T(), err)
+	}
+	clock.AdvanceTime(time.Hour)
+	asOf := clock.Now()
+	clock.AdvanceTime(time.Hour)
+
+	// The future, as seen from asOf.
+	_, err = storageutil.CreateObject(t.ctx, t.wrapped, "foo", []byte("burrito"))
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(t.ctx, t.wrapped, "later", []byte("enchilada"))
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(t.ctx, t.wrapped, "new/qux", []byte("qux"))
+	require.NoError(t.T(), err)
+	for _, name := range []string{"deleted", "dir/bar"} {
+		require.NoError(t.T(), t.wrapped.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: name}))
+	}
+
+	t.bucket, err = gcsx.NewPointInTimeBucket(asOf, t.wrapped)
+	require.NoError(t.T(), err)
+}
+
+func (t *PointInTimeBucketTest) TestHierarchicalBucket() {
+	hns := fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{Hierarchical: true})
+
+	_, err := gcsx.NewPointInTimeBucket(time.Now(), hns)
+
+	assert.Error(t.T(), err)
+}
+
+func (t *PointInTimeBucketTest) TestStatObject() {
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "foo"})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), t.fooGeneration, m.Generation)
+	assert.Equal(t.T(), uint64(len("taco")), m.Size)
+
+	for _, name := range []string{"later", "new/qux"} {
+		_, _, err = t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+		var notFound *gcs.NotFoundError
+		assert.ErrorAs(t.T(), err, &notFound, name)
+	}
+	m, _, err = t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "dir/bar"})
+	require.NoError(t.T(), err)
+	assert.Nil(t.T(), m.Lifetime)
+}
+
+func (t *PointInTimeBucketTest) TestNewReader() {
+	rd, err := t.bucket.NewReaderWithReadHandle(t.ctx, &gcs.ReadObjectRequest{Name: "foo"})
+	require.NoError(t.T(), err)
+	defer rd.Close()
+
+	contents, err := io.ReadAll(rd)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco", string(contents))
+}
+
+func (t *PointInTimeBucketTest) TestListObjects() {
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{Delimiter: "/"})
+	require.NoError(t.T(), err)
+
+	var names []string
+	for _, m := range listing.MinObjects {
+		assert.Nil(t.T(), m.Lifetime)
+		names = append(names, m.Name)
+	}
+	assert.Equal(t.T(), []string{"deleted", "foo"}, names)
+	// dir/ was only emptied after the fact, while new/ is too new.
+	assert.Equal(t.T(), []string{"dir/", "kept/"}, listing.CollapsedRuns)
+}
+
+func (t *PointInTimeBucketTest) TestListObjects_Versions() {
+	listing, err := t.bucket.ListObjects(t.ctx, &gcs.ListObjectsRequest{Prefix: "foo", Versions: true})
+	require.NoError(t.T(), err)
+
+	require.Len(t.T(), listing.MinObjects, 1)
+	assert.Equal(t.T(), t.fooGeneration, listing.MinObjects[0].Generation)
+}
+
+func (t *PointInTimeBucketTest) TestMutations() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "foo", []byte("burrito"))
+	assert.ErrorIs(t.T(), err, syscall.EROFS)
+
+	err = t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "foo"})
+	assert.ErrorIs(t.T(), err, syscall.EROFS)
+
+	_, err = t.bucket.MoveObject(t.ctx, &gcs.MoveObjectRequest{SrcName: "foo", DstName: "bar"})
+	assert.ErrorIs(t.T(), err, syscall.EROFS)
+}
@@ -31,11 +31,14 @@ type Syncer interface {
 	// *   If the temp file has not been modified, return a nil new object.
 	//
 	// *   Otherwise, write out a new generation in the bucket (failing with
-	//     *gcs.PreconditionError if the source generation is no longer current).
+	//     *gcs.PreconditionError if the source generation is no longer current),
+	//     with the custom metadata of the source object changed by metadata, in
+	//     the format of gcs.UpdateObjectRequest.Metadata.
 	SyncObject(
 		ctx context.Context,
 		fileName string,
 		srcObject *gcs.Object,
+		metadata map[string]*string,
 		content TempFile) (o *gcs.Object, err error)
 }
 
@@ -87,10 +90,12 @@ func (oc *fullObjectCreator) Create(
 	objectName string,
 	srcObject *gcs.Object,
 	mtime *time.Time,
+	metadata map[string]*string,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	r io.Reader) (o *gcs.Object, err error) {
 	req := gcs.NewCreateObjectRequest(srcObject, objectName, mtime, chunkRetryDeadlineSecs, chunkTransferTimeoutSecs)
+	applyMetadataUpdates(req.Metadata, metadata)
 	req.Contents = r
 	o, err = oc.bucket.CreateObject(ctx, req)
 	if err != nil {
@@ -101,6 +106,18 @@ func (oc *fullObjectCreator) Create(
 	return
 }
 
+// applyMetadataUpdates applies updates, in the format of
+// gcs.UpdateObjectRequest.Metadata, to the custom metadata m.
+func applyMetadataUpdates(m map[string]string, updates map[string]*string) {
+	for k, v := range updates {
+		if v == nil {
+			delete(m, k)
+			continue
+		}
+		m[k] = *v
+	}
+}
+
 ////////////////////////////////////////////////////////////////////////
 // syncer
 ////////////////////////////////////////////////////////////////////////
@@ -112,6 +129,7 @@ type objectCreator interface {
 		objectName string,
 		srcObject *gcs.Object,
 		mtime *time.Time,
+		metadata map[string]*string,
 		chunkRetryDeadlineSecs int64,
 		chunkTransferTimeoutSecs int64,
 		r io.Reader) (o *gcs.Object, err error)
@@ -159,6 +177,7 @@ func (os *syncer) SyncObject(
 	ctx context.Context,
 	objectName string,
 	srcObject *gcs.Object,
+	metadata map[string]*string,
 	content TempFile) (o *gcs.Object, err error) {
 	// Stat the content.
 	sr, err := content.Stat()
@@ -177,7 +196,7 @@ func (os *syncer) SyncObject(
 			err = fmt.Errorf("error in seeking: %w", err)
 			return
 		}
-		return os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+		return os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
 	}
 
 	// Make sure the dirty threshold makes sense.
@@ -217,7 +236,7 @@ func (os *syncer) SyncObject(
 			return
 		}
 
-		o, err = os.composeCreator.Create(ctx, objectName, srcObject, sr.Mtime, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+		o, err = os.composeCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
 	} else {
 		_, err = content.Seek(0, 0)
 		if err != nil {
@@ -225,7 +244,7 @@ func (os *syncer) SyncObject(
 			return
 		}
 
-		o, err = os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
+		o, err = os.fullCreator.Create(ctx, objectName, srcObject, sr.Mtime, metadata, os.chunkRetryDeadlineSecs, os.chunkTransferTimeoutSecs, content)
 	}
 
 	// Deal with errors.
@@ -45,6 +45,7 @@ type FullObjectCreatorTest struct {
 	srcObject   gcs.Object
 	srcContents string
 	mtime       time.Time
+	metadata    map[string]*string
 }
 
 func init() { RegisterTestSuite(&FullObjectCreatorTest{}) }
@@ -67,6 +68,7 @@ func (t *FullObjectCreatorTest) call() (o *gcs.Object, err error) {
 		t.srcObject.Name,
 		&t.srcObject,
 		&t.mtime,
+		t.metadata,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -164,6 +166,35 @@ func (t *FullObjectCreatorTest) CallsCreateObjectsWithObjectProperties() {
 	ExpectEq("test_value", req.Metadata["test_key"])
 }
 
+func (t *FullObjectCreatorTest) CallsCreateObjectWithMetadataUpdates() {
+	t.srcObject.Name = "foo"
+	t.srcObject.Metadata = map[string]string{
+		"kept":    "a",
+		"changed": "b",
+		"removed": "c",
+	}
+	changed := "d"
+	t.metadata = map[string]*string{
+		"changed": &changed,
+		"removed": nil,
+	}
+
+	var req *gcs.CreateObjectRequest
+	ExpectCall(t.bucket, "CreateObject")(Any(), Any()).
+		WillOnce(DoAll(SaveArg(1, &req), Return(nil, errors.New(""))))
+
+	// Call
+	t.call()
+
+	AssertNe(nil, req)
+	ExpectEq(3, len(req.Metadata))
+	ExpectEq("a", req.Metadata["kept"])
+	ExpectEq("d", req.Metadata["changed"])
+	ExpectEq(t.mtime.Format(time.RFC3339Nano), req.Metadata["gcsfuse_mtime"])
+	// The source object is left alone.
+	ExpectEq("b", t.srcObject.Metadata["changed"])
+}
+
 func (t *FullObjectCreatorTest) CallsCreateObjectWhenSrcObjectIsNil() {
 	t.srcContents = "taco"
 	// CreateObject
@@ -177,6 +208,7 @@ func (t *FullObjectCreatorTest) CallsCreateObjectWhenSrcObjectIsNil() {
 		t.srcObject.Name,
 		nil,
 		&t.mtime,
+		nil,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -198,6 +230,7 @@ func (t *FullObjectCreatorTest) CallsCreateObjectWhenSrcObjectAndMtimeAreNil() {
 		t.srcObject.Name,
 		nil,
 		nil,
+		nil,
 		chunkRetryDeadlineSecs,
 		chunkTransferTimeoutSecs,
 		strings.NewReader(t.srcContents))
@@ -237,6 +270,7 @@ type fakeObjectCreator struct {
 	// Supplied arguments
 	srcObject *gcs.Object
 	mtime     time.Time
+	metadata  map[string]*string
 	contents  []byte
 
 	// Canned results
@@ -249,6 +283,7 @@ func (oc *fakeObjectCreator) Create(
 	fileName string,
 	srcObject *gcs.Object,
 	mtime *time.Time,
+	metadata map[string]*string,
 	chunkRetryDeadlineSecs int64,
 	chunkTransferTimeoutSecs int64,
 	r io.Reader) (o *gcs.Object, err error) {
@@ -258,6 +293,7 @@ func (oc *fakeObjectCreator) Create(
 
 	// Record args.
 	oc.srcObject = srcObject
+	oc.metadata = metadata
 	if mtime != nil {
 		oc.mtime = *mtime
 	}
@@ -289,6 +325,7 @@ type SyncerTest struct {
 	clock  timeutil.SimulatedClock
 
 	srcObject *gcs.Object
+	metadata  map[string]*string
 	content   TempFile
 }
 
@@ -335,7 +372,7 @@ func (t *SyncerTest) SetUp(ti *TestInfo) {
 }
 
 func (t *SyncerTest) call() (o *gcs.Object, err error) {
-	o, err = t.syncer.SyncObject(t.ctx, t.srcObject.Name, t.srcObject, t.content)
+	o, err = t.syncer.SyncObject(t.ctx, t.srcObject.Name, t.srcObject, t.metadata, t.content)
 	return
 }
 
@@ -354,7 +391,7 @@ func (rc dummyReadCloser) Close() error {
 func (t *SyncerTest) SyncObjectShouldInvokeFullObjectCreatorWhenSrcObjectIsNil() {
 	// It doesn't make sense to validate returned object or error since fake
 	// is not handling them.
-	_, _ = t.syncer.SyncObject(t.ctx, t.srcObject.Name, nil, t.content)
+	_, _ = t.syncer.SyncObject(t.ctx, t.srcObject.Name, nil, nil, t.content)
 
 	ExpectTrue(t.fullCreator.called)
 	ExpectFalse(t.appendCreator.called)
@@ -371,6 +408,19 @@ func (t *SyncerTest) NotDirty() {
 	ExpectFalse(t.appendCreator.called)
 }
 
+func (t *SyncerTest) PassesMetadataUpdatesToCreator() {
+	label := "taco"
+	t.metadata = map[string]*string{"label": &label}
+	err := t.content.Truncate(int64(len(srcObjectContents) - 1))
+	AssertEq(nil, err)
+
+	// Call
+	t.call()
+
+	AssertTrue(t.fullCreator.called)
+	ExpectEq(t.metadata, t.fullCreator.metadata)
+}
+
 func (t *SyncerTest) SmallerThanSource() {
 	// Truncate downward.
 	err := t.content.Truncate(int64(len(srcObjectContents) - 1))
@@ -15,6 +15,7 @@
 package gcsx
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/jacobsa/fuse/fsutil"
 	"github.com/jacobsa/timeutil"
+	"golang.org/x/sys/unix"
 )
 
 // TempFile is a temporary file that keeps track of the lowest offset at which
@@ -39,6 +41,11 @@ type TempFile interface {
 	io.WriterAt
 	Truncate(n int64) (err error)
 
+	// Allocate space for the byte range [offset, offset+length), extending the
+	// file if it ends beyond the current size unless keepSize is set. Semantics
+	// match fallocate(2) with a mode of zero or FALLOC_FL_KEEP_SIZE.
+	Allocate(offset int64, length int64, keepSize bool) (err error)
+
 	// Retrieve the file name
 	Name() string
 
@@ -305,6 +312,49 @@ func (tf *tempFile) Truncate(n int64) error {
 	return tf.f.Truncate(n)
 }
 
+func (tf *tempFile) Allocate(offset int64, length int64, keepSize bool) error {
+	err := tf.ensureComplete()
+	if err != nil {
+		return fmt.Errorf("cannot Allocate incomplete file: %w", err)
+	}
+
+	size, err := tf.f.Seek(0, 2)
+	if err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+	extends := !keepSize && offset+length > size
+
+	var mode uint32
+	if keepSize {
+		mode = unix.FALLOC_FL_KEEP_SIZE
+	}
+	err = unix.Fallocate(int(tf.f.Fd()), mode, offset, length)
+
+	// Not every file system backing the temp directory can reserve space, in
+	// which case all that matters is that the size is right.
+	if errors.Is(err, unix.EOPNOTSUPP) {
+		err = nil
+		if extends {
+			err = tf.f.Truncate(offset + length)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("fallocate: %w", err)
+	}
+
+	// Reserving space leaves the contents unmodified, but extending the file
+	// dirties it like a truncate would.
+	if extends {
+		tf.dirtyThreshold = minInt64(tf.dirtyThreshold, size)
+		tf.state = fileDirty
+
+		newMtime := tf.clock.Now()
+		tf.mtime = &newMtime
+	}
+
+	return nil
+}
+
 func (tf *tempFile) SetMtime(mtime time.Time) {
 	tf.mtime = &mtime
 }
@@ -104,6 +104,12 @@ func (tf *checkingTempFile) Truncate(n int64) error {
 	return tf.wrapped.Truncate(n)
 }
 
+func (tf *checkingTempFile) Allocate(offset int64, length int64, keepSize bool) error {
+	tf.wrapped.CheckInvariants()
+	defer tf.wrapped.CheckInvariants()
+	return tf.wrapped.Allocate(offset, length, keepSize)
+}
+
 func (tf *checkingTempFile) SetMtime(mtime time.Time) {
 	tf.wrapped.CheckInvariants()
 	defer tf.wrapped.CheckInvariants()
@@ -236,6 +242,41 @@ func (t *TempFileTest) Truncate() {
 	ExpectEq(expected, string(actual))
 }
 
+func (t *TempFileTest) Allocate_Extends() {
+	// Call
+	err := t.tf.Allocate(int64(initialContentSize), 4, false)
+	ExpectEq(nil, err)
+
+	// Check Stat.
+	sr, err := t.tf.Stat()
+
+	AssertEq(nil, err)
+	ExpectEq(initialContentSize+4, sr.Size)
+	ExpectEq(initialContentSize, sr.DirtyThreshold)
+	ExpectThat(sr.Mtime, Pointee(timeutil.TimeEq(t.clock.Now())))
+
+	// Read back.
+	expected := initialContent + "\x00\x00\x00\x00"
+
+	actual, err := readAll(&t.tf)
+	AssertEq(nil, err)
+	ExpectEq(expected, string(actual))
+}
+
+func (t *TempFileTest) Allocate_KeepSize() {
+	// Call
+	err := t.tf.Allocate(0, 1<<20, true)
+	ExpectEq(nil, err)
+
+	// The contents should be untouched.
+	sr, err := t.tf.Stat()
+
+	AssertEq(nil, err)
+	ExpectEq(initialContentSize, sr.Size)
+	ExpectEq(initialContentSize, sr.DirtyThreshold)
+	ExpectEq(nil, sr.Mtime)
+}
+
 func (t *TempFileTest) SetMtime() {
 	mtime := time.Date(2015, 4, 5, 2, 15, 0, 0, time.Local)
 	AssertThat(mtime, Not(timeutil.TimeEq(t.clock.Now())))
@@ -0,0 +1,917 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"golang.org/x/net/context"
+)
+
+// WhiteoutPrefix begins the base name of the empty objects with which a union
+// bucket hides names in its lower layers.
+const WhiteoutPrefix = ".wh."
+
+// NewUnionBucket creates a view that overlays the supplied layers, given in
+// decreasing order of priority, into one namespace. A name resolves to the
+// object in the highest-priority layer that has it, and listings merge all
+// layers.
+//
+// Everything is written to the first layer, the top one. Objects in lower
+// layers are copied up to it before they are changed, and deleting a name
+// that a lower layer has leaves a whiteout in it: for dir/name, the empty
+// object dir/.wh.name, which hides dir/name and everything under dir/name/ in
+// the layers below the one holding it. Names beginning with .wh. are
+// therefore reserved.
+//
+// Whiteouts, and whether anything is visible under a directory, are cached
+// for the supplied TTL, which may be zero.
+//
+// Hierarchical and zonal buckets can't be layers.
+func NewUnionBucket(layers []gcs.Bucket, ttl time.Duration, clock timeutil.Clock) (b gcs.Bucket, err error) {
+	if len(layers) == 0 {
+		err = errors.New("a union needs at least one layer")
+		return
+	}
+
+	for _, l := range layers {
+		if t := l.BucketType(); t.Hierarchical || t.Zonal {
+			err = fmt.Errorf("bucket %q: hierarchical and zonal buckets can't be union layers", l.Name())
+			return
+		}
+	}
+
+	b = &unionBucket{
+		layers: layers,
+		cache:  newUnionCache(len(layers), ttl, clock),
+	}
+
+	return
+}
+
+type unionBucket struct {
+	// In decreasing order of priority.
+	layers []gcs.Bucket
+
+	cache *unionCache
+}
+
+func (b *unionBucket) top() gcs.Bucket {
+	return b.layers[0]
+}
+
+func isNotFound(err error) bool {
+	var notFoundErr *gcs.NotFoundError
+	return errors.As(err, &notFoundErr)
+}
+
+// isWhiteout reports whether the named object is a whiteout.
+func isWhiteout(name string) bool {
+	return !strings.HasSuffix(name, "/") && strings.HasPrefix(path.Base(name), WhiteoutPrefix)
+}
+
+// checkNotReserved returns an error wrapping EINVAL if objects can't be
+// created with the supplied name.
+func checkNotReserved(name string) error {
+	if strings.HasPrefix(path.Base(name), WhiteoutPrefix) {
+		return fmt.Errorf("%q: names beginning with %q are reserved for whiteouts: %w", name, WhiteoutPrefix, syscall.EINVAL)
+	}
+	return nil
+}
+
+// whiteoutName returns the name of the whiteout that hides the named object,
+// along with the directory it stands for.
+func whiteoutName(name string) string {
+	dir, base := path.Split(strings.TrimSuffix(name, "/"))
+	return dir + WhiteoutPrefix + base
+}
+
+// whiteoutNames returns the names of every whiteout that hides the named
+// object: its own, and those of the directories it is in.
+func whiteoutNames(name string) (names []string) {
+	name = strings.TrimSuffix(name, "/")
+	for i := 1; i < len(name); i++ {
+		if name[i] == '/' && name[i-1] != '/' {
+			names = append(names, whiteoutName(name[:i]))
+		}
+	}
+	if name != "" {
+		names = append(names, whiteoutName(name))
+	}
+	return
+}
+
+// dirWhiteouts returns the names of the whiteouts in the directory dir of the
+// layer at index i.
+func (b *unionBucket) dirWhiteouts(ctx context.Context, i int, dir string) (map[string]bool, error) {
+	return b.cache.dirWhiteouts(i, dir, func() (map[string]bool, error) {
+		whiteouts := make(map[string]bool)
+		req := &gcs.ListObjectsRequest{Prefix: dir + WhiteoutPrefix, Delimiter: "/"}
+		for {
+			l, err := b.layers[i].ListObjects(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, m := range l.MinObjects {
+				if isWhiteout(m.Name) {
+					whiteouts[m.Name] = true
+				}
+			}
+
+			if l.ContinuationToken == "" {
+				return whiteouts, nil
+			}
+			req.ContinuationToken = l.ContinuationToken
+		}
+	})
+}
+
+// whitedOut reports whether the layer at index i holds a whiteout that hides
+// the named object.
+func (b *unionBucket) whitedOut(ctx context.Context, i int, name string) (bool, error) {
+	for _, w := range whiteoutNames(name) {
+		whiteouts, err := b.dirWhiteouts(ctx, i, w[:strings.LastIndex(w, "/")+1])
+		if err != nil {
+			return false, err
+		}
+		if whiteouts[w] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// resolve calls try with the index of each layer in turn, from the top, until
+// it returns anything but a not found error or a whiteout hides the named
+// object from the layers below, and returns the index it stopped at.
+func (b *unionBucket) resolve(ctx context.Context, name string, try func(i int) error) (i int, err error) {
+	if !isWhiteout(name) {
+		for i = range b.layers {
+			if err = try(i); !isNotFound(err) {
+				return
+			}
+
+			if i == len(b.layers)-1 {
+				break
+			}
+			var hidden bool
+			if hidden, err = b.whitedOut(ctx, i, name); err != nil {
+				return
+			}
+			if hidden {
+				break
+			}
+		}
+	}
+
+	err = &gcs.NotFoundError{
+		Err: fmt.Errorf("object %q not found in any layer", name),
+	}
+	return
+}
+
+// find returns the object that the supplied name resolves to, along with the
+// index of the layer holding it.
+func (b *unionBucket) find(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (i int, m *gcs.MinObject, e *gcs.ExtendedObjectAttributes, err error) {
+	i, err = b.resolve(ctx, req.Name, func(i int) (err error) {
+		m, e, err = b.layers[i].StatObject(ctx, req)
+		return
+	})
+	return
+}
+
+// copyUp copies the supplied object in a lower layer to the top layer, under
+// the supplied name.
+func (b *unionBucket) copyUp(
+	ctx context.Context,
+	layer gcs.Bucket,
+	m *gcs.MinObject,
+	e *gcs.ExtendedObjectAttributes,
+	dstName string,
+	dstGenerationPrecondition *int64) (o *gcs.Object, err error) {
+	rd, err := layer.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+		Name:       m.Name,
+		Generation: m.Generation,
+		// Keep the contents as they are stored, along with their encoding.
+		ReadCompressed: true,
+	})
+	if err != nil {
+		err = fmt.Errorf("copying %q up from bucket %q: %w", m.Name, layer.Name(), err)
+		return
+	}
+	defer rd.Close()
+
+	req := &gcs.CreateObjectRequest{
+		Name:                   dstName,
+		ContentEncoding:        m.ContentEncoding,
+		Metadata:               m.Metadata,
+		Contents:               rd,
+		GenerationPrecondition: dstGenerationPrecondition,
+	}
+	if e != nil {
+		req.ContentType = e.ContentType
+		req.ContentLanguage = e.ContentLanguage
+		req.CacheControl = e.CacheControl
+		req.ContentDisposition = e.ContentDisposition
+		req.CustomTime = e.CustomTime
+		req.EventBasedHold = e.EventBasedHold
+		req.StorageClass = e.StorageClass
+	}
+
+	if o, err = b.top().CreateObject(ctx, req); err != nil {
+		err = fmt.Errorf("copying %q up from bucket %q: %w", m.Name, layer.Name(), err)
+	}
+	return
+}
+
+// ensureTop copies the named object up to the top layer unless it is already
+// there, in which case copied is false. A nonzero generation must be that of
+// the object found.
+func (b *unionBucket) ensureTop(
+	ctx context.Context,
+	name string,
+	generation int64) (m *gcs.MinObject, copied bool, err error) {
+	i, m, e, err := b.find(ctx, &gcs.StatObjectRequest{
+		Name:                           name,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+	if err != nil || i == 0 {
+		return
+	}
+
+	if generation != 0 && generation != m.Generation {
+		err = &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q is at generation %d, not %d", name, m.Generation, generation),
+		}
+		return
+	}
+
+	// The top layer doesn't have the object, or it would have been found there.
+	var o *gcs.Object
+	var notExists int64
+	if o, err = b.copyUp(ctx, b.layers[i], m, e, name, &notExists); err != nil {
+		return
+	}
+
+	m, copied = storageutil.ConvertObjToMinObject(o), true
+	return
+}
+
+// topPreconditions returns the preconditions with which to write the named
+// object to the top layer. Preconditions on the generation of an object that
+// only a lower layer has become one that the top layer doesn't have it.
+func (b *unionBucket) topPreconditions(
+	ctx context.Context,
+	name string,
+	generation *int64,
+	metaGeneration *int64) (*int64, *int64, error) {
+	if generation == nil || *generation == 0 {
+		return generation, metaGeneration, nil
+	}
+
+	_, _, err := b.top().StatObject(ctx, &gcs.StatObjectRequest{Name: name})
+	if err == nil {
+		return generation, metaGeneration, nil
+	}
+	if !isNotFound(err) {
+		return nil, nil, err
+	}
+
+	var notExists int64
+	return &notExists, nil, nil
+}
+
+// hide writes a whiteout for the named object to the top layer if it is
+// visible in a lower one, and reports whether it did.
+func (b *unionBucket) hide(ctx context.Context, name string) (bool, error) {
+	i, _, _, err := b.find(ctx, &gcs.StatObjectRequest{Name: name})
+	if isNotFound(err) || (err == nil && i == 0) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	w := whiteoutName(name)
+	_, err = b.top().CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     w,
+		Contents: strings.NewReader(""),
+	})
+	if err != nil {
+		return false, fmt.Errorf("hiding %q: %w", name, err)
+	}
+	b.cache.addWhiteout(w)
+	b.cache.changed(name)
+	return true, nil
+}
+
+// anyVisible reports whether any object under the supplied prefix is visible,
+// so that the directory it stands for is.
+func (b *unionBucket) anyVisible(ctx context.Context, prefix string) (bool, error) {
+	return b.cache.anyVisible(prefix, func() (bool, error) {
+		l, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{
+			Prefix:     prefix,
+			MaxResults: 1,
+		})
+		if err != nil {
+			return false, err
+		}
+		return len(l.MinObjects) > 0, nil
+	})
+}
+
+func (b *unionBucket) Name() string {
+	return b.top().Name()
+}
+
+func (b *unionBucket) BucketType() gcs.BucketType {
+	return b.top().BucketType()
+}
+
+// NewReaderWithReadHandle reads from each layer in turn rather than finding
+// the object first, which takes as many calls and then one more.
+func (b *unionBucket) NewReaderWithReadHandle(
+	ctx context.Context,
+	req *gcs.ReadObjectRequest) (rd gcs.StorageReader, err error) {
+	_, err = b.resolve(ctx, req.Name, func(i int) (err error) {
+		rd, err = b.layers[i].NewReaderWithReadHandle(ctx, req)
+		return
+	})
+	return
+}
+
+func (b *unionBucket) NewMultiRangeDownloader(
+	ctx context.Context, req *gcs.MultiRangeDownloaderRequest) (mrd gcs.MultiRangeDownloader, err error) {
+	var i int
+	if i, _, _, err = b.find(ctx, &gcs.StatObjectRequest{Name: req.Name}); err != nil {
+		return
+	}
+
+	mrd, err = b.layers[i].NewMultiRangeDownloader(ctx, req)
+	return
+}
+
+func (b *unionBucket) StatObject(
+	ctx context.Context,
+	req *gcs.StatObjectRequest) (m *gcs.MinObject, e *gcs.ExtendedObjectAttributes, err error) {
+	_, m, e, err = b.find(ctx, req)
+	return
+}
+
+// listItem is an object or a collapsed run in a listing.
+type listItem struct {
+	name  string
+	isRun bool
+}
+
+// less orders items by name, with a collapsed run after the object of the
+// same name.
+func (x listItem) less(y listItem) bool {
+	if x.name != y.name {
+		return x.name < y.name
+	}
+	return !x.isRun && y.isRun
+}
+
+// successor returns the least name that is listed after the item.
+func (x listItem) successor() string {
+	if !x.isRun {
+		return x.name + "\x00"
+	}
+
+	// Everything under a collapsed run is collapsed into it. Runs end with the
+	// delimiter, which can't be the largest byte.
+	s := []byte(x.name)
+	s[len(s)-1]++
+	return string(s)
+}
+
+// lastItem returns the last item in a listing that isn't empty.
+func lastItem(l *gcs.Listing) (last listItem) {
+	if n := len(l.MinObjects); n > 0 {
+		last = listItem{name: l.MinObjects[n-1].Name}
+	}
+	if n := len(l.CollapsedRuns); n > 0 {
+		if run := (listItem{name: l.CollapsedRuns[n-1], isRun: true}); last.less(run) {
+			last = run
+		}
+	}
+	return
+}
+
+// listLayer lists a page of the layer that isn't empty, unless it is the last.
+func listLayer(ctx context.Context, layer gcs.Bucket, req *gcs.ListObjectsRequest) (l *gcs.Listing, err error) {
+	for {
+		if l, err = layer.ListObjects(ctx, req); err != nil {
+			return
+		}
+		if len(l.MinObjects) > 0 || len(l.CollapsedRuns) > 0 || l.ContinuationToken == "" {
+			return
+		}
+		req.ContinuationToken = l.ContinuationToken
+	}
+}
+
+// listWhiteouts returns the names of the whiteouts in the layer at index i
+// that may hide something listed for the supplied request from start up to
+// end, or to the end of the listing if end is empty, given the page of the
+// layer listed for it that covers that range. A whiteout is in the directory
+// of what it hides, and so is everything in between, so those that the page
+// leaves out are either outside the prefix, in its directory, or in the
+// directories above start and end. Only the whiteouts of those directories
+// are added.
+func (b *unionBucket) listWhiteouts(ctx context.Context, i int, req *gcs.ListObjectsRequest, start, end string, page *gcs.Listing) (map[string]bool, error) {
+	whiteouts := make(map[string]bool)
+	for _, m := range page.MinObjects {
+		if isWhiteout(m.Name) {
+			whiteouts[m.Name] = true
+		}
+	}
+
+	dir := req.Prefix[:strings.LastIndex(req.Prefix, "/")+1]
+	dirs := []string{dir}
+	if req.Delimiter == "" {
+		for _, name := range []string{start, end} {
+			if !strings.HasPrefix(name, req.Prefix) {
+				continue
+			}
+			for k := len(dir); ; {
+				j := strings.IndexByte(name[k:], '/')
+				if j < 0 {
+					break
+				}
+				k += j + 1
+				if d := name[:k]; !slices.Contains(dirs, d) {
+					dirs = append(dirs, d)
+				}
+			}
+		}
+	}
+
+	for _, d := range dirs {
+		dirWhiteouts, err := b.dirWhiteouts(ctx, i, d)
+		if err != nil {
+			return nil, err
+		}
+		for w := range dirWhiteouts {
+			whiteouts[w] = true
+		}
+	}
+
+	return whiteouts, nil
+}
+
+// ListObjects merges the listings of the layers, leaving out whiteouts, what
+// they hide and collapsed runs under which nothing is visible. Continuation
+// tokens are the names that the next pages start at. Listing generations
+// isn't supported.
+func (b *unionBucket) ListObjects(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest) (l *gcs.Listing, err error) {
+	if req.Versions {
+		err = fmt.Errorf("listing generations in a union: %w", syscall.ENOTSUP)
+		return
+	}
+
+	start := max(req.StartOffset, req.ContinuationToken)
+	for {
+		if l, err = b.listPage(ctx, req, start); err != nil {
+			return
+		}
+
+		// Callers may take an empty page to be the last one.
+		if len(l.MinObjects) > 0 || len(l.CollapsedRuns) > 0 || l.ContinuationToken == "" {
+			return
+		}
+		start = l.ContinuationToken
+	}
+}
+
+// listPage lists the page of the union that begins at the supplied name.
+func (b *unionBucket) listPage(
+	ctx context.Context,
+	req *gcs.ListObjectsRequest,
+	start string) (l *gcs.Listing, err error) {
+	// The layers below one that hides the listed directory don't take part.
+	layers := b.layers
+	if dir := req.Prefix[:strings.LastIndex(req.Prefix, "/")+1]; dir != "" {
+		for i := range len(layers) - 1 {
+			var hidden bool
+			if hidden, err = b.whitedOut(ctx, i, dir); err != nil {
+				return
+			}
+			if hidden {
+				layers = layers[:i+1]
+				break
+			}
+		}
+	}
+
+	// List a page of each layer. Whatever comes after the end of the shortest
+	// of those that have more waits for the next page.
+	type entry struct {
+		item  listItem
+		layer int
+		m     *gcs.MinObject
+	}
+	var entries []entry
+	var bound *listItem
+	whiteouts := make([]map[string]bool, len(layers)-1)
+	for i, layer := range layers {
+		lReq := *req
+		lReq.StartOffset = start
+		lReq.ContinuationToken = ""
+
+		var page *gcs.Listing
+		if page, err = listLayer(ctx, layer, &lReq); err != nil {
+			return
+		}
+
+		for _, m := range page.MinObjects {
+			if !isWhiteout(m.Name) {
+				entries = append(entries, entry{item: listItem{name: m.Name}, layer: i, m: m})
+			}
+		}
+		for _, run := range page.CollapsedRuns {
+			entries = append(entries, entry{item: listItem{name: run, isRun: true}, layer: i})
+		}
+
+		var end string
+		if page.ContinuationToken != "" {
+			last := lastItem(page)
+			if bound == nil || last.less(*bound) {
+				bound = &last
+			}
+			end = last.successor()
+		}
+
+		// The bottom layer has nothing to hide.
+		if i < len(layers)-1 {
+			if whiteouts[i], err = b.listWhiteouts(ctx, i, req, start, end, page); err != nil {
+				return
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(x, y int) bool {
+		if entries[x].item != entries[y].item {
+			return entries[x].item.less(entries[y].item)
+		}
+		return entries[x].layer < entries[y].layer
+	})
+
+	hidden := func(e entry) bool {
+		for i := range e.layer {
+			for _, w := range whiteoutNames(e.item.name) {
+				if whiteouts[i][w] {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	l = new(gcs.Listing)
+	var last *listItem
+	for j := 0; j < len(entries); {
+		// The first entry of those for the same item comes from the layer with
+		// the highest priority, and shadows the others.
+		e := entries[j]
+		inLowest := true
+		for ; j < len(entries) && entries[j].item == e.item; j++ {
+			inLowest = inLowest && entries[j].layer == len(layers)-1
+		}
+
+		if bound != nil && bound.less(e.item) {
+			break
+		}
+		if hidden(e) {
+			continue
+		}
+
+		// Whiteouts above the lowest layer may hide everything under a run.
+		if e.item.isRun && !inLowest {
+			var visible bool
+			if visible, err = b.anyVisible(ctx, e.item.name); err != nil {
+				return
+			}
+			if !visible {
+				continue
+			}
+		}
+
+		if req.MaxResults > 0 && len(l.MinObjects)+len(l.CollapsedRuns) == req.MaxResults {
+			bound = last
+			break
+		}
+
+		if e.item.isRun {
+			l.CollapsedRuns = append(l.CollapsedRuns, e.item.name)
+		} else {
+			l.MinObjects = append(l.MinObjects, e.m)
+		}
+		last = &e.item
+	}
+
+	if bound != nil {
+		l.ContinuationToken = bound.successor()
+	}
+
+	return
+}
+
+func (b *unionBucket) GetFolder(ctx context.Context, req *gcs.GetFolderRequest) (*gcs.Folder, error) {
+	return b.top().GetFolder(ctx, req)
+}
+
+func (b *unionBucket) GCSName(object *gcs.MinObject) string {
+	return b.top().GCSName(object)
+}
+
+////////////////////////////////////////////////////////////////////////
+// Mutations
+////////////////////////////////////////////////////////////////////////
+
+func (b *unionBucket) CreateObject(
+	ctx context.Context,
+	req *gcs.CreateObjectRequest) (o *gcs.Object, err error) {
+	if err = checkNotReserved(req.Name); err != nil {
+		return
+	}
+
+	mReq := new(gcs.CreateObjectRequest)
+	*mReq = *req
+	mReq.GenerationPrecondition, mReq.MetaGenerationPrecondition, err = b.topPreconditions(
+		ctx, req.Name, req.GenerationPrecondition, req.MetaGenerationPrecondition)
+	if err != nil {
+		return
+	}
+
+	if o, err = b.top().CreateObject(ctx, mReq); err == nil {
+		b.cache.changed(req.Name)
+	}
+	return
+}
+
+func (b *unionBucket) CreateObjectChunkWriter(ctx context.Context, req *gcs.CreateObjectRequest, chunkSize int, callBack func(bytesUploadedSoFar int64)) (w gcs.Writer, err error) {
+	if err = checkNotReserved(req.Name); err != nil {
+		return
+	}
+
+	mReq := new(gcs.CreateObjectRequest)
+	*mReq = *req
+	mReq.GenerationPrecondition, mReq.MetaGenerationPrecondition, err = b.topPreconditions(
+		ctx, req.Name, req.GenerationPrecondition, req.MetaGenerationPrecondition)
+	if err != nil {
+		return
+	}
+
+	w, err = b.top().CreateObjectChunkWriter(ctx, mReq, chunkSize, callBack)
+	return
+}
+
+// Appendable objects live in zonal buckets, which can't be layers.
+func (b *unionBucket) CreateAppendableObjectWriter(ctx context.Context, req *gcs.CreateObjectChunkWriterRequest) (gcs.Writer, error) {
+	return nil, fmt.Errorf("appending to %q in a union: %w", req.Name, syscall.ENOTSUP)
+}
+
+func (b *unionBucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	m, err := b.top().FinalizeUpload(ctx, w)
+	if err == nil {
+		b.cache.changed(m.Name)
+	}
+	return m, err
+}
+
+func (b *unionBucket) FlushPendingWrites(ctx context.Context, w gcs.Writer) (*gcs.MinObject, error) {
+	return b.top().FlushPendingWrites(ctx, w)
+}
+
+func (b *unionBucket) CopyObject(
+	ctx context.Context,
+	req *gcs.CopyObjectRequest) (o *gcs.Object, err error) {
+	if err = checkNotReserved(req.DstName); err != nil {
+		return
+	}
+
+	mReq := new(gcs.CopyObjectRequest)
+	*mReq = *req
+	if mReq.DstGenerationPrecondition, _, err = b.topPreconditions(ctx, req.DstName, req.DstGenerationPrecondition, nil); err != nil {
+		return
+	}
+
+	defer func() {
+		if err == nil {
+			b.cache.changed(req.DstName)
+		}
+	}()
+
+	// Copies from other buckets are up to GCS.
+	if req.SrcBucket != "" && req.SrcBucket != b.Name() {
+		o, err = b.top().CopyObject(ctx, mReq)
+		return
+	}
+
+	i, m, e, err := b.find(ctx, &gcs.StatObjectRequest{
+		Name:                           req.SrcName,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+	if err != nil {
+		return
+	}
+	if i == 0 {
+		o, err = b.top().CopyObject(ctx, mReq)
+		return
+	}
+
+	if (req.SrcGeneration != 0 && req.SrcGeneration != m.Generation) ||
+		(req.SrcMetaGenerationPrecondition != nil && *req.SrcMetaGenerationPrecondition != m.MetaGeneration) {
+		err = &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q is at generation %d (meta-generation %d)", req.SrcName, m.Generation, m.MetaGeneration),
+		}
+		return
+	}
+
+	o, err = b.copyUp(ctx, b.layers[i], m, e, req.DstName, mReq.DstGenerationPrecondition)
+	return
+}
+
+// ComposeObjects copies sources that are in lower layers up to the top one
+// before composing them there.
+func (b *unionBucket) ComposeObjects(
+	ctx context.Context,
+	req *gcs.ComposeObjectsRequest) (o *gcs.Object, err error) {
+	if err = checkNotReserved(req.DstName); err != nil {
+		return
+	}
+
+	mReq := new(gcs.ComposeObjectsRequest)
+	*mReq = *req
+	mReq.Sources = append([]gcs.ComposeSource(nil), req.Sources...)
+
+	copiedUp := make(map[string]*gcs.MinObject)
+	for k, src := range req.Sources {
+		m, seen := copiedUp[src.Name]
+		if !seen {
+			var copied bool
+			if m, copied, err = b.ensureTop(ctx, src.Name, src.Generation); err != nil {
+				return
+			}
+			if !copied {
+				m = nil
+			}
+			copiedUp[src.Name] = m
+		}
+
+		if m != nil && src.Generation != 0 {
+			mReq.Sources[k].Generation = m.Generation
+		}
+	}
+
+	// Preconditions on a destination that was copied up refer to the copy.
+	if m := copiedUp[req.DstName]; m != nil {
+		if req.DstGenerationPrecondition != nil && *req.DstGenerationPrecondition != 0 {
+			mReq.DstGenerationPrecondition = &m.Generation
+		}
+		if req.DstMetaGenerationPrecondition != nil {
+			mReq.DstMetaGenerationPrecondition = &m.MetaGeneration
+		}
+	} else {
+		mReq.DstGenerationPrecondition, mReq.DstMetaGenerationPrecondition, err = b.topPreconditions(
+			ctx, req.DstName, req.DstGenerationPrecondition, req.DstMetaGenerationPrecondition)
+		if err != nil {
+			return
+		}
+	}
+
+	if o, err = b.top().ComposeObjects(ctx, mReq); err == nil {
+		b.cache.changed(req.DstName)
+	}
+	return
+}
+
+func (b *unionBucket) UpdateObject(
+	ctx context.Context,
+	req *gcs.UpdateObjectRequest) (o *gcs.Object, err error) {
+	m, copied, err := b.ensureTop(ctx, req.Name, req.Generation)
+	if err != nil {
+		return
+	}
+
+	mReq := new(gcs.UpdateObjectRequest)
+	*mReq = *req
+	if copied {
+		if req.Generation != 0 {
+			mReq.Generation = m.Generation
+		}
+		if req.MetaGenerationPrecondition != nil {
+			mReq.MetaGenerationPrecondition = &m.MetaGeneration
+		}
+	}
+
+	o, err = b.top().UpdateObject(ctx, mReq)
+	return
+}
+
+// DeleteObject deletes the object from the top layer, and hides it if a lower
+// layer has it too.
+func (b *unionBucket) DeleteObject(
+	ctx context.Context,
+	req *gcs.DeleteObjectRequest) error {
+	err := b.top().DeleteObject(ctx, req)
+	if req.OnlyDeleteFromCache || (err != nil && !isNotFound(err)) {
+		return err
+	}
+	deleted := err == nil
+	if deleted {
+		b.cache.changed(req.Name)
+	}
+
+	hidden, err := b.hide(ctx, req.Name)
+	if err != nil {
+		return err
+	}
+
+	if !deleted && !hidden {
+		return &gcs.NotFoundError{
+			Err: fmt.Errorf("object %q not found in any layer", req.Name),
+		}
+	}
+	return nil
+}
+
+func (b *unionBucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (o *gcs.Object, err error) {
+	if err = checkNotReserved(req.DstName); err != nil {
+		return
+	}
+
+	i, m, e, err := b.find(ctx, &gcs.StatObjectRequest{
+		Name:                           req.SrcName,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+	if err != nil {
+		return
+	}
+
+	if i == 0 {
+		o, err = b.top().MoveObject(ctx, req)
+	} else if (req.SrcGeneration != 0 && req.SrcGeneration != m.Generation) ||
+		(req.SrcMetaGenerationPrecondition != nil && *req.SrcMetaGenerationPrecondition != m.MetaGeneration) {
+		err = &gcs.PreconditionError{
+			Err: fmt.Errorf("object %q is at generation %d (meta-generation %d)", req.SrcName, m.Generation, m.MetaGeneration),
+		}
+	} else {
+		o, err = b.copyUp(ctx, b.layers[i], m, e, req.DstName, nil)
+	}
+	if err != nil {
+		return
+	}
+	b.cache.changed(req.SrcName)
+	b.cache.changed(req.DstName)
+
+	// Lower layers can't be changed, so the source is hidden instead.
+	_, err = b.hide(ctx, req.SrcName)
+	return
+}
+
+// Folders only exist in hierarchical buckets, which can't be layers.
+
+func (b *unionBucket) DeleteFolder(ctx context.Context, folderName string) error {
+	return fmt.Errorf("folder %q in a union: %w", folderName, syscall.ENOTSUP)
+}
+
+func (b *unionBucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Folder, error) {
+	return nil, fmt.Errorf("folder %q in a union: %w", folderName, syscall.ENOTSUP)
+}
+
+func (b *unionBucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	return nil, fmt.Errorf("folder %q in a union: %w", folderName, syscall.ENOTSUP)
+}
@@ -0,0 +1,286 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx_test
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/context"
+)
+
+type UnionBucketTest struct {
+	suite.Suite
+	ctx    context.Context
+	top    gcs.Bucket
+	middle gcs.Bucket
+	bottom gcs.Bucket
+	bucket gcs.Bucket
+	clock  *timeutil.SimulatedClock
+}
+
+func TestUnionBucket(t *testing.T) {
+	suite.Run(t, new(UnionBucketTest))
+}
+
+func (t *UnionBucketTest) SetupTest() {
+	t.ctx = context.Background()
+	t.top = fake.NewFakeBucket(timeutil.RealClock(), "top", gcs.BucketType{})
+	t.middle = fake.NewFakeBucket(timeutil.RealClock(), "middle", gcs.BucketType{})
+	t.bottom = fake.NewFakeBucket(timeutil.RealClock(), "bottom", gcs.BucketType{})
+
+	t.create(t.bottom, "a", "b", "dir/", "dir/x", "dir/y", "other/z")
+	t.create(t.middle, "b", "c", "dir/y")
+	t.create(t.top, "c")
+
+	// Changes made through the union are seen through its cache straight
+	// away.
+	t.clock = &timeutil.SimulatedClock{}
+	t.clock.SetTime(time.Now())
+	var err error
+	t.bucket, err = gcsx.NewUnionBucket([]gcs.Bucket{t.top, t.middle, t.bottom}, time.Minute, t.clock)
+	require.NoError(t.T(), err)
+}
+
+// create creates objects holding the name of the layer and their own.
+func (t *UnionBucketTest) create(layer gcs.Bucket, names ...string) {
+	for _, name := range names {
+		_, err := storageutil.CreateObject(t.ctx, layer, name, []byte(layer.Name()+":"+name))
+		require.NoError(t.T(), err)
+	}
+}
+
+func (t *UnionBucketTest) read(b gcs.Bucket, name string) string {
+	contents, err := storageutil.ReadObject(t.ctx, b, name)
+	require.NoError(t.T(), err)
+	return string(contents)
+}
+
+func (t *UnionBucketTest) assertNotFound(b gcs.Bucket, name string) {
+	_, _, err := b.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+	var notFound *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFound, name)
+}
+
+func (t *UnionBucketTest) list(req *gcs.ListObjectsRequest) (names []string, runs []string) {
+	objects, runs, err := storageutil.ListAll(t.ctx, t.bucket, req)
+	require.NoError(t.T(), err)
+	for _, m := range objects {
+		names = append(names, m.Name)
+	}
+	return
+}
+
+func (t *UnionBucketTest) TestHierarchicalLayer() {
+	hns := fake.NewFakeBucket(timeutil.RealClock(), "hns", gcs.BucketType{Hierarchical: true})
+
+	_, err := gcsx.NewUnionBucket([]gcs.Bucket{t.top, hns}, 0, timeutil.RealClock())
+
+	assert.Error(t.T(), err)
+}
+
+func (t *UnionBucketTest) TestNewReader_HighestPriorityLayerWins() {
+	assert.Equal(t.T(), "bottom:a", t.read(t.bucket, "a"))
+	assert.Equal(t.T(), "middle:b", t.read(t.bucket, "b"))
+	assert.Equal(t.T(), "top:c", t.read(t.bucket, "c"))
+	assert.Equal(t.T(), "bottom:dir/x", t.read(t.bucket, "dir/x"))
+	assert.Equal(t.T(), "middle:dir/y", t.read(t.bucket, "dir/y"))
+	t.assertNotFound(t.bucket, "missing")
+}
+
+func (t *UnionBucketTest) TestListObjects() {
+	names, runs := t.list(&gcs.ListObjectsRequest{Delimiter: "/"})
+
+	assert.Equal(t.T(), []string{"a", "b", "c"}, names)
+	assert.Equal(t.T(), []string{"dir/", "other/"}, runs)
+
+	names, _ = t.list(&gcs.ListObjectsRequest{Prefix: "dir/"})
+	assert.Equal(t.T(), []string{"dir/", "dir/x", "dir/y"}, names)
+}
+
+func (t *UnionBucketTest) TestListObjects_Paginated() {
+	want, wantRuns := t.list(&gcs.ListObjectsRequest{Delimiter: "/"})
+
+	names, runs := t.list(&gcs.ListObjectsRequest{Delimiter: "/", MaxResults: 1})
+
+	assert.Equal(t.T(), want, names)
+	assert.Equal(t.T(), wantRuns, runs)
+}
+
+func (t *UnionBucketTest) TestListObjects_PaginatedWithWhiteouts() {
+	t.create(t.bottom, "deep/a/b/-x", "deep/a/b/c", "deep/a/b/d/e", "deep/a/f", "deep/g")
+	t.create(t.middle, "deep/a/b/-y")
+	// The whiteouts of -x and -y sort after them, and those of e and f before.
+	for _, name := range []string{"deep/a/b/-x", "deep/a/b/-y", "deep/a/b/d/e", "deep/a/f"} {
+		require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: name}))
+	}
+
+	for _, maxResults := range []int{0, 1, 2, 3} {
+		names, _ := t.list(&gcs.ListObjectsRequest{Prefix: "deep/", MaxResults: maxResults})
+
+		assert.Equal(t.T(), []string{"deep/a/b/c", "deep/g"}, names, maxResults)
+	}
+}
+
+func (t *UnionBucketTest) TestCreateObject_WritesToTop() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "a", []byte("new"))
+	require.NoError(t.T(), err)
+
+	assert.Equal(t.T(), "new", t.read(t.bucket, "a"))
+	assert.Equal(t.T(), "new", t.read(t.top, "a"))
+	assert.Equal(t.T(), "bottom:a", t.read(t.bottom, "a"))
+}
+
+func (t *UnionBucketTest) TestCreateObject_PreconditionOnLowerObject() {
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "b"})
+	require.NoError(t.T(), err)
+
+	_, err = t.bucket.CreateObject(t.ctx, &gcs.CreateObjectRequest{
+		Name:                   "b",
+		Contents:               strings.NewReader(""),
+		GenerationPrecondition: &m.Generation,
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "", t.read(t.bucket, "b"))
+}
+
+func (t *UnionBucketTest) TestCreateObject_ReservedName() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "dir/.wh.x", nil)
+
+	assert.ErrorIs(t.T(), err, syscall.EINVAL)
+}
+
+func (t *UnionBucketTest) TestDeleteObject_LowerLayerLeavesWhiteout() {
+	err := t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "dir/x"})
+	require.NoError(t.T(), err)
+
+	t.assertNotFound(t.bucket, "dir/x")
+	assert.Equal(t.T(), "", t.read(t.top, "dir/.wh.x"))
+	assert.Equal(t.T(), "bottom:dir/x", t.read(t.bottom, "dir/x"))
+	names, _ := t.list(&gcs.ListObjectsRequest{Prefix: "dir/", Delimiter: "/"})
+	assert.Equal(t.T(), []string{"dir/", "dir/y"}, names)
+
+	// It can be recreated.
+	t.create(t.bucket, "dir/x")
+	assert.Equal(t.T(), "top:dir/x", t.read(t.bucket, "dir/x"))
+}
+
+func (t *UnionBucketTest) TestDeleteObject_TopLayerUncoversNothing() {
+	// "c" is in the middle layer too, so it needs hiding.
+	err := t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "c"})
+	require.NoError(t.T(), err)
+	t.assertNotFound(t.bucket, "c")
+
+	t.create(t.bucket, "new")
+	err = t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "new"})
+	require.NoError(t.T(), err)
+	t.assertNotFound(t.bucket, "new")
+	t.assertNotFound(t.top, ".wh.new")
+}
+
+func (t *UnionBucketTest) TestDeleteObject_WhiteoutHidesDirectory() {
+	for _, name := range []string{"other/z", "dir/x", "dir/y"} {
+		require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: name}))
+	}
+	_, runs := t.list(&gcs.ListObjectsRequest{Delimiter: "/"})
+	// Nothing visible is left under other/, but dir/ is still there.
+	assert.Equal(t.T(), []string{"dir/"}, runs)
+
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "dir/"}))
+
+	_, runs = t.list(&gcs.ListObjectsRequest{Delimiter: "/"})
+	assert.Empty(t.T(), runs)
+	// A directory created anew doesn't show what was there before.
+	t.create(t.bucket, "dir/")
+	names, _ := t.list(&gcs.ListObjectsRequest{Prefix: "dir/"})
+	assert.Equal(t.T(), []string{"dir/"}, names)
+	t.assertNotFound(t.bucket, "dir/x")
+}
+
+func (t *UnionBucketTest) TestUpdateObject_CopiesUp() {
+	value := "value"
+
+	_, err := t.bucket.UpdateObject(t.ctx, &gcs.UpdateObjectRequest{
+		Name:     "a",
+		Metadata: map[string]*string{"key": &value},
+	})
+
+	require.NoError(t.T(), err)
+	m, _, err := t.top.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "value", m.Metadata["key"])
+	assert.Equal(t.T(), "bottom:a", t.read(t.top, "a"))
+	m, _, err = t.bottom.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t.T(), err)
+	assert.NotContains(t.T(), m.Metadata, "key")
+}
+
+func (t *UnionBucketTest) TestComposeObjects_AppendsToLowerObject() {
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "a"})
+	require.NoError(t.T(), err)
+	t.create(t.bucket, "tmp")
+
+	_, err = t.bucket.ComposeObjects(t.ctx, &gcs.ComposeObjectsRequest{
+		DstName:                   "a",
+		DstGenerationPrecondition: &m.Generation,
+		Sources: []gcs.ComposeSource{
+			{Name: "a", Generation: m.Generation},
+			{Name: "tmp"},
+		},
+	})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "bottom:atop:tmp", t.read(t.bucket, "a"))
+	assert.Equal(t.T(), "bottom:a", t.read(t.bottom, "a"))
+}
+
+func (t *UnionBucketTest) TestMoveObject_FromLowerLayer() {
+	_, err := t.bucket.MoveObject(t.ctx, &gcs.MoveObjectRequest{SrcName: "b", DstName: "moved"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "middle:b", t.read(t.bucket, "moved"))
+	// Neither the middle nor the bottom layer's b shows through.
+	t.assertNotFound(t.bucket, "b")
+	assert.Equal(t.T(), "middle:b", t.read(t.middle, "b"))
+}
+
+func (t *UnionBucketTest) TestCopyObject_FromLowerLayer() {
+	_, err := t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: "dir/x", DstName: "copy"})
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "bottom:dir/x", t.read(t.top, "copy"))
+	assert.Equal(t.T(), "bottom:dir/x", t.read(t.bucket, "dir/x"))
+}
+
+func (t *UnionBucketTest) TestWhiteoutsAreCached() {
+	assert.Equal(t.T(), "bottom:dir/x", t.read(t.bucket, "dir/x"))
+	// A whiteout written to a layer other than through the union.
+	t.create(t.top, "dir/.wh.x")
+
+	assert.Equal(t.T(), "bottom:dir/x", t.read(t.bucket, "dir/x"))
+	t.clock.AdvanceTime(time.Minute)
+	t.assertNotFound(t.bucket, "dir/x")
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcsx
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jacobsa/timeutil"
+)
+
+// unionCache remembers for a while the whiteouts in the directories of each
+// layer of a union, and whether anything is visible under the directories
+// that listings collapse. Without it, every lookup would list the whiteouts
+// of each directory above the name in each layer, and every listing would
+// list each collapsed run again. A zero TTL disables it.
+//
+// Changes made through the union are reflected in it straight away; changes
+// made to the layers by others are seen once the entries expire, as with the
+// stat cache.
+type unionCache struct {
+	ttl   time.Duration
+	clock timeutil.Clock
+
+	mu sync.Mutex
+
+	// The names of the whiteouts in each directory, for each layer.
+	//
+	// GUARDED_BY(mu)
+	whiteouts []map[string]cachedWhiteouts
+
+	// Whether anything is visible under each collapsed run.
+	//
+	// GUARDED_BY(mu)
+	visible map[string]cachedVisibility
+}
+
+type cachedWhiteouts struct {
+	names   map[string]bool
+	expires time.Time
+}
+
+type cachedVisibility struct {
+	visible bool
+	expires time.Time
+}
+
+func newUnionCache(layers int, ttl time.Duration, clock timeutil.Clock) *unionCache {
+	c := &unionCache{
+		ttl:       ttl,
+		clock:     clock,
+		whiteouts: make([]map[string]cachedWhiteouts, layers),
+		visible:   make(map[string]cachedVisibility),
+	}
+	for i := range c.whiteouts {
+		c.whiteouts[i] = make(map[string]cachedWhiteouts)
+	}
+	return c
+}
+
+// dirWhiteouts returns the names of the whiteouts in the directory dir of the
+// layer at index i, calling list for them unless they are cached.
+func (c *unionCache) dirWhiteouts(i int, dir string, list func() (map[string]bool, error)) (map[string]bool, error) {
+	if c.ttl == 0 {
+		return list()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.whiteouts[i][dir]
+	c.mu.Unlock()
+	if ok && c.clock.Now().Before(entry.expires) {
+		return entry.names, nil
+	}
+
+	names, err := list()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for d, e := range c.whiteouts[i] {
+		if !now.Before(e.expires) {
+			delete(c.whiteouts[i], d)
+		}
+	}
+	c.whiteouts[i][dir] = cachedWhiteouts{names: names, expires: now.Add(c.ttl)}
+	return names, nil
+}
+
+// anyVisible reports whether anything is visible under the collapsed run,
+// calling check to find out unless it is cached.
+func (c *unionCache) anyVisible(run string, check func() (bool, error)) (bool, error) {
+	if c.ttl == 0 {
+		return check()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.visible[run]
+	c.mu.Unlock()
+	if ok && c.clock.Now().Before(entry.expires) {
+		return entry.visible, nil
+	}
+
+	visible, err := check()
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for r, e := range c.visible {
+		if !now.Before(e.expires) {
+			delete(c.visible, r)
+		}
+	}
+	c.visible[run] = cachedVisibility{visible: visible, expires: now.Add(c.ttl)}
+	return visible, nil
+}
+
+// addWhiteout records a whiteout written to the top layer.
+func (c *unionCache) addWhiteout(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dir := name[:strings.LastIndex(name, "/")+1]
+	if entry, ok := c.whiteouts[0][dir]; ok {
+		names := make(map[string]bool, len(entry.names)+1)
+		for n := range entry.names {
+			names[n] = true
+		}
+		names[name] = true
+		c.whiteouts[0][dir] = cachedWhiteouts{names: names, expires: entry.expires}
+	}
+}
+
+// changed forgets whether anything is visible under the directories above the
+// named object, which has been created or hidden, and under the one it stands
+// for, which a whiteout hides too.
+func (c *unionCache) changed(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range len(name) {
+		if name[i] == '/' {
+			delete(c.visible, name[:i+1])
+		}
+	}
+	delete(c.visible, strings.TrimSuffix(name, "/")+"/")
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package inodeid derives inode IDs from the bucket and object an inode stands
+// for, so that an object gets the same inode ID every time it is mounted.
+package inodeid
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+type key struct {
+	Bucket string `json:"bucket"`
+	Name   string `json:"name"`
+}
+
+// A record is one line of an index file.
+type record struct {
+	ID fuseops.InodeID `json:"id"`
+	key
+}
+
+// Index hands out inode IDs. The ID for an object is a hash of its bucket and
+// name, unless that ID is held by another object, in which case the following
+// IDs are tried in turn.
+//
+// Only the IDs handed out after such a collision are remembered, as the others
+// follow from the hash. Optionally, they are recorded in a file, from which a
+// later Index picks them up again, so that an object whose hash collided gets
+// the same ID as before after a remount. Hash collisions being rare, the index
+// stays small.
+//
+// An Index is not safe for concurrent use.
+type Index struct {
+	// The ID each object was handed out after a collision.
+	byKey map[key]fuseops.InodeID
+
+	// The inverse of byKey.
+	byID map[fuseops.InodeID]key
+
+	// The file that IDs are recorded in, or nil.
+	f *os.File
+}
+
+// New returns an index that records the IDs it hands out after collisions in
+// the file at path, after reading those recorded there before. The file is
+// created if missing, and rewritten if it holds records that aren't needed. If
+// path is empty, nothing is recorded.
+func New(path string) (*Index, error) {
+	x := &Index{
+		byKey: make(map[key]fuseops.InodeID),
+		byID:  make(map[fuseops.InodeID]key),
+	}
+	if path == "" {
+		return x, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open inode index: %w", err)
+	}
+
+	var records []record
+	var unneeded bool
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var r record
+		// A crash can leave the last line half written.
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			logger.Warnf("Ignoring inode index record %q: %v", scanner.Bytes(), err)
+			unneeded = true
+			continue
+		}
+		if _, ok := x.byKey[r.key]; ok || r.ID == hash(r.key) {
+			unneeded = true
+			continue
+		}
+		if _, ok := x.byID[r.ID]; ok {
+			unneeded = true
+			continue
+		}
+		x.add(r.ID, r.key)
+		records = append(records, r)
+	}
+	f.Close()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read inode index: %w", err)
+	}
+
+	if unneeded {
+		if err := rewrite(path, records); err != nil {
+			return nil, fmt.Errorf("rewrite inode index: %w", err)
+		}
+	}
+	if x.f, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600); err != nil {
+		return nil, fmt.Errorf("open inode index: %w", err)
+	}
+	return x, nil
+}
+
+// Assign returns the ID for the named object. owner returns the object that
+// the inode with the supplied ID stands for, if there is one.
+func (x *Index) Assign(bucket, name string, owner func(fuseops.InodeID) (bucket, name string, ok bool)) fuseops.InodeID {
+	k := key{Bucket: bucket, Name: name}
+	id, recorded := x.byKey[k]
+	if !recorded {
+		id = hash(k)
+	}
+
+	var collided bool
+	for {
+		if b, n, ok := owner(id); ok {
+			// An inode for an older generation of the object may still hold its
+			// ID, which doesn't make the ID it gets instead worth remembering.
+			if (key{Bucket: b, Name: n}) != k {
+				collided = true
+			}
+		} else if o, ok := x.byID[id]; ok && o != k {
+			collided = true
+		} else {
+			break
+		}
+		id = next(id)
+	}
+
+	if collided && !recorded {
+		x.add(id, k)
+		if x.f != nil {
+			line, _ := json.Marshal(record{ID: id, key: k})
+			// The ID is still good for this mount.
+			if _, err := x.f.Write(append(line, '\n')); err != nil {
+				logger.Warnf("Recording inode ID %d for %q: %v", id, name, err)
+			}
+		}
+	}
+	return id
+}
+
+// Close closes the index file, if any.
+func (x *Index) Close() error {
+	if x.f == nil {
+		return nil
+	}
+	return x.f.Close()
+}
+
+func (x *Index) add(id fuseops.InodeID, k key) {
+	x.byID[id] = k
+	x.byKey[k] = id
+}
+
+// rewrite replaces the index file at path with one holding just the supplied
+// records.
+func rewrite(path string, records []record) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	w := bufio.NewWriter(f)
+	for _, r := range records {
+		line, _ := json.Marshal(r)
+		w.Write(append(line, '\n'))
+	}
+	if err = w.Flush(); err == nil {
+		err = f.Chmod(0600)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func hash(k key) fuseops.InodeID {
+	h := fnv.New64a()
+	h.Write([]byte(k.Bucket))
+	h.Write([]byte{0})
+	h.Write([]byte(k.Name))
+	id := fuseops.InodeID(h.Sum64())
+	if id <= fuseops.RootInodeID {
+		id = next(id)
+	}
+	return id
+}
+
+// next returns the ID after id, leaving out 0 and the root ID.
+func next(id fuseops.InodeID) fuseops.InodeID {
+	id++
+	if id <= fuseops.RootInodeID {
+		id = fuseops.RootInodeID + 1
+	}
+	return id
+}
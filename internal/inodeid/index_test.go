@@ -0,0 +1,126 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inodeid
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noOwner(fuseops.InodeID) (bucket, name string, ok bool) { return }
+
+// ownedBy returns an owner function under which the supplied ID is held by the
+// named object.
+func ownedBy(id fuseops.InodeID, bucket, name string) func(fuseops.InodeID) (string, string, bool) {
+	return func(other fuseops.InodeID) (string, string, bool) {
+		return bucket, name, other == id
+	}
+}
+
+func TestAssign_SameIDAcrossIndexes(t *testing.T) {
+	a, err := New("")
+	require.NoError(t, err)
+	b, err := New("")
+	require.NoError(t, err)
+
+	id := a.Assign("some-bucket", "foo/bar", noOwner)
+
+	assert.Greater(t, uint64(id), uint64(fuseops.RootInodeID))
+	assert.Equal(t, id, b.Assign("some-bucket", "foo/bar", noOwner))
+	assert.Equal(t, id, a.Assign("some-bucket", "foo/bar", noOwner))
+	assert.NotEqual(t, id, a.Assign("other-bucket", "foo/bar", noOwner))
+	assert.NotEqual(t, id, a.Assign("some-bucket", "foo/bar/", noOwner))
+	assert.Empty(t, a.byKey)
+}
+
+func TestAssign_SkipsIDsOfOlderGenerations(t *testing.T) {
+	x, err := New("")
+	require.NoError(t, err)
+	home := x.Assign("some-bucket", "foo", noOwner)
+
+	// The inode for an older generation of foo still has the ID.
+	second := x.Assign("some-bucket", "foo", ownedBy(home, "some-bucket", "foo"))
+
+	assert.Equal(t, home+1, second)
+	assert.Empty(t, x.byKey)
+	assert.Equal(t, home, x.Assign("some-bucket", "foo", noOwner))
+}
+
+func TestAssign_Collision(t *testing.T) {
+	x, err := New("")
+	require.NoError(t, err)
+	home := hash(key{Bucket: "some-bucket", Name: "bar"})
+
+	// Pretend that foo hashed to the same ID as bar, and was seen first.
+	id := x.Assign("some-bucket", "bar", ownedBy(home, "some-bucket", "foo"))
+
+	assert.Equal(t, home+1, id)
+	// Even once foo is gone, bar keeps its ID.
+	assert.Equal(t, id, x.Assign("some-bucket", "bar", noOwner))
+}
+
+func TestNew_ReadsRecordedIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inodes")
+	x, err := New(path)
+	require.NoError(t, err)
+	home := hash(key{Bucket: "some-bucket", Name: "bar"})
+	collided := x.Assign("some-bucket", "bar", ownedBy(home, "some-bucket", "foo"))
+	x.Assign("some-bucket", "baz", noOwner)
+	require.NoError(t, x.Close())
+	// A crash left a partial record behind.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"id":12`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	y, err := New(path)
+	require.NoError(t, err)
+
+	// Without the record, bar would now get its hash, which nobody holds.
+	assert.Equal(t, collided, y.Assign("some-bucket", "bar", noOwner))
+	// Records appended after the partial one are read back.
+	qux := hash(key{Bucket: "some-bucket", Name: "qux"})
+	id := y.Assign("some-bucket", "qux", ownedBy(qux, "some-bucket", "foo"))
+	require.NoError(t, y.Close())
+	z, err := New(path)
+	require.NoError(t, err)
+	defer z.Close()
+	assert.Equal(t, id, z.Assign("some-bucket", "qux", noOwner))
+}
+
+func TestNew_DropsUnneededRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inodes")
+	home := hash(key{Bucket: "some-bucket", Name: "bar"})
+	contents := fmt.Sprintf(`{"id":%d,"bucket":"some-bucket","name":"bar"}
+{"id":%d,"bucket":"some-bucket","name":"foo"}
+{"id":%d,"bucket":"some-bucket","name":"bar"}
+{"id":12`, home+1, hash(key{Bucket: "some-bucket", Name: "foo"}), home+2)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+
+	x, err := New(path)
+	require.NoError(t, err)
+	require.NoError(t, x.Close())
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(`{"id":%d,"bucket":"some-bucket","name":"bar"}`+"\n", home+1), string(got))
+}
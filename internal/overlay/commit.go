@@ -0,0 +1,196 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package overlay commits the local overlay of a mount, which keeps the
+// writes made to a bucket that couldn't take them (see --overlay-dir), to a
+// bucket that can.
+//
+// The overlay is a bucket in which created and modified objects are kept as
+// they are, and deleted ones as whiteouts, as in the top layer of a union
+// bucket. A whiteout named dir/.wh.name stands for the deletion of dir/name
+// and of everything under dir/name/.
+package overlay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+)
+
+// Objects under the prefix that mounts create their temporary objects with
+// are left behind by mounts that didn't exit cleanly, and aren't committed.
+const tmpObjectPrefix = ".gcsfuse_tmp/"
+
+// Result counts what a commit did to the destination bucket.
+type Result struct {
+	// Objects written.
+	Written int
+
+	// Objects deleted for whiteouts.
+	Deleted int
+}
+
+// Commit applies the whiteouts of the overlay to dst and then writes the
+// other objects of the overlay to it, removing each from the overlay once it
+// has been committed. A commit that fails part way can be run again to finish
+// it.
+func Commit(ctx context.Context, overlay gcs.Bucket, dst gcs.Bucket) (r Result, err error) {
+	objects, _, err := storageutil.ListAll(ctx, overlay, &gcs.ListObjectsRequest{})
+	if err != nil {
+		err = fmt.Errorf("listing the overlay: %w", err)
+		return
+	}
+
+	inOverlay := make(map[string]bool)
+	var whiteouts, others []*gcs.MinObject
+	for _, m := range objects {
+		inOverlay[m.Name] = true
+		if isWhiteout(m.Name) {
+			whiteouts = append(whiteouts, m)
+		} else {
+			others = append(others, m)
+		}
+	}
+
+	// Whiteouts come first, as the objects of a directory that was removed and
+	// created anew are in the overlay along with its whiteout.
+	for _, w := range whiteouts {
+		var n int
+		n, err = applyWhiteout(ctx, dst, hiddenName(w.Name), inOverlay)
+		r.Deleted += n
+		if err == nil {
+			err = remove(ctx, overlay, w)
+		}
+		if err != nil {
+			err = fmt.Errorf("committing whiteout %q: %w", w.Name, err)
+			return
+		}
+	}
+
+	for _, m := range others {
+		if !strings.HasPrefix(m.Name, tmpObjectPrefix) {
+			if err = write(ctx, overlay, dst, m); err != nil {
+				err = fmt.Errorf("committing %q: %w", m.Name, err)
+				return
+			}
+			r.Written++
+		}
+		if err = remove(ctx, overlay, m); err != nil {
+			err = fmt.Errorf("committing %q: %w", m.Name, err)
+			return
+		}
+	}
+
+	return
+}
+
+func isWhiteout(name string) bool {
+	return !strings.HasSuffix(name, "/") && strings.HasPrefix(path.Base(name), gcsx.WhiteoutPrefix)
+}
+
+// hiddenName returns the name that the named whiteout hides.
+func hiddenName(whiteout string) string {
+	dir, base := path.Split(whiteout)
+	return dir + strings.TrimPrefix(base, gcsx.WhiteoutPrefix)
+}
+
+func isNotFound(err error) bool {
+	var notFoundErr *gcs.NotFoundError
+	return errors.As(err, &notFoundErr)
+}
+
+// applyWhiteout deletes the named object from dst, along with everything
+// under it as a directory, bar the objects in the overlay.
+func applyWhiteout(ctx context.Context, dst gcs.Bucket, name string, inOverlay map[string]bool) (n int, err error) {
+	victims, _, err := storageutil.ListAll(ctx, dst, &gcs.ListObjectsRequest{Prefix: name + "/"})
+	if err != nil {
+		return
+	}
+	m, _, err := dst.StatObject(ctx, &gcs.StatObjectRequest{Name: name})
+	switch {
+	case err == nil:
+		victims = append(victims, m)
+	case !isNotFound(err):
+		return
+	}
+	err = nil
+
+	for _, m := range victims {
+		if inOverlay[m.Name] {
+			continue
+		}
+		err = dst.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: m.Name, Generation: m.Generation})
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		logger.Infof("Deleted %q from bucket %q for an overlay whiteout", m.Name, dst.Name())
+		n++
+	}
+	return
+}
+
+// write writes the overlay object to dst, with its contents as they are
+// stored and its attributes.
+func write(ctx context.Context, overlay gcs.Bucket, dst gcs.Bucket, m *gcs.MinObject) error {
+	m, e, err := overlay.StatObject(ctx, &gcs.StatObjectRequest{
+		Name:                           m.Name,
+		ForceFetchFromGcs:              true,
+		ReturnExtendedObjectAttributes: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	rd, err := overlay.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{
+		Name:           m.Name,
+		Generation:     m.Generation,
+		ReadCompressed: true,
+	})
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	_, err = dst.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:               m.Name,
+		ContentType:        e.ContentType,
+		ContentLanguage:    e.ContentLanguage,
+		ContentEncoding:    m.ContentEncoding,
+		CacheControl:       e.CacheControl,
+		Metadata:           m.Metadata,
+		ContentDisposition: e.ContentDisposition,
+		CustomTime:         e.CustomTime,
+		EventBasedHold:     e.EventBasedHold,
+		StorageClass:       e.StorageClass,
+		CRC32C:             m.CRC32C,
+		Contents:           rd,
+	})
+	return err
+}
+
+// remove removes the object from the overlay, unless it has changed since it
+// was listed.
+func remove(ctx context.Context, overlay gcs.Bucket, m *gcs.MinObject) error {
+	return overlay.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: m.Name, Generation: m.Generation})
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package overlay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/gcsx"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type CommitTest struct {
+	suite.Suite
+	ctx     context.Context
+	overlay gcs.Bucket
+	bucket  gcs.Bucket
+
+	// The overlay on the bucket, as a mount sees it.
+	union gcs.Bucket
+}
+
+func TestCommitTestSuite(t *testing.T) {
+	suite.Run(t, new(CommitTest))
+}
+
+func (t *CommitTest) SetupTest() {
+	t.ctx = context.Background()
+	t.overlay = fake.NewFakeBucket(timeutil.RealClock(), "overlay", gcs.BucketType{})
+	t.bucket = fake.NewFakeBucket(timeutil.RealClock(), "some_bucket", gcs.BucketType{})
+	var err error
+	t.union, err = gcsx.NewUnionBucket([]gcs.Bucket{t.overlay, t.bucket}, 0, timeutil.RealClock())
+	require.NoError(t.T(), err)
+
+	require.NoError(t.T(), storageutil.CreateObjects(t.ctx, t.bucket, map[string][]byte{
+		"a":       []byte("taco"),
+		"b":       []byte("burrito"),
+		"dir/":    nil,
+		"dir/c":   []byte("enchilada"),
+		"dir/d/e": []byte("queso"),
+	}))
+}
+
+// contents returns the contents of the objects in the bucket, by name.
+func (t *CommitTest) contents(b gcs.Bucket) map[string]string {
+	objects, _, err := storageutil.ListAll(t.ctx, b, &gcs.ListObjectsRequest{})
+	require.NoError(t.T(), err)
+	contents := make(map[string]string)
+	for _, m := range objects {
+		c, err := storageutil.ReadObject(t.ctx, b, m.Name)
+		require.NoError(t.T(), err)
+		contents[m.Name] = string(c)
+	}
+	return contents
+}
+
+func (t *CommitTest) TestCommit() {
+	_, err := storageutil.CreateObject(t.ctx, t.union, "a", []byte("nachos"))
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(t.ctx, t.union, "new", []byte("salsa"))
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.union.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: "b"}))
+	want := t.contents(t.union)
+
+	r, err := Commit(t.ctx, t.overlay, t.bucket)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), Result{Written: 2, Deleted: 1}, r)
+	assert.Equal(t.T(), want, t.contents(t.bucket))
+	assert.Empty(t.T(), t.contents(t.overlay))
+}
+
+func (t *CommitTest) TestCommit_RecreatedDirectory() {
+	for _, name := range []string{"dir/c", "dir/d/e", "dir/"} {
+		require.NoError(t.T(), t.union.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: name}))
+	}
+	_, err := storageutil.CreateObject(t.ctx, t.union, "dir/", nil)
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(t.ctx, t.union, "dir/f", []byte("guacamole"))
+	require.NoError(t.T(), err)
+	want := t.contents(t.union)
+
+	_, err = Commit(t.ctx, t.overlay, t.bucket)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), want, t.contents(t.bucket))
+	assert.Equal(t.T(), map[string]string{"a": "taco", "b": "burrito", "dir/": "", "dir/f": "guacamole"}, want)
+}
+
+func (t *CommitTest) TestCommit_SkipsTemporaryObjects() {
+	_, err := storageutil.CreateObject(t.ctx, t.overlay, tmpObjectPrefix+"123", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	r, err := Commit(t.ctx, t.overlay, t.bucket)
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), Result{}, r)
+	assert.NotContains(t.T(), t.contents(t.bucket), tmpObjectPrefix+"123")
+	assert.Empty(t.T(), t.contents(t.overlay))
+}
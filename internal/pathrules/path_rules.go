@@ -0,0 +1,321 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pathrules restricts what may be done to paths in a mount, according
+// to an ordered list of rules that each match paths by a glob or a regular
+// expression.
+//
+// Rules are written as <action>=<glob> or <action>=regex:<regexp>. Paths are
+// relative to the mount, without a leading or trailing slash, and a rule that
+// matches a directory applies to everything beneath it. Globs have the syntax
+// of path.Match, so * doesn't match a slash, while regular expressions match
+// anywhere in the path unless anchored. Of the rules that apply to a path, the
+// first one wins.
+package pathrules
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+)
+
+// Action is what a rule does to the paths it applies to.
+type Action string
+
+const (
+	// ReadOnly refuses any change with EROFS.
+	ReadOnly Action = "read-only"
+
+	// Hidden leaves paths out of listings and fails looking them up with
+	// ENOENT. Creating them fails with EACCES.
+	Hidden Action = "hidden"
+
+	// NoDelete refuses removing paths, or renaming them away, with EACCES.
+	NoDelete Action = "no-delete"
+
+	// NoOverwrite refuses changing the contents of files that have any, and
+	// renaming over existing paths, with EACCES. New files may be written
+	// until they are first closed.
+	NoOverwrite Action = "no-overwrite"
+)
+
+const regexPrefix = "regex:"
+
+type rule struct {
+	action Action
+
+	// Exactly one of these is set.
+	glob  string
+	regex *regexp.Regexp
+
+	// The literal prefix of regex, and whether it is matched regardless of
+	// case, if regex is anchored at the start of the path.
+	anchored bool
+	prefix   string
+	fold     bool
+}
+
+func (r *rule) matches(p string) bool {
+	if r.regex != nil {
+		return r.regex.MatchString(p)
+	}
+	ok, _ := path.Match(r.glob, p)
+	return ok
+}
+
+// mayApplyBeneath reports whether the rule might apply to a path beneath the
+// directory at p, erring on the side of yes.
+func (r *rule) mayApplyBeneath(p string) bool {
+	if r.regex != nil {
+		// Past its literal prefix, a regular expression is taken to match
+		// anything.
+		return !r.anchored || startsAlike(p+"/", r.prefix, r.fold)
+	}
+
+	// As * doesn't match a slash, the glob can only match a path with as many
+	// components as it has, and the leading ones have to match p.
+	dirs := strings.Split(p, "/")
+	globs := strings.Split(r.glob, "/")
+	if len(globs) <= len(dirs) {
+		return false
+	}
+	for i, d := range dirs {
+		if ok, _ := path.Match(globs[i], d); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// literalPrefix returns the literal text that every match of re starts with,
+// and whether it is matched regardless of case, if re is anchored at the
+// start of its input.
+func literalPrefix(re *syntax.Regexp) (prefix string, fold bool, anchored bool) {
+	if re.Op != syntax.OpConcat || len(re.Sub) == 0 || re.Sub[0].Op != syntax.OpBeginText {
+		return "", false, false
+	}
+
+	var b strings.Builder
+	for i, sub := range re.Sub[1:] {
+		if sub.Op != syntax.OpLiteral {
+			break
+		}
+		subFold := sub.Flags&syntax.FoldCase != 0
+		if i > 0 && subFold != fold {
+			break
+		}
+		fold = subFold
+		b.WriteString(string(sub.Rune))
+	}
+	return b.String(), fold, true
+}
+
+// startsAlike reports whether the shorter of a and b is a prefix of the
+// other, comparing case-insensitively if fold is set.
+func startsAlike(a, b string, fold bool) bool {
+	for a != "" && b != "" {
+		_, na := utf8.DecodeRuneInString(a)
+		_, nb := utf8.DecodeRuneInString(b)
+		if a[:na] != b[:nb] && !(fold && strings.EqualFold(a[:na], b[:nb])) {
+			return false
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return true
+}
+
+// Rules is an ordered list of rules. A nil *Rules has no rules.
+type Rules struct {
+	rules []rule
+}
+
+// Parse parses rules written as <action>=<glob> or <action>=regex:<regexp>,
+// returning nil if there are none.
+func Parse(entries []string) (*Rules, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	rs := &Rules{}
+	for _, e := range entries {
+		action, pattern, ok := strings.Cut(e, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("invalid path rule %q; should be <action>=<glob> or <action>=%s<regexp>", e, regexPrefix)
+		}
+
+		r := rule{action: Action(action)}
+		switch r.action {
+		case ReadOnly, Hidden, NoDelete, NoOverwrite:
+		default:
+			return nil, fmt.Errorf("invalid action in path rule %q; should be one of %s, %s, %s or %s", e, ReadOnly, Hidden, NoDelete, NoOverwrite)
+		}
+
+		if expr, isRegex := strings.CutPrefix(pattern, regexPrefix); isRegex {
+			var err error
+			if r.regex, err = regexp.Compile(expr); err != nil {
+				return nil, fmt.Errorf("invalid regexp in path rule %q: %w", e, err)
+			}
+			re, err := syntax.Parse(expr, syntax.Perl)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regexp in path rule %q: %w", e, err)
+			}
+			r.prefix, r.fold, r.anchored = literalPrefix(re)
+		} else {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid glob in path rule %q: %w", e, err)
+			}
+			r.glob = pattern
+		}
+
+		rs.rules = append(rs.rules, r)
+	}
+
+	return rs, nil
+}
+
+// Match returns the action of the first rule that applies to the supplied
+// path, if any. Rules never apply to the root of the mount.
+func (rs *Rules) Match(p string) (Action, bool) {
+	if rs == nil || p == "" {
+		return "", false
+	}
+
+	for _, r := range rs.rules {
+		// Try the path, then each directory above it.
+		for q := p; ; {
+			if r.matches(q) {
+				return r.action, true
+			}
+			i := strings.LastIndexByte(q, '/')
+			if i < 0 {
+				break
+			}
+			q = q[:i]
+		}
+	}
+
+	return "", false
+}
+
+// IsHidden reports whether the supplied path is hidden.
+func (rs *Rules) IsHidden(p string) bool {
+	action, _ := rs.Match(p)
+	return action == Hidden
+}
+
+// CheckLookUp returns an error if the supplied path may not be looked up.
+func (rs *Rules) CheckLookUp(p string) error {
+	if rs.IsHidden(p) {
+		return syscall.ENOENT
+	}
+	return nil
+}
+
+// CheckCreate returns an error if the supplied path may not be created.
+func (rs *Rules) CheckCreate(p string) error {
+	switch action, _ := rs.Match(p); action {
+	case ReadOnly:
+		return fmt.Errorf("%q is read-only: %w", p, syscall.EROFS)
+	case Hidden:
+		return fmt.Errorf("%q is hidden: %w", p, syscall.EACCES)
+	}
+	return nil
+}
+
+// CheckDelete returns an error if the supplied path may not be removed or
+// renamed away.
+func (rs *Rules) CheckDelete(p string) error {
+	switch action, _ := rs.Match(p); action {
+	case ReadOnly:
+		return fmt.Errorf("%q is read-only: %w", p, syscall.EROFS)
+	case Hidden:
+		return syscall.ENOENT
+	case NoDelete:
+		return fmt.Errorf("%q may not be deleted: %w", p, syscall.EACCES)
+	}
+	return nil
+}
+
+// CheckDeleteTree returns an error if the directory at the supplied path may
+// not be removed or renamed away along with everything beneath it. A rule is
+// taken to apply beneath the directory if it might match any path there,
+// whether or not one exists.
+func (rs *Rules) CheckDeleteTree(p string) error {
+	if err := rs.CheckDelete(p); err != nil {
+		return err
+	}
+	for _, r := range rs.beneath(p) {
+		switch r.action {
+		case ReadOnly:
+			return fmt.Errorf("%q has read-only paths beneath it: %w", p, syscall.EROFS)
+		case Hidden:
+			return fmt.Errorf("%q has hidden paths beneath it: %w", p, syscall.EACCES)
+		case NoDelete:
+			return fmt.Errorf("%q has paths beneath it that may not be deleted: %w", p, syscall.EACCES)
+		}
+	}
+	return nil
+}
+
+// CheckCreateTree returns an error if the directory at the supplied path may
+// not be created along with paths beneath it, as renaming a directory there
+// would. Rules are taken to apply beneath it as for CheckDeleteTree.
+func (rs *Rules) CheckCreateTree(p string) error {
+	if err := rs.CheckCreate(p); err != nil {
+		return err
+	}
+	for _, r := range rs.beneath(p) {
+		switch r.action {
+		case ReadOnly:
+			return fmt.Errorf("%q has read-only paths beneath it: %w", p, syscall.EROFS)
+		case Hidden:
+			return fmt.Errorf("%q has hidden paths beneath it: %w", p, syscall.EACCES)
+		}
+	}
+	return nil
+}
+
+// beneath returns the rules that might apply to paths beneath the directory
+// at p.
+func (rs *Rules) beneath(p string) []*rule {
+	if rs == nil || p == "" {
+		return nil
+	}
+	var rules []*rule
+	for i := range rs.rules {
+		if rs.rules[i].mayApplyBeneath(p) {
+			rules = append(rules, &rs.rules[i])
+		}
+	}
+	return rules
+}
+
+// CheckModify returns an error if the existing path may not be changed.
+// overwrite says whether the change replaces contents the path already has,
+// as renaming over it does.
+func (rs *Rules) CheckModify(p string, overwrite bool) error {
+	switch action, _ := rs.Match(p); action {
+	case ReadOnly:
+		return fmt.Errorf("%q is read-only: %w", p, syscall.EROFS)
+	case NoOverwrite:
+		if overwrite {
+			return fmt.Errorf("%q may not be overwritten: %w", p, syscall.EACCES)
+		}
+	}
+	return nil
+}
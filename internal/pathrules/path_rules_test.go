@@ -0,0 +1,151 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pathrules
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_NoRules(t *testing.T) {
+	rs, err := Parse(nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, rs)
+	_, ok := rs.Match("foo")
+	assert.False(t, ok)
+}
+
+func TestMatch(t *testing.T) {
+	rs, err := Parse([]string{
+		"hidden=golden/.staging",
+		"read-only=golden",
+		"no-delete=*/logs",
+		`no-overwrite=regex:\.ckpt$`,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path       string
+		wantAction Action
+		wantOk     bool
+	}{
+		{path: "", wantOk: false},
+		{path: "golden", wantAction: ReadOnly, wantOk: true},
+		{path: "golden/a/b", wantAction: ReadOnly, wantOk: true},
+		{path: "golden/.staging/a", wantAction: Hidden, wantOk: true},
+		{path: "goldenrod", wantOk: false},
+		{path: "jobs/logs/today", wantAction: NoDelete, wantOk: true},
+		{path: "jobs/x/logs", wantOk: false},
+		{path: "jobs/model.ckpt", wantAction: NoOverwrite, wantOk: true},
+		{path: "golden/model.ckpt", wantAction: ReadOnly, wantOk: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			action, ok := rs.Match(tc.path)
+
+			assert.Equal(t, tc.wantOk, ok)
+			assert.Equal(t, tc.wantAction, action)
+		})
+	}
+}
+
+func TestChecks(t *testing.T) {
+	rs, err := Parse([]string{"read-only=ro", "hidden=hid", "no-delete=nd", "no-overwrite=now"})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, rs.CheckLookUp("hid/a"), syscall.ENOENT)
+	assert.NoError(t, rs.CheckLookUp("ro/a"))
+
+	assert.ErrorIs(t, rs.CheckCreate("ro/a"), syscall.EROFS)
+	assert.ErrorIs(t, rs.CheckCreate("hid/a"), syscall.EACCES)
+	assert.NoError(t, rs.CheckCreate("nd/a"))
+	assert.NoError(t, rs.CheckCreate("now/a"))
+
+	assert.ErrorIs(t, rs.CheckDelete("ro/a"), syscall.EROFS)
+	assert.ErrorIs(t, rs.CheckDelete("nd/a"), syscall.EACCES)
+	assert.NoError(t, rs.CheckDelete("now/a"))
+
+	assert.ErrorIs(t, rs.CheckModify("ro/a", false), syscall.EROFS)
+	assert.ErrorIs(t, rs.CheckModify("now/a", true), syscall.EACCES)
+	assert.NoError(t, rs.CheckModify("now/a", false))
+	assert.NoError(t, rs.CheckModify("nd/a", true))
+}
+
+func TestCheckTree(t *testing.T) {
+	rs, err := Parse([]string{
+		"read-only=data/golden",
+		"no-delete=jobs/*/logs",
+		`hidden=regex:^jobs/[^/]+/\.staging$`,
+		"no-overwrite=ckpt",
+		`read-only=regex:(?i)^Archive/`,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		path       string
+		wantDelete error
+		wantCreate error
+	}{
+		{path: "data", wantDelete: syscall.EROFS, wantCreate: syscall.EROFS},
+		{path: "data/golden", wantDelete: syscall.EROFS, wantCreate: syscall.EROFS},
+		{path: "data/other"},
+		{path: "dat"},
+		{path: "x/y"},
+		{path: "jobs", wantDelete: syscall.EACCES, wantCreate: syscall.EACCES},
+		{path: "jobs/a", wantDelete: syscall.EACCES, wantCreate: syscall.EACCES},
+		// The regexp is taken to match anything after "jobs/".
+		{path: "jobs/a/b", wantDelete: syscall.EACCES, wantCreate: syscall.EACCES},
+		{path: "jobsite"},
+		{path: "ckpt"},
+		{path: "Archive", wantDelete: syscall.EROFS, wantCreate: syscall.EROFS},
+		{path: "a/archive"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if tc.wantDelete == nil {
+				assert.NoError(t, rs.CheckDeleteTree(tc.path))
+			} else {
+				assert.ErrorIs(t, rs.CheckDeleteTree(tc.path), tc.wantDelete)
+			}
+			if tc.wantCreate == nil {
+				assert.NoError(t, rs.CheckCreateTree(tc.path))
+			} else {
+				assert.ErrorIs(t, rs.CheckCreateTree(tc.path), tc.wantCreate)
+			}
+		})
+	}
+}
+
+func TestCheckTree_UnanchoredRegexp(t *testing.T) {
+	rs, err := Parse([]string{`read-only=regex:\.lock$`})
+	require.NoError(t, err)
+
+	// The rule can apply beneath any directory.
+	assert.ErrorIs(t, rs.CheckDeleteTree("a/b"), syscall.EROFS)
+}
+
+func TestParse_InvalidRules(t *testing.T) {
+	for _, entry := range []string{"read-only", "read-only=", "append-only=a", "hidden=[a", "hidden=regex:(a"} {
+		_, err := Parse([]string{entry})
+
+		assert.Error(t, err, entry)
+	}
+}
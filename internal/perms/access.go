@@ -0,0 +1,149 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perms
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+// Access bits, as in the permission bits of a mode.
+const (
+	Read  uint32 = 4
+	Write uint32 = 2
+	Exec  uint32 = 1
+)
+
+// Caller is the process on whose behalf an operation is made. The kernel
+// only passes on its uid and pid, so its groups are read from /proc when they
+// are first needed.
+type Caller struct {
+	Uid uint32
+	Pid uint32
+
+	groupsRead bool
+	gid        uint32
+	hasGid     bool
+	groups     []uint32
+}
+
+// NewCaller returns the caller of the operation with the supplied context.
+func NewCaller(opCtx fuseops.OpContext) *Caller {
+	return &Caller{Uid: opCtx.Uid, Pid: opCtx.Pid}
+}
+
+// IsRoot reports whether the caller is root, to whom every access is granted.
+// That includes the kernel itself, which sends ops such as the write back of
+// dirty pages with uid 0.
+func (c *Caller) IsRoot() bool {
+	return c.Uid == 0
+}
+
+// readGroups reads the file system gid and supplementary groups of the
+// caller. A caller that has exited, or whose pid isn't known, is in no
+// groups.
+func (c *Caller) readGroups() {
+	if c.groupsRead {
+		return
+	}
+	c.groupsRead = true
+	if c.Pid == 0 {
+		return
+	}
+
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", c.Pid))
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		key, value, _ := strings.Cut(s.Text(), ":")
+		fields := strings.Fields(value)
+		switch key {
+		case "Gid":
+			// Real, effective, saved set and file system gids.
+			if len(fields) == 4 {
+				if gid, err := strconv.ParseUint(fields[3], 10, 32); err == nil {
+					c.gid, c.hasGid = uint32(gid), true
+				}
+			}
+		case "Groups":
+			for _, field := range fields {
+				if gid, err := strconv.ParseUint(field, 10, 32); err == nil {
+					c.groups = append(c.groups, uint32(gid))
+				}
+			}
+		}
+	}
+}
+
+// Gid returns the gid that files created by the caller belong to, if it is
+// known.
+func (c *Caller) Gid() (uint32, bool) {
+	c.readGroups()
+	return c.gid, c.hasGid
+}
+
+// InGroup reports whether the caller is a member of the supplied group.
+func (c *Caller) InGroup(gid uint32) bool {
+	c.readGroups()
+	if c.hasGid && c.gid == gid {
+		return true
+	}
+	for _, g := range c.groups {
+		if g == gid {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOwner reports whether the caller owns the file with the supplied
+// attributes, or is root.
+func (c *Caller) IsOwner(attrs fuseops.InodeAttributes) bool {
+	return c.IsRoot() || c.Uid == attrs.Uid
+}
+
+// CheckAccess returns EACCES unless the permission bits in the supplied
+// attributes grant the caller each of the wanted access bits.
+func CheckAccess(c *Caller, attrs fuseops.InodeAttributes, want uint32) error {
+	if c.IsRoot() {
+		return nil
+	}
+
+	perm := uint32(attrs.Mode.Perm())
+	var granted uint32
+	switch {
+	case c.Uid == attrs.Uid:
+		granted = perm >> 6
+	case c.InGroup(attrs.Gid):
+		granted = perm >> 3
+	default:
+		granted = perm
+	}
+
+	if want&^granted&7 != 0 {
+		return syscall.EACCES
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package perms_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/perms"
+	"github.com/jacobsa/fuse/fuseops"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaller_ReadsGroupsOfProcess(t *testing.T) {
+	c := perms.NewCaller(fuseops.OpContext{Uid: uint32(os.Getuid()), Pid: uint32(os.Getpid())})
+
+	gid, ok := c.Gid()
+
+	require.True(t, ok)
+	assert.Equal(t, uint32(os.Getegid()), gid)
+	groups, err := os.Getgroups()
+	require.NoError(t, err)
+	for _, g := range groups {
+		assert.True(t, c.InGroup(uint32(g)), g)
+	}
+}
+
+func TestCaller_UnknownProcessIsInNoGroups(t *testing.T) {
+	c := perms.NewCaller(fuseops.OpContext{Uid: 1000})
+
+	_, ok := c.Gid()
+
+	assert.False(t, ok)
+	assert.False(t, c.InGroup(0))
+}
+
+func TestCheckAccess(t *testing.T) {
+	attrs := fuseops.InodeAttributes{Mode: 0750, Uid: 1000, Gid: 2000}
+	// Callers without a pid are in no groups.
+	owner := perms.NewCaller(fuseops.OpContext{Uid: 1000})
+	other := perms.NewCaller(fuseops.OpContext{Uid: 1001})
+	root := perms.NewCaller(fuseops.OpContext{Uid: 0})
+
+	assert.NoError(t, perms.CheckAccess(owner, attrs, perms.Read|perms.Write|perms.Exec))
+	assert.ErrorIs(t, perms.CheckAccess(other, attrs, perms.Read), syscall.EACCES)
+	assert.NoError(t, perms.CheckAccess(root, attrs, perms.Write))
+	assert.True(t, owner.IsOwner(attrs))
+	assert.False(t, other.IsOwner(attrs))
+	assert.True(t, root.IsOwner(attrs))
+
+	attrs.Mode = 0704
+	assert.NoError(t, perms.CheckAccess(other, attrs, perms.Read))
+	assert.ErrorIs(t, perms.CheckAccess(other, attrs, perms.Exec), syscall.EACCES)
+}
+
+func TestCheckAccess_Group(t *testing.T) {
+	attrs := fuseops.InodeAttributes{Mode: 0050, Uid: uint32(os.Getuid()) + 1, Gid: uint32(os.Getegid())}
+	c := perms.NewCaller(fuseops.OpContext{Uid: uint32(os.Getuid()) + 1000, Pid: uint32(os.Getpid())})
+
+	assert.NoError(t, perms.CheckAccess(c, attrs, perms.Read|perms.Exec))
+	assert.ErrorIs(t, perms.CheckAccess(c, attrs, perms.Write), syscall.EACCES)
+}
@@ -0,0 +1,363 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package renamejournal makes directory renames in flat buckets, which move
+// the objects under a directory one by one, recoverable. Before any object is
+// moved, the intent to rename is recorded both in a local journal and in an
+// object under ObjectPrefix in the bucket. A rename that is interrupted, for
+// example by a crash, can then be resumed or rolled back later, by this mount
+// or another one.
+package renamejournal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/clock"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+)
+
+// ObjectPrefix is the reserved prefix under which intent records are kept,
+// one object per rename named after its ID.
+const ObjectPrefix = ".gcsfuse_renames/"
+
+// A heartbeat is recorded on the intent record of a running rename by setting
+// this metadata key, which keeps other mounts from recovering it.
+const HeartbeatMetadataKey = "gcsfuse_rename_heartbeat"
+
+const (
+	// DefaultHeartbeatInterval is how often a running rename records a
+	// heartbeat unless configured otherwise.
+	DefaultHeartbeatInterval = time.Minute
+
+	// DefaultStaleAfter is how long a rename must go without a heartbeat before
+	// it is considered interrupted, unless configured otherwise. It leaves room
+	// for several missed heartbeats.
+	DefaultStaleAfter = 5 * time.Minute
+)
+
+// Object is an object to be moved by a rename.
+type Object struct {
+	// The name of the object relative to the old directory.
+	Name string `json:"name"`
+
+	// The generation that is moved. Later generations are left alone.
+	Generation     int64 `json:"generation"`
+	MetaGeneration int64 `json:"metageneration"`
+}
+
+// Intent is the record of a directory rename.
+type Intent struct {
+	ID string `json:"id"`
+
+	// The object names of the old and new directory, ending in a slash.
+	OldDir string `json:"old_dir"`
+	NewDir string `json:"new_dir"`
+
+	// Whether the old directory is backed by an object, which is deleted once
+	// everything beneath it has been moved.
+	OldDirExplicit bool `json:"old_dir_explicit"`
+
+	// Whether the rename created the object backing the new directory, which is
+	// deleted again by a rollback.
+	NewDirCreated bool `json:"new_dir_created"`
+
+	Started time.Time `json:"started"`
+	Objects []Object  `json:"objects"`
+
+	// When the intent record was last written or heartbeated.
+	updated time.Time
+}
+
+// Config configures a Journal.
+type Config struct {
+	// The directory holding the local journal. Each bucket has a subdirectory
+	// of its own.
+	LocalDir string
+
+	// The number of objects moved at once.
+	Parallelism int
+
+	// How often a running rename records a heartbeat on its intent record.
+	HeartbeatInterval time.Duration
+
+	// How long an intent record must have gone without a heartbeat before
+	// Recover considers the rename interrupted.
+	StaleAfter time.Duration
+
+	Clock Clock
+}
+
+// Clock tells the time, and waits for it to pass between heartbeats.
+type Clock interface {
+	clock.Clock
+	Now() time.Time
+}
+
+// Journal carries out and recovers the directory renames of a bucket.
+type Journal struct {
+	bucket gcs.Bucket
+	cfg    Config
+
+	// Serializes appends to the progress logs of the local journal.
+	logMu sync.Mutex
+}
+
+// New returns a journal for the renames in the supplied bucket.
+func New(bucket gcs.Bucket, cfg Config) *Journal {
+	return &Journal{bucket: bucket, cfg: cfg}
+}
+
+func (j *Journal) localDir() string {
+	return filepath.Join(j.cfg.LocalDir, j.bucket.Name())
+}
+
+func (j *Journal) intentPath(id string) string {
+	return filepath.Join(j.localDir(), id+".json")
+}
+
+// The progress log of an intent lists the names of the objects that have
+// been moved, one per line.
+func (j *Journal) progressPath(id string) string {
+	return filepath.Join(j.localDir(), id+".log")
+}
+
+// Begin records the intent to move the supplied objects from oldDir to newDir,
+// first in the local journal and then in the bucket. The objects must all be
+// under oldDir.
+func (j *Journal) Begin(
+	ctx context.Context,
+	oldDir, newDir string,
+	oldDirExplicit, newDirCreated bool,
+	objects []*gcs.MinObject) (*Intent, error) {
+	in := &Intent{
+		ID:             uuid.NewString(),
+		OldDir:         oldDir,
+		NewDir:         newDir,
+		OldDirExplicit: oldDirExplicit,
+		NewDirCreated:  newDirCreated,
+		Started:        j.cfg.Clock.Now().UTC(),
+		Objects:        make([]Object, 0, len(objects)),
+	}
+	for _, o := range objects {
+		name, ok := strings.CutPrefix(o.Name, oldDir)
+		if !ok {
+			return nil, fmt.Errorf("object %q is not under %q", o.Name, oldDir)
+		}
+		in.Objects = append(in.Objects, Object{
+			Name:           name,
+			Generation:     o.Generation,
+			MetaGeneration: o.MetaGeneration,
+		})
+	}
+
+	contents, err := json.Marshal(in)
+	if err != nil {
+		return nil, fmt.Errorf("marshal intent: %w", err)
+	}
+
+	if err = os.MkdirAll(j.localDir(), 0700); err != nil {
+		return nil, fmt.Errorf("create journal directory: %w", err)
+	}
+	if err = os.WriteFile(j.intentPath(in.ID), contents, 0600); err != nil {
+		return nil, fmt.Errorf("write local intent: %w", err)
+	}
+
+	var doesNotExist int64
+	if _, err = j.bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:                   ObjectPrefix + in.ID,
+		Contents:               bytes.NewReader(contents),
+		ContentType:            "application/json",
+		GenerationPrecondition: &doesNotExist,
+	}); err != nil {
+		j.removeLocal(in.ID)
+		return nil, fmt.Errorf("write intent record: %w", err)
+	}
+
+	return in, nil
+}
+
+// End forgets a rename that has been finished or rolled back.
+func (j *Journal) End(ctx context.Context, in *Intent) error {
+	if err := j.deleteObject(ctx, &gcs.DeleteObjectRequest{Name: ObjectPrefix + in.ID}); err != nil {
+		return fmt.Errorf("delete intent record: %w", err)
+	}
+
+	j.removeLocal(in.ID)
+	return nil
+}
+
+func (j *Journal) removeLocal(id string) {
+	for _, p := range []string{j.intentPath(id), j.progressPath(id)} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			logger.Warnf("Rename journal: removing %s: %v", p, err)
+		}
+	}
+}
+
+// Pending returns the renames recorded in the bucket that haven't ended,
+// whether or not they are still running.
+func (j *Journal) Pending(ctx context.Context) ([]*Intent, error) {
+	var records []*gcs.MinObject
+	objects := make(chan *gcs.MinObject)
+	listErr := make(chan error, 1)
+	go func() {
+		listErr <- storageutil.ListPrefix(ctx, j.bucket, ObjectPrefix, objects)
+		close(objects)
+	}()
+	for o := range objects {
+		records = append(records, o)
+	}
+	if err := <-listErr; err != nil {
+		return nil, err
+	}
+
+	var intents []*Intent
+	for _, o := range records {
+		contents, err := storageutil.ReadObject(ctx, j.bucket, o.Name)
+		var notFoundErr *gcs.NotFoundError
+		if errors.As(err, &notFoundErr) {
+			// Ended in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read intent record %q: %w", o.Name, err)
+		}
+
+		in := &Intent{}
+		if err = json.Unmarshal(contents, in); err != nil {
+			return nil, fmt.Errorf("parse intent record %q: %w", o.Name, err)
+		}
+		in.updated = o.Updated
+		intents = append(intents, in)
+	}
+
+	return intents, nil
+}
+
+// pruneLocal removes the local records of renames other than the supplied
+// ones. Those were either never recorded in the bucket or have ended, possibly
+// after being recovered by another mount.
+func (j *Journal) pruneLocal(pending []*Intent) {
+	ids := make(map[string]bool)
+	for _, in := range pending {
+		ids[in.ID] = true
+	}
+
+	entries, err := os.ReadDir(j.localDir())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("Rename journal: reading %s: %v", j.localDir(), err)
+		}
+		return
+	}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if ok && !ids[id] {
+			j.removeLocal(id)
+		}
+	}
+}
+
+// Stale reports whether the rename has gone without a heartbeat for long
+// enough to be considered interrupted.
+func (j *Journal) Stale(in *Intent) bool {
+	return j.cfg.Clock.Now().Sub(in.updated) >= j.cfg.StaleAfter
+}
+
+// startHeartbeat starts recording on the intent record that the rename is
+// running, every HeartbeatInterval by the clock, and returns a function that
+// stops it and waits for it to have stopped.
+func (j *Journal) startHeartbeat(ctx context.Context, in *Intent) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	// Ask for the first beat before returning, so that it falls due once the
+	// clock has advanced by the interval from now.
+	beat := j.cfg.Clock.After(j.cfg.HeartbeatInterval)
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-beat:
+			}
+
+			now := j.cfg.Clock.Now().UTC().Format(time.RFC3339)
+			if _, err := j.bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+				Name:     ObjectPrefix + in.ID,
+				Metadata: map[string]*string{HeartbeatMetadataKey: &now},
+			}); err != nil && ctx.Err() == nil {
+				logger.Warnf("Rename journal: heartbeat of %s: %v", in.ID, err)
+			}
+			beat = j.cfg.Clock.After(j.cfg.HeartbeatInterval)
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// moved returns the names of the objects of the intent that the local journal
+// records as moved.
+func (j *Journal) moved(in *Intent) map[string]bool {
+	moved := make(map[string]bool)
+	f, err := os.Open(j.progressPath(in.ID))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("Rename journal: reading progress of %s: %v", in.ID, err)
+		}
+		return moved
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		moved[scanner.Text()] = true
+	}
+
+	return moved
+}
+
+// recordMoved appends the name of a moved object to the progress log. The log
+// only saves work on resumption, so failing to write it is not an error.
+func (j *Journal) recordMoved(in *Intent, name string) {
+	j.logMu.Lock()
+	defer j.logMu.Unlock()
+
+	f, err := os.OpenFile(j.progressPath(in.ID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		logger.Warnf("Rename journal: recording progress of %s: %v", in.ID, err)
+		return
+	}
+	defer f.Close()
+
+	if _, err = fmt.Fprintln(f, name); err != nil {
+		logger.Warnf("Rename journal: recording progress of %s: %v", in.ID, err)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renamejournal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/clock"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const staleAfter = 5 * time.Minute
+
+type JournalTest struct {
+	suite.Suite
+	ctx     context.Context
+	clock   clock.SimulatedClock
+	bucket  gcs.Bucket
+	journal *Journal
+	objects []*gcs.MinObject
+}
+
+func TestJournalTestSuite(t *testing.T) {
+	suite.Run(t, new(JournalTest))
+}
+
+func (t *JournalTest) SetupTest() {
+	t.ctx = context.Background()
+	t.clock.SetTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	t.bucket = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{})
+	t.journal = t.newJournal()
+
+	contents := map[string][]byte{
+		"foo/":      nil,
+		"foo/a":     []byte("taco"),
+		"foo/b":     []byte("burrito"),
+		"foo/sub/":  nil,
+		"foo/sub/c": []byte("enchilada"),
+		"bar/":      nil,
+	}
+	require.NoError(t.T(), storageutil.CreateObjects(t.ctx, t.bucket, contents))
+	t.objects = nil
+	for _, name := range []string{"foo/a", "foo/b", "foo/sub/", "foo/sub/c"} {
+		m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: name})
+		require.NoError(t.T(), err)
+		t.objects = append(t.objects, m)
+	}
+}
+
+// newJournal returns a journal sharing the local directory of t.journal, as a
+// later mount on the same machine would.
+func (t *JournalTest) newJournal() *Journal {
+	localDir := t.T().TempDir()
+	if t.journal != nil {
+		localDir = t.journal.cfg.LocalDir
+	}
+
+	return New(t.bucket, Config{
+		LocalDir:    localDir,
+		Parallelism: 2,
+		StaleAfter:  staleAfter,
+		Clock:       &t.clock,
+	})
+}
+
+func (t *JournalTest) readObject(name string) (string, error) {
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, name)
+	return string(contents), err
+}
+
+func (t *JournalTest) assertTree(dir string) {
+	for name, expected := range map[string]string{"a": "taco", "b": "burrito", "sub/": "", "sub/c": "enchilada"} {
+		contents, err := t.readObject(dir + name)
+		require.NoError(t.T(), err, dir+name)
+		assert.Equal(t.T(), expected, contents)
+	}
+}
+
+func (t *JournalTest) assertGone(dir string) {
+	for _, name := range []string{"a", "b", "sub/", "sub/c"} {
+		_, err := t.readObject(dir + name)
+		var notFoundErr *gcs.NotFoundError
+		assert.ErrorAs(t.T(), err, &notFoundErr, dir+name)
+	}
+}
+
+func (t *JournalTest) assertNoRecords() {
+	pending, err := t.journal.Pending(t.ctx)
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), pending)
+	entries, err := os.ReadDir(t.journal.localDir())
+	require.NoError(t.T(), err)
+	assert.Empty(t.T(), entries)
+}
+
+func (t *JournalTest) TestBeginRecordsIntent() {
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, true, t.objects)
+
+	require.NoError(t.T(), err)
+	assert.FileExists(t.T(), filepath.Join(t.journal.localDir(), in.ID+".json"))
+	pending, err := t.journal.Pending(t.ctx)
+	require.NoError(t.T(), err)
+	require.Len(t.T(), pending, 1)
+	assert.Equal(t.T(), in.ID, pending[0].ID)
+	assert.Equal(t.T(), "bar/", pending[0].NewDir)
+	var names []string
+	for _, o := range pending[0].Objects {
+		names = append(names, o.Name)
+	}
+	assert.ElementsMatch(t.T(), []string{"a", "b", "sub/", "sub/c"}, names)
+}
+
+func (t *JournalTest) TestRunMovesObjects() {
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, false, t.objects)
+	require.NoError(t.T(), err)
+
+	require.NoError(t.T(), t.journal.Run(t.ctx, in))
+	require.NoError(t.T(), t.journal.End(t.ctx, in))
+
+	t.assertTree("bar/")
+	t.assertGone("foo/")
+	t.assertNoRecords()
+}
+
+func (t *JournalTest) TestRecoverResumesInterruptedRename() {
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, false, t.objects)
+	require.NoError(t.T(), err)
+	// The mount got as far as copying one object without deleting it, and
+	// moving another one, before it crashed.
+	require.NoError(t.T(), t.journal.moveObject(t.ctx, in, in.Objects[0]))
+	_, err = t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: "foo/" + in.Objects[1].Name, DstName: "bar/" + in.Objects[1].Name})
+	require.NoError(t.T(), err)
+	t.clock.AdvanceTime(staleAfter)
+	t.journal = t.newJournal()
+
+	err = t.journal.Recover(t.ctx, false)
+
+	require.NoError(t.T(), err)
+	t.assertTree("bar/")
+	t.assertGone("foo/")
+	_, err = t.readObject("foo/")
+	assert.Error(t.T(), err)
+	t.assertNoRecords()
+}
+
+func (t *JournalTest) TestRecoverRollsBackInterruptedRename() {
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, true, t.objects)
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.journal.moveObject(t.ctx, in, in.Objects[0]))
+	_, err = t.bucket.CopyObject(t.ctx, &gcs.CopyObjectRequest{SrcName: "foo/" + in.Objects[1].Name, DstName: "bar/" + in.Objects[1].Name})
+	require.NoError(t.T(), err)
+	t.clock.AdvanceTime(staleAfter)
+
+	err = t.journal.Recover(t.ctx, true)
+
+	require.NoError(t.T(), err)
+	t.assertTree("foo/")
+	t.assertGone("bar/")
+	_, err = t.readObject("bar/")
+	assert.Error(t.T(), err)
+	t.assertNoRecords()
+}
+
+func (t *JournalTest) TestRecoverLeavesRunningRenameAlone() {
+	_, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, false, t.objects)
+	require.NoError(t.T(), err)
+	t.clock.AdvanceTime(staleAfter / 2)
+
+	err = t.journal.Recover(t.ctx, false)
+
+	require.NoError(t.T(), err)
+	t.assertTree("foo/")
+	pending, err := t.journal.Pending(t.ctx)
+	require.NoError(t.T(), err)
+	assert.Len(t.T(), pending, 1)
+}
+
+func (t *JournalTest) TestHeartbeatKeepsRenameFromBeingTakenOver() {
+	t.journal.cfg.HeartbeatInterval = staleAfter / 2
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, false, t.objects)
+	require.NoError(t.T(), err)
+	stopHeartbeat := t.journal.startHeartbeat(t.ctx, in)
+	t.clock.AdvanceTime(staleAfter / 2)
+	assert.Eventually(t.T(), func() bool {
+		pending, err := t.journal.Pending(t.ctx)
+		return err == nil && len(pending) == 1 && pending[0].updated.Equal(t.clock.Now())
+	}, 5*time.Second, 10*time.Millisecond)
+	t.clock.AdvanceTime(staleAfter / 2)
+
+	// The rename started longer ago than staleAfter, but its last heartbeat
+	// wasn't.
+	require.NoError(t.T(), t.newJournal().Recover(t.ctx, false))
+	t.assertTree("foo/")
+
+	// Once the heartbeat stops, the rename is taken over.
+	stopHeartbeat()
+	t.clock.AdvanceTime(staleAfter)
+	require.NoError(t.T(), t.newJournal().Recover(t.ctx, false))
+	t.assertTree("bar/")
+	t.assertGone("foo/")
+}
+
+func (t *JournalTest) TestRecoverPrunesEndedLocalRecords() {
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, false, t.objects)
+	require.NoError(t.T(), err)
+	// Another mount finished the rename.
+	require.NoError(t.T(), t.bucket.DeleteObject(t.ctx, &gcs.DeleteObjectRequest{Name: ObjectPrefix + in.ID}))
+
+	require.NoError(t.T(), t.journal.Recover(t.ctx, false))
+
+	t.assertNoRecords()
+}
+
+func (t *JournalTest) TestRunStopsAtChangedObject() {
+	in, err := t.journal.Begin(t.ctx, "foo/", "bar/", true, false, t.objects)
+	require.NoError(t.T(), err)
+	_, err = storageutil.CreateObject(t.ctx, t.bucket, "foo/b", []byte("tostada"))
+	require.NoError(t.T(), err)
+
+	err = t.journal.Run(t.ctx, in)
+
+	assert.Error(t.T(), err)
+	contents, err := t.readObject("foo/b")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "tostada", contents)
+	_, err = t.readObject("bar/b")
+	assert.Error(t.T(), err)
+}
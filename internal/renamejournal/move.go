@@ -0,0 +1,253 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package renamejournal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"golang.org/x/sync/errgroup"
+)
+
+// Run moves the objects of the intent that haven't been moved yet to the new
+// directory, Parallelism at a time. Each object is copied on the condition
+// that nothing exists under its new name and then deleted from under its old
+// one, so Run can be repeated after an interruption. It stops at the first
+// object that has been changed by somebody else since the intent was
+// recorded.
+func (j *Journal) Run(ctx context.Context, in *Intent) error {
+	if j.cfg.HeartbeatInterval > 0 {
+		defer j.startHeartbeat(ctx, in)()
+	}
+
+	moved := j.moved(in)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(j.cfg.Parallelism)
+	for _, o := range in.Objects {
+		if moved[o.Name] {
+			continue
+		}
+
+		group.Go(func() error {
+			if err := j.moveObject(groupCtx, in, o); err != nil {
+				return fmt.Errorf("move %q: %w", in.OldDir+o.Name, err)
+			}
+			j.recordMoved(in, o.Name)
+			return nil
+		})
+	}
+
+	return group.Wait()
+}
+
+func (j *Journal) moveObject(ctx context.Context, in *Intent, o Object) error {
+	src := in.OldDir + o.Name
+	dst := in.NewDir + o.Name
+
+	var doesNotExist int64
+	_, err := j.bucket.CopyObject(ctx, &gcs.CopyObjectRequest{
+		SrcName:                       src,
+		SrcGeneration:                 o.Generation,
+		SrcMetaGenerationPrecondition: &o.MetaGeneration,
+		DstName:                       dst,
+		DstGenerationPrecondition:     &doesNotExist,
+	})
+
+	var notFoundErr *gcs.NotFoundError
+	var preconditionErr *gcs.PreconditionError
+	switch {
+	case err == nil:
+	case errors.As(err, &notFoundErr), errors.As(err, &preconditionErr):
+		// Either an earlier attempt got this far, and then the copy exists, or
+		// the source has been changed.
+		if _, _, statErr := j.bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: dst, ForceFetchFromGcs: true}); statErr != nil {
+			return fmt.Errorf("CopyObject: %w", err)
+		}
+	default:
+		return fmt.Errorf("CopyObject: %w", err)
+	}
+
+	// A missing source has been deleted by an earlier attempt.
+	if err = j.deleteObject(ctx, &gcs.DeleteObjectRequest{
+		Name:                       src,
+		Generation:                 o.Generation,
+		MetaGenerationPrecondition: &o.MetaGeneration,
+	}); err != nil {
+		return fmt.Errorf("DeleteObject: %w", err)
+	}
+
+	return nil
+}
+
+// Resume finishes an interrupted rename and ends it.
+func (j *Journal) Resume(ctx context.Context, in *Intent) error {
+	if err := j.Run(ctx, in); err != nil {
+		return err
+	}
+
+	if in.OldDirExplicit {
+		if err := j.deleteObject(ctx, &gcs.DeleteObjectRequest{Name: in.OldDir}); err != nil {
+			return fmt.Errorf("delete %q: %w", in.OldDir, err)
+		}
+	}
+
+	return j.End(ctx, in)
+}
+
+// Rollback moves the objects of a rename that have already been moved back to
+// the old directory, removes the copies of those that haven't, and ends the
+// rename.
+func (j *Journal) Rollback(ctx context.Context, in *Intent) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(j.cfg.Parallelism)
+	for _, o := range in.Objects {
+		group.Go(func() error {
+			if err := j.restoreObject(groupCtx, in, o); err != nil {
+				return fmt.Errorf("restore %q: %w", in.OldDir+o.Name, err)
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return err
+	}
+
+	if in.OldDirExplicit {
+		if err := j.ensureDirObject(ctx, in.OldDir); err != nil {
+			return err
+		}
+	}
+	if in.NewDirCreated {
+		if err := j.deleteObject(ctx, &gcs.DeleteObjectRequest{Name: in.NewDir}); err != nil {
+			return fmt.Errorf("delete %q: %w", in.NewDir, err)
+		}
+	}
+
+	return j.End(ctx, in)
+}
+
+func (j *Journal) restoreObject(ctx context.Context, in *Intent, o Object) error {
+	src := in.OldDir + o.Name
+	dst := in.NewDir + o.Name
+
+	var notFoundErr *gcs.NotFoundError
+	_, _, err := j.bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: src, ForceFetchFromGcs: true})
+	srcExists := err == nil
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return fmt.Errorf("StatObject: %w", err)
+	}
+
+	copied, _, err := j.bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: dst, ForceFetchFromGcs: true})
+	if errors.As(err, &notFoundErr) {
+		if !srcExists {
+			logger.Warnf("Rename journal: %q is neither under %q nor under %q", o.Name, in.OldDir, in.NewDir)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("StatObject: %w", err)
+	}
+
+	if !srcExists {
+		var doesNotExist int64
+		if _, err = j.bucket.CopyObject(ctx, &gcs.CopyObjectRequest{
+			SrcName:                       dst,
+			SrcGeneration:                 copied.Generation,
+			SrcMetaGenerationPrecondition: &copied.MetaGeneration,
+			DstName:                       src,
+			DstGenerationPrecondition:     &doesNotExist,
+		}); err != nil {
+			return fmt.Errorf("CopyObject: %w", err)
+		}
+	}
+
+	if err = j.deleteObject(ctx, &gcs.DeleteObjectRequest{
+		Name:                       dst,
+		Generation:                 copied.Generation,
+		MetaGenerationPrecondition: &copied.MetaGeneration,
+	}); err != nil {
+		return fmt.Errorf("DeleteObject: %w", err)
+	}
+
+	return nil
+}
+
+// deleteObject deletes an object, which is not an error if the object has
+// already been deleted.
+func (j *Journal) deleteObject(ctx context.Context, req *gcs.DeleteObjectRequest) error {
+	err := j.bucket.DeleteObject(ctx, req)
+	var notFoundErr *gcs.NotFoundError
+	if errors.As(err, &notFoundErr) {
+		return nil
+	}
+	return err
+}
+
+// ensureDirObject creates the object backing a directory unless it exists.
+func (j *Journal) ensureDirObject(ctx context.Context, name string) error {
+	var doesNotExist int64
+	_, err := j.bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:                   name,
+		Contents:               strings.NewReader(""),
+		GenerationPrecondition: &doesNotExist,
+	})
+
+	var preconditionErr *gcs.PreconditionError
+	if err != nil && !errors.As(err, &preconditionErr) {
+		return fmt.Errorf("create %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// Recover resumes or, if rollback is set, rolls back every rename of the
+// bucket that has gone stale. It carries on past renames that fail to
+// recover, and returns the first error.
+func (j *Journal) Recover(ctx context.Context, rollback bool) error {
+	intents, err := j.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	j.pruneLocal(intents)
+
+	var firstErr error
+	for _, in := range intents {
+		if !j.Stale(in) {
+			logger.Infof("Rename journal: rename of %q to %q is still running elsewhere", in.OldDir, in.NewDir)
+			continue
+		}
+
+		if rollback {
+			logger.Infof("Rename journal: rolling back rename of %q to %q", in.OldDir, in.NewDir)
+			err = j.Rollback(ctx, in)
+		} else {
+			logger.Infof("Rename journal: resuming rename of %q to %q", in.OldDir, in.NewDir)
+			err = j.Resume(ctx, in)
+		}
+		if err != nil {
+			err = fmt.Errorf("recover rename %s: %w", in.ID, err)
+			logger.Errorf("Rename journal: %v", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
@@ -43,6 +43,11 @@ type bucketHandle struct {
 	bucketType           *gcs.BucketType
 	controlClient        StorageControlClient
 	finalizeFileForRapid bool
+
+	// The client and billing project the bucket was opened with, used to reach
+	// the source bucket of a cross-bucket copy.
+	client         *storage.Client
+	billingProject string
 }
 
 func (bh *bucketHandle) Name() string {
@@ -311,7 +316,14 @@ func (bh *bucketHandle) CopyObject(ctx context.Context, req *gcs.CopyObjectReque
 		err = gcs.GetGCSError(err)
 	}()
 
-	srcObj := bh.bucket.Object(req.SrcName)
+	srcBucket := bh.bucket
+	if req.SrcBucket != "" && req.SrcBucket != bh.bucketName {
+		srcBucket = bh.client.Bucket(req.SrcBucket)
+		if bh.billingProject != "" {
+			srcBucket = srcBucket.UserProject(bh.billingProject)
+		}
+	}
+	srcObj := srcBucket.Object(req.SrcName)
 	dstObj := bh.bucket.Object(req.DstName)
 
 	// Switching to the requested generation of source object.
@@ -324,6 +336,16 @@ func (bh *bucketHandle) CopyObject(ctx context.Context, req *gcs.CopyObjectReque
 		srcObj = srcObj.If(storage.Conditions{MetagenerationMatch: *req.SrcMetaGenerationPrecondition})
 	}
 
+	// Putting a condition on the generation of the destination, zero meaning
+	// that it must not exist.
+	if req.DstGenerationPrecondition != nil {
+		if *req.DstGenerationPrecondition == 0 {
+			dstObj = dstObj.If(storage.Conditions{DoesNotExist: true})
+		} else {
+			dstObj = dstObj.If(storage.Conditions{GenerationMatch: *req.DstGenerationPrecondition})
+		}
+	}
+
 	objAttrs, err := dstObj.CopierFrom(srcObj).Run(ctx)
 
 	if err != nil {
@@ -362,6 +384,7 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		IncludeTrailingDelimiter: req.IncludeTrailingDelimiter,
 		IncludeFoldersAsPrefixes: req.IncludeFoldersAsPrefixes,
 		StartOffset:              req.StartOffset,
+		Versions:                 req.Versions,
 		//MaxResults: , (Field not present in storage.Query of Go Storage Library but present in ListObjectsQuery in Jacobsa code.)
 	}
 	minObjAttrs := []string{"Name", "Size", "Generation", "Metageneration", "Updated", "Metadata", "ContentEncoding", "CRC32C"}
@@ -370,6 +393,9 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		// For objects in regional buckets, this field will be *unset*.
 		minObjAttrs = append(minObjAttrs, "Finalized")
 	}
+	if req.Versions {
+		minObjAttrs = append(minObjAttrs, "Created", "Deleted")
+	}
 	err = query.SetAttrSelection(minObjAttrs)
 
 	if err != nil {
@@ -405,6 +431,10 @@ func (bh *bucketHandle) ListObjects(ctx context.Context, req *gcs.ListObjectsReq
 		} else {
 			// Converting attrs to *Object type.
 			currMinObject := storageutil.ObjectAttrsToMinObject(attrs)
+			if req.Versions {
+				// For noncurrent generations, Deleted is when they became noncurrent.
+				currMinObject.Lifetime = &gcs.Lifetime{Created: attrs.Created, Noncurrent: attrs.Deleted}
+			}
 			list.MinObjects = append(list.MinObjects, currMinObject)
 		}
 
@@ -455,6 +455,11 @@ func (b *fastStatBucket) ListObjects(
 		return
 	}
 
+	// Noncurrent generations must not shadow the live objects in the cache.
+	if req.Versions {
+		return
+	}
+
 	if b.BucketType().Hierarchical {
 		b.insertHierarchicalListing(ctx, listing)
 		return
@@ -45,6 +45,27 @@ func NewFakeBucket(clock timeutil.Clock, name string, bucketType gcs.BucketType)
 	return b
 }
 
+// LinkBuckets lets the supplied fake buckets, which must have been created by
+// NewFakeBucket, act as the source of each other's cross-bucket copies.
+func LinkBuckets(buckets ...gcs.Bucket) {
+	peers := make(map[string]*bucket)
+	for _, b := range buckets {
+		fb := b.(*bucket)
+		peers[fb.name] = fb
+		fb.peers = peers
+	}
+}
+
+// EnableVersioning makes the supplied fake bucket, which must have been created
+// by NewFakeBucket, keep the generations that objects are overwritten or
+// deleted with as noncurrent versions, as a bucket with object versioning does.
+func EnableVersioning(b gcs.Bucket) {
+	fb := b.(*bucket)
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+	fb.versioned = true
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Helper types
 ////////////////////////////////////////////////////////////////////////
@@ -52,6 +73,9 @@ func NewFakeBucket(clock timeutil.Clock, name string, bucketType gcs.BucketType)
 type fakeObject struct {
 	metadata gcs.Object
 	data     []byte
+
+	// When the generation was created.
+	created time.Time
 }
 
 // A slice of objects compared by name.
@@ -179,6 +203,18 @@ type bucket struct {
 	//
 	// INVARIANT: This is an upper bound for generation numbers in objects.
 	prevGeneration int64 // GUARDED_BY(mu)
+
+	// The buckets this one can copy objects from, by name, as set up by
+	// LinkBuckets. Constant after linking.
+	peers map[string]*bucket
+
+	// Whether replaced and deleted generations are kept in noncurrent.
+	versioned bool // GUARDED_BY(mu)
+
+	// The generations that are no longer live, if versioned.
+	//
+	// INVARIANT: Sorted by name, then by generation.
+	noncurrent fakeObjectSlice // GUARDED_BY(mu)
 }
 
 func checkName(name string) (err error) {
@@ -218,7 +254,7 @@ func (b *bucket) checkInvariants() {
 	}
 
 	// Make sure prevGeneration is an upper bound for object generation numbers.
-	for _, o := range b.objects {
+	for _, o := range append(b.objects[:len(b.objects):len(b.objects)], b.noncurrent...) {
 		if !(o.metadata.Generation <= b.prevGeneration) {
 			panic(
 				fmt.Sprintf(
@@ -261,6 +297,7 @@ func (b *bucket) mintObject(
 
 	// Set up data.
 	o.data = contents
+	o.created = o.metadata.Updated
 
 	return
 }
@@ -277,6 +314,39 @@ func (b *bucket) mintFolder(folderName string) (f gcs.Folder) {
 	return
 }
 
+// Keep the supplied generation, which is no longer live, if the bucket is
+// versioned.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) retire(o fakeObject) {
+	if !b.versioned {
+		return
+	}
+
+	o.metadata.Deleted = b.clock.Now()
+	b.noncurrent = append(b.noncurrent, o)
+	// Generations only grow, so a stable sort by name keeps each name's
+	// generations in order.
+	sort.Stable(b.noncurrent)
+}
+
+// Return the noncurrent generation of the named object, if any.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) findNoncurrent(name string, generation int64) (o fakeObject, ok bool) {
+	for i := b.noncurrent.lowerBound(name); i < len(b.noncurrent); i++ {
+		o = b.noncurrent[i]
+		if o.metadata.Name != name {
+			break
+		}
+		if o.metadata.Generation == generation {
+			return o, true
+		}
+	}
+
+	return fakeObject{}, false
+}
+
 // In a hierarchical bucket, all directory objects are also retained as folder entries,
 // even if we create objects with non-control client API.
 // Therefore, whenever we create directory objects in the fake bucket,
@@ -412,6 +482,7 @@ func createOrUpdateFakeObject(b *bucket, req *gcs.CreateObjectRequest, contents
 			content = contents
 		}
 		fo = b.mintObject(req, content)
+		b.retire(b.objects[existingIndex])
 		b.objects[existingIndex] = fo
 	} else {
 		fo = b.mintObject(req, contents)
@@ -447,32 +518,30 @@ func (b *bucket) createObjectLocked(
 	return createOrUpdateFakeObject(b, req, contents, false)
 }
 
-// Create a reader based on the supplied request, also returning the index
-// within b.objects of the entry for the requested generation.
+// Create a reader based on the supplied request, also returning the entry for
+// the requested generation.
 //
 // LOCKS_REQUIRED(b.mu)
 func (b *bucket) newReaderLocked(
-	req *gcs.ReadObjectRequest) (r io.Reader, index int, err error) {
+	req *gcs.ReadObjectRequest) (r io.Reader, o fakeObject, err error) {
 	// Find the object with the requested name.
-	index = b.objects.find(req.Name)
-	if index == len(b.objects) {
-		err = &gcs.NotFoundError{
-			Err: fmt.Errorf("object %s not found", req.Name),
-		}
-
-		return
+	index := b.objects.find(req.Name)
+	if index < len(b.objects) {
+		o = b.objects[index]
 	}
 
-	o := b.objects[index]
+	// Does the generation match? A noncurrent one will do.
+	if index == len(b.objects) ||
+		(req.Generation != 0 && req.Generation != o.metadata.Generation) {
+		var ok bool
+		if o, ok = b.findNoncurrent(req.Name, req.Generation); !ok {
+			err = &gcs.NotFoundError{
+				Err: fmt.Errorf(
+					"object %s generation %v not found", req.Name, req.Generation),
+			}
 
-	// Does the generation match?
-	if req.Generation != 0 && req.Generation != o.metadata.Generation {
-		err = &gcs.NotFoundError{
-			Err: fmt.Errorf(
-				"object %s generation %v not found", req.Name, req.Generation),
+			return
 		}
-
-		return
 	}
 
 	// Extract the requested range.
@@ -572,6 +641,11 @@ func (b *bucket) ListObjects(
 		maxResults = 1000
 	}
 
+	if req.Versions {
+		b.listVersionsLocked(req, maxResults, listing)
+		return
+	}
+
 	// Find where in the space of object names to start.
 	nameStart := req.Prefix
 	if req.ContinuationToken != "" && req.ContinuationToken > nameStart {
@@ -675,6 +749,55 @@ func (b *bucket) ListObjects(
 	return
 }
 
+// Fill in a listing of every generation of the objects matching the supplied
+// request. A page ends only between names, so that the generations of an
+// object are never split across pages.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) listVersionsLocked(
+	req *gcs.ListObjectsRequest,
+	maxResults int,
+	listing *gcs.Listing) {
+	all := append(b.noncurrent[:len(b.noncurrent):len(b.noncurrent)], b.objects...)
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].metadata.Name != all[j].metadata.Name {
+			return all[i].metadata.Name < all[j].metadata.Name
+		}
+		return all[i].metadata.Generation < all[j].metadata.Generation
+	})
+
+	nameStart := max(req.Prefix, req.ContinuationToken, req.StartOffset)
+	var lastName string
+	for i := all.lowerBound(nameStart); i < len(all); i++ {
+		name := all[i].metadata.Name
+		if !strings.HasPrefix(name, req.Prefix) {
+			break
+		}
+
+		if req.Delimiter != "" {
+			delimiterIndex := strings.Index(name[len(req.Prefix):], req.Delimiter)
+			if delimiterIndex >= 0 {
+				resultPrefix := name[:len(req.Prefix)+delimiterIndex+len(req.Delimiter)]
+				if len(listing.CollapsedRuns) == 0 ||
+					listing.CollapsedRuns[len(listing.CollapsedRuns)-1] != resultPrefix {
+					listing.CollapsedRuns = append(listing.CollapsedRuns, resultPrefix)
+				}
+				continue
+			}
+		}
+
+		if len(listing.MinObjects) >= maxResults && name != lastName {
+			listing.ContinuationToken = name
+			return
+		}
+
+		m := copyMinObject(&all[i].metadata)
+		m.Lifetime = &gcs.Lifetime{Created: all[i].created, Noncurrent: all[i].metadata.Deleted}
+		listing.MinObjects = append(listing.MinObjects, m)
+		lastName = name
+	}
+}
+
 // LOCKS_EXCLUDED(b.mu)
 func (b *bucket) NewReaderWithReadHandle(ctx context.Context, req *gcs.ReadObjectRequest) (rd gcs.StorageReader, err error) {
 	b.mu.Lock()
@@ -755,15 +878,89 @@ func (b *bucket) FinalizeUpload(ctx context.Context, w gcs.Writer) (*gcs.MinObje
 func (b *bucket) CopyObject(
 	ctx context.Context,
 	req *gcs.CopyObjectRequest) (o *gcs.Object, err error) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
 	// Check that the destination name is legal.
 	err = checkName(req.DstName)
 	if err != nil {
 		return
 	}
 
+	// Find the source in another bucket before taking our own lock, so that
+	// copies in opposite directions can't deadlock.
+	var src fakeObject
+	if req.SrcBucket != "" && req.SrcBucket != b.name {
+		peer, ok := b.peers[req.SrcBucket]
+		if !ok {
+			err = &gcs.NotFoundError{
+				Err: fmt.Errorf("bucket %q not found", req.SrcBucket),
+			}
+
+			return
+		}
+
+		peer.mu.Lock()
+		src, err = peer.copySource(req)
+		peer.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if req.SrcBucket == "" || req.SrcBucket == b.name {
+		src, err = b.copySource(req)
+		if err != nil {
+			return
+		}
+	}
+
+	// Does the destination have the correct generation?
+	existingIndex := b.objects.find(req.DstName)
+	if req.DstGenerationPrecondition != nil {
+		var existingGen int64
+		if existingIndex < len(b.objects) {
+			existingGen = b.objects[existingIndex].metadata.Generation
+		}
+
+		if existingGen != *req.DstGenerationPrecondition {
+			err = &gcs.PreconditionError{
+				Err: fmt.Errorf(
+					"object %q has generation %d", req.DstName, existingGen),
+			}
+
+			return
+		}
+	}
+
+	// Copy it and assign a new generation number, to ensure that the generation
+	// number for the destination name is strictly increasing.
+	dst := src
+	dst.metadata.Name = req.DstName
+	dst.metadata.MediaLink = "http://localhost/download/storage/fake/" + req.DstName
+	dst.created = b.clock.Now()
+
+	b.prevGeneration++
+	dst.metadata.Generation = b.prevGeneration
+
+	// Insert into our array.
+	if existingIndex < len(b.objects) {
+		b.retire(b.objects[existingIndex])
+		b.objects[existingIndex] = dst
+	} else {
+		b.objects = append(b.objects, dst)
+		sort.Sort(b.objects)
+	}
+
+	o = copyObject(&dst.metadata)
+	return
+}
+
+// copySource returns the source object of the supplied copy request, having
+// checked its preconditions.
+//
+// LOCKS_REQUIRED(b.mu)
+func (b *bucket) copySource(req *gcs.CopyObjectRequest) (src fakeObject, err error) {
 	// Does the object exist?
 	srcIndex := b.objects.find(req.SrcName)
 	if srcIndex == len(b.objects) {
@@ -800,25 +997,7 @@ func (b *bucket) CopyObject(
 		}
 	}
 
-	// Copy it and assign a new generation number, to ensure that the generation
-	// number for the destination name is strictly increasing.
-	dst := b.objects[srcIndex]
-	dst.metadata.Name = req.DstName
-	dst.metadata.MediaLink = "http://localhost/download/storage/fake/" + req.DstName
-
-	b.prevGeneration++
-	dst.metadata.Generation = b.prevGeneration
-
-	// Insert into our array.
-	existingIndex := b.objects.find(req.DstName)
-	if existingIndex < len(b.objects) {
-		b.objects[existingIndex] = dst
-	} else {
-		b.objects = append(b.objects, dst)
-		sort.Sort(b.objects)
-	}
-
-	o = copyObject(&dst.metadata)
+	src = b.objects[srcIndex]
 	return
 }
 
@@ -847,9 +1026,9 @@ func (b *bucket) ComposeObjects(
 
 	for _, src := range req.Sources {
 		var r io.Reader
-		var srcIndex int
+		var srcObject fakeObject
 
-		r, srcIndex, err = b.newReaderLocked(&gcs.ReadObjectRequest{
+		r, srcObject, err = b.newReaderLocked(&gcs.ReadObjectRequest{
 			Name:       src.Name,
 			Generation: src.Generation,
 		})
@@ -859,7 +1038,7 @@ func (b *bucket) ComposeObjects(
 		}
 
 		srcReaders = append(srcReaders, r)
-		dstComponentCount += b.objects[srcIndex].metadata.ComponentCount
+		dstComponentCount += srcObject.metadata.ComponentCount
 	}
 
 	// GCS doesn't like the component count to go too high.
@@ -1048,6 +1227,7 @@ func (b *bucket) DeleteObject(
 	}
 
 	// Remove the object.
+	b.retire(b.objects[index])
 	b.objects = append(b.objects[:index], b.objects[index+1:]...)
 
 	return
@@ -1094,6 +1274,7 @@ func (b *bucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*g
 	dst := b.objects[srcIndex]
 	dst.metadata.Name = req.DstName
 	dst.metadata.MediaLink = "http://localhost/download/storage/fake/" + req.DstName
+	dst.created = b.clock.Now()
 
 	b.prevGeneration++
 	dst.metadata.Generation = b.prevGeneration
@@ -1103,6 +1284,7 @@ func (b *bucket) MoveObject(ctx context.Context, req *gcs.MoveObjectRequest) (*g
 	// Insert dest object into our array.
 	existingIndex := b.objects.find(req.DstName)
 	if existingIndex < len(b.objects) {
+		b.retire(b.objects[existingIndex])
 		b.objects[existingIndex] = dst
 	} else {
 		b.objects = append(b.objects, dst)
@@ -1205,6 +1387,9 @@ func (b *bucket) CreateFolder(ctx context.Context, folderName string) (*gcs.Fold
 }
 
 func (b *bucket) RenameFolder(ctx context.Context, folderName string, destinationFolderId string) (*gcs.Folder, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	// Check that the destination name is legal.
 	err := checkName(destinationFolderId)
 	if err != nil {
@@ -15,13 +15,18 @@
 package fake
 
 import (
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	gcstesting "github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake/testing"
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
 	"github.com/jacobsa/ogletest"
 	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 )
 
@@ -42,3 +47,85 @@ func init() {
 
 	gcstesting.RegisterBucketTests(makeDeps)
 }
+
+func TestLinkedBucketsCopy(t *testing.T) {
+	ctx := context.Background()
+	clock := &timeutil.SimulatedClock{}
+	src := NewFakeBucket(clock, "src_bucket", gcs.BucketType{})
+	dst := NewFakeBucket(clock, "dst_bucket", gcs.BucketType{})
+	unlinked := NewFakeBucket(clock, "unlinked_bucket", gcs.BucketType{})
+	LinkBuckets(src, dst)
+	o, err := src.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     "foo",
+		Contents: strings.NewReader("taco"),
+	})
+	require.NoError(t, err)
+
+	copied, err := dst.CopyObject(ctx, &gcs.CopyObjectRequest{
+		SrcBucket:     "src_bucket",
+		SrcName:       "foo",
+		SrcGeneration: o.Generation,
+		DstName:       "bar",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "bar", copied.Name)
+	assert.Equal(t, uint64(len("taco")), copied.Size)
+	_, _, err = src.StatObject(ctx, &gcs.StatObjectRequest{Name: "foo"})
+	assert.NoError(t, err)
+	_, err = unlinked.CopyObject(ctx, &gcs.CopyObjectRequest{
+		SrcBucket: "src_bucket",
+		SrcName:   "foo",
+		DstName:   "bar",
+	})
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t, err, &notFoundErr)
+}
+
+func TestVersionedBucket(t *testing.T) {
+	ctx := context.Background()
+	b := NewFakeBucket(&timeutil.SimulatedClock{}, "some_bucket", gcs.BucketType{})
+	EnableVersioning(b)
+	var gens []int64
+	for _, contents := range []string{"taco", "burrito", "enchilada"} {
+		o, err := b.CreateObject(ctx, &gcs.CreateObjectRequest{
+			Name:     "foo",
+			Contents: strings.NewReader(contents),
+		})
+		require.NoError(t, err)
+		gens = append(gens, o.Generation)
+	}
+	_, err := b.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     "foo/bar",
+		Contents: strings.NewReader(""),
+	})
+	require.NoError(t, err)
+	require.NoError(t, b.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: "foo"}))
+
+	listing, err := b.ListObjects(ctx, &gcs.ListObjectsRequest{
+		Prefix:    "foo",
+		Delimiter: "/",
+		Versions:  true,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, listing.MinObjects, 3)
+	for i, o := range listing.MinObjects {
+		assert.Equal(t, "foo", o.Name)
+		assert.Equal(t, gens[i], o.Generation)
+	}
+	assert.Equal(t, []string{"foo/"}, listing.CollapsedRuns)
+	// The live generation is gone, but the older ones can still be read.
+	_, err = storageutil.ReadObject(ctx, b, "foo")
+	assert.Error(t, err)
+	r, err := b.NewReaderWithReadHandle(ctx, &gcs.ReadObjectRequest{Name: "foo", Generation: gens[1]})
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "burrito", string(data))
+	// Plain listings leave the noncurrent generations out.
+	listing, err = b.ListObjects(ctx, &gcs.ListObjectsRequest{Prefix: "foo"})
+	require.NoError(t, err)
+	require.Len(t, listing.MinObjects, 1)
+	assert.Equal(t, "foo/bar", listing.MinObjects[0].Name)
+}
@@ -1745,6 +1745,82 @@ func (t *copyTest) SrcMetaGenerationPrecondition_Satisfied() {
 	ExpectEq(nil, err)
 }
 
+func (t *copyTest) DstGenerationPrecondition_Unsatisfied() {
+	var err error
+
+	// Create a source and a destination object.
+	_, err = t.bucket.CreateObject(
+		t.ctx,
+		&gcs.CreateObjectRequest{
+			Name:     "foo",
+			Contents: strings.NewReader("taco"),
+		})
+
+	AssertEq(nil, err)
+
+	dst, err := t.bucket.CreateObject(
+		t.ctx,
+		&gcs.CreateObjectRequest{
+			Name:     "bar",
+			Contents: strings.NewReader("burrito"),
+		})
+
+	AssertEq(nil, err)
+
+	// Attempt to copy, requiring that the destination not exist.
+	var precond int64
+	req := &gcs.CopyObjectRequest{
+		SrcName:                   "foo",
+		DstName:                   "bar",
+		DstGenerationPrecondition: &precond,
+	}
+
+	_, err = t.bucket.CopyObject(t.ctx, req)
+	AssertThat(err, HasSameTypeAs(&gcs.PreconditionError{}))
+
+	// The destination should be unchanged.
+	m, _, err := t.bucket.StatObject(
+		t.ctx,
+		&gcs.StatObjectRequest{Name: "bar"})
+
+	AssertEq(nil, err)
+	ExpectEq(dst.Generation, m.Generation)
+}
+
+func (t *copyTest) DstGenerationPrecondition_Satisfied() {
+	var err error
+
+	// Create a source and a destination object.
+	_, err = t.bucket.CreateObject(
+		t.ctx,
+		&gcs.CreateObjectRequest{
+			Name:     "foo",
+			Contents: strings.NewReader("taco"),
+		})
+
+	AssertEq(nil, err)
+
+	dst, err := t.bucket.CreateObject(
+		t.ctx,
+		&gcs.CreateObjectRequest{
+			Name:     "bar",
+			Contents: strings.NewReader("burrito"),
+		})
+
+	AssertEq(nil, err)
+
+	// Copy, with a precondition on the destination's generation.
+	req := &gcs.CopyObjectRequest{
+		SrcName:                   "foo",
+		DstName:                   "bar",
+		DstGenerationPrecondition: &dst.Generation,
+	}
+
+	o, err := t.bucket.CopyObject(t.ctx, req)
+	AssertEq(nil, err)
+	ExpectEq(len("taco"), o.Size)
+}
+
 ////////////////////////////////////////////////////////////////////////
 // Compose
 ////////////////////////////////////////////////////////////////////////
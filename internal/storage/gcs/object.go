@@ -91,6 +91,18 @@ type MinObject struct {
 	Metadata        map[string]string
 	ContentEncoding string
 	CRC32C          *uint32 // Missing for CMEK buckets
+
+	// Set only in listings that include noncurrent generations.
+	Lifetime *Lifetime
+}
+
+// Lifetime is the span of time during which a generation of an object was the
+// live one.
+type Lifetime struct {
+	Created time.Time
+
+	// When the generation was overwritten or deleted. Zero if it is still live.
+	Noncurrent time.Time
 }
 
 // ExtendedObjectAttributes contains the missing attributes of Object which are not present in MinObject.
@@ -107,6 +107,10 @@ type CopyObjectRequest struct {
 	SrcName string
 	DstName string
 
+	// The bucket holding the source object, or empty for the bucket the request
+	// is made to. The copy is carried out by GCS in either case.
+	SrcBucket string
+
 	// The generation of the source object to copy, or zero for the latest
 	// generation.
 	SrcGeneration int64
@@ -367,6 +371,11 @@ type ListObjectsRequest struct {
 	// StartOffset is used to filter results to objects whose names are
 	// lexicographically equal to or after startOffset.
 	StartOffset string
+
+	// Versions asks for every generation of each object, noncurrent ones
+	// included, rather than only the live one. Generations of the same name are
+	// listed in increasing order, with their Lifetime set.
+	Versions bool
 }
 
 // Listing contains a set of objects and delimter-based collapsed runs returned
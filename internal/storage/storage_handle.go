@@ -517,6 +517,8 @@ func (sh *storageClient) BucketHandle(ctx context.Context, bucketName string, bi
 	bh = &bucketHandle{
 		bucket:               storageBucketHandle,
 		bucketName:           bucketName,
+		client:               client,
+		billingProject:       billingProject,
 		controlClient:        controlClient,
 		bucketType:           bucketType,
 		finalizeFileForRapid: finalizeFileForRapid,
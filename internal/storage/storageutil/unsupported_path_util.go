@@ -47,3 +47,67 @@ func IsUnsupportedPath(name string) bool {
 	}
 	return slices.Contains(unsupportedPathNames, name)
 }
+
+// EscapeChar begins the path segments escaped by EscapeUnsupportedPath.
+const EscapeChar = "%"
+
+var (
+	segmentEscaper   = strings.NewReplacer("%", "%25", ".", "%2E")
+	segmentUnescaper = strings.NewReplacer("%25", "%", "%2E", ".")
+)
+
+func escapeSegment(s string) string {
+	switch {
+	case s == "":
+		return EscapeChar
+	case s == "." || s == ".." || strings.HasPrefix(s, EscapeChar):
+		return segmentEscaper.Replace(s)
+	default:
+		return s
+	}
+}
+
+// mapSegments applies f to each path segment of name, leaving alone the empty
+// segment after the trailing slash of a directory name.
+func mapSegments(name string, f func(string) (string, bool)) (string, bool) {
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		if i == len(segments)-1 && s == "" {
+			break
+		}
+		var ok bool
+		if segments[i], ok = f(s); !ok {
+			return "", false
+		}
+	}
+	return strings.Join(segments, "/"), true
+}
+
+// EscapeUnsupportedPath returns the name under which the object with the
+// supplied name is shown when unsupported paths are escaped. Each empty, "."
+// or ".." path segment of the name is escaped, as is each segment beginning
+// with EscapeChar so that the mapping can be reversed: "a//b" becomes "a/%/b"
+// and "../c" becomes "%2E%2E/c".
+func EscapeUnsupportedPath(name string) string {
+	escaped, _ := mapSegments(name, func(s string) (string, bool) {
+		return escapeSegment(s), true
+	})
+	return escaped
+}
+
+// UnescapeUnsupportedPath returns the name of the object that is shown under
+// the supplied name when unsupported paths are escaped. It reports false if
+// a segment of the name begins with EscapeChar without being one that
+// EscapeUnsupportedPath produces.
+func UnescapeUnsupportedPath(name string) (string, bool) {
+	return mapSegments(name, func(s string) (string, bool) {
+		if !strings.HasPrefix(s, EscapeChar) {
+			return s, true
+		}
+		var unescaped string
+		if s != EscapeChar {
+			unescaped = segmentUnescaper.Replace(s)
+		}
+		return unescaped, escapeSegment(unescaped) == s
+	})
+}
@@ -107,3 +107,44 @@ func (ts *GcsUtilTest) TestIsUnsupportedPathName() {
 		})
 	}
 }
+
+func (ts *GcsUtilTest) TestEscapeUnsupportedPath() {
+	cases := []struct {
+		name    string
+		escaped string
+	}{
+		{name: "", escaped: ""},
+		{name: "foo/bar", escaped: "foo/bar"},
+		{name: "foo/", escaped: "foo/"},
+		{name: "foo//bar", escaped: "foo/%/bar"},
+		{name: "foo//", escaped: "foo/%/"},
+		{name: "/foo", escaped: "%/foo"},
+		{name: "/", escaped: "%/"},
+		{name: "./foo", escaped: "%2E/foo"},
+		{name: "foo/..", escaped: "foo/%2E%2E"},
+		{name: "foo/../", escaped: "foo/%2E%2E/"},
+		{name: "foo/.config", escaped: "foo/.config"},
+		{name: "%foo", escaped: "%25foo"},
+		{name: "%2E/a%b", escaped: "%252E/a%b"},
+	}
+
+	for _, tc := range cases {
+		ts.Run(fmt.Sprintf("name=%s", tc.name), func() {
+			escaped := EscapeUnsupportedPath(tc.name)
+			unescaped, ok := UnescapeUnsupportedPath(escaped)
+
+			assert.Equal(ts.T(), tc.escaped, escaped)
+			assert.False(ts.T(), IsUnsupportedPath(escaped) && escaped != "")
+			assert.True(ts.T(), ok)
+			assert.Equal(ts.T(), tc.name, unescaped)
+		})
+	}
+}
+
+func (ts *GcsUtilTest) TestUnescapeUnsupportedPath_NotEscaped() {
+	for _, name := range []string{"%zz", "foo/%41", "%2F", "%%"} {
+		_, ok := UnescapeUnsupportedPath(name)
+
+		assert.False(ts.T(), ok, name)
+	}
+}
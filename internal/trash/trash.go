@@ -0,0 +1,434 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trash moves objects that are unlinked in trash mode under a trash
+// prefix of their bucket, rather than deleting them, and lists, restores and
+// purges them there.
+//
+// An object named foo/bar that is trashed at time T is moved to
+// <prefix><T>/foo/bar, where T is formatted so that the trash lists in the
+// order in which objects were trashed. Its original name and T are also
+// recorded in its metadata.
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/logger"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/jacobsa/timeutil"
+)
+
+const (
+	// OriginalNameMetadataKey records the name a trashed object had before it
+	// was trashed.
+	OriginalNameMetadataKey = "gcsfuse_trash_original_name"
+
+	// DeletedMetadataKey records when an object was trashed, in RFC 3339
+	// format.
+	DeletedMetadataKey = "gcsfuse_trash_deleted"
+)
+
+// The layout of the time in the names of trashed objects. Unlike RFC 3339,
+// it has a fixed width and so sorts in time order.
+const stampLayout = "20060102T150405.000000000Z"
+
+// ErrExists is returned when restoring an object whose original name has been
+// taken since.
+var ErrExists = errors.New("an object with the original name exists")
+
+// Entry is an object in the trash.
+type Entry struct {
+	// The name of the object in the trash.
+	Name string
+
+	// The name of the object before it was trashed.
+	OriginalName string
+
+	// When the object was trashed.
+	Deleted time.Time
+
+	Size           uint64
+	Generation     int64
+	MetaGeneration int64
+}
+
+// Trash is the trash of a bucket.
+type Trash struct {
+	bucket gcs.Bucket
+	prefix string
+	clock  timeutil.Clock
+}
+
+// New returns the trash kept under the supplied prefix, which ends in a slash,
+// of the bucket.
+func New(bucket gcs.Bucket, prefix string, clock timeutil.Clock) *Trash {
+	return &Trash{
+		bucket: bucket,
+		prefix: prefix,
+		clock:  clock,
+	}
+}
+
+// Contains reports whether the named object is in the trash, or is the object
+// backing the trash directory itself.
+func (t *Trash) Contains(name string) bool {
+	return strings.HasPrefix(name, t.prefix)
+}
+
+// Move moves the latest generation of the named object into the trash. It is
+// not an error if the object doesn't exist.
+func (t *Trash) Move(ctx context.Context, name string) error {
+	return t.move(ctx, name, t.clock.Now().UTC())
+}
+
+// MoveDir moves the objects under the named directory, which ends in a slash,
+// into the trash, the directory's own object included, all as trashed at the
+// same time. A hierarchical bucket's directory is a folder, which may not have
+// an object, so there the directory is recorded in the trash by an empty
+// object of its own and the folder is then deleted.
+func (t *Trash) MoveDir(ctx context.Context, name string) error {
+	deleted := t.clock.Now().UTC()
+	hierarchical := t.bucket.BucketType().Hierarchical
+
+	req := &gcs.ListObjectsRequest{Prefix: name}
+	for {
+		listing, err := t.bucket.ListObjects(ctx, req)
+		if err != nil {
+			return fmt.Errorf("ListObjects: %w", err)
+		}
+		for _, m := range listing.MinObjects {
+			if t.Contains(m.Name) || (hierarchical && m.Name == name) {
+				continue
+			}
+			if err = t.move(ctx, m.Name, deleted); err != nil {
+				return fmt.Errorf("move %q: %w", m.Name, err)
+			}
+		}
+		if listing.ContinuationToken == "" {
+			break
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+
+	if !hierarchical {
+		return nil
+	}
+
+	deletedStr := deleted.Format(time.RFC3339Nano)
+	var doesNotExist int64
+	_, err := t.bucket.CreateObject(ctx, &gcs.CreateObjectRequest{
+		Name:     t.trashedName(name, deleted),
+		Contents: strings.NewReader(""),
+		Metadata: map[string]string{
+			OriginalNameMetadataKey: name,
+			DeletedMetadataKey:      deletedStr,
+		},
+		GenerationPrecondition: &doesNotExist,
+	})
+	if err != nil {
+		return fmt.Errorf("CreateObject: %w", err)
+	}
+
+	// As when deleting a directory, the folder may or may not have an object.
+	var notFoundErr *gcs.NotFoundError
+	_ = t.bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: name})
+	if err = t.bucket.DeleteFolder(ctx, name); err != nil && !errors.As(err, &notFoundErr) {
+		return fmt.Errorf("DeleteFolder: %w", err)
+	}
+	return nil
+}
+
+// trashedName returns the name in the trash of the named object trashed at
+// the supplied time.
+func (t *Trash) trashedName(name string, deleted time.Time) string {
+	return t.prefix + deleted.Format(stampLayout) + "/" + name
+}
+
+func (t *Trash) move(ctx context.Context, name string, deleted time.Time) error {
+	var notFoundErr *gcs.NotFoundError
+	m, _, err := t.bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: name, ForceFetchFromGcs: true})
+	if errors.As(err, &notFoundErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("StatObject: %w", err)
+	}
+
+	dst := t.trashedName(name, deleted)
+	hierarchical := t.bucket.BucketType().Hierarchical
+
+	// Hierarchical buckets move objects atomically. Elsewhere the object is
+	// copied, and the original deleted once the copy is complete.
+	if hierarchical {
+		_, err = t.bucket.MoveObject(ctx, &gcs.MoveObjectRequest{
+			SrcName:                       name,
+			DstName:                       dst,
+			SrcGeneration:                 m.Generation,
+			SrcMetaGenerationPrecondition: &m.MetaGeneration,
+		})
+		if err != nil {
+			return fmt.Errorf("MoveObject: %w", err)
+		}
+	} else {
+		var doesNotExist int64
+		_, err = t.bucket.CopyObject(ctx, &gcs.CopyObjectRequest{
+			SrcName:                       name,
+			SrcGeneration:                 m.Generation,
+			SrcMetaGenerationPrecondition: &m.MetaGeneration,
+			DstName:                       dst,
+			DstGenerationPrecondition:     &doesNotExist,
+		})
+		if err != nil {
+			return fmt.Errorf("CopyObject: %w", err)
+		}
+	}
+
+	deletedStr := deleted.Format(time.RFC3339Nano)
+	_, err = t.bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name: dst,
+		Metadata: map[string]*string{
+			OriginalNameMetadataKey: &name,
+			DeletedMetadataKey:      &deletedStr,
+		},
+	})
+	if err != nil {
+		if hierarchical {
+			// The object has been moved, and its name still tells where from.
+			logger.Warnf("Trash: recording the original name of %q: %v", dst, err)
+			return nil
+		}
+		_ = t.bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{Name: dst})
+		return fmt.Errorf("UpdateObject: %w", err)
+	}
+
+	if !hierarchical {
+		err = t.bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{
+			Name:                       name,
+			Generation:                 m.Generation,
+			MetaGenerationPrecondition: &m.MetaGeneration,
+		})
+		if err != nil && !errors.As(err, &notFoundErr) {
+			return fmt.Errorf("DeleteObject: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// entry returns the trash entry for the supplied object, or nil if the object
+// isn't a trashed one.
+func (t *Trash) entry(m *gcs.MinObject) *Entry {
+	rest, ok := strings.CutPrefix(m.Name, t.prefix)
+	if !ok {
+		return nil
+	}
+	stamp, originalName, ok := strings.Cut(rest, "/")
+	if !ok || originalName == "" {
+		return nil
+	}
+	deleted, err := time.Parse(stampLayout, stamp)
+	if err != nil {
+		return nil
+	}
+
+	// The metadata is authoritative, but may be missing if recording it failed.
+	if name, ok := m.Metadata[OriginalNameMetadataKey]; ok {
+		originalName = name
+	}
+	if d, err := time.Parse(time.RFC3339Nano, m.Metadata[DeletedMetadataKey]); err == nil {
+		deleted = d
+	}
+
+	return &Entry{
+		Name:           m.Name,
+		OriginalName:   originalName,
+		Deleted:        deleted,
+		Size:           m.Size,
+		Generation:     m.Generation,
+		MetaGeneration: m.MetaGeneration,
+	}
+}
+
+// List returns the objects in the trash, the ones trashed first first.
+func (t *Trash) List(ctx context.Context) ([]*Entry, error) {
+	var entries []*Entry
+	req := &gcs.ListObjectsRequest{Prefix: t.prefix}
+	for {
+		listing, err := t.bucket.ListObjects(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("ListObjects: %w", err)
+		}
+		for _, m := range listing.MinObjects {
+			if e := t.entry(m); e != nil {
+				entries = append(entries, e)
+			}
+		}
+		if listing.ContinuationToken == "" {
+			break
+		}
+		req.ContinuationToken = listing.ContinuationToken
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Deleted.Before(entries[j].Deleted)
+	})
+	return entries, nil
+}
+
+// Latest picks the entries to restore for the supplied original name: the one
+// trashed last for the name itself, and for each name under it, treating the
+// name as a directory. Entries are returned in the order of entries, which
+// must be that of List.
+func Latest(entries []*Entry, name string) []*Entry {
+	dir := strings.TrimSuffix(name, "/") + "/"
+	latest := make(map[string]int)
+	for i, e := range entries {
+		if e.OriginalName == name || strings.HasPrefix(e.OriginalName, dir) {
+			latest[e.OriginalName] = i
+		}
+	}
+
+	var picked []*Entry
+	for i, e := range entries {
+		if j, ok := latest[e.OriginalName]; ok && i == j {
+			picked = append(picked, e)
+		}
+	}
+	return picked
+}
+
+// Restore moves a trashed object back to its original name. It fails with
+// ErrExists if that name has been taken since.
+func (t *Trash) Restore(ctx context.Context, e *Entry) error {
+	if t.bucket.BucketType().Hierarchical && strings.HasSuffix(e.OriginalName, "/") {
+		return t.restoreFolder(ctx, e)
+	}
+
+	var err error
+	if t.bucket.BucketType().Hierarchical {
+		// MoveObject would replace an object that has taken the name.
+		var notFoundErr *gcs.NotFoundError
+		_, _, err = t.bucket.StatObject(ctx, &gcs.StatObjectRequest{Name: e.OriginalName, ForceFetchFromGcs: true})
+		if err == nil {
+			return fmt.Errorf("restore %q: %w", e.OriginalName, ErrExists)
+		}
+		if !errors.As(err, &notFoundErr) {
+			return fmt.Errorf("StatObject: %w", err)
+		}
+
+		_, err = t.bucket.MoveObject(ctx, &gcs.MoveObjectRequest{
+			SrcName:                       e.Name,
+			DstName:                       e.OriginalName,
+			SrcGeneration:                 e.Generation,
+			SrcMetaGenerationPrecondition: &e.MetaGeneration,
+		})
+		if err != nil {
+			return fmt.Errorf("MoveObject: %w", err)
+		}
+	} else {
+		var doesNotExist int64
+		var preconditionErr *gcs.PreconditionError
+		_, err = t.bucket.CopyObject(ctx, &gcs.CopyObjectRequest{
+			SrcName:                       e.Name,
+			SrcGeneration:                 e.Generation,
+			SrcMetaGenerationPrecondition: &e.MetaGeneration,
+			DstName:                       e.OriginalName,
+			DstGenerationPrecondition:     &doesNotExist,
+		})
+		if errors.As(err, &preconditionErr) {
+			return fmt.Errorf("restore %q: %w", e.OriginalName, ErrExists)
+		}
+		if err != nil {
+			return fmt.Errorf("CopyObject: %w", err)
+		}
+	}
+
+	// The restored object shouldn't look trashed.
+	_, err = t.bucket.UpdateObject(ctx, &gcs.UpdateObjectRequest{
+		Name: e.OriginalName,
+		Metadata: map[string]*string{
+			OriginalNameMetadataKey: nil,
+			DeletedMetadataKey:      nil,
+		},
+	})
+	if err != nil {
+		logger.Warnf("Trash: clearing the trash metadata of %q: %v", e.OriginalName, err)
+	}
+
+	if !t.bucket.BucketType().Hierarchical {
+		if err = t.delete(ctx, e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreFolder recreates the folder of a hierarchical bucket that MoveDir
+// recorded in the trash, and removes the record.
+func (t *Trash) restoreFolder(ctx context.Context, e *Entry) error {
+	var notFoundErr *gcs.NotFoundError
+	_, err := t.bucket.GetFolder(ctx, &gcs.GetFolderRequest{Name: e.OriginalName})
+	if err == nil {
+		return fmt.Errorf("restore %q: %w", e.OriginalName, ErrExists)
+	}
+	if !errors.As(err, &notFoundErr) {
+		return fmt.Errorf("GetFolder: %w", err)
+	}
+
+	if _, err = t.bucket.CreateFolder(ctx, e.OriginalName); err != nil {
+		return fmt.Errorf("CreateFolder: %w", err)
+	}
+	return t.delete(ctx, e)
+}
+
+func (t *Trash) delete(ctx context.Context, e *Entry) error {
+	err := t.bucket.DeleteObject(ctx, &gcs.DeleteObjectRequest{
+		Name:                       e.Name,
+		Generation:                 e.Generation,
+		MetaGenerationPrecondition: &e.MetaGeneration,
+	})
+	var notFoundErr *gcs.NotFoundError
+	if err != nil && !errors.As(err, &notFoundErr) {
+		return fmt.Errorf("DeleteObject: %w", err)
+	}
+	return nil
+}
+
+// Purge deletes the objects that were trashed before the supplied time for
+// good, and returns how many there were.
+func (t *Trash) Purge(ctx context.Context, before time.Time) (int, error) {
+	entries, err := t.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int
+	for _, e := range entries {
+		if !e.Deleted.Before(before) {
+			break
+		}
+		if err = t.delete(ctx, e); err != nil {
+			return purged, fmt.Errorf("purge %q: %w", e.Name, err)
+		}
+		purged++
+	}
+	return purged, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/fake"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/gcs"
+	"github.com/googlecloudplatform/gcsfuse/v3/internal/storage/storageutil"
+	"github.com/jacobsa/timeutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const prefix = ".trash/"
+
+type TrashTest struct {
+	suite.Suite
+	ctx    context.Context
+	clock  timeutil.SimulatedClock
+	bucket gcs.Bucket
+	trash  *Trash
+}
+
+func TestTrashTestSuite(t *testing.T) {
+	suite.Run(t, new(TrashTest))
+}
+
+func (t *TrashTest) SetupTest() {
+	t.ctx = context.Background()
+	t.clock.SetTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	t.bucket = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{})
+	t.trash = New(t.bucket, prefix, &t.clock)
+
+	require.NoError(t.T(), storageutil.CreateObjects(t.ctx, t.bucket, map[string][]byte{
+		"foo/":  nil,
+		"foo/a": []byte("taco"),
+		"bar":   []byte("burrito"),
+	}))
+}
+
+func (t *TrashTest) readObject(name string) (string, error) {
+	contents, err := storageutil.ReadObject(t.ctx, t.bucket, name)
+	return string(contents), err
+}
+
+func (t *TrashTest) assertGone(name string) {
+	_, err := t.readObject(name)
+	var notFoundErr *gcs.NotFoundError
+	assert.ErrorAs(t.T(), err, &notFoundErr, name)
+}
+
+func (t *TrashTest) list() []*Entry {
+	entries, err := t.trash.List(t.ctx)
+	require.NoError(t.T(), err)
+	return entries
+}
+
+func (t *TrashTest) TestMove() {
+	err := t.trash.Move(t.ctx, "foo/a")
+
+	require.NoError(t.T(), err)
+	t.assertGone("foo/a")
+	entries := t.list()
+	require.Len(t.T(), entries, 1)
+	assert.Equal(t.T(), "foo/a", entries[0].OriginalName)
+	assert.Equal(t.T(), prefix+"20260101T000000.000000000Z/foo/a", entries[0].Name)
+	assert.Equal(t.T(), t.clock.Now(), entries[0].Deleted)
+	assert.Equal(t.T(), uint64(len("taco")), entries[0].Size)
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: entries[0].Name})
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "foo/a", m.Metadata[OriginalNameMetadataKey])
+}
+
+func (t *TrashTest) TestMove_Missing() {
+	err := t.trash.Move(t.ctx, "baz")
+
+	assert.NoError(t.T(), err)
+	assert.Empty(t.T(), t.list())
+}
+
+func (t *TrashTest) TestMove_Hierarchical() {
+	t.bucket = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{Hierarchical: true})
+	t.trash = New(t.bucket, prefix, &t.clock)
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "foo/a", []byte("taco"))
+	require.NoError(t.T(), err)
+
+	err = t.trash.Move(t.ctx, "foo/a")
+
+	require.NoError(t.T(), err)
+	t.assertGone("foo/a")
+	entries := t.list()
+	require.Len(t.T(), entries, 1)
+	assert.Equal(t.T(), "foo/a", entries[0].OriginalName)
+}
+
+func (t *TrashTest) TestMoveDir() {
+	err := t.trash.MoveDir(t.ctx, "foo/")
+
+	require.NoError(t.T(), err)
+	t.assertGone("foo/")
+	t.assertGone("foo/a")
+	entries := t.list()
+	require.Len(t.T(), entries, 2)
+	assert.Equal(t.T(), "foo/", entries[0].OriginalName)
+	assert.Equal(t.T(), "foo/a", entries[1].OriginalName)
+	assert.Equal(t.T(), entries[0].Deleted, entries[1].Deleted)
+}
+
+func (t *TrashTest) TestMoveDir_Implicit() {
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "baz/qux", []byte("enchilada"))
+	require.NoError(t.T(), err)
+
+	err = t.trash.MoveDir(t.ctx, "baz/")
+
+	require.NoError(t.T(), err)
+	t.assertGone("baz/qux")
+	entries := t.list()
+	require.Len(t.T(), entries, 1)
+	assert.Equal(t.T(), "baz/qux", entries[0].OriginalName)
+}
+
+func (t *TrashTest) TestMoveDir_Hierarchical() {
+	t.bucket = fake.NewFakeBucket(&t.clock, "some_bucket", gcs.BucketType{Hierarchical: true})
+	t.trash = New(t.bucket, prefix, &t.clock)
+	_, err := t.bucket.CreateFolder(t.ctx, "foo/")
+	require.NoError(t.T(), err)
+
+	err = t.trash.MoveDir(t.ctx, "foo/")
+
+	require.NoError(t.T(), err)
+	var notFoundErr *gcs.NotFoundError
+	_, err = t.bucket.GetFolder(t.ctx, &gcs.GetFolderRequest{Name: "foo/"})
+	assert.ErrorAs(t.T(), err, &notFoundErr)
+	entries := t.list()
+	require.Len(t.T(), entries, 1)
+	assert.Equal(t.T(), "foo/", entries[0].OriginalName)
+
+	require.NoError(t.T(), t.trash.Restore(t.ctx, entries[0]))
+
+	_, err = t.bucket.GetFolder(t.ctx, &gcs.GetFolderRequest{Name: "foo/"})
+	assert.NoError(t.T(), err)
+	assert.Empty(t.T(), t.list())
+	assert.ErrorIs(t.T(), t.trash.Restore(t.ctx, entries[0]), ErrExists)
+}
+
+func (t *TrashTest) TestRestore() {
+	require.NoError(t.T(), t.trash.Move(t.ctx, "foo/a"))
+
+	err := t.trash.Restore(t.ctx, t.list()[0])
+
+	require.NoError(t.T(), err)
+	contents, err := t.readObject("foo/a")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "taco", contents)
+	assert.Empty(t.T(), t.list())
+	m, _, err := t.bucket.StatObject(t.ctx, &gcs.StatObjectRequest{Name: "foo/a"})
+	require.NoError(t.T(), err)
+	assert.NotContains(t.T(), m.Metadata, OriginalNameMetadataKey)
+	assert.NotContains(t.T(), m.Metadata, DeletedMetadataKey)
+}
+
+func (t *TrashTest) TestRestore_NameTaken() {
+	require.NoError(t.T(), t.trash.Move(t.ctx, "bar"))
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "bar", []byte("enchilada"))
+	require.NoError(t.T(), err)
+
+	err = t.trash.Restore(t.ctx, t.list()[0])
+
+	assert.ErrorIs(t.T(), err, ErrExists)
+	contents, err := t.readObject("bar")
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), "enchilada", contents)
+	assert.Len(t.T(), t.list(), 1)
+}
+
+func (t *TrashTest) TestLatest() {
+	require.NoError(t.T(), t.trash.Move(t.ctx, "bar"))
+	t.clock.AdvanceTime(time.Minute)
+	_, err := storageutil.CreateObject(t.ctx, t.bucket, "bar", []byte("enchilada"))
+	require.NoError(t.T(), err)
+	require.NoError(t.T(), t.trash.Move(t.ctx, "bar"))
+	require.NoError(t.T(), t.trash.Move(t.ctx, "foo/a"))
+	require.NoError(t.T(), t.trash.Move(t.ctx, "foo/"))
+	entries := t.list()
+
+	assert.Equal(t.T(), entries[1:2], Latest(entries, "bar"))
+	assert.Equal(t.T(), entries[2:], Latest(entries, "foo"))
+	assert.Empty(t.T(), Latest(entries, "fo"))
+}
+
+func (t *TrashTest) TestPurge() {
+	require.NoError(t.T(), t.trash.Move(t.ctx, "bar"))
+	t.clock.AdvanceTime(time.Hour)
+	require.NoError(t.T(), t.trash.Move(t.ctx, "foo/a"))
+
+	n, err := t.trash.Purge(t.ctx, t.clock.Now().Add(-time.Minute))
+
+	require.NoError(t.T(), err)
+	assert.Equal(t.T(), 1, n)
+	entries := t.list()
+	require.Len(t.T(), entries, 1)
+	assert.Equal(t.T(), "foo/a", entries[0].OriginalName)
+}
@@ -66,3 +66,18 @@ func GetVolumeBlockSize(path string) uint64 {
 	}
 	return blockSize
 }
+
+// GetFreeSpace returns the number of bytes available to unprivileged users on
+// the file system containing the given path.
+func GetFreeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	if stat.Frsize > 0 {
+		blockSize = uint64(stat.Frsize)
+	}
+	return stat.Bavail * blockSize, nil
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/googlecloudplatform/gcsfuse/v3/internal/util/diskutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetSpeculativeFileSizeOnDisk(t *testing.T) {
@@ -99,3 +100,16 @@ func TestGetVolumeBlockSize_InvalidDir(t *testing.T) {
 	// expect default value if directory doesn't exist.
 	assert.Equal(t, expectedVolumeBlockSize, blockSize)
 }
+
+func TestGetFreeSpace(t *testing.T) {
+	free, err := diskutil.GetFreeSpace(t.TempDir())
+
+	require.NoError(t, err)
+	assert.Positive(t, free)
+}
+
+func TestGetFreeSpace_MissingPath(t *testing.T) {
+	_, err := diskutil.GetFreeSpace("/no/such/dir")
+
+	assert.Error(t, err)
+}
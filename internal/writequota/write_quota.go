@@ -0,0 +1,200 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package writequota limits the bytes written and the files created through
+// a mount, either in total or in each of its top-level directories.
+//
+// Usage is counted from the time of mounting, so files that are overwritten
+// count again, and files that are deleted don't give their share back.
+package writequota
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/jacobsa/fuse/fuseops"
+)
+
+type usage struct {
+	bytes   int64
+	objects int64
+}
+
+// Quota tracks the usage of a mount against its write quotas.
+type Quota struct {
+	// The limits, of which zero ones don't apply.
+	maxBytes   int64
+	maxObjects int64
+
+	perTopLevelDir bool
+	metricHandle   metrics.MetricHandle
+
+	mu sync.Mutex
+
+	// Usage by scope: the top-level directory, or "" for the whole mount.
+	//
+	// GUARDED_BY(mu)
+	usage map[string]*usage
+
+	// Files that have had writes refused since they were last synced.
+	//
+	// GUARDED_BY(mu)
+	refused map[fuseops.InodeID]struct{}
+}
+
+// New returns a quota of maxBytes bytes written and maxObjects files created,
+// either of which may be zero for no limit. If perTopLevelDir is set, each
+// top-level directory of the mount gets a quota of its own, as do the files
+// in the root directory together.
+func New(maxBytes, maxObjects int64, perTopLevelDir bool, metricHandle metrics.MetricHandle) *Quota {
+	return &Quota{
+		maxBytes:       maxBytes,
+		maxObjects:     maxObjects,
+		perTopLevelDir: perTopLevelDir,
+		metricHandle:   metricHandle,
+		usage:          make(map[string]*usage),
+		refused:        make(map[fuseops.InodeID]struct{}),
+	}
+}
+
+// scope returns the scope charged for writes to the file with the supplied
+// local name, and a description of it for errors.
+func (q *Quota) scope(name string) (string, string) {
+	if !q.perTopLevelDir {
+		return "", "the mount"
+	}
+	dir, _, ok := strings.Cut(name, "/")
+	if !ok {
+		return "", "the root directory"
+	}
+	return dir, fmt.Sprintf("directory %q", dir)
+}
+
+// LOCKS_REQUIRED(q.mu)
+func (q *Quota) usageOf(scope string) *usage {
+	u, ok := q.usage[scope]
+	if !ok {
+		u = &usage{}
+		q.usage[scope] = u
+	}
+	return u
+}
+
+// ChargeWrite charges writing n bytes to the file with the supplied inode ID
+// and local name. If that would exceed the quota, nothing is charged, the
+// write is remembered for Refused, and an error wrapping EDQUOT is returned.
+//
+// LOCKS_EXCLUDED(q.mu)
+func (q *Quota) ChargeWrite(id fuseops.InodeID, name string, n int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err := q.chargeBytes(name, n)
+	if err != nil {
+		q.refused[id] = struct{}{}
+	}
+	return err
+}
+
+// ChargeExtend charges extending the file with the supplied local name by n
+// bytes without writing them, as fallocate does. Unlike a write, the kernel
+// doesn't buffer it, so a refusal is only reported through the error.
+//
+// LOCKS_EXCLUDED(q.mu)
+func (q *Quota) ChargeExtend(name string, n int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.chargeBytes(name, n)
+}
+
+// LOCKS_REQUIRED(q.mu)
+func (q *Quota) chargeBytes(name string, n int64) error {
+	scope, desc := q.scope(name)
+	u := q.usageOf(scope)
+	if q.maxBytes > 0 && u.bytes+n > q.maxBytes {
+		q.metricHandle.FsWriteQuotaExceededCount(1, metrics.QuotaBytesAttr)
+		return fmt.Errorf("writing %d bytes to %q would exceed the write quota of %d bytes of %s: %w", n, name, q.maxBytes, desc, syscall.EDQUOT)
+	}
+
+	u.bytes += n
+	q.metricHandle.FsWriteQuotaBytesCount(n)
+	return nil
+}
+
+// RefundWrite gives back the charge for writing or extending the file with the
+// supplied local name by n bytes, which couldn't be done after all.
+//
+// LOCKS_EXCLUDED(q.mu)
+func (q *Quota) RefundWrite(name string, n int64) {
+	scope, _ := q.scope(name)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.usageOf(scope).bytes -= n
+}
+
+// ChargeCreate charges creating the file with the supplied local name. If that
+// would exceed the quota, nothing is charged and an error wrapping EDQUOT is
+// returned.
+//
+// LOCKS_EXCLUDED(q.mu)
+func (q *Quota) ChargeCreate(name string) error {
+	scope, desc := q.scope(name)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.usageOf(scope)
+	if q.maxObjects > 0 && u.objects+1 > q.maxObjects {
+		q.metricHandle.FsWriteQuotaExceededCount(1, metrics.QuotaObjectsAttr)
+		return fmt.Errorf("creating %q would exceed the write quota of %d files of %s: %w", name, q.maxObjects, desc, syscall.EDQUOT)
+	}
+
+	u.objects++
+	q.metricHandle.FsWriteQuotaObjectsCount(1)
+	return nil
+}
+
+// RefundCreate gives back the charge for creating the file with the supplied
+// local name, which couldn't be created after all.
+//
+// LOCKS_EXCLUDED(q.mu)
+func (q *Quota) RefundCreate(name string) {
+	scope, _ := q.scope(name)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.usageOf(scope).objects--
+}
+
+// Refused reports whether writes to the file with the supplied inode ID have
+// been refused since the last call for it. The kernel may buffer writes and
+// send them on later, so their errors only reach the application through
+// fsync.
+//
+// LOCKS_EXCLUDED(q.mu)
+func (q *Quota) Refused(id fuseops.InodeID) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	_, ok := q.refused[id]
+	delete(q.refused, id)
+	return ok
+}
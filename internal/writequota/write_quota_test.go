@@ -0,0 +1,83 @@
+// Copyright 2026 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writequota
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/googlecloudplatform/gcsfuse/v3/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChargeWrite_MountWide(t *testing.T) {
+	q := New(10, 0, false, metrics.NewNoopMetrics())
+
+	require.NoError(t, q.ChargeWrite(2, "a/foo", 6))
+	err := q.ChargeWrite(3, "b/bar", 5)
+
+	assert.ErrorIs(t, err, syscall.EDQUOT)
+	assert.NoError(t, q.ChargeWrite(3, "b/bar", 4))
+	assert.False(t, q.Refused(2))
+	assert.True(t, q.Refused(3))
+	assert.False(t, q.Refused(3))
+}
+
+func TestChargeWrite_PerTopLevelDir(t *testing.T) {
+	q := New(10, 0, true, metrics.NewNoopMetrics())
+
+	require.NoError(t, q.ChargeWrite(2, "a/foo", 6))
+	require.NoError(t, q.ChargeWrite(3, "b/bar", 6))
+	require.NoError(t, q.ChargeWrite(4, "baz", 6))
+
+	assert.ErrorIs(t, q.ChargeWrite(5, "a/qux/quux", 6), syscall.EDQUOT)
+	assert.ErrorIs(t, q.ChargeWrite(6, "qux", 6), syscall.EDQUOT)
+}
+
+func TestChargeCreate(t *testing.T) {
+	q := New(0, 2, true, metrics.NewNoopMetrics())
+
+	require.NoError(t, q.ChargeCreate("a/foo"))
+	require.NoError(t, q.ChargeCreate("a/bar"))
+	require.NoError(t, q.ChargeCreate("b/foo"))
+	assert.ErrorIs(t, q.ChargeCreate("a/baz"), syscall.EDQUOT)
+
+	q.RefundCreate("a/bar")
+
+	assert.NoError(t, q.ChargeCreate("a/baz"))
+	assert.NoError(t, q.ChargeWrite(2, "a/baz", 1<<30))
+}
+
+func TestChargeExtend(t *testing.T) {
+	q := New(10, 0, false, metrics.NewNoopMetrics())
+
+	require.NoError(t, q.ChargeExtend("foo", 6))
+
+	assert.ErrorIs(t, q.ChargeExtend("foo", 5), syscall.EDQUOT)
+	assert.NoError(t, q.ChargeWrite(2, "foo", 4))
+	assert.False(t, q.Refused(2))
+}
+
+func TestRefundWrite(t *testing.T) {
+	q := New(10, 0, true, metrics.NewNoopMetrics())
+	require.NoError(t, q.ChargeWrite(2, "a/foo", 6))
+	require.NoError(t, q.ChargeWrite(3, "b/foo", 6))
+
+	q.RefundWrite("a/foo", 6)
+
+	assert.NoError(t, q.ChargeWrite(2, "a/foo", 10))
+	assert.ErrorIs(t, q.ChargeWrite(3, "b/foo", 5), syscall.EDQUOT)
+}
@@ -20,6 +20,14 @@ import (
 	"time"
 )
 
+// Dir is a custom type for the dir attribute.
+type Dir string
+
+const (
+	DirCacheDirAttr Dir = "cache_dir"
+	DirTempDirAttr  Dir = "temp_dir"
+)
+
 // FsErrorCategory is a custom type for the fs_error_category attribute.
 type FsErrorCategory string
 
@@ -106,6 +114,14 @@ const (
 	IoMethodOpenedAttr IoMethod = "opened"
 )
 
+// Quota is a custom type for the quota attribute.
+type Quota string
+
+const (
+	QuotaBytesAttr   Quota = "bytes"
+	QuotaObjectsAttr Quota = "objects"
+)
+
 // ReadType is a custom type for the read_type attribute.
 type ReadType string
 
@@ -160,6 +176,9 @@ type MetricHandle interface {
 	// FileCacheReadLatencies - The cumulative distribution of the file cache read latencies along with cache hit - true/false.
 	FileCacheReadLatencies(ctx context.Context, latency time.Duration, cacheHit bool)
 
+	// FsLocalFreeBytes - The free space, sampled every 30 seconds, of the local directories gcsfuse writes to: the cache directory and the directory where writes are staged.
+	FsLocalFreeBytes(inc int64, dir Dir)
+
 	// FsOpsCount - The cumulative number of ops processed by the file system.
 	FsOpsCount(inc int64, fsOp FsOp)
 
@@ -169,6 +188,21 @@ type MetricHandle interface {
 	// FsOpsLatency - The cumulative distribution of file system operation latencies
 	FsOpsLatency(ctx context.Context, latency time.Duration, fsOp FsOp)
 
+	// FsSyncFsBytesCount - The cumulative number of bytes of dirty files written out to GCS by syncfs calls, which the kernel only passes on to virtiofs mounts.
+	FsSyncFsBytesCount(inc int64)
+
+	// FsSyncFsFileCount - The cumulative number of dirty files written out to GCS by syncfs calls, which the kernel only passes on to virtiofs mounts.
+	FsSyncFsFileCount(inc int64)
+
+	// FsWriteQuotaBytesCount - The cumulative number of bytes written through the mount and charged to write quotas.
+	FsWriteQuotaBytesCount(inc int64)
+
+	// FsWriteQuotaExceededCount - The cumulative number of writes and file creations that failed with EDQUOT, along with the quota they would have exceeded: bytes or objects.
+	FsWriteQuotaExceededCount(inc int64, quota Quota)
+
+	// FsWriteQuotaObjectsCount - The cumulative number of files created through the mount and charged to write quotas.
+	FsWriteQuotaObjectsCount(inc int64)
+
 	// GcsDownloadBytesCount - The cumulative number of bytes downloaded from GCS along with type - Sequential/Random
 	GcsDownloadBytesCount(inc int64, readType ReadType)
 
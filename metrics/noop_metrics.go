@@ -33,12 +33,24 @@ func (*noopMetrics) FileCacheReadCount(inc int64, cacheHit bool, readType ReadTy
 func (*noopMetrics) FileCacheReadLatencies(ctx context.Context, latency time.Duration, cacheHit bool) {
 }
 
+func (*noopMetrics) FsLocalFreeBytes(inc int64, dir Dir) {}
+
 func (*noopMetrics) FsOpsCount(inc int64, fsOp FsOp) {}
 
 func (*noopMetrics) FsOpsErrorCount(inc int64, fsErrorCategory FsErrorCategory, fsOp FsOp) {}
 
 func (*noopMetrics) FsOpsLatency(ctx context.Context, latency time.Duration, fsOp FsOp) {}
 
+func (*noopMetrics) FsSyncFsBytesCount(inc int64) {}
+
+func (*noopMetrics) FsSyncFsFileCount(inc int64) {}
+
+func (*noopMetrics) FsWriteQuotaBytesCount(inc int64) {}
+
+func (*noopMetrics) FsWriteQuotaExceededCount(inc int64, quota Quota) {}
+
+func (*noopMetrics) FsWriteQuotaObjectsCount(inc int64) {}
+
 func (*noopMetrics) GcsDownloadBytesCount(inc int64, readType ReadType) {}
 
 func (*noopMetrics) GcsReadBytesCount(inc int64) {}
@@ -49,6 +49,8 @@ var (
 	fileCacheReadCountCacheHitFalseReadTypeUnknownAttrSet                               = metric.WithAttributeSet(attribute.NewSet(attribute.Bool("cache_hit", false), attribute.String("read_type", "Unknown")))
 	fileCacheReadLatenciesCacheHitTrueAttrSet                                           = metric.WithAttributeSet(attribute.NewSet(attribute.Bool("cache_hit", true)))
 	fileCacheReadLatenciesCacheHitFalseAttrSet                                          = metric.WithAttributeSet(attribute.NewSet(attribute.Bool("cache_hit", false)))
+	fsLocalFreeBytesDirCacheDirAttrSet                                                  = metric.WithAttributeSet(attribute.NewSet(attribute.String("dir", "cache_dir")))
+	fsLocalFreeBytesDirTempDirAttrSet                                                   = metric.WithAttributeSet(attribute.NewSet(attribute.String("dir", "temp_dir")))
 	fsOpsCountFsOpBatchForgetAttrSet                                                    = metric.WithAttributeSet(attribute.NewSet(attribute.String("fs_op", "BatchForget")))
 	fsOpsCountFsOpCreateFileAttrSet                                                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("fs_op", "CreateFile")))
 	fsOpsCountFsOpCreateLinkAttrSet                                                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("fs_op", "CreateLink")))
@@ -499,6 +501,8 @@ var (
 	fsOpsLatencyFsOpSyncFileAttrSet                                                     = metric.WithAttributeSet(attribute.NewSet(attribute.String("fs_op", "SyncFile")))
 	fsOpsLatencyFsOpUnlinkAttrSet                                                       = metric.WithAttributeSet(attribute.NewSet(attribute.String("fs_op", "Unlink")))
 	fsOpsLatencyFsOpWriteFileAttrSet                                                    = metric.WithAttributeSet(attribute.NewSet(attribute.String("fs_op", "WriteFile")))
+	fsWriteQuotaExceededCountQuotaBytesAttrSet                                          = metric.WithAttributeSet(attribute.NewSet(attribute.String("quota", "bytes")))
+	fsWriteQuotaExceededCountQuotaObjectsAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("quota", "objects")))
 	gcsDownloadBytesCountReadTypeBufferedAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Buffered")))
 	gcsDownloadBytesCountReadTypeParallelAttrSet                                        = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Parallel")))
 	gcsDownloadBytesCountReadTypeRandomAttrSet                                          = metric.WithAttributeSet(attribute.NewSet(attribute.String("read_type", "Random")))
@@ -577,6 +581,8 @@ type otelMetrics struct {
 	fileCacheReadCountCacheHitFalseReadTypeRandomAtomic                                *atomic.Int64
 	fileCacheReadCountCacheHitFalseReadTypeSequentialAtomic                            *atomic.Int64
 	fileCacheReadCountCacheHitFalseReadTypeUnknownAtomic                               *atomic.Int64
+	fsLocalFreeBytesDirCacheDirAtomic                                                  *atomic.Int64
+	fsLocalFreeBytesDirTempDirAtomic                                                   *atomic.Int64
 	fsOpsCountFsOpBatchForgetAtomic                                                    *atomic.Int64
 	fsOpsCountFsOpCreateFileAtomic                                                     *atomic.Int64
 	fsOpsCountFsOpCreateLinkAtomic                                                     *atomic.Int64
@@ -1002,6 +1008,12 @@ type otelMetrics struct {
 	fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpSyncFileAtomic                   *atomic.Int64
 	fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpUnlinkAtomic                     *atomic.Int64
 	fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpWriteFileAtomic                  *atomic.Int64
+	fsSyncFsBytesCountAtomic                                                           *atomic.Int64
+	fsSyncFsFileCountAtomic                                                            *atomic.Int64
+	fsWriteQuotaBytesCountAtomic                                                       *atomic.Int64
+	fsWriteQuotaExceededCountQuotaBytesAtomic                                          *atomic.Int64
+	fsWriteQuotaExceededCountQuotaObjectsAtomic                                        *atomic.Int64
+	fsWriteQuotaObjectsCountAtomic                                                     *atomic.Int64
 	gcsDownloadBytesCountReadTypeBufferedAtomic                                        *atomic.Int64
 	gcsDownloadBytesCountReadTypeParallelAtomic                                        *atomic.Int64
 	gcsDownloadBytesCountReadTypeRandomAtomic                                          *atomic.Int64
@@ -1146,6 +1158,19 @@ func (o *otelMetrics) FileCacheReadLatencies(
 	}
 }
 
+func (o *otelMetrics) FsLocalFreeBytes(
+	inc int64, dir Dir) {
+	switch dir {
+	case DirCacheDirAttr:
+		o.fsLocalFreeBytesDirCacheDirAtomic.Add(inc)
+	case DirTempDirAttr:
+		o.fsLocalFreeBytesDirTempDirAtomic.Add(inc)
+	default:
+		updateUnrecognizedAttribute(string(dir))
+		return
+	}
+}
+
 func (o *otelMetrics) FsOpsCount(
 	inc int64, fsOp FsOp) {
 	if inc < 0 {
@@ -2183,6 +2208,59 @@ func (o *otelMetrics) FsOpsLatency(
 	}
 }
 
+func (o *otelMetrics) FsSyncFsBytesCount(
+	inc int64) {
+	if inc < 0 {
+		logger.Errorf("Counter metric fs/sync_fs_bytes_count received a negative increment: %d", inc)
+		return
+	}
+	o.fsSyncFsBytesCountAtomic.Add(inc)
+}
+
+func (o *otelMetrics) FsSyncFsFileCount(
+	inc int64) {
+	if inc < 0 {
+		logger.Errorf("Counter metric fs/sync_fs_file_count received a negative increment: %d", inc)
+		return
+	}
+	o.fsSyncFsFileCountAtomic.Add(inc)
+}
+
+func (o *otelMetrics) FsWriteQuotaBytesCount(
+	inc int64) {
+	if inc < 0 {
+		logger.Errorf("Counter metric fs/write_quota_bytes_count received a negative increment: %d", inc)
+		return
+	}
+	o.fsWriteQuotaBytesCountAtomic.Add(inc)
+}
+
+func (o *otelMetrics) FsWriteQuotaExceededCount(
+	inc int64, quota Quota) {
+	if inc < 0 {
+		logger.Errorf("Counter metric fs/write_quota_exceeded_count received a negative increment: %d", inc)
+		return
+	}
+	switch quota {
+	case QuotaBytesAttr:
+		o.fsWriteQuotaExceededCountQuotaBytesAtomic.Add(inc)
+	case QuotaObjectsAttr:
+		o.fsWriteQuotaExceededCountQuotaObjectsAtomic.Add(inc)
+	default:
+		updateUnrecognizedAttribute(string(quota))
+		return
+	}
+}
+
+func (o *otelMetrics) FsWriteQuotaObjectsCount(
+	inc int64) {
+	if inc < 0 {
+		logger.Errorf("Counter metric fs/write_quota_objects_count received a negative increment: %d", inc)
+		return
+	}
+	o.fsWriteQuotaObjectsCountAtomic.Add(inc)
+}
+
 func (o *otelMetrics) GcsDownloadBytesCount(
 	inc int64, readType ReadType) {
 	if inc < 0 {
@@ -2425,6 +2503,9 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		fileCacheReadCountCacheHitFalseReadTypeSequentialAtomic,
 		fileCacheReadCountCacheHitFalseReadTypeUnknownAtomic atomic.Int64
 
+	var fsLocalFreeBytesDirCacheDirAtomic,
+		fsLocalFreeBytesDirTempDirAtomic atomic.Int64
+
 	var fsOpsCountFsOpBatchForgetAtomic,
 		fsOpsCountFsOpCreateFileAtomic,
 		fsOpsCountFsOpCreateLinkAtomic,
@@ -2852,6 +2933,17 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpUnlinkAtomic,
 		fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpWriteFileAtomic atomic.Int64
 
+	var fsSyncFsBytesCountAtomic atomic.Int64
+
+	var fsSyncFsFileCountAtomic atomic.Int64
+
+	var fsWriteQuotaBytesCountAtomic atomic.Int64
+
+	var fsWriteQuotaExceededCountQuotaBytesAtomic,
+		fsWriteQuotaExceededCountQuotaObjectsAtomic atomic.Int64
+
+	var fsWriteQuotaObjectsCountAtomic atomic.Int64
+
 	var gcsDownloadBytesCountReadTypeBufferedAtomic,
 		gcsDownloadBytesCountReadTypeParallelAtomic,
 		gcsDownloadBytesCountReadTypeRandomAtomic,
@@ -2940,7 +3032,16 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(50, 100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000, 1000000, 2000000, 5000000, 10000000, 20000000, 50000000, 100000000, 200000000, 500000000))
 
-	_, err5 := meter.Int64ObservableCounter("fs/ops_count",
+	_, err5 := meter.Int64ObservableUpDownCounter("fs/local_free_bytes",
+		metric.WithDescription("The free space, sampled every 30 seconds, of the local directories gcsfuse writes to: the cache directory and the directory where writes are staged."),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			observeUpDownCounter(obsrv, &fsLocalFreeBytesDirCacheDirAtomic, fsLocalFreeBytesDirCacheDirAttrSet)
+			observeUpDownCounter(obsrv, &fsLocalFreeBytesDirTempDirAtomic, fsLocalFreeBytesDirTempDirAttrSet)
+			return nil
+		}))
+
+	_, err6 := meter.Int64ObservableCounter("fs/ops_count",
 		metric.WithDescription("The cumulative number of ops processed by the file system."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -2972,7 +3073,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err6 := meter.Int64ObservableCounter("fs/ops_error_count",
+	_, err7 := meter.Int64ObservableCounter("fs/ops_error_count",
 		metric.WithDescription("The cumulative number of errors generated by file system operations."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3379,12 +3480,53 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	fsOpsLatency, err7 := meter.Int64Histogram("fs/ops_latency",
+	fsOpsLatency, err8 := meter.Int64Histogram("fs/ops_latency",
 		metric.WithDescription("The cumulative distribution of file system operation latencies"),
 		metric.WithUnit("us"),
 		metric.WithExplicitBucketBoundaries(50, 100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000, 1000000, 2000000, 5000000, 10000000, 20000000, 50000000, 100000000, 200000000, 500000000))
 
-	_, err8 := meter.Int64ObservableCounter("gcs/download_bytes_count",
+	_, err9 := meter.Int64ObservableCounter("fs/sync_fs_bytes_count",
+		metric.WithDescription("The cumulative number of bytes of dirty files written out to GCS by syncfs calls, which the kernel only passes on to virtiofs mounts."),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fsSyncFsBytesCountAtomic)
+			return nil
+		}))
+
+	_, err10 := meter.Int64ObservableCounter("fs/sync_fs_file_count",
+		metric.WithDescription("The cumulative number of dirty files written out to GCS by syncfs calls, which the kernel only passes on to virtiofs mounts."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fsSyncFsFileCountAtomic)
+			return nil
+		}))
+
+	_, err11 := meter.Int64ObservableCounter("fs/write_quota_bytes_count",
+		metric.WithDescription("The cumulative number of bytes written through the mount and charged to write quotas."),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fsWriteQuotaBytesCountAtomic)
+			return nil
+		}))
+
+	_, err12 := meter.Int64ObservableCounter("fs/write_quota_exceeded_count",
+		metric.WithDescription("The cumulative number of writes and file creations that failed with EDQUOT, along with the quota they would have exceeded: bytes or objects."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fsWriteQuotaExceededCountQuotaBytesAtomic, fsWriteQuotaExceededCountQuotaBytesAttrSet)
+			conditionallyObserve(obsrv, &fsWriteQuotaExceededCountQuotaObjectsAtomic, fsWriteQuotaExceededCountQuotaObjectsAttrSet)
+			return nil
+		}))
+
+	_, err13 := meter.Int64ObservableCounter("fs/write_quota_objects_count",
+		metric.WithDescription("The cumulative number of files created through the mount and charged to write quotas."),
+		metric.WithUnit(""),
+		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
+			conditionallyObserve(obsrv, &fsWriteQuotaObjectsCountAtomic)
+			return nil
+		}))
+
+	_, err14 := meter.Int64ObservableCounter("gcs/download_bytes_count",
 		metric.WithDescription("The cumulative number of bytes downloaded from GCS along with type - Sequential/Random"),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3395,7 +3537,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err9 := meter.Int64ObservableCounter("gcs/read_bytes_count",
+	_, err15 := meter.Int64ObservableCounter("gcs/read_bytes_count",
 		metric.WithDescription("The cumulative number of bytes read from GCS objects."),
 		metric.WithUnit("By"),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3403,7 +3545,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err10 := meter.Int64ObservableCounter("gcs/read_count",
+	_, err16 := meter.Int64ObservableCounter("gcs/read_count",
 		metric.WithDescription("Specifies the number of gcs reads made along with type - Sequential/Random"),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3414,7 +3556,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err11 := meter.Int64ObservableCounter("gcs/reader_count",
+	_, err17 := meter.Int64ObservableCounter("gcs/reader_count",
 		metric.WithDescription("The cumulative number of GCS object readers opened or closed."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3423,7 +3565,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err12 := meter.Int64ObservableCounter("gcs/request_count",
+	_, err18 := meter.Int64ObservableCounter("gcs/request_count",
 		metric.WithDescription("The cumulative number of GCS requests processed along with the GCS method."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3449,12 +3591,12 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	gcsRequestLatencies, err13 := meter.Int64Histogram("gcs/request_latencies",
+	gcsRequestLatencies, err19 := meter.Int64Histogram("gcs/request_latencies",
 		metric.WithDescription("The cumulative distribution of the GCS request latencies."),
 		metric.WithUnit("ms"),
 		metric.WithExplicitBucketBoundaries(100, 200, 400, 800, 1500, 3000, 5000, 10000, 20000, 50000, 100000, 200000, 500000))
 
-	_, err14 := meter.Int64ObservableCounter("gcs/retry_count",
+	_, err20 := meter.Int64ObservableCounter("gcs/retry_count",
 		metric.WithDescription("The cumulative number of retry requests made to GCS."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3463,7 +3605,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err15 := meter.Int64ObservableUpDownCounter("test/updown_counter",
+	_, err21 := meter.Int64ObservableUpDownCounter("test/updown_counter",
 		metric.WithDescription("Test metric for updown counters."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3471,7 +3613,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	_, err16 := meter.Int64ObservableUpDownCounter("test/updown_counter_with_attrs",
+	_, err22 := meter.Int64ObservableUpDownCounter("test/updown_counter_with_attrs",
 		metric.WithDescription("Test metric for updown counters with attributes."),
 		metric.WithUnit(""),
 		metric.WithInt64Callback(func(_ context.Context, obsrv metric.Int64Observer) error {
@@ -3480,7 +3622,7 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 			return nil
 		}))
 
-	errs := []error{err0, err1, err2, err3, err4, err5, err6, err7, err8, err9, err10, err11, err12, err13, err14, err15, err16}
+	errs := []error{err0, err1, err2, err3, err4, err5, err6, err7, err8, err9, err10, err11, err12, err13, err14, err15, err16, err17, err18, err19, err20, err21, err22}
 	if err := errors.Join(errs...); err != nil {
 		return nil, err
 	}
@@ -3504,6 +3646,8 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		fileCacheReadCountCacheHitFalseReadTypeSequentialAtomic:                            &fileCacheReadCountCacheHitFalseReadTypeSequentialAtomic,
 		fileCacheReadCountCacheHitFalseReadTypeUnknownAtomic:                               &fileCacheReadCountCacheHitFalseReadTypeUnknownAtomic,
 		fileCacheReadLatencies:                                                             fileCacheReadLatencies,
+		fsLocalFreeBytesDirCacheDirAtomic:                                                  &fsLocalFreeBytesDirCacheDirAtomic,
+		fsLocalFreeBytesDirTempDirAtomic:                                                   &fsLocalFreeBytesDirTempDirAtomic,
 		fsOpsCountFsOpBatchForgetAtomic:                                                    &fsOpsCountFsOpBatchForgetAtomic,
 		fsOpsCountFsOpCreateFileAtomic:                                                     &fsOpsCountFsOpCreateFileAtomic,
 		fsOpsCountFsOpCreateLinkAtomic:                                                     &fsOpsCountFsOpCreateLinkAtomic,
@@ -3929,7 +4073,13 @@ func NewOTelMetrics(ctx context.Context, workers int, bufferSize int) (*otelMetr
 		fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpSyncFileAtomic:                   &fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpSyncFileAtomic,
 		fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpUnlinkAtomic:                     &fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpUnlinkAtomic,
 		fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpWriteFileAtomic:                  &fsOpsErrorCountFsErrorCategoryTOOMANYOPENFILESFsOpWriteFileAtomic,
-		fsOpsLatency: fsOpsLatency,
+		fsOpsLatency:                                               fsOpsLatency,
+		fsSyncFsBytesCountAtomic:                                   &fsSyncFsBytesCountAtomic,
+		fsSyncFsFileCountAtomic:                                    &fsSyncFsFileCountAtomic,
+		fsWriteQuotaBytesCountAtomic:                               &fsWriteQuotaBytesCountAtomic,
+		fsWriteQuotaExceededCountQuotaBytesAtomic:                  &fsWriteQuotaExceededCountQuotaBytesAtomic,
+		fsWriteQuotaExceededCountQuotaObjectsAtomic:                &fsWriteQuotaExceededCountQuotaObjectsAtomic,
+		fsWriteQuotaObjectsCountAtomic:                             &fsWriteQuotaObjectsCountAtomic,
 		gcsDownloadBytesCountReadTypeBufferedAtomic:                &gcsDownloadBytesCountReadTypeBufferedAtomic,
 		gcsDownloadBytesCountReadTypeParallelAtomic:                &gcsDownloadBytesCountReadTypeParallelAtomic,
 		gcsDownloadBytesCountReadTypeRandomAtomic:                  &gcsDownloadBytesCountReadTypeRandomAtomic,
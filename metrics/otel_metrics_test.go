@@ -480,6 +480,75 @@ func TestFileCacheReadLatencies(t *testing.T) {
 	}
 }
 
+func TestFsLocalFreeBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(m *otelMetrics)
+		expected map[attribute.Set]int64
+	}{
+		{
+			name: "dir_cache_dir",
+			f: func(m *otelMetrics) {
+				m.FsLocalFreeBytes(5, "cache_dir")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("dir", "cache_dir")): 5,
+			},
+		},
+		{
+			name: "dir_temp_dir",
+			f: func(m *otelMetrics) {
+				m.FsLocalFreeBytes(5, "temp_dir")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("dir", "temp_dir")): 5,
+			},
+		}, {
+			name: "multiple_attributes_summed",
+			f: func(m *otelMetrics) {
+				m.FsLocalFreeBytes(5, "cache_dir")
+				m.FsLocalFreeBytes(2, "temp_dir")
+				m.FsLocalFreeBytes(3, "cache_dir")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("dir", "cache_dir")): 8,
+				attribute.NewSet(attribute.String("dir", "temp_dir")): 2,
+			},
+		},
+		{
+			name: "negative_increment",
+			f: func(m *otelMetrics) {
+				m.FsLocalFreeBytes(-5, "cache_dir")
+				m.FsLocalFreeBytes(2, "cache_dir")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("dir", "cache_dir")): -3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			encoder := attribute.DefaultEncoder()
+			m, rd := setupOTel(ctx, t)
+
+			tc.f(m)
+			waitForMetricsProcessing()
+
+			metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+			metric, ok := metrics["fs/local_free_bytes"]
+			if len(tc.expected) == 0 {
+				assert.False(t, ok, "fs/local_free_bytes metric should not be found")
+				return
+			}
+			require.True(t, ok, "fs/local_free_bytes metric not found")
+			expectedMap := make(map[string]int64)
+			for k, v := range tc.expected {
+				expectedMap[k.Encoded(encoder)] = v
+			}
+			assert.Equal(t, expectedMap, metric)
+		})
+	}
+}
+
 func TestFsOpsCount(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -4570,6 +4639,175 @@ func TestFsOpsLatency(t *testing.T) {
 	}
 }
 
+func TestFsSyncFsBytesCount(t *testing.T) {
+	ctx := context.Background()
+	encoder := attribute.DefaultEncoder()
+	m, rd := setupOTel(ctx, t)
+
+	m.FsSyncFsBytesCount(1024)
+	m.FsSyncFsBytesCount(2048)
+	waitForMetricsProcessing()
+
+	metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok := metrics["fs/sync_fs_bytes_count"]
+	require.True(t, ok, "fs/sync_fs_bytes_count metric not found")
+	s := attribute.NewSet()
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Positive increments should be summed.")
+
+	// Test negative increment
+	m.FsSyncFsBytesCount(-100)
+	waitForMetricsProcessing()
+
+	metrics = gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok = metrics["fs/sync_fs_bytes_count"]
+	require.True(t, ok, "fs/sync_fs_bytes_count metric not found after negative increment")
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Negative increment should not change the metric value.")
+}
+
+func TestFsSyncFsFileCount(t *testing.T) {
+	ctx := context.Background()
+	encoder := attribute.DefaultEncoder()
+	m, rd := setupOTel(ctx, t)
+
+	m.FsSyncFsFileCount(1024)
+	m.FsSyncFsFileCount(2048)
+	waitForMetricsProcessing()
+
+	metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok := metrics["fs/sync_fs_file_count"]
+	require.True(t, ok, "fs/sync_fs_file_count metric not found")
+	s := attribute.NewSet()
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Positive increments should be summed.")
+
+	// Test negative increment
+	m.FsSyncFsFileCount(-100)
+	waitForMetricsProcessing()
+
+	metrics = gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok = metrics["fs/sync_fs_file_count"]
+	require.True(t, ok, "fs/sync_fs_file_count metric not found after negative increment")
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Negative increment should not change the metric value.")
+}
+
+func TestFsWriteQuotaBytesCount(t *testing.T) {
+	ctx := context.Background()
+	encoder := attribute.DefaultEncoder()
+	m, rd := setupOTel(ctx, t)
+
+	m.FsWriteQuotaBytesCount(1024)
+	m.FsWriteQuotaBytesCount(2048)
+	waitForMetricsProcessing()
+
+	metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok := metrics["fs/write_quota_bytes_count"]
+	require.True(t, ok, "fs/write_quota_bytes_count metric not found")
+	s := attribute.NewSet()
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Positive increments should be summed.")
+
+	// Test negative increment
+	m.FsWriteQuotaBytesCount(-100)
+	waitForMetricsProcessing()
+
+	metrics = gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok = metrics["fs/write_quota_bytes_count"]
+	require.True(t, ok, "fs/write_quota_bytes_count metric not found after negative increment")
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Negative increment should not change the metric value.")
+}
+
+func TestFsWriteQuotaExceededCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		f        func(m *otelMetrics)
+		expected map[attribute.Set]int64
+	}{
+		{
+			name: "quota_bytes",
+			f: func(m *otelMetrics) {
+				m.FsWriteQuotaExceededCount(5, "bytes")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("quota", "bytes")): 5,
+			},
+		},
+		{
+			name: "quota_objects",
+			f: func(m *otelMetrics) {
+				m.FsWriteQuotaExceededCount(5, "objects")
+			},
+			expected: map[attribute.Set]int64{
+				attribute.NewSet(attribute.String("quota", "objects")): 5,
+			},
+		}, {
+			name: "multiple_attributes_summed",
+			f: func(m *otelMetrics) {
+				m.FsWriteQuotaExceededCount(5, "bytes")
+				m.FsWriteQuotaExceededCount(2, "objects")
+				m.FsWriteQuotaExceededCount(3, "bytes")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("quota", "bytes")): 8,
+				attribute.NewSet(attribute.String("quota", "objects")): 2,
+			},
+		},
+		{
+			name: "negative_increment",
+			f: func(m *otelMetrics) {
+				m.FsWriteQuotaExceededCount(-5, "bytes")
+				m.FsWriteQuotaExceededCount(2, "bytes")
+			},
+			expected: map[attribute.Set]int64{attribute.NewSet(attribute.String("quota", "bytes")): 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			encoder := attribute.DefaultEncoder()
+			m, rd := setupOTel(ctx, t)
+
+			tc.f(m)
+			waitForMetricsProcessing()
+
+			metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+			metric, ok := metrics["fs/write_quota_exceeded_count"]
+			if len(tc.expected) == 0 {
+				assert.False(t, ok, "fs/write_quota_exceeded_count metric should not be found")
+				return
+			}
+			require.True(t, ok, "fs/write_quota_exceeded_count metric not found")
+			expectedMap := make(map[string]int64)
+			for k, v := range tc.expected {
+				expectedMap[k.Encoded(encoder)] = v
+			}
+			assert.Equal(t, expectedMap, metric)
+		})
+	}
+}
+
+func TestFsWriteQuotaObjectsCount(t *testing.T) {
+	ctx := context.Background()
+	encoder := attribute.DefaultEncoder()
+	m, rd := setupOTel(ctx, t)
+
+	m.FsWriteQuotaObjectsCount(1024)
+	m.FsWriteQuotaObjectsCount(2048)
+	waitForMetricsProcessing()
+
+	metrics := gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok := metrics["fs/write_quota_objects_count"]
+	require.True(t, ok, "fs/write_quota_objects_count metric not found")
+	s := attribute.NewSet()
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Positive increments should be summed.")
+
+	// Test negative increment
+	m.FsWriteQuotaObjectsCount(-100)
+	waitForMetricsProcessing()
+
+	metrics = gatherNonZeroCounterMetrics(ctx, t, rd)
+	metric, ok = metrics["fs/write_quota_objects_count"]
+	require.True(t, ok, "fs/write_quota_objects_count metric not found after negative increment")
+	assert.Equal(t, map[string]int64{s.Encoded(encoder): 3072}, metric, "Negative increment should not change the metric value.")
+}
+
 func TestGcsDownloadBytesCount(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -18,8 +18,6 @@ package local_file
 import (
 	"os"
 	"path"
-	"strings"
-	"testing"
 
 	. "github.com/googlecloudplatform/gcsfuse/v3/tools/integration_tests/util/client"
 	"github.com/googlecloudplatform/gcsfuse/v3/tools/integration_tests/util/operations"
@@ -27,17 +25,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-////////////////////////////////////////////////////////////////////////
-// Helpers
-////////////////////////////////////////////////////////////////////////
-
-func verifyRenameOperationNotSupported(err error, t *testing.T) {
-	if err == nil || !strings.Contains(err.Error(), "operation not supported") {
-		t.Fatalf("os.Rename(), expected err: %s, got err: %v",
-			"operation not supported", err)
-	}
-}
-
 ////////////////////////////////////////////////////////////////////////
 // Tests
 ////////////////////////////////////////////////////////////////////////
@@ -64,7 +51,7 @@ func (t *LocalFileTestSuite) TestRenameOfLocalFile() {
 	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, fileName, t.T())
 }
 
-func (t *LocalFileTestSuite) TestRenameOfDirectoryWithLocalFileFails() {
+func (t *LocalFileTestSuite) TestRenameOfDirectoryWithLocalFile() {
 	fileName1 := path.Base(t.T().Name()) + "1"
 	fileName2 := path.Base(t.T().Name()) + "2"
 	testDirPath = setup.SetupTestDirectory(testDirName)
@@ -76,17 +63,21 @@ func (t *LocalFileTestSuite) TestRenameOfDirectoryWithLocalFileFails() {
 	_, fh := CreateLocalFileInTestDir(ctx, storageClient, testDirPath, path.Join(ExplicitDirName, fileName2), t.T())
 	WritingToLocalFileShouldNotWriteToGCS(ctx, storageClient, fh, testDirName, path.Join(ExplicitDirName, fileName2), t.T())
 
-	// Attempt to rename directory containing local file.
+	// Rename directory containing local file.
 	err := os.Rename(
 		path.Join(testDirPath, ExplicitDirName),
 		path.Join(testDirPath, NewDirName))
 
-	// Verify rename operation fails.
-	verifyRenameOperationNotSupported(err, t.T())
-	// Write more content to local file.
-	WritingToLocalFileShouldNotWriteToGCS(ctx, storageClient, fh, testDirName, fileName2, t.T())
-	// Close the local file.
-	CloseFileAndValidateContentFromGCS(ctx, storageClient, fh, testDirName, path.Join(ExplicitDirName, fileName2), FileContents+FileContents, t.T())
+	// Verify that both files moved, and the local file was written out.
+	require.NoError(t.T(), err)
+	ValidateObjectContentsFromGCS(ctx, storageClient, testDirName, path.Join(NewDirName, fileName1), GCSFileContent, t.T())
+	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, path.Join(ExplicitDirName, fileName1), t.T())
+	ValidateObjectContentsFromGCS(ctx, storageClient, testDirName, path.Join(NewDirName, fileName2), FileContents, t.T())
+	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, path.Join(ExplicitDirName, fileName2), t.T())
+	// Write more content to the file, which now goes to its new name.
+	operations.WriteWithoutClose(fh, FileContents, t.T())
+	CloseFileAndValidateContentFromGCS(ctx, storageClient, fh, testDirName, path.Join(NewDirName, fileName2), FileContents+FileContents, t.T())
+	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, path.Join(ExplicitDirName, fileName2), t.T())
 }
 
 func (t *LocalFileTestSuite) TestRenameOfLocalFileSucceedsAfterSync() {
@@ -112,9 +103,15 @@ func (t *LocalFileTestSuite) TestRenameOfLocalFileSucceedsAfterSync() {
 }
 
 func (t *LocalFileTestSuite) TestRenameOfDirectoryWithLocalFileSucceedsAfterSync() {
-	t.TestRenameOfDirectoryWithLocalFileFails()
+	fileName := path.Base(t.T().Name())
+	testDirPath = setup.SetupTestDirectory(testDirName)
+	operations.CreateDirectory(path.Join(testDirPath, ExplicitDirName), t.T())
+	// Create local file with some content, and close it.
+	_, fh := CreateLocalFileInTestDir(ctx, storageClient, testDirPath, path.Join(ExplicitDirName, fileName), t.T())
+	WritingToLocalFileShouldNotWriteToGCS(ctx, storageClient, fh, testDirName, path.Join(ExplicitDirName, fileName), t.T())
+	CloseFileAndValidateContentFromGCS(ctx, storageClient, fh, testDirName, path.Join(ExplicitDirName, fileName), FileContents, t.T())
 
-	// Attempt to rename directory again after sync.
+	// Attempt to rename directory after sync.
 	err := os.Rename(
 		path.Join(testDirPath, ExplicitDirName),
 		path.Join(testDirPath, NewDirName))
@@ -123,10 +120,6 @@ func (t *LocalFileTestSuite) TestRenameOfDirectoryWithLocalFileSucceedsAfterSync
 	if err != nil {
 		t.T().Fatalf("os.Rename() failed on directory containing synced files: %v", err)
 	}
-	fileName1 := path.Base(t.T().Name()) + "1"
-	fileName2 := path.Base(t.T().Name()) + "2"
-	ValidateObjectContentsFromGCS(ctx, storageClient, testDirName, path.Join(NewDirName, fileName1), GCSFileContent, t.T())
-	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, path.Join(ExplicitDirName, fileName1), t.T())
-	ValidateObjectContentsFromGCS(ctx, storageClient, testDirName, path.Join(NewDirName, fileName2), FileContents+FileContents, t.T())
-	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, path.Join(ExplicitDirName, fileName2), t.T())
+	ValidateObjectContentsFromGCS(ctx, storageClient, testDirName, path.Join(NewDirName, fileName), FileContents, t.T())
+	ValidateObjectNotFoundErrOnGCS(ctx, storageClient, testDirName, path.Join(ExplicitDirName, fileName), t.T())
 }
@@ -141,8 +141,8 @@ func makeGcsfuseArgs(
 		}
 	}
 
-	// Set the bucket and mount point.
-	return append(args, device, mountPoint), nil
+	// Set the bucket and mount point, which mustn't be taken for a subcommand.
+	return append(args, "--", device, mountPoint), nil
 }
 
 // Parse the supplied command-line arguments from a mount(8) invocation on OS X
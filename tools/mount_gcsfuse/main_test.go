@@ -142,8 +142,8 @@ func TestMakeGcsfuseArgs(t *testing.T) {
 			args, err := makeGcsfuseArgs(device, mountPoint, tc.opts)
 
 			if assert.Nil(t, err) {
-				assert.ElementsMatch(t, args[:len(args)-2], tc.expectedFlags)
-				assert.Equal(t, args[len(args)-2:], []string{device, mountPoint})
+				assert.ElementsMatch(t, args[:len(args)-3], tc.expectedFlags)
+				assert.Equal(t, args[len(args)-3:], []string{"--", device, mountPoint})
 			}
 		})
 	}